@@ -35,11 +35,13 @@ func main() {
 
 	// log errors
 	if err := rootCmd.Execute(); err != nil {
-		// when some errors occur(such as args dis-match), the log may not be initialized
-		if factory.Log == nil {
+		// when some errors occur(such as args dis-match), the log may not be initialized;
+		// with machine-readable output it is silenced, but errors still belong on stderr
+		if factory.Log == nil || factory.MachineReadableOutput() {
 			factory.Log = log.NewInfoLevel()
 		}
 		factory.Log.Error(err)
+		os.Exit(cmdutil.ExitCode(err))
 	}
 }
 
@@ -60,7 +62,7 @@ func initFactory() *cmdutil.Factory {
 		panic(fmt.Sprintf("failed to create callback server (internal error): %v", err))
 	}
 
-	factory.AuthClient = auth.NewImplicitFlowClient(cbs)
+	factory.AuthClient = auth.NewImplicitFlowClient(factory.EffectiveDashURL(), cbs)
 
 	factory.Prompter = prompt.New()
 
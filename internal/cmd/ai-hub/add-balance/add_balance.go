@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -60,7 +61,7 @@ func runAddBalance(f *cmdutil.Factory, opts Options) error {
 	result, err := f.ApiClient.AddAIHubBalance(context.Background(), amountMillicents, &provider)
 	s.Stop()
 	if err != nil {
-		checkoutURL := fmt.Sprintf("https://dash.zeabur.com/checkout?type=ai-hub&amount=%d", opts.amount)
+		checkoutURL := fmt.Sprintf("%s/checkout?type=ai-hub&amount=%d", f.EffectiveLegacyDashURL(), opts.amount)
 		f.Log.Infof("Failed to add balance: %s", err)
 		f.Log.Infof("Opening checkout page in browser...")
 		if openErr := browser.OpenURL(checkoutURL); openErr != nil {
@@ -69,12 +70,7 @@ func runAddBalance(f *cmdutil.Factory, opts Options) error {
 		return nil
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(result)
-	}
-
 	newBalanceDollars := float64(result.NewBalance) / 100000.0
 	f.Log.Infof("Balance added successfully! New balance: $%.2f", newBalanceDollars)
-
-	return nil
+	return f.Printer.Data(printer.Result(result))
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -92,10 +93,6 @@ func updateAutoRecharge(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(result)
-	}
-
 	if result.AutoRechargeThreshold == 0 && result.AutoRechargeAmount == 0 {
 		f.Log.Infof("Auto-recharge disabled")
 	} else {
@@ -104,6 +101,5 @@ func updateAutoRecharge(f *cmdutil.Factory, opts Options) error {
 			float64(result.AutoRechargeAmount)/100000.0,
 		)
 	}
-
-	return nil
+	return f.Printer.Data(printer.Result(result))
 }
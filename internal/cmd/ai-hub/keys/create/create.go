@@ -2,12 +2,12 @@ package create
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -55,17 +55,10 @@ func runCreate(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(result)
-	}
-
 	f.Log.Infof("Key created successfully!")
-	f.Log.Infof("Key ID: %s", result.Key.KeyID)
-	if result.Key.Alias != "" {
-		f.Log.Infof("Alias: %s", result.Key.Alias)
-	}
-	fmt.Printf("API Key: %s\n", result.APIKey)
 	f.Log.Infof("WARNING: This API key will only be shown once. Please save it now.")
-
-	return nil
+	return f.Printer.Data(printer.WithTable(result,
+		[]string{"Key ID", "Alias", "API Key"},
+		[][]string{{result.Key.KeyID, result.Key.Alias, result.APIKey}},
+	))
 }
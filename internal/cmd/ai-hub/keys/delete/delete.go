@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -97,11 +98,6 @@ func deleteKey(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(map[string]string{"status": "success", "keyID": opts.keyID})
-	}
-
 	f.Log.Infof("Key %s deleted successfully", opts.keyID)
-
-	return nil
+	return f.Printer.Data(printer.Result(map[string]string{"status": "success", "keyID": opts.keyID}))
 }
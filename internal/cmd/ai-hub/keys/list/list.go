@@ -41,14 +41,5 @@ func runList(f *cmdutil.Factory, opts Options) error {
 
 	keys := model.AIHubKeys(tenant.Keys)
 
-	if f.JSON {
-		if len(keys) == 0 {
-			return f.Printer.JSON([]any{})
-		}
-		return f.Printer.JSON(keys)
-	}
-
-	f.Printer.Table(keys.Header(), keys.Rows())
-
-	return nil
+	return f.Printer.Data(keys)
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct{}
@@ -37,10 +38,6 @@ func runStatus(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(tenant)
-	}
-
 	balanceDollars := float64(tenant.Balance) / 100000.0
 
 	f.Log.Infof("Balance: $%.2f", balanceDollars)
@@ -55,6 +52,5 @@ func runStatus(f *cmdutil.Factory, opts Options) error {
 	} else {
 		f.Log.Infof("Auto-Recharge: disabled")
 	}
-
-	return nil
+	return f.Printer.Data(printer.Result(tenant))
 }
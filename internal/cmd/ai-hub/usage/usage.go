@@ -10,6 +10,7 @@ import (
 
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -52,18 +53,12 @@ func runUsage(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(usage)
-	}
-
 	f.Log.Infof("Total Spend: $%.6f", usage.TotalSpend)
 
-	if len(usage.ModelsCost) > 0 {
+	costs := model.AIHubModelCosts(usage.ModelsCost)
+	if len(costs) > 0 {
 		f.Log.Infof("")
 		f.Log.Infof("Per-Model Breakdown:")
-		costs := model.AIHubModelCosts(usage.ModelsCost)
-		f.Printer.Table(costs.Header(), costs.Rows())
 	}
-
-	return nil
+	return f.Printer.Data(printer.WithTable(usage, costs.Header(), costs.Rows()))
 }
@@ -0,0 +1,257 @@
+// Package api provides the api command, for raw GraphQL and Z-Send REST calls
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+)
+
+type Options struct {
+	fields    []string
+	rawFields []string
+	paginate  bool
+	subscribe bool
+
+	rest   bool
+	method string
+	input  string
+	apiKey string
+}
+
+func NewCmdAPI(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "api [<query> | <path>]",
+		Short: "Make an authenticated GraphQL request or Z-Send REST call",
+		Long: heredoc.Doc(`
+			Send a GraphQL query or mutation to the Zeabur API with your credentials
+			and print the "data" of the response as JSON.
+
+			The query is the argument, or is read from a file with @<file>, or from
+			stdin when the argument is "-" or missing.
+
+			Pass variables with -F key=value. The value is read from a file with
+			@<file>, and true, false, null, numbers and JSON objects or arrays are
+			decoded unless the variable is declared as String, ID or ObjectID. Use
+			-f key=value to always send a string. Variables declared as ObjectID
+			accept prefixed IDs such as service-<id>.
+
+			With --paginate, a query declaring $skip is run page by page until the
+			first connection with edges and pageInfo { hasNextPage } is exhausted,
+			and all edges are printed together.
+
+			With --subscribe, a subscription is run and every event is printed on
+			its own line until it ends or you press Ctrl-C.
+
+			With --rest, the argument is a path of the Z-Send REST API, e.g.
+			/emails. Fields are sent as a JSON body, or as query parameters for GET
+			and DELETE; --input sends a file as the body instead.
+		`),
+		Example: heredoc.Doc(`
+			$ zeabur api 'query { me { username } }'
+			$ zeabur api 'query($id: ObjectID!) { service(_id: $id) { name } }' -F id=service-65f0c1e2a3b4c5d6e7f80912
+			$ zeabur api @projects.graphql --paginate
+			$ zeabur api --subscribe 'subscription($id: ObjectID!) { buildLogReceived(deploymentID: $id) { message } }' -F id=...
+			$ zeabur api --rest /emails -F from=me@example.com -F 'to=["you@example.com"]' -F subject=Hi -f text=Hello
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var arg string
+			if len(args) > 0 {
+				arg = args[0]
+			}
+			if opts.rest {
+				return runREST(f, opts, cmd, arg)
+			}
+			return runGraphQL(f, opts, cmd, arg)
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&opts.fields, "field", "F", nil, "Add a typed variable or field in key=value format")
+	cmd.Flags().StringArrayVarP(&opts.rawFields, "raw-field", "f", nil, "Add a string variable or field in key=value format")
+	cmd.Flags().BoolVar(&opts.paginate, "paginate", false, "Fetch every page of a connection, advancing $skip")
+	cmd.Flags().BoolVar(&opts.subscribe, "subscribe", false, "Run a subscription and print its events as they arrive")
+	cmd.Flags().BoolVar(&opts.rest, "rest", false, "Call the Z-Send REST API instead of GraphQL")
+	cmd.Flags().StringVarP(&opts.method, "method", "X", "", "HTTP method of a REST call (default GET, or POST with fields)")
+	cmd.Flags().StringVar(&opts.input, "input", "", "File to send as the body of a REST call (- for stdin)")
+	cmd.Flags().StringVar(&opts.apiKey, "api-key", "", "Z-Send API key for REST calls (or set ZSEND_API_KEY)")
+
+	return cmd
+}
+
+func runGraphQL(f *cmdutil.Factory, opts *Options, cmd *cobra.Command, arg string) error {
+	if opts.method != "" || opts.input != "" || opts.apiKey != "" {
+		return errors.New("--method, --input and --api-key only apply to --rest")
+	}
+	if opts.paginate && opts.subscribe {
+		return errors.New("--paginate and --subscribe cannot be used together")
+	}
+
+	query, err := readQuery(arg, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+
+	types := declaredTypes(query)
+	vars, err := parseFields(opts.fields, opts.rawFields, types, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	op := operationType(query)
+	switch {
+	case opts.subscribe:
+		if op != "subscription" {
+			return fmt.Errorf("--subscribe needs a subscription, got a %s", op)
+		}
+		return f.ApiClient.SubscribeRaw(ctx, query, vars, func(data json.RawMessage) error {
+			return f.Printer.Event(data, []string{string(data)})
+		})
+	case op == "subscription":
+		return errors.New("use --subscribe to run a subscription")
+	case opts.paginate:
+		if op != "query" {
+			return fmt.Errorf("--paginate needs a query, got a %s", op)
+		}
+		result, err := paginate(ctx, f, query, vars, types)
+		if err != nil {
+			return err
+		}
+		return f.Printer.Data(result)
+	}
+
+	data, err := f.ApiClient.ExecRaw(ctx, query, vars)
+	if err != nil {
+		return err
+	}
+	return f.Printer.Data(data)
+}
+
+// readQuery returns the query given as the argument, read from a file
+// with @<file>, or read from stdin for "-" or no argument.
+func readQuery(arg string, stdin io.Reader) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch {
+	case arg == "" || arg == "-":
+		if file, ok := stdin.(*os.File); ok && arg == "" && term.IsTerminal(int(file.Fd())) {
+			return "", errors.New("a query is required: pass it as an argument, @<file> or on stdin")
+		}
+		data, err = io.ReadAll(stdin)
+	case strings.HasPrefix(arg, "@"):
+		data, err = readSource(strings.TrimPrefix(arg, "@"), stdin)
+	default:
+		data = []byte(arg)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read query: %w", err)
+	}
+
+	query := strings.TrimSpace(string(data))
+	if query == "" {
+		return "", errors.New("the query is empty")
+	}
+	return query, nil
+}
+
+func runREST(f *cmdutil.Factory, opts *Options, cmd *cobra.Command, path string) error {
+	if opts.paginate || opts.subscribe {
+		return errors.New("--paginate and --subscribe do not apply to --rest")
+	}
+	if !strings.HasPrefix(path, "/") {
+		return errors.New("--rest needs a path starting with /, e.g. /emails")
+	}
+	if opts.input != "" && len(opts.fields)+len(opts.rawFields) > 0 {
+		return errors.New("--input cannot be combined with fields")
+	}
+
+	if opts.apiKey == "" {
+		opts.apiKey = os.Getenv("ZSEND_API_KEY")
+	}
+	if opts.apiKey == "" {
+		return fmt.Errorf("Z-Send API key is required (--api-key or ZSEND_API_KEY)")
+	}
+	if !strings.HasPrefix(opts.apiKey, "zs_") {
+		return fmt.Errorf("invalid API key format: must start with zs_")
+	}
+
+	method := strings.ToUpper(opts.method)
+	if method == "" {
+		method = http.MethodGet
+		if opts.input != "" || len(opts.fields)+len(opts.rawFields) > 0 {
+			method = http.MethodPost
+		}
+	}
+
+	var body json.RawMessage
+	switch {
+	case opts.input != "":
+		data, err := readSource(opts.input, cmd.InOrStdin())
+		if err != nil {
+			return fmt.Errorf("read input: %w", err)
+		}
+		if !json.Valid(data) {
+			return errors.New("the input is not valid JSON")
+		}
+		body = data
+	case len(opts.fields)+len(opts.rawFields) > 0:
+		fields, err := parseFields(opts.fields, opts.rawFields, nil, cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+		if method == http.MethodGet || method == http.MethodDelete {
+			path = withQuery(path, fields)
+			break
+		}
+		if body, err = json.Marshal(fields); err != nil {
+			return err
+		}
+	}
+
+	data, err := f.ApiClient.ZSendREST(context.Background(), opts.apiKey, method, path, body)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if !json.Valid(data) {
+		_, err := fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return err
+	}
+	return f.Printer.Data(json.RawMessage(data))
+}
+
+// withQuery appends fields to path as query parameters.
+func withQuery(path string, fields map[string]any) string {
+	q := url.Values{}
+	for k, v := range fields {
+		q.Set(k, fmt.Sprint(v))
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + q.Encode()
+}
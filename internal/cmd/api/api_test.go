@@ -0,0 +1,133 @@
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	apiCmd "github.com/zeabur/cli/internal/cmd/api"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// TestAPI_CoercesVariables checks that typed fields are decoded unless the
+// variable is declared as a string type, and that ObjectID variables lose
+// their resource prefix.
+func TestAPI_CoercesVariables(t *testing.T) {
+	h := cmdtest.New()
+	var got map[string]any
+	h.API.GraphQL = func(_ string, variables map[string]any) (json.RawMessage, error) {
+		got = variables
+		return json.RawMessage(`{"ok":true}`), nil
+	}
+
+	query := `query($id: ObjectID!, $name: String, $limit: Int, $on: Boolean) { ok }`
+	err := h.Run(apiCmd.NewCmdAPI(h.Factory), query,
+		"-F", "id=service-65f0c1e2a3b4c5d6e7f80912",
+		"-F", "name=123",
+		"-F", "limit=5",
+		"-F", "on=true")
+	if err != nil {
+		t.Fatalf("api: %v", err)
+	}
+
+	want := map[string]string{"id": "65f0c1e2a3b4c5d6e7f80912", "name": "123", "limit": "5", "on": "true"}
+	for k, v := range want {
+		if fmt.Sprint(got[k]) != v {
+			t.Errorf("variable %s = %v, want %s", k, got[k], v)
+		}
+	}
+	if _, ok := got["name"].(string); !ok {
+		t.Errorf("name = %T, want a string", got["name"])
+	}
+	if _, ok := got["limit"].(int64); !ok {
+		t.Errorf("limit = %T, want an integer", got["limit"])
+	}
+
+	var out map[string]bool
+	if err := h.Printer.DecodeLastJSON(&out); err != nil || !out["ok"] {
+		t.Fatalf("printed %v (%v), want the response data", out, err)
+	}
+}
+
+// TestAPI_Paginate serves 25 nodes 10 at a time and checks that they are
+// printed as one connection.
+func TestAPI_Paginate(t *testing.T) {
+	h := cmdtest.New()
+	h.API.GraphQL = func(_ string, variables map[string]any) (json.RawMessage, error) {
+		skip, limit := variables["skip"].(int), 10
+		var edges []string
+		for i := skip; i < min(skip+limit, 25); i++ {
+			edges = append(edges, fmt.Sprintf(`{"node":{"n":%d}}`, i))
+		}
+		return json.RawMessage(fmt.Sprintf(`{"projects":{"edges":[%s],"pageInfo":{"hasNextPage":%t}}}`,
+			strings.Join(edges, ","), skip+limit < 25)), nil
+	}
+
+	query := `query($skip: Int) { projects(skip: $skip) { edges { node { n } } pageInfo { hasNextPage } } }`
+	if err := h.Run(apiCmd.NewCmdAPI(h.Factory), query, "--paginate"); err != nil {
+		t.Fatalf("api: %v", err)
+	}
+
+	if calls := h.API.CallsTo("ExecRaw"); len(calls) != 3 {
+		t.Fatalf("ExecRaw called %d times, want 3", len(calls))
+	}
+	var out struct {
+		Projects struct {
+			Edges []struct {
+				Node struct{ N int } `json:"node"`
+			} `json:"edges"`
+		} `json:"projects"`
+	}
+	if err := h.Printer.DecodeLastJSON(&out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Projects.Edges) != 25 || out.Projects.Edges[24].Node.N != 24 {
+		t.Fatalf("printed %d edges, want all 25 in order", len(out.Projects.Edges))
+	}
+}
+
+func TestAPI_SubscriptionNeedsFlag(t *testing.T) {
+	h := cmdtest.New()
+	h.API.Events = []json.RawMessage{json.RawMessage(`{"log":{"message":"a"}}`), json.RawMessage(`{"log":{"message":"b"}}`)}
+
+	query := `subscription { log { message } }`
+	if err := h.Run(apiCmd.NewCmdAPI(h.Factory), query); err == nil || !strings.Contains(err.Error(), "--subscribe") {
+		t.Fatalf("want an error pointing at --subscribe, got %v", err)
+	}
+	if err := h.Run(apiCmd.NewCmdAPI(h.Factory), query, "--subscribe"); err != nil {
+		t.Fatalf("api --subscribe: %v", err)
+	}
+	if len(h.Printer.Events) != 2 {
+		t.Fatalf("printed %d events, want 2", len(h.Printer.Events))
+	}
+}
+
+func TestAPI_REST(t *testing.T) {
+	h := cmdtest.New()
+	token := "zs_test"
+	h.API.ZSend.APIKeys = []model.ZSendAPIKey{{ID: "key", Token: &token}}
+	var gotMethod, gotPath string
+	var gotBody map[string]any
+	h.API.REST = func(method, path string, body json.RawMessage) (json.RawMessage, error) {
+		gotMethod, gotPath = method, path
+		if err := json.Unmarshal(body, &gotBody); err != nil {
+			t.Errorf("body %s: %v", body, err)
+		}
+		return json.RawMessage(`{"id":"email-1"}`), nil
+	}
+
+	err := h.Run(apiCmd.NewCmdAPI(h.Factory), "--rest", "/emails", "--api-key", token,
+		"-F", `to=["you@example.com"]`, "-f", "subject=Hi")
+	if err != nil {
+		t.Fatalf("api --rest: %v", err)
+	}
+	if gotMethod != http.MethodPost || gotPath != "/emails" {
+		t.Errorf("request = %s %s, want POST /emails", gotMethod, gotPath)
+	}
+	if to, _ := gotBody["to"].([]any); len(to) != 1 || gotBody["subject"] != "Hi" {
+		t.Errorf("body = %v", gotBody)
+	}
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	zapi "github.com/zeabur/cli/pkg/api"
+)
+
+// varDecl matches a variable declaration of an operation, e.g.
+// `$serviceID: ObjectID!` or `$ids: [ObjectID!]`, capturing the name and
+// the named type without list brackets and non-null markers.
+var varDecl = regexp.MustCompile(`\$(\w+)\s*:\s*\[*\s*(\w+)`)
+
+// stringTypes are the scalars whose -F values are kept as strings rather
+// than guessed as numbers or booleans.
+var stringTypes = map[string]bool{"String": true, "ID": true, "ObjectID": true}
+
+// declaredTypes returns the named type of every variable query declares.
+func declaredTypes(query string) map[string]string {
+	types := map[string]string{}
+	for _, m := range varDecl.FindAllStringSubmatch(query, -1) {
+		types[m[1]] = m[2]
+	}
+	return types
+}
+
+// operationType returns "query", "mutation" or "subscription" for the
+// first operation in query. Comments and a leading shorthand `{` are
+// taken into account.
+func operationType(query string) string {
+	for _, line := range strings.Split(query, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, op := range []string{"mutation", "subscription"} {
+			if strings.HasPrefix(line, op) {
+				return op
+			}
+		}
+		return "query"
+	}
+	return "query"
+}
+
+// parseFields turns -F (typed) and -f (raw) key=value pairs into GraphQL
+// variables. Values of variables declared as ObjectID go through
+// api.ObjectID, so prefixed IDs like service-<hex> are accepted.
+func parseFields(typed, raw []string, types map[string]string, stdin io.Reader) (map[string]any, error) {
+	vars := map[string]any{}
+
+	for _, field := range raw {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid field %q: want key=value", field)
+		}
+		vars[key] = coerce(types[key], value)
+	}
+
+	for _, field := range typed {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid field %q: want key=value", field)
+		}
+		v, err := typedValue(types[key], value, stdin)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", key, err)
+		}
+		vars[key] = coerce(types[key], v)
+	}
+
+	return vars, nil
+}
+
+// typedValue interprets a -F value: @file reads a file (@- reads stdin),
+// and unless the variable is declared as a string type, true, false, null,
+// numbers and JSON objects or arrays are decoded.
+func typedValue(typ, value string, stdin io.Reader) (any, error) {
+	if path, ok := strings.CutPrefix(value, "@"); ok {
+		data, err := readSource(path, stdin)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	}
+	if stringTypes[typ] {
+		return value, nil
+	}
+
+	switch value {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n, nil
+	}
+	if n, err := strconv.ParseFloat(value, 64); err == nil {
+		return n, nil
+	}
+	if strings.HasPrefix(value, "{") || strings.HasPrefix(value, "[") {
+		var v any
+		if err := json.Unmarshal([]byte(value), &v); err == nil {
+			return v, nil
+		}
+	}
+	return value, nil
+}
+
+// coerce applies api.ObjectID to ObjectID variables, including every string
+// of a list.
+func coerce(typ string, v any) any {
+	if typ != "ObjectID" {
+		return v
+	}
+	switch v := v.(type) {
+	case string:
+		return zapi.ObjectID(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = coerce(typ, item)
+		}
+		return out
+	}
+	return v
+}
+
+// readSource reads a file, or stdin when path is "-".
+func readSource(path string, stdin io.Reader) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+)
+
+// defaultPageSize is the limit sent when the query declares $limit but the
+// user did not set it; the API caps pages at 100.
+const defaultPageSize = 100
+
+// paginate runs query page by page, like the client's listAll: it advances
+// $skip by the number of edges received until the connection's
+// pageInfo.hasNextPage is false, and returns the first page with the
+// edges of all pages.
+func paginate(ctx context.Context, f *cmdutil.Factory, query string, vars map[string]any, types map[string]string) (any, error) {
+	if _, ok := types["skip"]; !ok {
+		return nil, errors.New("--paginate needs the query to declare a $skip variable")
+	}
+	if _, ok := types["limit"]; ok {
+		if _, set := vars["limit"]; !set {
+			vars["limit"] = defaultPageSize
+		}
+	}
+
+	var (
+		result any
+		path   []string
+		edges  []any
+	)
+	for skip := 0; ; {
+		vars["skip"] = skip
+		data, err := f.ApiClient.ExecRaw(ctx, query, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		page, err := decode(data)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			result = page
+			if path = findConnection(page, nil); path == nil {
+				return nil, errors.New("--paginate needs a connection with edges and pageInfo { hasNextPage } in the query")
+			}
+		}
+
+		conn, _ := lookup(page, path).(map[string]any)
+		pageEdges, _ := conn["edges"].([]any)
+		edges = append(edges, pageEdges...)
+
+		pageInfo, _ := conn["pageInfo"].(map[string]any)
+		hasNext, ok := pageInfo["hasNextPage"].(bool)
+		if !ok {
+			return nil, fmt.Errorf("--paginate needs pageInfo { hasNextPage } in %s", jsonPath(path))
+		}
+		if !hasNext || len(pageEdges) == 0 {
+			first := lookup(result, path).(map[string]any)
+			first["edges"] = edges
+			first["pageInfo"] = pageInfo
+			return result, nil
+		}
+		skip += len(pageEdges)
+	}
+}
+
+// findConnection returns the path of the first object, in key order, that
+// has edges and pageInfo fields, or nil when there is none.
+func findConnection(v any, path []string) []string {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	if _, ok := obj["edges"].([]any); ok {
+		if _, ok := obj["pageInfo"].(map[string]any); ok {
+			return append([]string{}, path...)
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if p := findConnection(obj[k], append(slices.Clip(path), k)); p != nil {
+			return p
+		}
+	}
+	return nil
+}
+
+func lookup(v any, path []string) any {
+	for _, k := range path {
+		obj, _ := v.(map[string]any)
+		v = obj[k]
+	}
+	return v
+}
+
+func jsonPath(path []string) string {
+	if len(path) == 0 {
+		return "the response"
+	}
+	return strings.Join(path, ".")
+}
+
+// decode decodes a JSON response keeping numbers as written.
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return v, nil
+}
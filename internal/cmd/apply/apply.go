@@ -0,0 +1,100 @@
+// Package apply provides the apply command
+package apply
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	planCmd "github.com/zeabur/cli/internal/cmd/plan"
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/manifest"
+	"github.com/zeabur/cli/pkg/printer"
+)
+
+type Options struct {
+	file        string
+	diff        manifest.DiffOptions
+	skipConfirm bool
+}
+
+func NewCmdApply(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Converge a project to its manifest",
+		Long: heredoc.Doc(`
+			Compute the same plan as 'zeabur plan' and execute it: create missing
+			services, then update variables, port-forwarding modes, image tags and
+			domains. Services missing from the manifest are only deleted with --prune,
+			and the image tags of existing services are only re-applied, which
+			redeploys them, with --force-image-tags.
+		`),
+		Example: heredoc.Doc(`
+			$ zeabur apply
+			$ zeabur apply -f deploy/production.yaml --yes -i=false
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runApply(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.file, "file", "f", manifest.DefaultFile, "Path to the manifest file")
+	planCmd.AddDiffFlags(cmd, &opts.diff)
+	cmd.Flags().BoolVarP(&opts.skipConfirm, "yes", "y", false, "Skip confirmation")
+
+	return cmd
+}
+
+func runApply(f *cmdutil.Factory, opts *Options) error {
+	m, state, plan, err := planCmd.Compute(f, opts.file, opts.diff)
+	if err != nil {
+		return err
+	}
+
+	if plan.Empty() {
+		if f.StructuredOutput() {
+			return f.Printer.Data(plan)
+		}
+		plan.Render(os.Stdout)
+		return nil
+	}
+
+	if !f.StructuredOutput() {
+		plan.Render(os.Stdout)
+		fmt.Println()
+	}
+
+	if f.Interactive && !opts.skipConfirm {
+		if f.StructuredOutput() {
+			// keep stdout for the data, but show what is being confirmed
+			plan.Render(os.Stderr)
+			fmt.Fprintln(os.Stderr)
+		}
+		confirm, err := f.Prompter.Confirm("Do you want to apply these changes?", false)
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			return nil
+		}
+	} else if !opts.skipConfirm {
+		return fmt.Errorf("apply requires --yes flag in non-interactive mode")
+	}
+
+	err = manifest.Apply(context.Background(), f.ApiClient, m, state, plan, func(a manifest.Action) {
+		f.Log.Debugf("applied: %s %s", a.Kind, a.Describe())
+	})
+	if err != nil {
+		return err
+	}
+
+	f.Log.Infof("%s Apply complete. %d created, %d updated, %d deleted.", cmdutil.SuccessIcon,
+		plan.Count(manifest.ActionCreate), plan.Count(manifest.ActionUpdate), plan.Count(manifest.ActionDelete))
+	return f.Printer.Data(printer.Result(plan))
+}
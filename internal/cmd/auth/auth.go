@@ -6,6 +6,7 @@ import (
 
 	authLoginCmd "github.com/zeabur/cli/internal/cmd/auth/login"
 	authLogoutCmd "github.com/zeabur/cli/internal/cmd/auth/logout"
+	authProfileCmd "github.com/zeabur/cli/internal/cmd/auth/profile"
 	authStatusCmd "github.com/zeabur/cli/internal/cmd/auth/status"
 	"github.com/zeabur/cli/internal/cmdutil"
 )
@@ -22,6 +23,7 @@ func NewCmdAuth(f *cmdutil.Factory) *cobra.Command {
 	cmd.AddCommand(authLoginCmd.NewCmdLogin(f))
 	cmd.AddCommand(authLogoutCmd.NewCmdLogout(f))
 	cmd.AddCommand(authStatusCmd.NewCmdStatus(f))
+	cmd.AddCommand(authProfileCmd.NewCmdProfile(f))
 	// cmd.AddCommand(authTokenCmd.NewCmdToken(f, nil))
 
 	return cmd
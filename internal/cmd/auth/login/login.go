@@ -5,11 +5,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 
-	"github.com/hasura/go-graphql-client"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/pkg/api"
@@ -19,12 +16,14 @@ import (
 // Options is the struct for the login command
 type Options struct {
 	NewClient func(string) api.Client // to mock in tests
+
+	token string // --token, stored in the active profile
 }
 
 // NewCmdLogin creates the login command
 func NewCmdLogin(f *cmdutil.Factory) *cobra.Command {
 	opts := &Options{
-		NewClient: api.New,
+		NewClient: f.NewApiClient,
 	}
 	cmd := &cobra.Command{
 		Use:   "login",
@@ -35,11 +34,7 @@ func NewCmdLogin(f *cmdutil.Factory) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().String(config.KeyTokenString, "", "Zeabur token to use for authentication")
-	err := viper.BindPFlag(config.KeyTokenString, cmd.Flags().Lookup(config.KeyTokenString))
-	if err != nil {
-		return nil
-	}
+	cmd.Flags().StringVar(&opts.token, config.KeyTokenString, "", "Zeabur token to use for authentication")
 
 	return cmd
 }
@@ -56,14 +51,17 @@ func RunLogin(f *cmdutil.Factory, opts *Options) error {
 		f.Log.Debug("Running login in non-interactive mode")
 	}
 
+	// --token is written to the active profile rather than bound to the
+	// top-level key, so it lands in the profile being logged in
+	if opts.token != "" {
+		f.Config.SetTokenString(opts.token)
+	}
+
 	if f.LoggedIn() {
 		f.ApiClient = opts.NewClient(f.Config.GetTokenString())
 		user, err := f.ApiClient.GetUserInfo(context.Background())
 		if err != nil {
-			var graphqlErrors graphql.Errors
-			if errors.As(err, &graphqlErrors) &&
-				len(graphqlErrors) > 0 &&
-				strings.HasPrefix(graphqlErrors[0].Message, "401 Unauthorized") {
+			if errors.Is(err, api.ErrUnauthorized) {
 				f.Log.Debug("Token is expired or invalid, need to login again")
 			} else {
 				return fmt.Errorf("failed to get user info: %w", err)
@@ -0,0 +1,78 @@
+// Package add implements `zeabur auth profile add`.
+package add
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmd/auth/login"
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/config"
+	"github.com/zeabur/cli/pkg/printer"
+)
+
+type Options struct {
+	name  string
+	token string
+	use   bool
+}
+
+// NewCmdAdd builds `zeabur auth profile add`.
+func NewCmdAdd(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add a profile and log in to it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			return runAdd(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.token, config.KeyTokenString, "", "Zeabur token to use for authentication")
+	cmd.Flags().BoolVar(&opts.use, "use", false, "Make the new profile the active one")
+
+	return cmd
+}
+
+func runAdd(f *cmdutil.Factory, opts *Options) error {
+	if err := config.ValidateProfileName(opts.name); err != nil {
+		return err
+	}
+	if slices.Contains(f.Config.ListProfiles(), opts.name) {
+		return fmt.Errorf("profile %q already exists", opts.name)
+	}
+	if opts.use && f.HasProfileOverride() {
+		return fmt.Errorf("--use cannot be combined with --profile or %s", config.ProfileEnv)
+	}
+
+	// log in with the new profile active, so the token lands in it; the
+	// active profile only changes on disk with --use
+	previous := f.Config.GetProfile()
+	if err := f.Config.SetProfile(opts.name, opts.use); err != nil {
+		return err
+	}
+	if opts.token != "" {
+		f.Config.SetTokenString(opts.token)
+	}
+	if err := login.RunLogin(f, &login.Options{NewClient: f.NewApiClient}); err != nil {
+		return err
+	}
+
+	if !opts.use {
+		if err := f.Config.SetProfile(previous, false); err != nil {
+			return err
+		}
+	}
+
+	if opts.use {
+		f.Log.Infof("Profile %q added and now active", opts.name)
+	} else {
+		f.Log.Infof("Profile %q added; switch to it with `zeabur auth profile use %s` or run a single command with `--profile %s`", opts.name, opts.name, opts.name)
+	}
+	return f.Printer.Data(printer.Result(map[string]any{"profile": opts.name, "active": opts.use}))
+}
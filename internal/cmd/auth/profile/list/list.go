@@ -0,0 +1,73 @@
+// Package list implements `zeabur auth profile list`.
+package list
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
+)
+
+// NewCmdList builds `zeabur auth profile list`.
+func NewCmdList(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List auth profiles",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(f)
+		},
+	}
+}
+
+type profile struct {
+	Name      string `json:"name"`
+	Active    bool   `json:"active"`
+	User      string `json:"user"`
+	Username  string `json:"username"`
+	Workspace string `json:"workspace"`
+}
+
+func runList(f *cmdutil.Factory) error {
+	// the profile-scoped getters read the active profile, so activate each
+	// one in turn and restore the current one afterwards
+	current := f.Config.GetProfile()
+	names := f.Config.ListProfiles()
+	profiles := make([]profile, 0, len(names))
+	for _, name := range names {
+		if err := f.Config.SetProfile(name, false); err != nil {
+			return err
+		}
+		ws := f.Config.GetContext().GetWorkspace()
+		workspace := "personal"
+		if ws.IsTeam() {
+			workspace = ws.Name
+		}
+		profiles = append(profiles, profile{
+			Name:      name,
+			Active:    name == current,
+			User:      f.Config.GetUser(),
+			Username:  f.Config.GetUsername(),
+			Workspace: workspace,
+		})
+	}
+	if err := f.Config.SetProfile(current, false); err != nil {
+		return err
+	}
+
+	header := []string{"", "Profile", "User", "Username", "Workspace"}
+	rows := make([][]string, 0, len(profiles))
+	for _, p := range profiles {
+		marker := ""
+		if p.Active {
+			marker = "*"
+		}
+		user := p.User
+		if p.Username == "" {
+			user = "(not logged in)"
+		}
+		rows = append(rows, []string{marker, p.Name, user, p.Username, p.Workspace})
+	}
+	return f.Printer.Data(printer.WithTable(profiles, header, rows))
+}
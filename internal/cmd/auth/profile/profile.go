@@ -0,0 +1,33 @@
+// Package profile contains the cmd for managing named auth profiles
+package profile
+
+import (
+	"github.com/spf13/cobra"
+
+	profileAddCmd "github.com/zeabur/cli/internal/cmd/auth/profile/add"
+	profileListCmd "github.com/zeabur/cli/internal/cmd/auth/profile/list"
+	profileRemoveCmd "github.com/zeabur/cli/internal/cmd/auth/profile/remove"
+	profileUseCmd "github.com/zeabur/cli/internal/cmd/auth/profile/use"
+	"github.com/zeabur/cli/internal/cmdutil"
+)
+
+// NewCmdProfile builds the `zeabur auth profile` parent command.
+func NewCmdProfile(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile <command>",
+		Short: "Manage named auth profiles",
+		Long: `Manage named auth profiles, e.g. a personal and a company account.
+
+Each profile keeps its own token, workspace and context. The "default"
+profile is the one every install starts with. Use --profile or
+ZEABUR_PROFILE to run a single command under another profile without
+switching.`,
+	}
+
+	cmd.AddCommand(profileAddCmd.NewCmdAdd(f))
+	cmd.AddCommand(profileListCmd.NewCmdList(f))
+	cmd.AddCommand(profileUseCmd.NewCmdUse(f))
+	cmd.AddCommand(profileRemoveCmd.NewCmdRemove(f))
+
+	return cmd
+}
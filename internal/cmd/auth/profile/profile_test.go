@@ -0,0 +1,76 @@
+package profile_test
+
+import (
+	"testing"
+
+	"github.com/zeabur/cli/internal/cmd/auth/profile"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/pkg/config"
+)
+
+func newHarness(t *testing.T) *cmdtest.Harness {
+	t.Helper()
+
+	h := cmdtest.New()
+	if err := h.Config.SetProfile("work", false); err != nil {
+		t.Fatal(err)
+	}
+	h.Config.SetTokenString("work-token")
+	h.Config.SetUsername("acme-bot")
+	if err := h.Config.SetProfile(config.DefaultProfile, false); err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestProfileList(t *testing.T) {
+	h := newHarness(t)
+
+	if err := h.Run(profile.NewCmdProfile(h.Factory), "list"); err != nil {
+		t.Fatalf("auth profile list: %v", err)
+	}
+	rows := h.Printer.LastTable().Rows
+	if len(rows) != 2 {
+		t.Fatalf("rows = %v, want default and work", rows)
+	}
+	if rows[0][0] != "*" || rows[0][1] != config.DefaultProfile || rows[0][3] != h.API.User.Username {
+		t.Errorf("default row = %v", rows[0])
+	}
+	if rows[1][0] != "" || rows[1][1] != "work" || rows[1][3] != "acme-bot" {
+		t.Errorf("work row = %v", rows[1])
+	}
+	if h.Config.GetProfile() != config.DefaultProfile {
+		t.Errorf("list left profile %q active", h.Config.GetProfile())
+	}
+}
+
+func TestProfileUse(t *testing.T) {
+	h := newHarness(t)
+
+	if err := h.Run(profile.NewCmdProfile(h.Factory), "use", "work"); err != nil {
+		t.Fatalf("auth profile use: %v", err)
+	}
+	if h.Config.GetProfile() != "work" || h.Config.GetTokenString() != "work-token" {
+		t.Fatalf("active profile = %q with token %q", h.Config.GetProfile(), h.Config.GetTokenString())
+	}
+
+	if err := h.Run(profile.NewCmdProfile(h.Factory), "use", "missing"); err == nil {
+		t.Error("switched to a profile that does not exist")
+	}
+}
+
+// TestProfileUse_RejectsOverride mirrors `context set` under --workspace:
+// a one-shot --profile must not be mixed with a persistent switch.
+func TestProfileUse_RejectsOverride(t *testing.T) {
+	h := newHarness(t)
+	if err := h.Factory.SetProfileOverride("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Run(profile.NewCmdProfile(h.Factory), "use", config.DefaultProfile); err == nil {
+		t.Fatal("auth profile use succeeded under --profile")
+	}
+	if err := h.Factory.SetProfileOverride("missing"); err == nil {
+		t.Error("--profile accepted a profile that does not exist")
+	}
+}
@@ -0,0 +1,66 @@
+// Package remove implements `zeabur auth profile remove`.
+package remove
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/printer"
+)
+
+type Options struct {
+	name string
+	yes  bool
+}
+
+// NewCmdRemove builds `zeabur auth profile remove`.
+func NewCmdRemove(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:     "remove <name>",
+		Short:   "Remove an auth profile and its stored token and context",
+		Aliases: []string{"rm"},
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			return runRemove(f, opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip confirmation")
+	util.SetArgsCompletion(cmd, util.CompleteProfile)
+
+	return cmd
+}
+
+func runRemove(f *cmdutil.Factory, opts *Options) error {
+	if f.HasProfileOverride() && f.Config.GetProfile() == opts.name {
+		return fmt.Errorf("cannot remove profile %q while running under it", opts.name)
+	}
+
+	if f.Interactive && !opts.yes {
+		confirm, err := f.Prompter.Confirm(fmt.Sprintf("Are you sure you want to remove profile %q?", opts.name), false)
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			f.Log.Info("Remove profile canceled")
+			return nil
+		}
+	}
+
+	wasActive := f.Config.GetProfile() == opts.name
+	if err := f.Config.RemoveProfile(opts.name); err != nil {
+		return err
+	}
+
+	f.Log.Infof("Profile %q removed", opts.name)
+	if wasActive {
+		f.Log.Infof("Switched back to profile %q", f.Config.GetProfile())
+	}
+	return f.Printer.Data(printer.Result(map[string]string{"removed": opts.name, "profile": f.Config.GetProfile()}))
+}
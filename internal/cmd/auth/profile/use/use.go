@@ -0,0 +1,51 @@
+// Package use implements `zeabur auth profile use`.
+package use
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/config"
+	"github.com/zeabur/cli/pkg/printer"
+)
+
+// NewCmdUse builds `zeabur auth profile use`.
+func NewCmdUse(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "use <name>",
+		Short: "Switch the active auth profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUse(f, args[0])
+		},
+	}
+	util.SetArgsCompletion(cmd, util.CompleteProfile)
+
+	return cmd
+}
+
+func runUse(f *cmdutil.Factory, name string) error {
+	// same contract as `context set` under --workspace: the override is
+	// one-shot and must not be mixed with a persistent switch
+	if f.HasProfileOverride() {
+		return fmt.Errorf("`auth profile use` changes the active profile and cannot be combined with --profile or %s", config.ProfileEnv)
+	}
+	if !slices.Contains(f.Config.ListProfiles(), name) {
+		return fmt.Errorf("profile %q not found; create it with `zeabur auth profile add %s`", name, name)
+	}
+
+	if err := f.Config.SetProfile(name, true); err != nil {
+		return err
+	}
+
+	if user := f.Config.GetUsername(); user != "" {
+		f.Log.Infof("Switched to profile %q (%s)", name, user)
+	} else {
+		f.Log.Infof("Switched to profile %q, which is not logged in; run `zeabur auth login`", name)
+	}
+	return f.Printer.Data(printer.Result(map[string]string{"profile": name}))
+}
@@ -7,7 +7,10 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
-	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/config"
+	"github.com/zeabur/cli/pkg/credential"
+	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 // statusOptions contains the input to the status command.
@@ -35,31 +38,46 @@ func NewCmdStatus(f *cmdutil.Factory) *cobra.Command {
 // If you want to add new dependencies, please add them in the statusOptions struct
 
 func runStatus(f *cmdutil.Factory, opts *statusOptions) error {
+	backend, storeErr := f.Config.GetCredentialStore()
+
 	if !f.LoggedIn() {
-		if f.JSON {
-			return f.Printer.JSON(map[string]string{"status": "not logged in"})
+		if storeErr != nil {
+			return fmt.Errorf("read token from the %s credential store: %w", backend, storeErr)
 		}
 		f.Log.Infof("Not logged in.")
-		return nil
+		f.Log.Infof("Credential store: %s", backend)
+		return f.Printer.Data(printer.Result(map[string]string{"status": "not logged in", "credentialStore": backend}))
 	}
 
-	f.ApiClient = api.New(f.Config.GetTokenString())
+	f.ApiClient = f.NewApiClient(f.Config.GetTokenString())
 
 	user, err := f.ApiClient.GetUserInfo(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to get user info: %w", err)
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(user)
-	}
-
 	f.Log.Infof("Logged in as %s (%s), email: %s, plan: %s, credit: $%.2f",
 		user.Name, user.Username, user.Email, user.Subscription.Plan, float64(user.Credit)/100)
+	if profile := f.Config.GetProfile(); profile != config.DefaultProfile {
+		f.Log.Infof("Profile: %s", profile)
+	}
+	f.Log.Infof("Credential store: %s", credentialStoreLabel(backend))
 
+	status := struct {
+		*model.User
+		CredentialStore string `json:"credentialStore"`
+	}{user, backend}
 	if opts.verbose {
-		f.Printer.Table(user.Header(), user.Rows())
+		return f.Printer.Data(printer.WithTable(status, user.Header(), user.Rows()))
 	}
+	return f.Printer.Data(printer.Result(status))
+}
 
-	return nil
+// credentialStoreLabel explains the plaintext backend, the one users may
+// want to move away from.
+func credentialStoreLabel(backend string) string {
+	if backend == credential.BackendPlaintext {
+		return backend + " (token in the config file; set credential_store to keyring or file to move it)"
+	}
+	return backend
 }
@@ -19,7 +19,7 @@ const (
 
 func NewCmdCompletion(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:                   "zeabur completion [bash|zsh|fish|powershell]",
+		Use:                   "completion [bash|zsh|fish|powershell]",
 		Short:                 "Generate completion script",
 		DisableFlagsInUseLine: true,
 		ValidArgs:             []string{ShellBash, ShellZsh, ShellFish, ShellPowerShell},
@@ -29,20 +29,26 @@ func NewCmdCompletion(f *cmdutil.Factory) *cobra.Command {
 		},
 	}
 
+	// generating a script needs no account
+	cmdutil.DisableAuthCheck(cmd)
+
 	return cmd
 }
 
-// todo: test if this works
+// runCompletion prints the script for the whole command tree. The scripts
+// ask the CLI itself for candidates, so flags such as --project-id complete
+// with the IDs of the current workspace.
 func runCompletion(f *cmdutil.Factory, cmd *cobra.Command, cmdType string) error {
+	root := cmd.Root()
 	switch cmdType {
 	case ShellBash:
-		return cmd.GenBashCompletion(os.Stdout)
+		return root.GenBashCompletionV2(os.Stdout, true)
 	case ShellZsh:
-		return cmd.GenZshCompletion(os.Stdout)
+		return root.GenZshCompletion(os.Stdout)
 	case ShellFish:
-		return cmd.GenFishCompletion(os.Stdout, true)
+		return root.GenFishCompletion(os.Stdout, true)
 	case ShellPowerShell:
-		return cmd.GenPowerShellCompletion(os.Stdout)
+		return root.GenPowerShellCompletionWithDesc(os.Stdout)
 	default:
 		return fmt.Errorf("unsupported shell type %q", cmdType)
 	}
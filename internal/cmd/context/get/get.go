@@ -48,17 +48,7 @@ func runGet(f *cmdutil.Factory, opts *Options) error {
 		}
 	}
 
-	if f.JSON {
-		out := make([]map[string]string, len(data))
-		for i, row := range data {
-			out[i] = map[string]string{}
-			for j, h := range header {
-				out[i][h] = row[j]
-			}
-		}
-		return f.Printer.JSON(out)
-	}
-
+	// the structured formats print one object per row
 	f.Printer.Table(header, data)
 
 	// Human-readable mode also tells the user *why* everything is unset when
@@ -67,6 +57,8 @@ func runGet(f *cmdutil.Factory, opts *Options) error {
 	// (no prose mixed into the payload) so scripts keep parsing it.
 	if f.HasWorkspaceOverride() {
 		f.Log.Info("Note: --workspace is one-shot; persisted project/service/environment context is not used.")
+	} else if link, _ := f.Link(); link != nil {
+		f.Log.Infof("Note: context comes from %s; run `zeabur unlink` to use the global context here.", link.Path())
 	}
 
 	return nil
@@ -82,6 +82,12 @@ func runSet(f *cmdutil.Factory, opts *Options) error {
 		)
 	}
 
+	// The global context is still written, but a link takes precedence
+	// in this directory; say so rather than look like a no-op.
+	if link, _ := f.Link(); link != nil {
+		f.Log.Warnf("This directory is linked by %s; commands run here keep using the linked context.", link.Path())
+	}
+
 	if f.Interactive {
 		return runSetInteractive(f, opts)
 	}
@@ -54,14 +54,23 @@ func newStubConfig() *stubConfig {
 	return &stubConfig{v: viper.New()}
 }
 
-func (s *stubConfig) GetTokenString() string       { return "" }
-func (s *stubConfig) SetTokenString(string)        {}
-func (s *stubConfig) GetUser() string              { return "" }
-func (s *stubConfig) SetUser(string)               {}
-func (s *stubConfig) GetUsername() string          { return "alice" }
-func (s *stubConfig) SetUsername(string)           {}
-func (s *stubConfig) GetContext() zcontext.Context { return zcontext.NewViperContext(s.v) }
-func (s *stubConfig) Write() error                 { return nil }
+func (s *stubConfig) GetTokenString() string              { return "" }
+func (s *stubConfig) SetTokenString(string)               {}
+func (s *stubConfig) GetCredentialStore() (string, error) { return "plaintext", nil }
+func (s *stubConfig) GetUser() string                     { return "" }
+func (s *stubConfig) SetUser(string)                      {}
+func (s *stubConfig) GetUsername() string                 { return "alice" }
+func (s *stubConfig) SetUsername(string)                  {}
+func (s *stubConfig) GetAPIURL() string                   { return "" }
+func (s *stubConfig) GetWebsocketURL() string             { return "" }
+func (s *stubConfig) GetDashURL() string                  { return "" }
+func (s *stubConfig) GetSecretPatterns() []string         { return nil }
+func (s *stubConfig) GetContext() zcontext.Context        { return zcontext.NewViperContext(s.v) }
+func (s *stubConfig) GetProfile() string                  { return config.DefaultProfile }
+func (s *stubConfig) SetProfile(string, bool) error       { return nil }
+func (s *stubConfig) ListProfiles() []string              { return []string{config.DefaultProfile} }
+func (s *stubConfig) RemoveProfile(string) error          { return nil }
+func (s *stubConfig) Write() error                        { return nil }
 
 var _ config.Config = (*stubConfig)(nil)
 
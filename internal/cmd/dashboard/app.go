@@ -0,0 +1,420 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kballard/go-shellquote"
+
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/tui"
+)
+
+const (
+	maxLogLines     = 500
+	deploymentCount = 5
+	metricWindow    = time.Hour
+)
+
+// screen is what the dashboard draws on; a *tui.Terminal in practice.
+type screen interface {
+	Size() (width, height int)
+	Draw(lines []string) error
+	Keys(ctx context.Context) <-chan tui.Key
+}
+
+// app runs the dashboard. The state is only touched by the loop in run;
+// pollers and actions send it updates as functions.
+type app struct {
+	client        api.Client
+	projectID     string
+	projectName   string
+	environmentID string
+	interval      time.Duration
+
+	state   *state
+	updates chan func(*state)
+	refresh chan struct{}
+	actions sync.WaitGroup
+
+	// the service whose deployments, metrics and logs are being watched
+	watching    string
+	stopWatcher context.CancelFunc
+}
+
+func newApp(client api.Client, projectID, projectName, environmentID string, interval time.Duration) *app {
+	return &app{
+		client:        client,
+		projectID:     projectID,
+		projectName:   projectName,
+		environmentID: environmentID,
+		interval:      interval,
+		state:         &state{project: projectName},
+		updates:       make(chan func(*state)),
+		refresh:       make(chan struct{}, 1),
+	}
+}
+
+// run draws the dashboard on s until the user quits or ctx is done. It
+// waits for actions in flight, such as a restart, before it returns.
+func (a *app) run(ctx context.Context, s screen) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer a.actions.Wait()
+	defer cancel()
+
+	keys := s.Keys(ctx)
+	go a.pollServices(ctx)
+
+	// redraw now and then to follow terminal resizes and relative times
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+
+	for {
+		a.watchSelected(ctx)
+		width, height := s.Size()
+		if err := s.Draw(a.state.render(width, height)); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case update := <-a.updates:
+			update(a.state)
+		case k, ok := <-keys:
+			if !ok || a.handleKey(ctx, k) {
+				return nil
+			}
+		case <-tick.C:
+		}
+	}
+}
+
+// send hands update to the loop, unless the dashboard has quit.
+func (a *app) send(ctx context.Context, update func(*state)) {
+	select {
+	case a.updates <- update:
+	case <-ctx.Done():
+	}
+}
+
+func (a *app) pollServices(ctx context.Context) {
+	for {
+		services, err := a.client.ListAllServicesDetailByEnvironment(ctx, a.projectID, a.environmentID)
+		a.send(ctx, func(s *state) {
+			if err != nil {
+				s.err = fmt.Errorf("list services: %w", err)
+				return
+			}
+			s.setServices(services)
+			s.updatedAt = time.Now()
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.refresh:
+		case <-time.After(a.interval):
+		}
+	}
+}
+
+// watchSelected starts watching the selected service when the selection
+// changed, and stops watching the previous one.
+func (a *app) watchSelected(ctx context.Context) {
+	svc := a.state.current()
+	id := ""
+	if svc != nil {
+		id = svc.ID
+	}
+	if id == a.watching {
+		return
+	}
+	if a.stopWatcher != nil {
+		a.stopWatcher()
+	}
+	a.watching = id
+	a.state.resetService()
+	if id == "" {
+		return
+	}
+
+	ctx, a.stopWatcher = context.WithCancel(ctx)
+	go a.pollService(ctx, id)
+	go a.streamLogs(ctx, id)
+}
+
+// pollService fetches the deployments and metrics of the service id.
+func (a *app) pollService(ctx context.Context, id string) {
+	for {
+		var deployments model.Deployments
+		conn, err := a.client.ListDeployments(ctx, id, a.environmentID, deploymentCount)
+		if err == nil {
+			for _, edge := range conn.Edges {
+				deployments = append(deployments, edge.Node)
+			}
+		}
+
+		end := time.Now()
+		cpu, cpuErr := a.client.ServiceMetric(ctx, id, a.projectID, a.environmentID, string(model.MetricTypeCPU), end.Add(-metricWindow), end)
+		memory, memErr := a.client.ServiceMetric(ctx, id, a.projectID, a.environmentID, string(model.MetricTypeMemory), end.Add(-metricWindow), end)
+
+		a.send(ctx, func(s *state) {
+			if s.currentID() != id {
+				return
+			}
+			if err != nil {
+				s.err = fmt.Errorf("list deployments: %w", err)
+			} else {
+				s.deployments = deployments
+			}
+			if cpuErr == nil {
+				s.cpu = metricValues(cpu)
+			}
+			if memErr == nil {
+				s.memory = metricValues(memory)
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(a.interval):
+		}
+	}
+}
+
+func metricValues(m *model.ServiceMetric) []float64 {
+	values := make([]float64, 0, len(m.Metrics))
+	for _, p := range m.Metrics {
+		values = append(values, p.Value)
+	}
+	return values
+}
+
+// streamLogs shows the recent runtime logs of the service id, then follows
+// them.
+func (a *app) streamLogs(ctx context.Context, id string) {
+	if logs, err := a.client.GetRuntimeLogs(ctx, id, a.environmentID, ""); err == nil {
+		a.send(ctx, func(s *state) {
+			if s.currentID() == id {
+				for _, l := range logs {
+					s.appendLog(*l)
+				}
+			}
+		})
+	}
+
+	logChan, errChan := a.client.WatchRuntimeLogs(ctx, a.projectID, id, a.environmentID, "")
+	for l := range logChan {
+		a.send(ctx, func(s *state) {
+			if s.currentID() == id {
+				s.appendLog(l)
+			}
+		})
+	}
+	if err := <-errChan; err != nil && ctx.Err() == nil {
+		a.send(ctx, func(s *state) {
+			if s.currentID() == id {
+				s.message = "Log stream ended: " + err.Error()
+			}
+		})
+	}
+}
+
+// handleKey applies the key k and reports whether to quit.
+func (a *app) handleKey(ctx context.Context, k tui.Key) bool {
+	s := a.state
+	switch s.mode {
+	case modeConfirm:
+		s.mode = modeNormal
+		if k == "y" || k == "Y" {
+			a.do(ctx, s.pending)
+		} else {
+			s.message = "Canceled"
+		}
+		s.pending = nil
+		return false
+	case modeExec:
+		return a.handleExecKey(ctx, k)
+	}
+
+	switch k {
+	case "q", tui.KeyCtrlC:
+		return true
+	case "j", tui.KeyDown:
+		s.move(1)
+	case "k", tui.KeyUp:
+		s.move(-1)
+	case tui.KeyEscape:
+		s.execOutput, s.message, s.err = nil, "", nil
+	case "r":
+		a.confirm("Restart", "restarted", a.client.RestartService)
+	case "d":
+		a.confirm("Redeploy", "redeploying", a.client.RedeployService)
+	case "s":
+		a.confirm("Suspend", "suspended", a.client.SuspendService)
+	case "e":
+		if s.current() != nil {
+			s.mode, s.input = modeExec, ""
+		}
+	}
+	return false
+}
+
+func (a *app) handleExecKey(ctx context.Context, k tui.Key) bool {
+	s := a.state
+	switch {
+	case k == tui.KeyCtrlC || k == tui.KeyEscape:
+		s.mode = modeNormal
+	case k == tui.KeyBackspace:
+		if r := []rune(s.input); len(r) > 0 {
+			s.input = string(r[:len(r)-1])
+		}
+	case k == tui.KeyEnter:
+		s.mode = modeNormal
+		command, err := shellquote.Split(s.input)
+		if err != nil || len(command) == 0 {
+			s.message = "Invalid command"
+			return false
+		}
+		svc := s.current()
+		name, id := svc.Name, svc.ID
+		s.message = "Running " + s.input + " in " + name + " …"
+		a.spawn(ctx, func(ctx context.Context) func(*state) {
+			result, err := a.client.ExecuteCommand(ctx, id, a.environmentID, command)
+			return func(s *state) {
+				if err != nil {
+					s.err = fmt.Errorf("exec: %w", err)
+					return
+				}
+				s.execOutput = strings.Split(strings.TrimRight(result.Output, "\n"), "\n")
+				for i, line := range s.execOutput {
+					s.execOutput[i] = tui.Sanitize(line)
+				}
+				s.message = fmt.Sprintf("%s exited with %d in %s (esc to return to the logs)", command[0], result.ExitCode, name)
+			}
+		})
+	case k.Printable():
+		s.input += string(k)
+	}
+	return false
+}
+
+// confirm asks whether to apply run to the selected service.
+func (a *app) confirm(verb, done string, run func(ctx context.Context, id, environmentID string) error) {
+	s := a.state
+	svc := s.current()
+	if svc == nil {
+		return
+	}
+	name, id := svc.Name, svc.ID
+	s.mode = modeConfirm
+	s.message = fmt.Sprintf("%s %s? [y/N]", verb, name)
+	s.pending = func(ctx context.Context) func(*state) {
+		err := run(ctx, id, a.environmentID)
+		return func(s *state) {
+			if err != nil {
+				s.err = fmt.Errorf("%s %s: %w", strings.ToLower(verb), name, err)
+				return
+			}
+			s.message = name + " " + done
+		}
+	}
+}
+
+func (a *app) do(ctx context.Context, action func(context.Context) func(*state)) {
+	a.state.message = "Working …"
+	a.spawn(ctx, action)
+}
+
+// spawn runs fn in the background and applies the update it returns, then
+// refreshes the service list to show the effect. Quitting does not cancel
+// fn: a restart the user confirmed is sent even if they quit right away.
+func (a *app) spawn(ctx context.Context, fn func(context.Context) func(*state)) {
+	a.actions.Add(1)
+	go func() {
+		defer a.actions.Done()
+		update := fn(context.WithoutCancel(ctx))
+		a.send(ctx, update)
+		select {
+		case a.refresh <- struct{}{}:
+		default:
+		}
+	}()
+}
+
+type mode int
+
+const (
+	modeNormal mode = iota
+	modeConfirm
+	modeExec
+)
+
+// state is what the dashboard shows.
+type state struct {
+	project   string
+	services  model.ServiceDetails
+	selected  int
+	updatedAt time.Time
+
+	// of the selected service
+	deployments model.Deployments
+	cpu, memory []float64
+	logs        []string
+	execOutput  []string
+
+	mode    mode
+	pending func(context.Context) func(*state)
+	input   string
+	message string
+	err     error
+}
+
+func (s *state) current() *model.ServiceDetail {
+	if s.selected < 0 || s.selected >= len(s.services) {
+		return nil
+	}
+	return s.services[s.selected]
+}
+
+func (s *state) currentID() string {
+	if svc := s.current(); svc != nil {
+		return svc.ID
+	}
+	return ""
+}
+
+// setServices replaces the services, sorted by name, keeping the selected
+// one selected.
+func (s *state) setServices(services model.ServiceDetails) {
+	id := s.currentID()
+	slices.SortFunc(services, func(a, b *model.ServiceDetail) int { return strings.Compare(a.Name, b.Name) })
+	s.services = services
+	s.selected = max(0, slices.IndexFunc(services, func(svc *model.ServiceDetail) bool { return svc.ID == id }))
+}
+
+func (s *state) move(delta int) {
+	if len(s.services) > 0 {
+		s.selected = min(max(s.selected+delta, 0), len(s.services)-1)
+	}
+}
+
+func (s *state) resetService() {
+	s.deployments, s.cpu, s.memory, s.logs, s.execOutput = nil, nil, nil, nil, nil
+}
+
+func (s *state) appendLog(l model.Log) {
+	for _, line := range strings.Split(strings.TrimRight(l.Message, "\n"), "\n") {
+		s.logs = append(s.logs, l.Timestamp.Local().Format("15:04:05")+" "+tui.Sanitize(line))
+	}
+	if over := len(s.logs) - maxLogLines; over > 0 {
+		s.logs = slices.Delete(s.logs, 0, over)
+	}
+}
@@ -0,0 +1,109 @@
+// Package dashboard provides the dashboard command, a full-screen view of a project
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/log"
+	"github.com/zeabur/cli/pkg/tui"
+)
+
+type Options struct {
+	projectID     string
+	environmentID string
+	interval      time.Duration
+}
+
+func NewCmdDashboard(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:     "dashboard",
+		Short:   "Watch and manage the services of a project in a full-screen terminal UI",
+		Aliases: []string{"dash"},
+		Long: heredoc.Doc(`
+			Show the services of the current project with their status, domains and
+			git trigger, and for the selected service its recent deployments, CPU and
+			memory over the last hour and its runtime logs as they arrive.
+
+			Keys: ↑/↓ or j/k select a service, r restarts it, d redeploys it,
+			s suspends it, e runs a command in it, esc clears messages and exec
+			output, q quits.
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDashboard(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.projectID, "project-id", "", "Project ID, defaults to the current project")
+	cmd.Flags().StringVar(&opts.environmentID, "env-id", "", "Environment ID")
+	cmd.Flags().DurationVar(&opts.interval, "interval", 5*time.Second, "How often to refresh services, deployments and metrics")
+
+	return cmd
+}
+
+func runDashboard(f *cmdutil.Factory, opts *Options) error {
+	if f.StructuredOutput() {
+		return fmt.Errorf("dashboard is interactive and does not support --output %s", f.OutputFormat().Format)
+	}
+	if opts.interval < time.Second {
+		return errors.New("--interval must be at least 1s")
+	}
+
+	if opts.projectID == "" {
+		opts.projectID = f.CurrentProjectID()
+	}
+	if opts.projectID == "" {
+		if !f.Interactive {
+			return errors.New("--project-id is required: there is no current project")
+		}
+		_, project, err := f.Selector.SelectProject()
+		if err != nil {
+			return err
+		}
+		if project == nil {
+			return nil
+		}
+		opts.projectID = project.ID
+	}
+
+	project, err := f.ApiClient.GetProject(context.Background(), opts.projectID, "", "")
+	if err != nil {
+		return fmt.Errorf("get project: %w", err)
+	}
+	if opts.environmentID == "" {
+		if opts.environmentID, err = util.ResolveEnvironmentID(f.ApiClient, opts.projectID); err != nil {
+			return err
+		}
+	}
+
+	term, err := tui.Open(os.Stdin, os.Stdout)
+	if errors.Is(err, tui.ErrNotTerminal) {
+		return errors.New("dashboard needs an interactive terminal")
+	}
+	if err != nil {
+		return err
+	}
+	defer term.Close()
+
+	// log lines would tear the screen
+	f.Log = log.NewSilent()
+
+	// Ctrl-C arrives as a key in raw mode; SIGTERM still ends the dashboard
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+	defer stop()
+
+	return newApp(f.ApiClient, project.ID, project.Name, opts.environmentID, opts.interval).run(ctx, term)
+}
@@ -0,0 +1,137 @@
+package dashboard_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zeabur/cli/internal/cmd/dashboard"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/tui"
+)
+
+// fakeScreen records the frames drawn and plays keys sent by the test.
+type fakeScreen struct {
+	mu    sync.Mutex
+	frame string
+	keys  chan tui.Key
+}
+
+var _ dashboard.Screen = (*fakeScreen)(nil)
+
+func (s *fakeScreen) Size() (int, int) { return 120, 30 }
+
+func (s *fakeScreen) Draw(lines []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.frame = strings.Join(lines, "\n")
+	return nil
+}
+
+func (s *fakeScreen) Keys(context.Context) <-chan tui.Key { return s.keys }
+
+// waitFor waits until the screen shows text.
+func (s *fakeScreen) waitFor(t *testing.T, text string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		frame := s.frame
+		s.mu.Unlock()
+		if strings.Contains(frame, text) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.Fatalf("screen never showed %q; last frame:\n%s", text, s.frame)
+}
+
+func TestDashboard_SelectAndRestart(t *testing.T) {
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "shop")
+	h.API.SeedService(project.ID, "api")
+	web := h.API.SeedService(project.ID, "web")
+	h.API.SeedRuntimeLogs(web.ID, env.ID, model.Logs{{Timestamp: time.Now(), Message: "listening on :8080"}})
+	h.API.SeedDeployment(web.ID, env.ID, "RUNNING")
+
+	s := &fakeScreen{keys: make(chan tui.Key)}
+	done := make(chan error, 1)
+	go func() {
+		done <- dashboard.Run(context.Background(), h.API, project.ID, env.ID, time.Minute, s)
+	}()
+
+	s.waitFor(t, "▸ api")
+	s.keys <- "j"
+	s.waitFor(t, "▸ web")
+	s.waitFor(t, "listening on :8080")
+	s.waitFor(t, "RUNNING")
+
+	s.keys <- "r"
+	s.waitFor(t, "Restart web? [y/N]")
+	s.keys <- "y"
+	s.keys <- "q"
+
+	if err := <-done; err != nil {
+		t.Fatalf("dashboard: %v", err)
+	}
+	calls := h.API.CallsTo("RestartService")
+	if len(calls) != 1 || calls[0].Args[0] != web.ID {
+		t.Fatalf("RestartService calls = %v, want one for web", calls)
+	}
+}
+
+func TestDashboard_LogsAreSanitized(t *testing.T) {
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "shop")
+	api := h.API.SeedService(project.ID, "api")
+	h.API.SeedRuntimeLogs(api.ID, env.ID, model.Logs{{Timestamp: time.Now(), Message: "\x1b[2J\x1b]0;owned\x07\x1b[31mpanic\x1b[0m: boom\r"}})
+
+	s := &fakeScreen{keys: make(chan tui.Key)}
+	done := make(chan error, 1)
+	go func() {
+		done <- dashboard.Run(context.Background(), h.API, project.ID, env.ID, time.Minute, s)
+	}()
+
+	s.waitFor(t, "panic: boom")
+	s.keys <- "q"
+	if err := <-done; err != nil {
+		t.Fatalf("dashboard: %v", err)
+	}
+
+	for _, seq := range []string{"\x1b[2J", "\x1b]0;", "\x1b[31m", "\r"} {
+		if strings.Contains(s.frame, seq) {
+			t.Errorf("frame contains %q from the log line", seq)
+		}
+	}
+}
+
+func TestDashboard_Exec(t *testing.T) {
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "shop")
+	h.API.SeedService(project.ID, "api")
+	h.API.CommandResults["ls -la /app"] = &model.CommandResult{Output: "main.go\n"}
+
+	s := &fakeScreen{keys: make(chan tui.Key)}
+	done := make(chan error, 1)
+	go func() {
+		done <- dashboard.Run(context.Background(), h.API, project.ID, env.ID, time.Minute, s)
+	}()
+
+	s.waitFor(t, "▸ api")
+	s.keys <- "e"
+	for _, k := range tui.ParseKeys([]byte("ls -la '/app'\r")) {
+		s.keys <- k
+	}
+	s.waitFor(t, "main.go")
+	s.keys <- "q"
+
+	if err := <-done; err != nil {
+		t.Fatalf("dashboard: %v", err)
+	}
+}
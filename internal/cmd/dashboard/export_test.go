@@ -0,0 +1,16 @@
+package dashboard
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeabur/cli/pkg/api"
+)
+
+// Screen is the screen the dashboard draws on.
+type Screen = screen
+
+// Run runs the dashboard of the project on s, without a terminal.
+func Run(ctx context.Context, client api.Client, projectID, environmentID string, interval time.Duration, s Screen) error {
+	return newApp(client, projectID, "test", environmentID, interval).run(ctx, s)
+}
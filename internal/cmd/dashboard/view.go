@@ -0,0 +1,197 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/tui"
+)
+
+const helpLine = "↑/↓ select  r restart  d redeploy  s suspend  e exec  esc clear  q quit"
+
+var (
+	green  = color.New(color.FgGreen).SprintFunc()
+	yellow = color.New(color.FgYellow).SprintFunc()
+	red    = color.New(color.FgRed).SprintFunc()
+)
+
+// render lays the state out on a width by height screen.
+func (s *state) render(width, height int) []string {
+	if width < 20 || height < 5 {
+		return []string{tui.Fit("Terminal too small", width)}
+	}
+
+	header := " zeabur dashboard · " + s.project
+	if !s.updatedAt.IsZero() {
+		header += " · updated " + s.updatedAt.Format("15:04:05")
+	}
+	lines := []string{tui.Reverse(tui.Fit(header, width))}
+
+	bodyHeight := height - 2
+	listWidth := min(max(24, width/3), 40)
+	left := s.renderServices(listWidth, bodyHeight)
+	right := s.renderService(width-listWidth-1, bodyHeight)
+	for i := range bodyHeight {
+		lines = append(lines, left[i]+tui.Faint("│")+right[i])
+	}
+
+	return append(lines, s.renderFooter(width))
+}
+
+func (s *state) renderServices(width, height int) []string {
+	lines := make([]string, 0, height)
+	if len(s.services) == 0 {
+		lines = append(lines, tui.Fit(" Loading services …", width))
+	}
+
+	// scroll so that the selected service stays visible
+	first := max(0, s.selected-height+1)
+	for i := first; i < len(s.services) && len(lines) < height; i++ {
+		svc := s.services[i]
+		row := " " + statusDot(svc.Status) + " " + tui.Fit(svc.Name, width-3)
+		if i == s.selected {
+			row = tui.Reverse(" ▸ " + tui.Fit(svc.Name, width-3))
+		}
+		lines = append(lines, row)
+	}
+	return fill(lines, width, height)
+}
+
+func (s *state) renderService(width, height int) []string {
+	svc := s.current()
+	if svc == nil {
+		return fill(nil, width, height)
+	}
+
+	field := func(label, value string) string {
+		return " " + tui.Faint(tui.Fit(label, 10)) + tui.Fit(value, width-11)
+	}
+	lines := []string{
+		" " + tui.Bold(tui.Fit(svc.Name, width-1)),
+		" " + tui.Faint(tui.Fit("Status", 10)) + statusText(svc.Status, width-11),
+		field("Domains", domains(svc.Domains)),
+		field("Git", gitTrigger(svc.GitTrigger)),
+		metricLine("CPU", s.cpu, width, func(v float64) string { return fmt.Sprintf("%.1f%%", v*100) }),
+		metricLine("Memory", s.memory, width, func(v float64) string { return fmt.Sprintf("%.0fMB", v) }),
+		"",
+		" " + tui.Bold("Deployments"),
+	}
+	if len(s.deployments) == 0 {
+		lines = append(lines, " "+tui.Faint("none"))
+	}
+	for _, d := range s.deployments {
+		status := tui.Fit(d.Status, 10)
+		rest := tui.Fit(since(d.CreatedAt)+"  "+firstLine(d.CommitMessage), width-14)
+		lines = append(lines, " "+statusDot(d.Status)+" "+colorStatus(d.Status, status)+" "+rest)
+	}
+
+	title, tail := "Logs", s.logs
+	if s.execOutput != nil {
+		title, tail = "Exec output", s.execOutput
+	}
+	lines = append(lines, "", " "+tui.Bold(title))
+	if room := height - len(lines); room > 0 && len(tail) > room {
+		tail = tail[len(tail)-room:]
+	}
+	for _, l := range tail {
+		lines = append(lines, " "+tui.Fit(l, width-1))
+	}
+	return fill(lines, width, height)
+}
+
+func (s *state) renderFooter(width int) string {
+	switch {
+	case s.mode == modeExec:
+		return tui.Fit(" exec> "+s.input+"█", width)
+	case s.err != nil:
+		return red(tui.Fit(" "+s.err.Error(), width))
+	case s.message != "":
+		return tui.Fit(" "+s.message, width)
+	}
+	return tui.Faint(tui.Fit(" "+helpLine, width))
+}
+
+// fill pads lines to width and adds or cuts lines to height.
+func fill(lines []string, width, height int) []string {
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for i := range lines {
+		lines[i] = tui.Pad(lines[i], width)
+	}
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return lines
+}
+
+func metricLine(label string, values []float64, width int, format func(float64) string) string {
+	if len(values) == 0 {
+		return " " + tui.Faint(tui.Fit(label, 10)) + tui.Fit("-", width-11)
+	}
+	current := format(values[len(values)-1])
+	spark := tui.Sparkline(values, max(0, width-11-len(current)-1))
+	return " " + tui.Faint(tui.Fit(label, 10)) + green(spark) + " " + current
+}
+
+func statusDot(status string) string {
+	return colorStatus(status, "●")
+}
+
+func statusText(status string, width int) string {
+	return colorStatus(status, tui.Fit(status, width))
+}
+
+func colorStatus(status, s string) string {
+	switch status {
+	case "RUNNING":
+		return green(s)
+	case "CRASHED", "FAILED", "BUILD_FAILED", "REMOVED":
+		return red(s)
+	}
+	return yellow(s)
+}
+
+func domains(ds []model.Domain) string {
+	if len(ds) == 0 {
+		return "-"
+	}
+	names := make([]string, 0, len(ds))
+	for _, d := range ds {
+		names = append(names, d.Domain)
+	}
+	return strings.Join(names, ", ")
+}
+
+func gitTrigger(t *model.GitTrigger) string {
+	if t == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s (%s)", t.BranchName, t.Provider)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// since formats the time since t, e.g. "5m ago".
+func since(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+}
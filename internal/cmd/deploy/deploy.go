@@ -3,13 +3,14 @@ package deploy
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/internal/util"
-	"github.com/zeabur/cli/pkg/constant"
 	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
 	"github.com/zeabur/cli/pkg/selector"
 	"github.com/zeabur/cli/pkg/zcontext"
 )
@@ -25,6 +26,10 @@ type Options struct {
 	projectID     string
 	serviceID     string
 	environmentID string
+
+	// wait follows the deployment until it is running or has failed
+	wait    bool
+	timeout time.Duration
 }
 
 func NewCmdDeploy(f *cmdutil.Factory) *cobra.Command {
@@ -44,10 +49,47 @@ func NewCmdDeploy(f *cmdutil.Factory) *cobra.Command {
 	cmd.Flags().StringVar(&opts.name, "name", "", "Service name")
 	cmd.Flags().StringVar(&opts.domainName, "domain", "", "Domain name")
 	cmd.Flags().BoolVar(&opts.create, "create", false, "Create a new service")
+	cmd.Flags().BoolVar(&opts.wait, "wait", false, "Wait for the deployment to finish and stream its logs, the runtime ones for a few seconds after it starts; exit non-zero if it fails")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 15*time.Minute, "Maximum time to wait for the deployment with --wait")
 
 	return cmd
 }
 
+// useLink targets the project / service / environment linked to the working
+// directory when none was given, so `zeabur deploy` inside a linked checkout
+// redeploys its service. With --create, only the linked project is used.
+func useLink(f *cmdutil.Factory, opts *Options) error {
+	if f.HasWorkspaceOverride() || opts.projectID != "" || opts.serviceID != "" {
+		return nil
+	}
+	link, err := f.Link()
+	if err != nil || link == nil {
+		return err
+	}
+
+	if opts.create {
+		opts.projectID = link.Project.ID
+		return nil
+	}
+	if link.Service.ID == "" {
+		return nil
+	}
+	opts.projectID = link.Project.ID
+	opts.serviceID = link.Service.ID
+	if opts.environmentID == "" {
+		opts.environmentID = link.Environment.ID
+	}
+	f.Log.Infof("Deploying to service %s linked in %s", linkedName(link.Service), link.Dir())
+	return nil
+}
+
+func linkedName(t zcontext.LinkTarget) string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.ID
+}
+
 func runDeploy(f *cmdutil.Factory, opts *Options) error {
 	var environment *model.Environment
 	var service *model.Service
@@ -62,6 +104,10 @@ func runDeploy(f *cmdutil.Factory, opts *Options) error {
 		opts.name = fileName
 	}
 
+	if err := useLink(f, opts); err != nil {
+		return err
+	}
+
 	if opts.projectID != "" {
 		// non-interactive mode: --project-id is provided
 		projectID = opts.projectID
@@ -119,8 +165,8 @@ func runDeploy(f *cmdutil.Factory, opts *Options) error {
 			environment = environments[0]
 		}
 	} else {
-		if f.JSON {
-			return fmt.Errorf("--project-id is required in non-interactive (--json) mode")
+		if f.StructuredOutput() {
+			return fmt.Errorf("--project-id is required with --output %s", f.OutputFormat().Format)
 		}
 		service, environment, err = selectInteractively(f, opts)
 		if err != nil {
@@ -132,6 +178,16 @@ func runDeploy(f *cmdutil.Factory, opts *Options) error {
 		projectID = service.Project.ID
 	}
 
+	// Remember the current deployment so --wait can tell the one created
+	// by this upload apart from it.
+	var previousDeploymentID string
+	if opts.wait {
+		previousDeploymentID, err = util.GetLatestDeploymentID(context.Background(), f.ApiClient, service.ID, environment.ID)
+		if err != nil {
+			return err
+		}
+	}
+
 	s := spinner.New(cmdutil.SpinnerCharSet, cmdutil.SpinnerInterval,
 		spinner.WithColor(cmdutil.SpinnerColor),
 		spinner.WithSuffix(" Uploading codes to Zeabur ..."),
@@ -144,21 +200,31 @@ func runDeploy(f *cmdutil.Factory, opts *Options) error {
 	}
 	s.Stop()
 
+	result := map[string]string{
+		"status":         "success",
+		"service_id":     service.ID,
+		"project_id":     projectID,
+		"environment_id": environment.ID,
+		"message":        "Service deployed successfully",
+	}
+
+	if opts.wait {
+		ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+		defer cancel()
+
+		deployment, err := waitForDeployment(ctx, f, projectID, service.ID, environment.ID, previousDeploymentID)
+		if err != nil {
+			return err
+		}
+		result["deployment_id"] = deployment.ID
+		result["deployment_status"] = deployment.Status
+	}
+
 	domainName := opts.domainName
 
 	if domainName == "" {
-		if f.JSON {
-			return f.Printer.JSON(map[string]string{
-				"status":         "success",
-				"service_id":     service.ID,
-				"project_id":     projectID,
-				"environment_id": environment.ID,
-				"message":        "Service deployed successfully",
-			})
-		}
-		fmt.Println("Service deployed successfully, you can access it via:")
-		fmt.Println(constant.ZeaburDashURL + "/projects/" + projectID + "/services/" + service.ID + "?envID=" + environment.ID)
-		return nil
+		f.Log.Infof("Service deployed successfully, you can access it via: %s/projects/%s/services/%s?envID=%s", f.EffectiveDashURL(), projectID, service.ID, environment.ID)
+		return f.Printer.Data(printer.Result(result))
 	}
 
 	s = spinner.New(cmdutil.SpinnerCharSet, cmdutil.SpinnerInterval,
@@ -174,19 +240,10 @@ func runDeploy(f *cmdutil.Factory, opts *Options) error {
 
 	s.Stop()
 
-	if f.JSON {
-		return f.Printer.JSON(map[string]string{
-			"status":         "success",
-			"service_id":     service.ID,
-			"project_id":     projectID,
-			"environment_id": environment.ID,
-			"domain":         *domain,
-			"message":        "Service deployed successfully",
-		})
-	}
-	fmt.Println("Domain created: ", "https://"+*domain)
+	result["domain"] = *domain
+	f.Log.Infof("Domain created: https://%s", *domain)
 
-	return nil
+	return f.Printer.Data(printer.Result(result))
 }
 
 func selectInteractively(f *cmdutil.Factory, opts *Options) (*model.Service, *model.Environment, error) {
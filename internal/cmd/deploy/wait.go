@@ -0,0 +1,153 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+const (
+	waitPollInterval = 3 * time.Second
+
+	// failureLogLines is how many trailing log lines are quoted in the
+	// error when a deployment fails.
+	failureLogLines = 10
+
+	// runtimeLogGrace is how long the runtime logs are still followed once
+	// the deployment is RUNNING, so its first lines are shown.
+	runtimeLogGrace = 10 * time.Second
+)
+
+// waitForDeployment follows the deployment created by an upload until it
+// settles. Build logs are streamed while the image builds, runtime logs
+// once it starts and for runtimeLogGrace after it is RUNNING; with
+// machine-readable output nothing is streamed so stdout stays parseable. It
+// returns an error describing the failure if the deployment does not reach
+// RUNNING.
+func waitForDeployment(ctx context.Context, f *cmdutil.Factory, projectID, serviceID, environmentID, previousID string) (*model.Deployment, error) {
+	f.Log.Info("Waiting for the deployment to start ...")
+
+	deployment, err := util.WaitForNewDeployment(ctx, f.ApiClient, serviceID, environmentID, previousID, waitPollInterval)
+	if err != nil {
+		return nil, timeoutError(err)
+	}
+	f.Log.Infof("Deployment %s created", deployment.ID)
+
+	stopStream := func() {}
+	defer func() { stopStream() }()
+
+	buildStreamed, runtimeStreamed := false, false
+	onStatus := func(d *model.Deployment) {
+		f.Log.Infof("Deployment status: %s", d.Status)
+		if f.MachineReadableOutput() {
+			return
+		}
+
+		switch {
+		case d.IsBuilding() && !buildStreamed:
+			buildStreamed = true
+			stopStream()
+			stopStream = streamLogs(ctx, f, func(ctx context.Context) (<-chan model.Log, <-chan error) {
+				return f.ApiClient.WatchBuildLogs(ctx, projectID, d.ID)
+			})
+		case !d.IsBuilding() && !runtimeStreamed:
+			runtimeStreamed = true
+			stopStream()
+			stopStream = streamLogs(ctx, f, func(ctx context.Context) (<-chan model.Log, <-chan error) {
+				return f.ApiClient.WatchRuntimeLogs(ctx, projectID, serviceID, environmentID, d.ID)
+			})
+		}
+	}
+
+	deployment, err = util.WaitForDeployment(ctx, f.ApiClient, deployment.ID, waitPollInterval, onStatus)
+	if err != nil {
+		return deployment, timeoutError(err)
+	}
+
+	if deployment.IsFailed() {
+		return deployment, failureError(f, deployment, serviceID, environmentID)
+	}
+
+	if runtimeStreamed {
+		f.Log.Infof("Deployment is running, following its logs for %s", runtimeLogGrace)
+		select {
+		case <-ctx.Done():
+		case <-time.After(runtimeLogGrace):
+		}
+	}
+
+	return deployment, nil
+}
+
+// streamLogs prints logs from watch until the returned stop function is
+// called or ctx is done.
+func streamLogs(ctx context.Context, f *cmdutil.Factory, watch func(ctx context.Context) (<-chan model.Log, <-chan error)) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	logs, errs := watch(ctx)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case log, ok := <-logs:
+				if !ok {
+					// the deployment status is still polled; only the logs stop
+					if err := <-errs; err != nil {
+						f.Log.Warnf("Log stream ended: %v", err)
+					}
+					return
+				}
+				if err := f.Printer.Event(log, log.Rows()[0]); err != nil {
+					f.Log.Warnf("Failed to print log: %v", err)
+				}
+			}
+		}
+	}()
+
+	return cancel
+}
+
+func timeoutError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timed out %w", err)
+	}
+	return err
+}
+
+// failureError builds the error returned for a failed deployment, quoting
+// the tail of its logs so CI output shows why it failed: runtime logs if it
+// crashed after starting, build logs otherwise.
+func failureError(f *cmdutil.Factory, deployment *model.Deployment, serviceID, environmentID string) error {
+	msg := fmt.Sprintf("deployment %s %s", deployment.ID, strings.ToLower(deployment.Status))
+
+	kind := "build"
+	var logs model.Logs
+	var err error
+	if deployment.Status == model.DeploymentStatusCrashed {
+		kind = "runtime"
+		logs, err = f.ApiClient.GetRuntimeLogs(context.Background(), serviceID, environmentID, deployment.ID)
+	} else {
+		logs, err = f.ApiClient.GetBuildLogs(context.Background(), deployment.ID)
+	}
+	if err != nil || len(logs) == 0 {
+		return errors.New(msg)
+	}
+	if len(logs) > failureLogLines {
+		logs = logs[len(logs)-failureLogLines:]
+	}
+
+	lines := make([]string, 0, len(logs))
+	for _, log := range logs {
+		lines = append(lines, "  "+log.Message)
+	}
+
+	return fmt.Errorf("%s, last %s logs:\n%s", msg, kind, strings.Join(lines, "\n"))
+}
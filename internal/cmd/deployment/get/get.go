@@ -72,11 +72,7 @@ func runGetNonInteractive(f *cmdutil.Factory, opts *Options) (err error) {
 		if err != nil {
 			return err
 		}
-		if f.JSON {
-			return f.Printer.JSON(deployment)
-		}
-		f.Printer.Table(deployment.Header(), deployment.Rows())
-		return nil
+		return f.Printer.Data(deployment)
 	}
 
 	// Resolve service ID from name
@@ -106,13 +102,7 @@ func runGetNonInteractive(f *cmdutil.Factory, opts *Options) (err error) {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(deployment)
-	}
-
-	f.Printer.Table(deployment.Header(), deployment.Rows())
-
-	return nil
+	return f.Printer.Data(deployment)
 }
 
 func getDeploymentByID(f *cmdutil.Factory, deploymentID string) (*model.Deployment, error) {
@@ -4,11 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/fill"
+	"github.com/zeabur/cli/pkg/logfile"
+	"github.com/zeabur/cli/pkg/logfilter"
 	"github.com/zeabur/cli/pkg/model"
 )
 
@@ -22,6 +29,15 @@ type Options struct {
 
 	logType string
 	watch   bool
+
+	filterFlags util.LogFilterFlags
+	tail        int
+	out         string
+
+	filter logfilter.Filter
+	// followFrom is the time of the last line --tail showed before --watch
+	// follows the stream; lines from the stream up to it are skipped.
+	followFrom time.Time
 }
 
 const (
@@ -35,6 +51,30 @@ func NewCmdLog(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "log",
 		Short: "Get deployment logs, if deployment-id is not specified, use serviceID/serviceName and environmentID to get the deployment",
+		Long: heredoc.Doc(`
+			Get the runtime or build logs of a deployment, or follow them with --watch.
+
+			Lines can be selected by time with --since and --until (a duration ago
+			such as 30m, an RFC 3339 timestamp or a date), by content with --grep and
+			--invert, and by level with --level, which keeps lines at that level or
+			above. The level is read from the level field of JSON lines, from a
+			logfmt level=... pair, or from a word such as ERROR or [warn] at the
+			start of the line; lines without one are dropped by --level.
+
+			With --out, the lines are written to a file instead of stdout: NDJSON
+			when its name ends in .ndjson or .jsonl, text otherwise, and
+			gzip-compressed when it also ends in .gz.
+		`),
+		Example: heredoc.Doc(`
+			# errors of the last hour
+			zeabur deployment log --service-name web --since 1h --level error
+
+			# follow the logs, leaving out health checks
+			zeabur deployment log --service-name web --watch --grep 'GET /healthz' --invert
+
+			# the last 1000 lines as compressed NDJSON
+			zeabur deployment log --service-name web --tail 1000 --out web.ndjson.gz
+		`),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runLog(f, opts)
 		},
@@ -47,11 +87,24 @@ func NewCmdLog(f *cmdutil.Factory) *cobra.Command {
 	cmd.Flags().StringVar(&opts.environmentID, "env-id", "", "Environment ID")
 	cmd.Flags().StringVarP(&opts.logType, "type", "t", logTypeRuntime, "Log type, runtime or build")
 	cmd.Flags().BoolVarP(&opts.watch, "watch", "w", false, "Watch logs")
+	util.AddLogFilterFlags(cmd, &opts.filterFlags, true)
+	cmd.Flags().IntVar(&opts.tail, "tail", 0, "Only show the last N matching logs; with --watch, show them before following")
+	cmd.Flags().StringVar(&opts.out, "out", "", "Write the logs to a file (.txt, .ndjson, optionally .gz) instead of stdout")
 
 	return cmd
 }
 
 func runLog(f *cmdutil.Factory, opts *Options) error {
+	if err := parseFilter(opts, time.Now()); err != nil {
+		return err
+	}
+
+	if opts.deploymentID == "" {
+		if err := f.LinkedService(&opts.serviceID, &opts.serviceName, &opts.environmentID); err != nil {
+			return err
+		}
+	}
+
 	if f.Interactive {
 		return runLogInteractive(f, opts)
 	} else {
@@ -150,28 +203,85 @@ func queryLogs(f *cmdutil.Factory, opts *Options) error {
 		return fmt.Errorf("unknown log type: %s", opts.logType)
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(logs)
+	logs = logfilter.Tail(opts.filter.Apply(logs), opts.tail)
+
+	if opts.out != "" {
+		return writeFile(f, opts.out, func(w *logfile.Writer) error {
+			for _, l := range logs {
+				if err := w.Write(l); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
 	}
 
-	f.Printer.Table(logs.Header(), logs.Rows())
-	return nil
+	return f.Printer.Data(logs)
 }
 
+// writeFile creates the --out file, lets write fill it and closes it.
+func writeFile(f *cmdutil.Factory, path string, write func(w *logfile.Writer) error) error {
+	w, err := logfile.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	err = write(w)
+	if closeErr := w.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("write %s: %w", path, closeErr)
+	}
+	if err == nil {
+		f.Log.Infof("Logs written to %s as %s", path, w.Format)
+	}
+	return err
+}
+
+// watchLogs streams logs until the subscription ends or the user presses
+// Ctrl-C. Every entry is printed as one line as soon as it arrives (see
+// printer.Printer.Event), so the output can be piped into jq or grep.
 func watchLogs(f *cmdutil.Factory, opts *Options) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if !opts.filter.Until.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, opts.filter.Until)
+		defer cancel()
+	}
+
+	if opts.out != "" {
+		return writeFile(f, opts.out, func(w *logfile.Writer) error {
+			return followLogs(ctx, f, opts, w.Write)
+		})
+	}
+	return followLogs(ctx, f, opts, func(l *model.Log) error {
+		return f.Printer.Event(l, l.Rows()[0])
+	})
+}
+
+// followLogs passes the logs matching the filter to emit as they arrive,
+// after the last --tail ones from before.
+func followLogs(ctx context.Context, f *cmdutil.Factory, opts *Options, emit func(l *model.Log) error) error {
 	var logChan <-chan model.Log
-	var err error
+	var errChan <-chan error
 
 	switch opts.logType {
 	case logTypeRuntime:
 		if opts.serviceID == "" || opts.environmentID == "" {
 			return errors.New("service-id and env-id are required for watching runtime logs")
 		}
-		logChan, err = f.ApiClient.WatchRuntimeLogs(context.Background(), opts.projectID, opts.serviceID, opts.environmentID, opts.deploymentID)
+		if opts.tail > 0 {
+			history, err := f.ApiClient.GetRuntimeLogs(ctx, opts.serviceID, opts.environmentID, opts.deploymentID)
+			if err != nil {
+				return fmt.Errorf("failed to get runtime logs: %w", err)
+			}
+			if err := emitHistory(opts, history, emit); err != nil {
+				return err
+			}
+		}
+		logChan, errChan = f.ApiClient.WatchRuntimeLogs(ctx, opts.projectID, opts.serviceID, opts.environmentID, opts.deploymentID)
 	case logTypeBuild:
 		deploymentID := opts.deploymentID
 		if deploymentID == "" {
-			deployment, exist, e := f.ApiClient.GetLatestDeployment(context.Background(), opts.serviceID, opts.environmentID)
+			deployment, exist, e := f.ApiClient.GetLatestDeployment(ctx, opts.serviceID, opts.environmentID)
 			if e != nil {
 				return fmt.Errorf("failed to get latest deployment: %w", e)
 			}
@@ -181,25 +291,70 @@ func watchLogs(f *cmdutil.Factory, opts *Options) error {
 			deploymentID = deployment.ID
 			f.Log.Infof("Deployment ID: %s", deploymentID)
 		}
-		logChan, err = f.ApiClient.WatchBuildLogs(context.Background(), opts.projectID, deploymentID)
+		if opts.tail > 0 {
+			history, err := f.ApiClient.GetBuildLogs(ctx, deploymentID)
+			if err != nil {
+				return fmt.Errorf("failed to get build logs: %w", err)
+			}
+			if err := emitHistory(opts, history, emit); err != nil {
+				return err
+			}
+		}
+		logChan, errChan = f.ApiClient.WatchBuildLogs(ctx, opts.projectID, deploymentID)
 	default:
 		return fmt.Errorf("unknown log type: %s", opts.logType)
 	}
 
-	if err != nil {
-		return fmt.Errorf("failed to watch logs: %w", err)
-	}
-
-	for log := range logChan {
-		if f.JSON {
-			if err := f.Printer.JSON(log); err != nil {
+	for {
+		select {
+		case <-ctx.Done():
+			// interrupted or past --until: end the stream cleanly rather
+			// than as a failure
+			return nil
+		case log, ok := <-logChan:
+			if !ok {
+				// the error channel is closed right after the log channel
+				if err := <-errChan; err != nil && ctx.Err() == nil {
+					return fmt.Errorf("log stream ended: %w", err)
+				}
+				return nil
+			}
+			if !opts.filter.Match(&log) || !log.Timestamp.After(opts.followFrom) {
+				continue
+			}
+			if err := emit(&log); err != nil {
 				return err
 			}
-			continue
 		}
-		f.Printer.Table(log.Header(), log.Rows())
 	}
+}
 
+// emitHistory emits the last --tail matching lines of history, and makes
+// the stream skip what they already cover.
+func emitHistory(opts *Options, history model.Logs, emit func(l *model.Log) error) error {
+	history = logfilter.Tail(opts.filter.Apply(history), opts.tail)
+	for _, l := range history {
+		if err := emit(l); err != nil {
+			return err
+		}
+	}
+	if len(history) > 0 {
+		opts.followFrom = history[len(history)-1].Timestamp
+	}
+	return nil
+}
+
+// parseFilter checks the filter flags and builds opts.filter from them.
+func parseFilter(opts *Options, now time.Time) error {
+	filter, err := opts.filterFlags.Filter(now)
+	if err != nil {
+		return err
+	}
+	opts.filter = filter
+
+	if opts.tail < 0 {
+		return errors.New("--tail must not be negative")
+	}
 	return nil
 }
 
@@ -0,0 +1,188 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zeabur/cli/internal/cmd/deployment/log"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
+)
+
+func watch(t *testing.T, output string) string {
+	t.Helper()
+
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	h.API.SeedRuntimeLogs(svc.ID, env.ID, model.Logs{
+		{Timestamp: ts, Message: "listening on :8080"},
+		{Timestamp: ts.Add(time.Second), Message: `GET / "200"`},
+	})
+
+	var buf bytes.Buffer
+	p := printer.NewWriter(&buf)
+	o, err := printer.ParseOutput(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetOutput(o)
+	h.Factory.Printer = p
+
+	if err := h.Run(log.NewCmdLog(h.Factory), "--service-id", svc.ID, "--env-id", env.ID, "--watch"); err != nil {
+		t.Fatalf("deployment log --watch: %v", err)
+	}
+	return buf.String()
+}
+
+// TestWatchLogs_NDJSON pins one compact object per line, so the stream can
+// be piped into jq.
+func TestWatchLogs_NDJSON(t *testing.T) {
+	want := `{"timestamp":"2024-01-01T00:00:00Z","message":"listening on :8080"}
+{"timestamp":"2024-01-01T00:00:01Z","message":"GET / \"200\""}
+`
+	for _, output := range []string{"ndjson", "json"} {
+		if got := watch(t, output); got != want {
+			t.Errorf("-o %s:\n%s\nwant\n%s", output, got, want)
+		}
+	}
+}
+
+// TestWatchLogs_Plain pins `timestamp message` lines without a table header.
+func TestWatchLogs_Plain(t *testing.T) {
+	want := `2024-01-01T00:00:00Z listening on :8080
+2024-01-01T00:00:01Z GET / "200"
+`
+	for _, output := range []string{"plain", "table"} {
+		if got := watch(t, output); got != want {
+			t.Errorf("-o %s:\n%s\nwant\n%s", output, got, want)
+		}
+	}
+}
+
+// TestWatchLogs_StreamError surfaces the error that ended the subscription
+// instead of exiting as if the stream had completed.
+func TestWatchLogs_StreamError(t *testing.T) {
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+	h.API.FailOn("WatchRuntimeLogs", errors.New("unauthorized"))
+
+	err := h.Run(log.NewCmdLog(h.Factory), "--service-id", svc.ID, "--env-id", env.ID, "--watch")
+	if err == nil || !strings.Contains(err.Error(), "unauthorized") {
+		t.Fatalf("deployment log --watch = %v, want the stream error", err)
+	}
+}
+
+// seedFilterLogs seeds runtime logs of mixed levels and returns the harness
+// and the flags selecting the service.
+func seedFilterLogs(t *testing.T) (*cmdtest.Harness, []string) {
+	t.Helper()
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+	ts := time.Now().Add(-time.Hour).Truncate(time.Second)
+	h.API.SeedRuntimeLogs(svc.ID, env.ID, model.Logs{
+		{Timestamp: ts, Message: `{"level":"info","msg":"listening"}`},
+		{Timestamp: ts.Add(time.Minute), Message: "GET /healthz 200"},
+		{Timestamp: ts.Add(50 * time.Minute), Message: `{"level":"error","msg":"db down"}`},
+		{Timestamp: ts.Add(51 * time.Minute), Message: "level=warn msg=retrying"},
+	})
+	return h, []string{"--service-id", svc.ID, "--env-id", env.ID}
+}
+
+func messages(rows [][]string) []string {
+	out := make([]string, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, row[1])
+	}
+	return out
+}
+
+func TestQueryLogs_Filters(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		flags []string
+		want  []string
+	}{
+		{"level", []string{"--level", "warn"}, []string{`{"level":"error","msg":"db down"}`, "level=warn msg=retrying"}},
+		{"grep", []string{"--grep", "health|retry"}, []string{"GET /healthz 200", "level=warn msg=retrying"}},
+		{"invert", []string{"--grep", "^\\{", "--invert"}, []string{"GET /healthz 200", "level=warn msg=retrying"}},
+		{"since", []string{"--since", "15m"}, []string{`{"level":"error","msg":"db down"}`, "level=warn msg=retrying"}},
+		{"until and tail", []string{"--until", "30m", "--tail", "1"}, []string{"GET /healthz 200"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h, flags := seedFilterLogs(t)
+			if err := h.Run(log.NewCmdLog(h.Factory), append(flags, tc.flags...)...); err != nil {
+				t.Fatalf("deployment log: %v", err)
+			}
+			if got := messages(h.Printer.LastTable().Rows); strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				t.Errorf("printed %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestQueryLogs_InvalidFilters(t *testing.T) {
+	for _, flags := range [][]string{
+		{"--level", "loud"},
+		{"--grep", "("},
+		{"--invert"},
+		{"--tail", "-1"},
+		{"--since", "yesterday"},
+	} {
+		h, service := seedFilterLogs(t)
+		if err := h.Run(log.NewCmdLog(h.Factory), append(service, flags...)...); err == nil {
+			t.Errorf("deployment log %v succeeded, want an error", flags)
+		}
+		if calls := h.API.CallsTo("GetRuntimeLogs"); len(calls) != 0 {
+			t.Errorf("deployment log %v fetched logs before validating the flags", flags)
+		}
+	}
+}
+
+// TestWatchLogs_Filters applies the filter to the stream, and shows the
+// --tail lines from before it without repeating them.
+func TestWatchLogs_Filters(t *testing.T) {
+	h, flags := seedFilterLogs(t)
+	var buf bytes.Buffer
+	p := printer.NewWriter(&buf)
+	p.SetOutput(printer.Output{Format: printer.FormatPlain})
+	h.Factory.Printer = p
+
+	if err := h.Run(log.NewCmdLog(h.Factory), append(flags, "--watch", "--level", "info", "--tail", "1")...); err != nil {
+		t.Fatalf("deployment log --watch: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 || !strings.HasSuffix(lines[0], "level=warn msg=retrying") {
+		t.Errorf("printed %q, want only the last matching line once", lines)
+	}
+}
+
+func TestQueryLogs_Out(t *testing.T) {
+	h, flags := seedFilterLogs(t)
+	path := filepath.Join(t.TempDir(), "web.ndjson")
+
+	if err := h.Run(log.NewCmdLog(h.Factory), append(flags, "--level", "error", "--out", path)...); err != nil {
+		t.Fatalf("deployment log --out: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), "\n"); got != 1 || !strings.Contains(string(data), `"message":"{\"level\":\"error\",\"msg\":\"db down\"}"`) {
+		t.Errorf("wrote:\n%s", data)
+	}
+	if len(h.Printer.Tables) != 0 {
+		t.Error("printed the logs as well as writing them")
+	}
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -26,6 +28,7 @@ func NewCmdAutoRenew(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.id, "id", "", "Registered domain ID")
+	util.SetFlagCompletion(cmd, "id", util.CompleteRegisteredDomain)
 	cmd.Flags().BoolVar(&opts.enable, "enable", false, "Enable auto-renew")
 	cmd.Flags().BoolVar(&opts.disable, "disable", false, "Disable auto-renew")
 
@@ -88,14 +91,10 @@ func runAutoRenew(f *cmdutil.Factory, opts *Options) error {
 		return fmt.Errorf("set auto-renew failed: %w", err)
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(domain)
-	}
-
 	status := "disabled"
 	if domain.AutoRenew {
 		status = "enabled"
 	}
 	f.Log.Infof("Auto-renew %s for %s", status, domain.Domain)
-	return nil
+	return f.Printer.Data(printer.Result(domain))
 }
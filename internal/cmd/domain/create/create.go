@@ -10,6 +10,7 @@ import (
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/fill"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -195,10 +196,6 @@ func runCreateDomainNonInteractive(f *cmdutil.Factory, opts *Options) error {
 	}
 	s.Stop()
 
-	if f.JSON {
-		return f.Printer.JSON(map[string]string{"status": "success", "id": *domain, "domain": *domain, "message": "Domain added"})
-	}
 	f.Log.Infof("Domain %s added", *domain)
-
-	return nil
+	return f.Printer.Data(printer.Result(map[string]string{"status": "success", "id": *domain, "domain": *domain, "message": "Domain added"}))
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -137,10 +138,6 @@ func runDeleteDomainNonInteractive(f *cmdutil.Factory, opts *Options) error {
 		return nil
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(map[string]string{"status": "success", "domain": opts.domainName, "message": "Domain deleted successfully"})
-	}
 	f.Log.Infof("Delete domain %s success", opts.domainName)
-
-	return nil
+	return f.Printer.Data(printer.Result(map[string]string{"status": "success", "domain": opts.domainName, "message": "Domain deleted successfully"}))
 }
@@ -7,7 +7,9 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmd/domain/dns/dnsutil"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -33,6 +35,7 @@ func NewCmdCreate(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.domain, "domain", "", "Domain name (e.g. example.com)")
+	util.SetFlagCompletion(cmd, "domain", util.CompleteRegisteredDomainName)
 	cmd.Flags().StringVar(&opts.domainID, "domain-id", "", "Registered domain ID (advanced)")
 	cmd.Flags().StringVar(&opts.recordType, "type", "", "Record type (A, AAAA, CNAME, MX, TXT, SRV, CAA, NS)")
 	cmd.Flags().StringVar(&opts.name, "name", "", "Record name (e.g. @ or subdomain)")
@@ -106,12 +109,8 @@ func runCreate(f *cmdutil.Factory, opts *Options) error {
 		return fmt.Errorf("create DNS record failed: %w", err)
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(record)
-	}
-
 	f.Log.Infof("DNS record created: %s %s %s", record.Type, record.Name, record.Content)
-	return nil
+	return f.Printer.Data(printer.Result(record))
 }
 
 func resolveDomainID(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
@@ -7,6 +7,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmd/domain/dns/dnsutil"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -31,6 +33,7 @@ func NewCmdDelete(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.domain, "domain", "", "Domain name (e.g. example.com)")
+	util.SetFlagCompletion(cmd, "domain", util.CompleteRegisteredDomainName)
 	cmd.Flags().StringVar(&opts.domainID, "domain-id", "", "Registered domain ID (advanced)")
 	cmd.Flags().StringVar(&opts.recordType, "type", "", "Record type to match (A, AAAA, CNAME, ...)")
 	cmd.Flags().StringVar(&opts.name, "name", "", "Record name to match (e.g. @ or subdomain)")
@@ -66,12 +69,8 @@ func runDelete(f *cmdutil.Factory, opts *Options) error {
 		return fmt.Errorf("delete DNS record failed: %w", err)
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(map[string]string{"status": "deleted"})
-	}
-
 	f.Log.Infof("DNS record deleted")
-	return nil
+	return f.Printer.Data(printer.Result(map[string]string{"status": "deleted"}))
 }
 
 func resolveDomainID(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
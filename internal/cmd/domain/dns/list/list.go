@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmd/domain/dns/dnsutil"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
 )
 
 type Options struct {
@@ -27,6 +28,7 @@ func NewCmdList(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.domain, "domain", "", "Domain name (e.g. example.com)")
+	util.SetFlagCompletion(cmd, "domain", util.CompleteRegisteredDomainName)
 	cmd.Flags().StringVar(&opts.domainID, "domain-id", "", "Registered domain ID (advanced)")
 
 	return cmd
@@ -45,19 +47,10 @@ func runList(f *cmdutil.Factory, opts *Options) error {
 	}
 
 	if len(records) == 0 {
-		if f.JSON {
-			return f.Printer.JSON([]any{})
-		}
 		f.Log.Infof("No DNS records found")
-		return nil
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(records)
-	}
-
-	f.Printer.Table(records.Header(), records.Rows())
-	return nil
+	return f.Printer.Data(records)
 }
 
 func resolveDomainID(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
 )
 
 type Options struct {
@@ -24,6 +25,7 @@ func NewCmdGetRegistered(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.id, "id", "", "Registered domain ID")
+	util.SetFlagCompletion(cmd, "id", util.CompleteRegisteredDomain)
 
 	return cmd
 }
@@ -57,21 +59,10 @@ func runGet(f *cmdutil.Factory, opts *Options) error {
 		return fmt.Errorf("get registered domain failed: %w", err)
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(domain)
-	}
-
-	f.Printer.Table(domain.Header(), domain.Rows())
-
 	if domain.RegistrantProfile != nil {
 		p := domain.RegistrantProfile
-		f.Log.Infof("")
-		f.Log.Infof("Registrant Profile:")
-		f.Printer.Table(
-			[]string{"Name", "Email", "Phone", "Country"},
-			[][]string{{p.FirstName + " " + p.LastName, p.Email, p.Phone, p.Country}},
-		)
+		f.Log.Infof("Registrant Profile: %s %s <%s>, %s, %s", p.FirstName, p.LastName, p.Email, p.Phone, p.Country)
 	}
 
-	return nil
+	return f.Printer.Data(domain)
 }
@@ -28,17 +28,8 @@ func runList(f *cmdutil.Factory) error {
 	}
 
 	if len(domains) == 0 {
-		if f.JSON {
-			return f.Printer.JSON([]any{})
-		}
 		f.Log.Infof("No registered domains found")
-		return nil
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(domains)
-	}
-
-	f.Printer.Table(domains.Header(), domains.Rows())
-	return nil
+	return f.Printer.Data(domains)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -144,16 +145,12 @@ func runPurchaseNonInteractive(f *cmdutil.Factory, opts *Options) error {
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "bind a credit card") || strings.Contains(errMsg, "insufficient balance") {
 			f.Log.Errorf("Purchase failed: %s", errMsg)
-			f.Log.Infof("Please bind a credit card or top up your balance at: https://zeabur.com/account/billing")
+			f.Log.Infof("Please bind a credit card or top up your balance at: %s/account/billing", f.EffectiveDashURL())
 			return fmt.Errorf("payment required")
 		}
 		return fmt.Errorf("purchase domain failed: %w", err)
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(result)
-	}
-
 	f.Log.Infof("Domain %s purchased successfully!", result.RegisteredDomain.Domain)
 	if result.PaymentAmountFromBalance != nil && *result.PaymentAmountFromBalance > 0 {
 		f.Log.Infof("  Paid from balance: $%.2f", float64(*result.PaymentAmountFromBalance)/100)
@@ -161,6 +158,5 @@ func runPurchaseNonInteractive(f *cmdutil.Factory, opts *Options) error {
 	if result.PaymentAmountFromPaymentMethod != nil && *result.PaymentAmountFromPaymentMethod > 0 {
 		f.Log.Infof("  Paid from card: $%.2f", float64(*result.PaymentAmountFromPaymentMethod)/100)
 	}
-
-	return nil
+	return f.Printer.Data(printer.Result(result))
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -70,10 +71,6 @@ func runDelete(f *cmdutil.Factory, opts *Options) error {
 		return fmt.Errorf("delete registrant profile failed: %w", err)
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(map[string]string{"status": "deleted"})
-	}
-
 	f.Log.Infof("Registrant profile deleted")
-	return nil
+	return f.Printer.Data(printer.Result(map[string]string{"status": "deleted"}))
 }
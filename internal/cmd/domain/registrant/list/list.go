@@ -28,17 +28,8 @@ func runList(f *cmdutil.Factory) error {
 	}
 
 	if len(profiles) == 0 {
-		if f.JSON {
-			return f.Printer.JSON([]any{})
-		}
 		f.Log.Infof("No registrant profiles found")
-		return nil
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(profiles)
-	}
-
-	f.Printer.Table(profiles.Header(), profiles.Rows())
-	return nil
+	return f.Printer.Data(profiles)
 }
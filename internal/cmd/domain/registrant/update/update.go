@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -116,10 +117,6 @@ func runUpdate(f *cmdutil.Factory, opts *Options) error {
 		return fmt.Errorf("update registrant profile failed: %w", err)
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(profile)
-	}
-
 	f.Log.Infof("Registrant profile updated: %s %s <%s>", profile.FirstName, profile.LastName, profile.Email)
-	return nil
+	return f.Printer.Data(printer.Result(profile))
 }
@@ -7,6 +7,8 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -26,6 +28,7 @@ func NewCmdRenew(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.id, "id", "", "Registered domain ID")
+	util.SetFlagCompletion(cmd, "id", util.CompleteRegisteredDomain)
 	cmd.Flags().BoolVarP(&opts.skipConfirm, "yes", "y", false, "Skip confirmation")
 
 	return cmd
@@ -78,10 +81,6 @@ func runRenew(f *cmdutil.Factory, opts *Options) error {
 		return fmt.Errorf("renew domain failed: %w", err)
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(domain)
-	}
-
 	f.Log.Infof("Domain %s renewed successfully! New expiry: %s", domain.Domain, domain.ExpiresAt.Format("2006-01-02"))
-	return nil
+	return f.Printer.Data(printer.Result(domain))
 }
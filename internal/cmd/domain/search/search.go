@@ -59,10 +59,5 @@ func runSearch(f *cmdutil.Factory, opts *Options) error {
 		return fmt.Errorf("check domain availability failed: %w", err)
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(result)
-	}
-
-	f.Printer.Table(result.Header(), result.Rows())
-	return nil
+	return f.Printer.Data(result)
 }
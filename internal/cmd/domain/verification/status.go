@@ -7,6 +7,8 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type statusOptions struct {
@@ -25,6 +27,7 @@ func newCmdStatus(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.id, "id", "", "Registered domain ID")
+	util.SetFlagCompletion(cmd, "id", util.CompleteRegisteredDomain)
 
 	return cmd
 }
@@ -71,17 +74,12 @@ func runStatus(f *cmdutil.Factory, opts *statusOptions) error {
 		status = *domain.RegistrantVerificationStatus
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(map[string]string{
+	return f.Printer.Data(printer.WithTable(
+		map[string]string{
 			"domain": domain.Domain,
 			"status": status,
-		})
-	}
-
-	f.Printer.Table(
+		},
 		[]string{"Domain", "Verification Status"},
 		[][]string{{domain.Domain, status}},
-	)
-
-	return nil
+	))
 }
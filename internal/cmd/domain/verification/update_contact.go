@@ -7,6 +7,7 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/model"
 )
 
@@ -37,6 +38,7 @@ func newCmdUpdateContact(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.id, "id", "", "Registered domain ID")
+	util.SetFlagCompletion(cmd, "id", util.CompleteRegisteredDomain)
 	cmd.Flags().StringVar(&opts.firstName, "first-name", "", "First name")
 	cmd.Flags().StringVar(&opts.lastName, "last-name", "", "Last name")
 	cmd.Flags().StringVar(&opts.email, "email", "", "Email address")
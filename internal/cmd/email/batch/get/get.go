@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -85,11 +86,7 @@ func getBatchJob(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(job)
-	}
-
-	f.Printer.Table(
+	return f.Printer.Data(printer.WithTable(job,
 		[]string{"Field", "Value"},
 		[][]string{
 			{"Job ID", job.JobID},
@@ -102,8 +99,7 @@ func getBatchJob(f *cmdutil.Factory, opts Options) error {
 			{"Completed At", job.CompletedAt},
 			{"Last Error", job.LastError},
 		},
-	)
-	return nil
+	))
 }
 
 func paramCheck(opts Options) error {
@@ -79,13 +79,5 @@ func runList(f *cmdutil.Factory, opts Options) error {
 		jobs = append(jobs, &reply.Jobs[i])
 	}
 
-	if f.JSON {
-		if len(jobs) == 0 {
-			return f.Printer.JSON([]any{})
-		}
-		return f.Printer.JSON(jobs)
-	}
-
-	f.Printer.Table(jobs.Header(), jobs.Rows())
-	return nil
+	return f.Printer.Data(jobs)
 }
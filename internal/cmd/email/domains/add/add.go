@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 var regionChoices = []string{
@@ -92,13 +93,8 @@ func addDomain(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(domain)
-	}
-
 	f.Log.Infof("Domain %q added successfully (ID: %s, Status: %s)", domain.Value, domain.ID, domain.Status)
-
-	return nil
+	return f.Printer.Data(printer.Result(domain))
 }
 
 func paramCheck(opts Options) error {
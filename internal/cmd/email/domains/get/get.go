@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -71,16 +72,20 @@ func getDomain(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(domain)
-	}
-
 	statusMsg := ""
 	if domain.StatusMsg != nil {
 		statusMsg = *domain.StatusMsg
 	}
 
-	f.Printer.Table(
+	if len(domain.Records) > 0 {
+		f.Log.Infof("DNS Records:")
+		for _, r := range domain.Records {
+			f.Log.Infof("  [%s] %s %s %s (TTL: %s, Priority: %s, Status: %s)",
+				r.Category, r.Type, r.Name, r.Content, r.TTL, r.Priority, r.Status)
+		}
+	}
+
+	return f.Printer.Data(printer.WithTable(domain,
 		[]string{"Field", "Value"},
 		[][]string{
 			{"ID", domain.ID},
@@ -89,26 +94,7 @@ func getDomain(f *cmdutil.Factory, opts Options) error {
 			{"Status", domain.Status},
 			{"Status Message", statusMsg},
 		},
-	)
-
-	if len(domain.Records) > 0 {
-		f.Log.Infof("\nDNS Records:")
-		rows := make([][]string, 0, len(domain.Records))
-		for _, r := range domain.Records {
-			rows = append(rows, []string{
-				r.Category,
-				r.Type,
-				r.Name,
-				r.Content,
-				r.TTL,
-				r.Priority,
-				r.Status,
-			})
-		}
-		f.Printer.Table([]string{"Category", "Type", "Name", "Content", "TTL", "Priority", "Status"}, rows)
-	}
-
-	return nil
+	))
 }
 
 func paramCheck(opts Options) error {
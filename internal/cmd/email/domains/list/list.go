@@ -44,14 +44,5 @@ func runList(f *cmdutil.Factory, opts Options) error {
 		domains = append(domains, &reply.Domains[i])
 	}
 
-	if f.JSON {
-		if len(domains) == 0 {
-			return f.Printer.JSON([]any{})
-		}
-		return f.Printer.JSON(domains)
-	}
-
-	f.Printer.Table(domains.Header(), domains.Rows())
-
-	return nil
+	return f.Printer.Data(domains)
 }
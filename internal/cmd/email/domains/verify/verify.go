@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -71,13 +72,8 @@ func verifyDomain(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(domain)
-	}
-
 	f.Log.Infof("Domain %q verification triggered (Status: %s)", domain.Value, domain.Status)
-
-	return nil
+	return f.Printer.Data(printer.Result(domain))
 }
 
 func paramCheck(opts Options) error {
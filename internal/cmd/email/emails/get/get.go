@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -72,16 +73,12 @@ func getEmail(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(email)
-	}
-
 	scheduledAt := ""
 	if email.ScheduledAt != nil {
 		scheduledAt = email.ScheduledAt.String()
 	}
 
-	f.Printer.Table(
+	return f.Printer.Data(printer.WithTable(email,
 		[]string{"Field", "Value"},
 		[][]string{
 			{"ID", email.ID},
@@ -94,8 +91,7 @@ func getEmail(f *cmdutil.Factory, opts Options) error {
 			{"Scheduled At", scheduledAt},
 			{"Created At", email.CreatedAt.String()},
 		},
-	)
-	return nil
+	))
 }
 
 func paramCheck(opts Options) error {
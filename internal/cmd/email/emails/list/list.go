@@ -75,13 +75,5 @@ func runList(f *cmdutil.Factory, opts Options) error {
 		emails = append(emails, &reply.Emails[i])
 	}
 
-	if f.JSON {
-		if len(emails) == 0 {
-			return f.Printer.JSON([]any{})
-		}
-		return f.Printer.JSON(emails)
-	}
-
-	f.Printer.Table(emails.Header(), emails.Rows())
-	return nil
+	return f.Printer.Data(emails)
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 var permissionChoices = []string{
@@ -92,17 +93,13 @@ func createKey(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(reply)
-	}
-
 	f.Log.Infof("API key %q created successfully (ID: %s)", reply.APIKey.Name, reply.APIKey.ID)
-	if reply.APIKey.Token != nil {
-		fmt.Printf("Token: %s\n", *reply.APIKey.Token)
-		fmt.Println("WARNING: This token will only be shown once. Please save it now.")
+	if reply.APIKey.Token == nil {
+		return f.Printer.Data(printer.Result(reply))
 	}
 
-	return nil
+	f.Log.Warn("This token will only be shown once. Please save it now.")
+	return f.Printer.Data(printer.WithTable(reply, []string{"Token"}, [][]string{{*reply.APIKey.Token}}))
 }
 
 func paramCheck(opts Options) error {
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -72,13 +73,9 @@ func getKey(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(key)
-	}
-
 	domains := strings.Join(key.Domains, ", ")
 
-	f.Printer.Table(
+	return f.Printer.Data(printer.WithTable(key,
 		[]string{"Field", "Value"},
 		[][]string{
 			{"ID", key.ID},
@@ -87,8 +84,7 @@ func getKey(f *cmdutil.Factory, opts Options) error {
 			{"Domains", domains},
 			{"Created At", key.CreatedAt.String()},
 		},
-	)
-	return nil
+	))
 }
 
 func paramCheck(opts Options) error {
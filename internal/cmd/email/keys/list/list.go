@@ -44,14 +44,5 @@ func runList(f *cmdutil.Factory, opts Options) error {
 		keys = append(keys, &reply.APIKeys[i])
 	}
 
-	if f.JSON {
-		if len(keys) == 0 {
-			return f.Printer.JSON([]any{})
-		}
-		return f.Printer.JSON(keys)
-	}
-
-	f.Printer.Table(keys.Header(), keys.Rows())
-
-	return nil
+	return f.Printer.Data(keys)
 }
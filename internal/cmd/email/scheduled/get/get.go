@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -85,11 +86,7 @@ func getScheduledEmail(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(email)
-	}
-
-	f.Printer.Table(
+	return f.Printer.Data(printer.WithTable(email,
 		[]string{"Field", "Value"},
 		[][]string{
 			{"ID", email.ID},
@@ -103,8 +100,7 @@ func getScheduledEmail(f *cmdutil.Factory, opts Options) error {
 			{"Last Error", email.LastError},
 			{"Created At", email.CreatedAt},
 		},
-	)
-	return nil
+	))
 }
 
 func paramCheck(opts Options) error {
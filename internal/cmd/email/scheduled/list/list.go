@@ -79,13 +79,5 @@ func runList(f *cmdutil.Factory, opts Options) error {
 		items = append(items, &reply.ScheduledEmails[i])
 	}
 
-	if f.JSON {
-		if len(items) == 0 {
-			return f.Printer.JSON([]any{})
-		}
-		return f.Printer.JSON(items)
-	}
-
-	f.Printer.Table(items.Header(), items.Rows())
-	return nil
+	return f.Printer.Data(items)
 }
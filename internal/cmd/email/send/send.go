@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/briandowns/spinner"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -131,11 +133,8 @@ func runSingle(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(reply)
-	}
-	fmt.Printf("Email sent successfully.\nID:     %s\nStatus: %s\n", reply.ID, reply.Status)
-	return nil
+	f.Log.Infof("Email sent successfully.")
+	return f.Printer.Data(printer.WithTable(reply, []string{"ID", "Status"}, [][]string{{reply.ID, reply.Status}}))
 }
 
 func runSchedule(f *cmdutil.Factory, opts Options) error {
@@ -158,11 +157,8 @@ func runSchedule(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(reply)
-	}
-	fmt.Printf("Email scheduled successfully.\nID:     %s\nStatus: %s\n", reply.ID, reply.Status)
-	return nil
+	f.Log.Infof("Email scheduled successfully.")
+	return f.Printer.Data(printer.WithTable(reply, []string{"ID", "Status"}, [][]string{{reply.ID, reply.Status}}))
 }
 
 func runBatch(f *cmdutil.Factory, opts Options) error {
@@ -195,9 +191,9 @@ func runBatch(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(reply)
-	}
-	fmt.Printf("Batch submitted successfully.\nJob ID: %s\nStatus: %s\nTotal:  %d\n", reply.JobID, reply.Status, reply.TotalCount)
-	return nil
+	f.Log.Infof("Batch submitted successfully.")
+	return f.Printer.Data(printer.WithTable(reply,
+		[]string{"Job ID", "Status", "Total"},
+		[][]string{{reply.JobID, reply.Status, strconv.Itoa(reply.TotalCount)}},
+	))
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct{}
@@ -38,16 +39,12 @@ func runStatus(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(status)
-	}
-
 	statusMsg := ""
 	if status.StatusMsg != nil {
 		statusMsg = *status.StatusMsg
 	}
 
-	f.Printer.Table(
+	return f.Printer.Data(printer.WithTable(status,
 		[]string{"Field", "Value"},
 		[][]string{
 			{"Status", status.Status},
@@ -63,7 +60,5 @@ func runStatus(f *cmdutil.Factory, opts Options) error {
 			{"Bounces (24h)", fmt.Sprintf("%d", status.BounceCount24h)},
 			{"Complaints (24h)", fmt.Sprintf("%d", status.ComplaintCount24h)},
 		},
-	)
-
-	return nil
+	))
 }
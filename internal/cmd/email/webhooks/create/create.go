@@ -10,6 +10,7 @@ import (
 
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -108,15 +109,10 @@ func createWebhook(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(reply)
-	}
-
 	f.Log.Infof("Webhook %q created successfully (ID: %s)", reply.Webhook.Name, reply.Webhook.ID)
 	f.Log.Infof("Secret: %s", reply.Secret)
 	f.Log.Infof("WARNING: This secret will only be shown once. Please save it now.")
-
-	return nil
+	return f.Printer.Data(printer.Result(reply))
 }
 
 func paramCheck(opts Options) error {
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -72,10 +73,6 @@ func getWebhook(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(wh)
-	}
-
 	enabled := "No"
 	if wh.Enabled {
 		enabled = "Yes"
@@ -85,7 +82,7 @@ func getWebhook(f *cmdutil.Factory, opts Options) error {
 		statusMsg = *wh.StatusMsg
 	}
 
-	f.Printer.Table(
+	return f.Printer.Data(printer.WithTable(wh,
 		[]string{"Field", "Value"},
 		[][]string{
 			{"ID", wh.ID},
@@ -100,8 +97,7 @@ func getWebhook(f *cmdutil.Factory, opts Options) error {
 			{"Failed", fmt.Sprintf("%d", wh.FailureCount)},
 			{"Created At", wh.CreatedAt.String()},
 		},
-	)
-	return nil
+	))
 }
 
 func paramCheck(opts Options) error {
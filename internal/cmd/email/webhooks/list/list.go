@@ -44,14 +44,5 @@ func runList(f *cmdutil.Factory, opts Options) error {
 		webhooks = append(webhooks, &reply.Webhooks[i])
 	}
 
-	if f.JSON {
-		if len(webhooks) == 0 {
-			return f.Printer.JSON([]any{})
-		}
-		return f.Printer.JSON(webhooks)
-	}
-
-	f.Printer.Table(webhooks.Header(), webhooks.Rows())
-
-	return nil
+	return f.Printer.Data(webhooks)
 }
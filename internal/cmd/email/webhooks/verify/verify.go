@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -71,13 +72,12 @@ func verifyWebhook(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		if err := f.Printer.JSON(reply); err != nil {
-			return err
-		}
-	} else if reply.Success {
+	if reply.Success {
 		f.Log.Infof("Webhook verification successful: %s", reply.Message)
 	}
+	if err := f.Printer.Data(printer.Result(reply)); err != nil {
+		return err
+	}
 
 	if !reply.Success {
 		return fmt.Errorf("webhook verification failed: %s", reply.Message)
@@ -0,0 +1,197 @@
+// Package link contains the cmd for linking a directory to a Zeabur service
+package link
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/printer"
+	"github.com/zeabur/cli/pkg/selector"
+	"github.com/zeabur/cli/pkg/zcontext"
+)
+
+type Options struct {
+	projectID     string
+	environmentID string
+	serviceID     string
+	serviceName   string
+
+	dir string
+}
+
+func NewCmdLink(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "link",
+		Short: "Link the current directory to a project, environment and service",
+		Long: heredoc.Doc(`Link the current directory to a project, environment and, optionally, a service.
+
+			The link is written to .zeabur/link.yaml. Commands run in the directory or
+			any of its subdirectories use it instead of the global context, so
+			deploy, variable and deployment log need no flags inside a linked checkout.
+
+			For example:
+				zeabur link
+				zeabur link --project-id=6512... --env-id=6512... --service-name=web
+				zeabur link --service-id=6512...`,
+		),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLink(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.projectID, "project-id", "", "Project ID to link")
+	cmd.Flags().StringVar(&opts.environmentID, "env-id", "", "Environment ID to link, defaults to the first environment of the project")
+	cmd.Flags().StringVar(&opts.serviceID, "service-id", "", "Service ID to link")
+	cmd.Flags().StringVar(&opts.serviceName, "service-name", "", "Service name to link")
+	cmd.Flags().StringVar(&opts.dir, "dir", ".", "Directory to link")
+
+	// a broken link file is what these commands fix
+	cmdutil.DisableLinkCheck(cmd)
+
+	return cmd
+}
+
+func runLink(f *cmdutil.Factory, opts *Options) error {
+	// A link is persistent like `context set`, and its IDs are only
+	// meaningful in the workspace they were picked from.
+	if f.HasWorkspaceOverride() {
+		return fmt.Errorf(
+			"`link` writes persistent state and cannot be combined with `--workspace`; " +
+				"run `zeabur workspace switch <team>` first, then `zeabur link`",
+		)
+	}
+
+	if f.Interactive {
+		if err := selectInteractively(f, opts); err != nil {
+			return err
+		}
+	}
+
+	link, err := resolve(f, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := zcontext.WriteLink(opts.dir, link); err != nil {
+		return fmt.Errorf("write link: %w", err)
+	}
+	f.ResetLink()
+
+	f.Log.Infof("Linked %s to project <%s>, environment <%s>", link.Dir(), link.Project.Name, link.Environment.Name)
+	if link.Service.ID != "" {
+		f.Log.Infof("Linked service <%s>", link.Service.Name)
+	}
+	return f.Printer.Data(printer.Result(map[string]any{
+		"path":        link.Path(),
+		"project":     link.Project,
+		"environment": link.Environment,
+		"service":     link.Service,
+	}))
+}
+
+// selectInteractively prompts for whatever the flags left open. The service
+// is optional: a project without services links the project alone.
+func selectInteractively(f *cmdutil.Factory, opts *Options) error {
+	if opts.serviceID != "" {
+		return nil
+	}
+
+	if opts.projectID == "" {
+		project, _, err := f.Selector.SelectProject()
+		if err != nil {
+			return err
+		}
+		opts.projectID = project.GetID()
+	}
+
+	if opts.environmentID == "" {
+		environment, _, err := f.Selector.SelectEnvironment(opts.projectID)
+		if err != nil {
+			return err
+		}
+		opts.environmentID = environment.GetID()
+	}
+
+	if opts.serviceName == "" {
+		service, _, err := f.Selector.SelectService(selector.SelectServiceOptions{
+			ProjectID: opts.projectID,
+			Auto:      true,
+		})
+		if err != nil {
+			return err
+		}
+		if service != nil {
+			opts.serviceID = service.GetID()
+		}
+	}
+
+	return nil
+}
+
+// resolve looks the linked resources up by the given IDs or service name,
+// so the link records names alongside IDs and rejects IDs that do not exist.
+func resolve(f *cmdutil.Factory, opts *Options) (*zcontext.Link, error) {
+	ctx := context.Background()
+	link := &zcontext.Link{}
+
+	if opts.serviceID != "" {
+		service, err := f.ApiClient.GetService(ctx, opts.serviceID, "", "", "")
+		if err != nil {
+			return nil, fmt.Errorf("get service: %w", err)
+		}
+		if service.Project == nil || service.Project.ID == "" {
+			return nil, fmt.Errorf("service %s has no associated project", opts.serviceID)
+		}
+		if opts.projectID != "" && opts.projectID != service.Project.ID {
+			return nil, fmt.Errorf("service %s does not belong to project %s", opts.serviceID, opts.projectID)
+		}
+		opts.projectID = service.Project.ID
+		link.Service = zcontext.LinkTarget{ID: service.ID, Name: service.Name}
+	}
+
+	if opts.projectID == "" {
+		return nil, fmt.Errorf("--project-id or --service-id is required")
+	}
+	project, err := f.ApiClient.GetProject(ctx, opts.projectID, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("get project: %w", err)
+	}
+	link.Project = zcontext.LinkTarget{ID: project.ID, Name: project.Name}
+
+	if link.Service.ID == "" && opts.serviceName != "" {
+		service, err := util.GetServiceByName(f.ApiClient, f.CurrentOwnerID(), f.Config.GetUsername(), project.Name, project.ID, opts.serviceName)
+		if err != nil {
+			return nil, err
+		}
+		link.Service = zcontext.LinkTarget{ID: service.ID, Name: service.Name}
+	}
+
+	if opts.environmentID == "" {
+		opts.environmentID, err = util.ResolveEnvironmentID(f.ApiClient, project.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	environment, err := f.ApiClient.GetEnvironment(ctx, opts.environmentID)
+	if err != nil {
+		return nil, fmt.Errorf("get environment: %w", err)
+	}
+	if environment.ProjectID != "" && environment.ProjectID != project.ID {
+		return nil, fmt.Errorf("environment %s does not belong to project %s", environment.ID, project.ID)
+	}
+	link.Environment = zcontext.LinkTarget{ID: environment.ID, Name: environment.Name}
+
+	if _, err := os.Stat(opts.dir); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
@@ -0,0 +1,118 @@
+package link_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zeabur/cli/internal/cmd/link"
+	"github.com/zeabur/cli/internal/cmd/unlink"
+	"github.com/zeabur/cli/internal/cmd/variable/list"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/pkg/zcontext"
+)
+
+// TestLink_ResolvesInsideCheckout links a directory by service name and
+// checks that a service-scoped command run in a subdirectory picks the
+// linked service up without flags, ahead of the global context.
+func TestLink_ResolvesInsideCheckout(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "api")
+	h.API.SeedService(project.ID, "worker")
+	svc := h.API.SeedService(project.ID, "web")
+	h.API.SeedVariables(svc.ID, env.ID, map[string]string{"PORT": "8080"})
+
+	other, _ := h.API.SeedProject("", "other")
+	h.Config.GetContext().SetProject(zcontext.NewBasicInfo(other.ID, other.Name))
+
+	if err := h.Run(link.NewCmdLink(h.Factory), "--project-id", project.ID, "--service-name", "web"); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+
+	l, err := zcontext.ReadLink(zcontext.LinkPath(dir))
+	if err != nil {
+		t.Fatalf("read link: %v", err)
+	}
+	want := zcontext.Link{
+		Project:     zcontext.LinkTarget{ID: project.ID, Name: "api"},
+		Environment: zcontext.LinkTarget{ID: env.ID, Name: env.Name},
+		Service:     zcontext.LinkTarget{ID: svc.ID, Name: "web"},
+	}
+	if l.Project != want.Project || l.Environment != want.Environment || l.Service != want.Service {
+		t.Fatalf("link = %+v, want %+v", l, want)
+	}
+	if h.Config.Writes != 0 {
+		t.Errorf("link wrote the global config %d times", h.Config.Writes)
+	}
+
+	if got := h.Factory.CurrentProjectID(); got != project.ID {
+		t.Errorf("CurrentProjectID() = %s, want the linked %s", got, project.ID)
+	}
+
+	if err := h.Run(list.NewCmdListVariables(h.Factory)); err != nil {
+		t.Fatalf("variable list: %v", err)
+	}
+	if rows := h.Printer.LastTable().Rows; len(rows) != 1 || rows[0][0] != "PORT" {
+		t.Fatalf("printed rows = %v, want the linked service's PORT", rows)
+	}
+
+	if err := h.Run(unlink.NewCmdUnlink(h.Factory)); err != nil {
+		t.Fatalf("unlink: %v", err)
+	}
+	if got := h.Factory.CurrentProjectID(); got != other.ID {
+		t.Errorf("after unlink CurrentProjectID() = %s, want the global %s", got, other.ID)
+	}
+}
+
+func TestLink_RejectsWorkspaceOverride(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	h := cmdtest.New()
+	project, _ := h.API.SeedProject("", "api")
+	h.Factory.SetWorkspaceOverride(&zcontext.Workspace{ID: "65aa1234567890abcdef1234", Name: "acme", Kind: zcontext.WorkspaceKindTeam})
+
+	if err := h.Run(link.NewCmdLink(h.Factory), "--project-id", project.ID); err == nil {
+		t.Fatal("link succeeded under --workspace")
+	}
+}
+
+// TestLink_BrokenLinkFails refuses to fall back to the global context when
+// the link file of the checkout cannot be parsed, and still lets unlink
+// remove it.
+func TestLink_BrokenLinkFails(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+	h.API.SeedVariables(svc.ID, env.ID, map[string]string{"PORT": "8080"})
+	h.Config.GetContext().SetProject(zcontext.NewBasicInfo(project.ID, project.Name))
+	h.Config.GetContext().SetService(zcontext.NewBasicInfo(svc.ID, svc.Name))
+
+	if err := os.MkdirAll(filepath.Dir(zcontext.LinkPath(dir)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(zcontext.LinkPath(dir), []byte("project: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := h.Run(list.NewCmdListVariables(h.Factory))
+	if err == nil || !strings.Contains(err.Error(), "read link file") {
+		t.Fatalf("variable list error = %v, want the broken link file", err)
+	}
+	if calls := h.API.CallsTo("ListVariables"); len(calls) != 0 {
+		t.Errorf("listed the variables of the global context: %v", calls)
+	}
+
+	if err := h.Run(unlink.NewCmdUnlink(h.Factory)); err != nil {
+		t.Fatalf("unlink: %v", err)
+	}
+	if _, err := os.Stat(zcontext.LinkPath(dir)); !os.IsNotExist(err) {
+		t.Errorf("link file still there: %v", err)
+	}
+}
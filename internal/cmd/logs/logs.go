@@ -0,0 +1,177 @@
+// Package logs provides the logs command, which follows the logs of several
+// services as one stream
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	logsShipCmd "github.com/zeabur/cli/internal/cmd/logs/ship"
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/logfilter"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+type Options struct {
+	projectID     string
+	environmentID string
+	services      []string
+	filterFlags   util.LogFilterFlags
+	buffer        time.Duration
+
+	filter logfilter.Filter
+}
+
+func NewCmdLogs(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Follow the runtime logs of several services as one stream",
+		Long: heredoc.Doc(`
+			Follow the runtime logs of every service of the current project, or of
+			the services given with --service, merged into one stream in time order.
+			Each line starts with the name of its service, in a color of its own.
+
+			Lines are held for --buffer so that lines from different services can
+			be put in order; set it to 0 to print them as soon as they arrive.
+
+			When the subscription of one service fails, the others keep going and
+			the failed one is resubscribed after a short delay. A refused token or
+			a missing service stops it for good; once every service is stopped
+			that way, the command exits with an error.
+		`),
+		Example: heredoc.Doc(`
+			# every service of the current project
+			zeabur logs
+
+			# a request across the API, the worker and the database
+			zeabur logs --service api,worker,postgresql --grep req-42
+
+			# warnings and errors only, as NDJSON
+			zeabur logs --level warn -o ndjson
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLogs(cmd.Context(), f, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.projectID, "project-id", "", "Project ID, defaults to the current project")
+	cmd.Flags().StringVar(&opts.environmentID, "env-id", "", "Environment ID")
+	cmd.Flags().StringSliceVarP(&opts.services, "service", "s", nil, "Services to follow by name or ID, comma-separated; defaults to every service of the project")
+	util.AddLogFilterFlags(cmd, &opts.filterFlags, false)
+	cmd.Flags().DurationVar(&opts.buffer, "buffer", time.Second, "How long to hold lines to put the services in time order")
+	util.SetFlagCompletion(cmd, "service", util.CompleteServiceName)
+
+	cmd.AddCommand(logsShipCmd.NewCmdShip(f))
+
+	return cmd
+}
+
+func runLogs(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
+	filter, err := opts.filterFlags.Filter(time.Now())
+	if err != nil {
+		return err
+	}
+	opts.filter = filter
+	if opts.buffer < 0 {
+		return errors.New("--buffer must not be negative")
+	}
+
+	if opts.projectID == "" {
+		opts.projectID = f.CurrentProjectID()
+	}
+	if opts.projectID == "" {
+		return errors.New("--project-id is required: there is no current project")
+	}
+	if opts.environmentID == "" {
+		opts.environmentID = f.CurrentEnvironmentID()
+	}
+	if opts.environmentID == "" {
+		if opts.environmentID, err = util.ResolveEnvironmentID(f.ApiClient, opts.projectID); err != nil {
+			return err
+		}
+	}
+
+	services, err := util.SelectServices(ctx, f.ApiClient, opts.projectID, opts.services)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	names := make([]string, 0, len(services))
+	for _, svc := range services {
+		names = append(names, svc.Name)
+	}
+	f.Log.Infof("Following the logs of %s", strings.Join(names, ", "))
+
+	s := &stream{
+		client:        f.ApiClient,
+		log:           f.Log,
+		projectID:     opts.projectID,
+		environmentID: opts.environmentID,
+		filter:        &opts.filter,
+		buffer:        opts.buffer,
+	}
+	return s.run(ctx, services, newPrinter(f, services))
+}
+
+// palette colors the service prefixes, in turn.
+var palette = []*color.Color{
+	color.New(color.FgCyan),
+	color.New(color.FgMagenta),
+	color.New(color.FgYellow),
+	color.New(color.FgGreen),
+	color.New(color.FgBlue),
+	color.New(color.FgHiCyan),
+	color.New(color.FgHiMagenta),
+	color.New(color.FgHiYellow),
+	color.New(color.FgHiGreen),
+	color.New(color.FgHiBlue),
+}
+
+// event is a line of the merged stream, as structured output prints it.
+type event struct {
+	Service   string    `json:"service"`
+	ServiceID string    `json:"serviceID"`
+	Timestamp time.Time `json:"timestamp"`
+	Message   string    `json:"message"`
+}
+
+// newPrinter returns a function printing lines with the name of their
+// service in front, padded to the longest name and colored in the table
+// format.
+func newPrinter(f *cmdutil.Factory, services model.Services) func(line) error {
+	width := 0
+	for _, svc := range services {
+		width = max(width, len(svc.Name))
+	}
+	colored := !f.MachineReadableOutput()
+	prefixes := make(map[string]string, len(services))
+	for i, svc := range services {
+		prefix := fmt.Sprintf("%-*s |", width, svc.Name)
+		if colored {
+			prefix = palette[i%len(palette)].Sprint(prefix)
+		}
+		prefixes[svc.ID] = prefix
+	}
+
+	return func(l line) error {
+		e := event{Service: l.service.Name, ServiceID: l.service.ID, Timestamp: l.log.Timestamp, Message: l.log.Message}
+		return f.Printer.Event(e, []string{prefixes[l.service.ID], l.log.Timestamp.Format(time.RFC3339), l.log.Message})
+	}
+}
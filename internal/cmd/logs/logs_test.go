@@ -0,0 +1,154 @@
+package logs_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zeabur/cli/internal/cmd/logs"
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
+)
+
+type event struct {
+	Service   string    `json:"service"`
+	Timestamp time.Time `json:"timestamp"`
+	Message   string    `json:"message"`
+}
+
+// seed adds a project whose api and worker services log interleaved lines.
+func seed(t *testing.T) (*cmdtest.Harness, string) {
+	t.Helper()
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "shop")
+	api := h.API.SeedService(project.ID, "api")
+	worker := h.API.SeedService(project.ID, "worker")
+	h.API.SeedService(project.ID, "postgresql")
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	h.API.SeedRuntimeLogs(api.ID, env.ID, model.Logs{
+		{Timestamp: ts, Message: "GET /orders req-1"},
+		{Timestamp: ts.Add(2 * time.Second), Message: "level=error msg=timeout req-1"},
+	})
+	h.API.SeedRuntimeLogs(worker.ID, env.ID, model.Logs{
+		{Timestamp: ts.Add(time.Second), Message: "picked up req-1"},
+		{Timestamp: ts.Add(3 * time.Second), Message: "done req-1"},
+	})
+	return h, project.ID
+}
+
+func events(t *testing.T, h *cmdtest.Harness) []event {
+	t.Helper()
+	out := make([]event, 0, len(h.Printer.Events))
+	for _, data := range h.Printer.Events {
+		var e event
+		if err := json.Unmarshal(data, &e); err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+func TestLogs_MergesInTimeOrder(t *testing.T) {
+	h, projectID := seed(t)
+
+	if err := h.Run(logs.NewCmdLogs(h.Factory), "--project-id", projectID, "--service", "worker,api", "--buffer", "50ms"); err != nil {
+		t.Fatalf("logs: %v", err)
+	}
+
+	var got []string
+	for _, e := range events(t, h) {
+		got = append(got, e.Service+": "+e.Message)
+	}
+	want := []string{
+		"api: GET /orders req-1",
+		"worker: picked up req-1",
+		"api: level=error msg=timeout req-1",
+		"worker: done req-1",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("stream:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if calls := h.API.CallsTo("WatchRuntimeLogs"); len(calls) != 2 {
+		t.Errorf("subscribed %d times, want once per selected service", len(calls))
+	}
+}
+
+func TestLogs_PrefixesAndFilters(t *testing.T) {
+	h, projectID := seed(t)
+	var buf bytes.Buffer
+	p := printer.NewWriter(&buf)
+	p.SetOutput(printer.Output{Format: printer.FormatPlain})
+	h.Factory.Printer = p
+	h.Factory.Output = "plain"
+
+	if err := h.Run(logs.NewCmdLogs(h.Factory), "--project-id", projectID, "--grep", "req-1", "--level", "error"); err != nil {
+		t.Fatalf("logs: %v", err)
+	}
+
+	want := "api        | 2024-01-01T00:00:02Z level=error msg=timeout req-1\n"
+	if buf.String() != want {
+		t.Errorf("printed %q, want %q", buf.String(), want)
+	}
+}
+
+func TestLogs_UnknownService(t *testing.T) {
+	h, projectID := seed(t)
+
+	err := h.Run(logs.NewCmdLogs(h.Factory), "--project-id", projectID, "--service", "api,cache")
+	if err == nil || !strings.Contains(err.Error(), `no service named "cache"`) {
+		t.Fatalf("error = %v", err)
+	}
+}
+
+// TestLogs_KeepsGoingWhenASubscriptionFails resubscribes after a failure
+// without repeating the lines from before it.
+func TestLogs_KeepsGoingWhenASubscriptionFails(t *testing.T) {
+	h, projectID := seed(t)
+	h.API.FailOn("WatchRuntimeLogs", errors.New("connection reset"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+	cmd := logs.NewCmdLogs(h.Factory)
+	cmd.SetContext(ctx)
+	if err := h.Run(cmd, "--project-id", projectID, "--service", "api,worker", "--buffer", "0"); err != nil {
+		t.Fatalf("logs: %v", err)
+	}
+
+	if got := len(events(t, h)); got != 4 {
+		t.Errorf("printed %d lines, want each of the 4 once", got)
+	}
+	if calls := h.API.CallsTo("WatchRuntimeLogs"); len(calls) != 4 {
+		t.Errorf("subscribed %d times, want twice per service", len(calls))
+	}
+}
+
+// TestLogs_StopsWhenUnauthorized gives up on a refused token instead of
+// resubscribing, and fails once no service is left to follow.
+func TestLogs_StopsWhenUnauthorized(t *testing.T) {
+	h, projectID := seed(t)
+	h.API.FailOn("WatchRuntimeLogs", fmt.Errorf("subscribe: %w", api.ErrUnauthorized))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	cmd := logs.NewCmdLogs(h.Factory)
+	cmd.SetContext(ctx)
+	err := h.Run(cmd, "--project-id", projectID, "--service", "api,worker", "--buffer", "0")
+	if ctx.Err() != nil {
+		t.Fatal("logs kept resubscribing")
+	}
+	if code := cmdutil.ExitCode(err); code != cmdutil.ExitUnauthorized {
+		t.Fatalf("exit code = %d for %v, want %d", code, err, cmdutil.ExitUnauthorized)
+	}
+	if calls := h.API.CallsTo("WatchRuntimeLogs"); len(calls) != 2 {
+		t.Errorf("subscribed %d times, want once per service", len(calls))
+	}
+}
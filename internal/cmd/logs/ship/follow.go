@@ -0,0 +1,208 @@
+package ship
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/logfilter"
+	"github.com/zeabur/cli/pkg/logship"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+const (
+	// deploymentPollInterval is how often the latest deployment of each
+	// service is checked for a new build.
+	deploymentPollInterval = 10 * time.Second
+	// buildLogGrace is how long the build logs of a deployment are still
+	// followed after it stopped building, for the lines in flight.
+	buildLogGrace = 10 * time.Second
+)
+
+// follower follows the logs of services and queues them as entries.
+type follower struct {
+	client  api.Client
+	log     *zap.SugaredLogger
+	origin  logship.Entry
+	filter  *logfilter.Filter
+	enqueue func(ctx context.Context, e logship.Entry) bool
+}
+
+// run follows the logs of the given types of services until ctx is done or
+// every subscription has ended. When every subscription failed for good,
+// their errors are returned, so a daemon whose token was revoked exits.
+func (fw *follower) run(ctx context.Context, services model.Services, types []string) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+		ok   bool
+	)
+	for _, svc := range services {
+		for _, t := range types {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var err error
+				if t == logTypeBuild {
+					err = fw.followBuilds(ctx, svc)
+				} else {
+					err = fw.followRuntime(ctx, svc)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					errs = append(errs, err)
+				} else {
+					ok = true
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	if ok {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// entry makes l of svc an entry.
+func (fw *follower) entry(svc *model.Service, logType, deploymentID string, l *model.Log) logship.Entry {
+	e := fw.origin
+	e.Service, e.ServiceID = svc.Name, svc.ID
+	e.Type, e.DeploymentID = logType, deploymentID
+	e.Timestamp, e.Message = l.Timestamp, l.Message
+	return e
+}
+
+// followRuntime ships the runtime logs of svc until ctx is done or the
+// subscription ends, and returns the error it failed with for good, if any.
+func (fw *follower) followRuntime(ctx context.Context, svc *model.Service) error {
+	watch := func(ctx context.Context) (<-chan model.Log, <-chan error) {
+		return fw.client.WatchRuntimeLogs(ctx, fw.origin.ProjectID, svc.ID, fw.origin.EnvironmentID, "")
+	}
+	emit := func(l model.Log) bool {
+		if !fw.filter.Match(&l) {
+			return true
+		}
+		return fw.enqueue(ctx, fw.entry(svc, logTypeRuntime, "", &l))
+	}
+	onError := func(err error, retryIn time.Duration) {
+		fw.log.Warnf("The runtime logs of %s failed, resubscribing in %s: %v", svc.Name, retryIn, err)
+	}
+
+	if err := util.FollowLogs(ctx, watch, emit, onError); err != nil {
+		fw.log.Errorf("The runtime logs of %s failed: %v", svc.Name, err)
+		return fmt.Errorf("the runtime logs of %s: %w", svc.Name, err)
+	}
+	if ctx.Err() == nil {
+		fw.log.Infof("The runtime logs of %s ended", svc.Name)
+	}
+	return nil
+}
+
+// followBuilds follows the build logs of each new deployment of svc until
+// ctx is done, or until checking the deployments fails for good, which is
+// returned. A build already running when it starts is followed from then
+// on; later ones from their first line.
+func (fw *follower) followBuilds(ctx context.Context, svc *model.Service) error {
+	var (
+		wg      sync.WaitGroup
+		current string // the deployment whose build logs are followed
+		stop    = func() {}
+		ending  *time.Timer
+	)
+	defer func() {
+		stop()
+		wg.Wait()
+	}()
+
+	for first := true; ; first = false {
+		d, exists, err := fw.client.GetLatestDeployment(ctx, svc.ID, fw.origin.EnvironmentID)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case util.PermanentLogError(err):
+			fw.log.Errorf("Checking the deployments of %s failed: %v", svc.Name, err)
+			return fmt.Errorf("the build logs of %s: %w", svc.Name, err)
+		case err != nil:
+			fw.log.Warnf("Checking the deployments of %s failed: %v", svc.Name, err)
+		case !exists:
+		case d.ID != current:
+			current = d.ID
+			if ending != nil {
+				ending.Stop()
+				ending = nil
+			}
+			stop()
+			if first && !d.IsBuilding() {
+				// built before shipping started
+				break
+			}
+			buildCtx, cancel := context.WithCancel(ctx)
+			stop = cancel
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				fw.followBuild(buildCtx, svc, d.ID, !first)
+			}()
+		case !d.IsBuilding() && ending == nil:
+			ending = time.AfterFunc(buildLogGrace, stop)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(deploymentPollInterval):
+		}
+	}
+}
+
+// followBuild ships the build logs of a deployment until ctx is done or the
+// subscription ends; from the first line when fromStart is set.
+func (fw *follower) followBuild(ctx context.Context, svc *model.Service, deploymentID string, fromStart bool) {
+	emit := func(l model.Log) bool {
+		if !fw.filter.Match(&l) {
+			return true
+		}
+		return fw.enqueue(ctx, fw.entry(svc, logTypeBuild, deploymentID, &l))
+	}
+
+	var shipped time.Time
+	if fromStart {
+		history, err := fw.client.GetBuildLogs(ctx, deploymentID)
+		if err != nil && ctx.Err() == nil {
+			fw.log.Warnf("Getting the build logs of %s failed: %v", svc.Name, err)
+		}
+		for _, l := range history {
+			if !emit(*l) {
+				return
+			}
+			shipped = l.Timestamp
+		}
+	}
+
+	watch := func(ctx context.Context) (<-chan model.Log, <-chan error) {
+		return fw.client.WatchBuildLogs(ctx, fw.origin.ProjectID, deploymentID)
+	}
+	onError := func(err error, retryIn time.Duration) {
+		fw.log.Warnf("The build logs of %s failed, resubscribing in %s: %v", svc.Name, retryIn, err)
+	}
+	err := util.FollowLogs(ctx, watch, func(l model.Log) bool {
+		// the history already had the lines up to shipped
+		if !l.Timestamp.After(shipped) {
+			return true
+		}
+		return emit(l)
+	}, onError)
+	if err != nil {
+		// followBuilds stops on its next check if the token is refused
+		fw.log.Warnf("The build logs of %s failed: %v", svc.Name, err)
+	}
+}
@@ -0,0 +1,370 @@
+// Package ship implements `zeabur logs ship`.
+package ship
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/logfilter"
+	"github.com/zeabur/cli/pkg/logship"
+)
+
+const (
+	logTypeRuntime = "runtime"
+	logTypeBuild   = "build"
+)
+
+type Options struct {
+	projectID     string
+	environmentID string
+	services      []string
+	sinks         []string
+	types         []string
+	batchSize     int
+	batchWait     time.Duration
+	bufferDir     string
+	maxBuffer     string
+	filterFlags   util.LogFilterFlags
+	test          bool
+
+	filter logfilter.Filter
+}
+
+// NewCmdShip builds `zeabur logs ship`.
+func NewCmdShip(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "ship --sink URL...",
+		Short: "Forward the logs of services to Loki, OTLP, syslog or files",
+		Long: heredoc.Docf(`
+			Follow the logs of every service of the current project, or of the
+			services given with --service, and forward them to one or more sinks
+			until interrupted:
+
+			%s
+
+			Lines are sent in batches of up to --batch-size, waiting up to
+			--batch-wait for a batch to fill. Until a sink has taken them, they are
+			kept in a buffer on disk, one per sink, so a sink outage or a restart
+			delays lines rather than losing them; when a buffer grows past
+			--max-buffer, following the logs pauses until the sink catches up.
+			Batches a sink rejects as invalid are dropped with a warning.
+
+			With --type build, the build logs of new deployments are forwarded too,
+			including the build in progress when shipping starts.
+		`, indent(logship.SinkSpecs, "  ")),
+		Example: heredoc.Doc(`
+			# every service of the current project to Loki
+			zeabur logs ship --sink loki+http://localhost:3100
+
+			# runtime and build logs to an OpenTelemetry collector and a local file
+			zeabur logs ship --type runtime,build \
+			  --sink otlp+http://localhost:4318 \
+			  --sink 'file:///var/log/zeabur/api.log?max-size=100MB&max-files=5'
+
+			# check that each sink accepts a line
+			zeabur logs ship --sink syslog+udp://logs.internal:514 --test
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runShip(cmd.Context(), f, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.projectID, "project-id", "", "Project ID, defaults to the current project")
+	cmd.Flags().StringVar(&opts.environmentID, "env-id", "", "Environment ID")
+	cmd.Flags().StringSliceVarP(&opts.services, "service", "s", nil, "Services to ship by name or ID, comma-separated; defaults to every service of the project")
+	cmd.Flags().StringArrayVar(&opts.sinks, "sink", nil, "Sink URL to ship to, can be repeated")
+	cmd.Flags().StringSliceVar(&opts.types, "type", []string{logTypeRuntime}, "Logs to ship: runtime, build or both")
+	cmd.Flags().IntVar(&opts.batchSize, "batch-size", 500, "Most lines sent to a sink at once")
+	cmd.Flags().DurationVar(&opts.batchWait, "batch-wait", time.Second, "How long to wait for a batch to fill before sending it")
+	cmd.Flags().StringVar(&opts.bufferDir, "buffer-dir", "", "Directory of the on-disk buffers (default the user cache directory)")
+	cmd.Flags().StringVar(&opts.maxBuffer, "max-buffer", "1GB", "Size of a sink's buffer past which following the logs pauses")
+	util.AddLogFilterFlags(cmd, &opts.filterFlags, false)
+	cmd.Flags().BoolVar(&opts.test, "test", false, "Send a test line to each sink and exit")
+	util.SetFlagCompletion(cmd, "service", util.CompleteServiceName)
+
+	return cmd
+}
+
+func runShip(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
+	if err := opts.check(); err != nil {
+		return err
+	}
+	maxBuffer, err := logship.ParseSize(opts.maxBuffer)
+	if err != nil {
+		return fmt.Errorf("--max-buffer: %w", err)
+	}
+
+	sinks := make([]logship.Sink, 0, len(opts.sinks))
+	defer func() {
+		for _, sink := range sinks {
+			_ = sink.Close()
+		}
+	}()
+	for _, spec := range opts.sinks {
+		sink, err := logship.ParseSink(spec)
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if opts.projectID == "" {
+		opts.projectID = f.CurrentProjectID()
+	}
+	if opts.projectID == "" {
+		return errors.New("--project-id is required: there is no current project")
+	}
+	if opts.environmentID == "" {
+		opts.environmentID = f.CurrentEnvironmentID()
+	}
+	if opts.environmentID == "" {
+		if opts.environmentID, err = util.ResolveEnvironmentID(f.ApiClient, opts.projectID); err != nil {
+			return err
+		}
+	}
+	project, err := f.ApiClient.GetProject(ctx, opts.projectID, "", "")
+	if err != nil {
+		return fmt.Errorf("get project: %w", err)
+	}
+	environment, err := f.ApiClient.GetEnvironment(ctx, opts.environmentID)
+	if err != nil {
+		return fmt.Errorf("get environment: %w", err)
+	}
+	origin := logship.Entry{
+		Project:       project.Name,
+		ProjectID:     project.ID,
+		Environment:   environment.Name,
+		EnvironmentID: environment.ID,
+	}
+
+	if opts.test {
+		return testSinks(ctx, f, opts, sinks, origin)
+	}
+
+	services, err := util.SelectServices(ctx, f.ApiClient, opts.projectID, opts.services)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shippers := make([]*logship.Shipper, 0, len(sinks))
+	defer func() {
+		for i, s := range shippers {
+			if n := s.Queue.Len(); n > 0 {
+				f.Log.Infof("%d line(s) not shipped to %s yet, kept for the next run", n, redact(opts.sinks[i]))
+			}
+			_ = s.Queue.Close()
+		}
+	}()
+	for i, sink := range sinks {
+		dir, err := opts.queueDir(opts.sinks[i])
+		if err != nil {
+			return err
+		}
+		queue, err := logship.OpenQueue(dir, maxBuffer)
+		if errors.Is(err, logship.ErrQueueLocked) {
+			return fmt.Errorf("the buffer of %s in %s is in use by another `zeabur logs ship` of the same project, environment and sink; stop it or set a different --buffer-dir", redact(opts.sinks[i]), dir)
+		}
+		if err != nil {
+			return fmt.Errorf("open the buffer of %s: %w", redact(opts.sinks[i]), err)
+		}
+		if n := queue.Len(); n > 0 {
+			f.Log.Infof("Resuming %d buffered line(s) for %s", n, redact(opts.sinks[i]))
+		}
+		shippers = append(shippers, newShipper(f, redact(opts.sinks[i]), sink, queue, opts))
+	}
+
+	names := make([]string, 0, len(services))
+	for _, svc := range services {
+		names = append(names, svc.Name)
+	}
+	targets := make([]string, 0, len(opts.sinks))
+	for _, spec := range opts.sinks {
+		targets = append(targets, redact(spec))
+	}
+	f.Log.Infof("Shipping the %s logs of %s to %s", strings.Join(opts.types, " and "), strings.Join(names, ", "), strings.Join(targets, ", "))
+
+	shipCtx, stopShipping := context.WithCancel(ctx)
+	defer stopShipping()
+	var shipping sync.WaitGroup
+	for _, s := range shippers {
+		shipping.Add(1)
+		go func() {
+			defer shipping.Done()
+			if err := s.Run(shipCtx); err != nil {
+				f.Log.Errorf("Shipping stopped: %v", err)
+			}
+		}()
+	}
+
+	fw := &follower{
+		client:  f.ApiClient,
+		log:     f.Log,
+		origin:  origin,
+		filter:  &opts.filter,
+		enqueue: enqueueAll(shippers),
+	}
+	err = fw.run(ctx, services, opts.types)
+
+	// every subscription ended on its own: ship what is left, then stop
+	if ctx.Err() == nil {
+		drain(ctx, shippers)
+	}
+	stopShipping()
+	shipping.Wait()
+	return err
+}
+
+func (opts *Options) check() error {
+	if len(opts.sinks) == 0 {
+		return errors.New("--sink is required")
+	}
+	for _, t := range opts.types {
+		if t != logTypeRuntime && t != logTypeBuild {
+			return fmt.Errorf("unknown --type %q, must be runtime or build", t)
+		}
+	}
+	if len(opts.types) == 0 {
+		return errors.New("--type must name runtime, build or both")
+	}
+	var types []string
+	for _, t := range []string{logTypeRuntime, logTypeBuild} {
+		if slices.Contains(opts.types, t) {
+			types = append(types, t)
+		}
+	}
+	opts.types = types
+	if opts.batchSize < 1 {
+		return errors.New("--batch-size must be positive")
+	}
+	if opts.batchWait < 0 {
+		return errors.New("--batch-wait must not be negative")
+	}
+	filter, err := opts.filterFlags.Filter(time.Now())
+	if err != nil {
+		return err
+	}
+	opts.filter = filter
+	return nil
+}
+
+// queueDir returns the directory of the buffer of the sink spec for the
+// project and environment being shipped.
+func (opts *Options) queueDir(spec string) (string, error) {
+	dir := opts.bufferDir
+	if dir == "" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			return "", fmt.Errorf("no directory for the buffers, set --buffer-dir: %w", err)
+		}
+		dir = filepath.Join(cache, "zeabur", "logship")
+	}
+	sum := sha256.Sum256([]byte(opts.projectID + "\x00" + opts.environmentID + "\x00" + spec))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])), nil
+}
+
+func newShipper(f *cmdutil.Factory, name string, sink logship.Sink, queue *logship.Queue, opts *Options) *logship.Shipper {
+	return &logship.Shipper{
+		Sink:      sink,
+		Queue:     queue,
+		BatchSize: opts.batchSize,
+		BatchWait: opts.batchWait,
+		OnError: func(err error, retryIn time.Duration) {
+			f.Log.Warnf("Shipping to %s failed, %d line(s) buffered, retrying in %s: %v", name, queue.Len(), retryIn, err)
+		},
+		OnDrop: func(err error, dropped int) {
+			f.Log.Warnf("%s rejected %d line(s), dropping them: %v", name, dropped, err)
+		},
+	}
+}
+
+// enqueueAll returns a function queueing entries for every shipper, which
+// waits while a buffer is full and returns false once ctx is done.
+func enqueueAll(shippers []*logship.Shipper) func(ctx context.Context, e logship.Entry) bool {
+	return func(ctx context.Context, e logship.Entry) bool {
+		for _, s := range shippers {
+			if err := s.Enqueue(ctx, e); err != nil {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// drain waits until the shippers have emptied their queues or ctx is done.
+func drain(ctx context.Context, shippers []*logship.Shipper) {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if !slices.ContainsFunc(shippers, func(s *logship.Shipper) bool { return s.Queue.Len() > 0 }) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// testSinks sends a line to each sink and reports whether it was taken.
+func testSinks(ctx context.Context, f *cmdutil.Factory, opts *Options, sinks []logship.Sink, origin logship.Entry) error {
+	e := origin
+	e.Timestamp = time.Now()
+	e.Service, e.Type = "zeabur-cli", "test"
+	e.Message = "Test line from zeabur logs ship"
+
+	header := []string{"Sink", "Result"}
+	rows := make([][]string, 0, len(sinks))
+	failed := 0
+	for i, sink := range sinks {
+		result := "ok"
+		sendCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		if err := sink.Send(sendCtx, []logship.Entry{e}); err != nil {
+			result = err.Error()
+			failed++
+		}
+		cancel()
+		rows = append(rows, []string{redact(opts.sinks[i]), result})
+	}
+	f.Printer.Table(header, rows)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sink(s) failed the test", failed, len(sinks))
+	}
+	return nil
+}
+
+// redact hides the password of a sink URL.
+func redact(spec string) string {
+	u, err := url.Parse(spec)
+	if err != nil {
+		return spec
+	}
+	return u.Redacted()
+}
+
+func indent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
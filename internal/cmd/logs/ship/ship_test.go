@@ -0,0 +1,272 @@
+package ship_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zeabur/cli/internal/cmd/logs/ship"
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/logship"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+var ts = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// seed adds a project whose api and worker services logged a line each.
+func seed(t *testing.T) (*cmdtest.Harness, *model.Project, *model.Environment) {
+	t.Helper()
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "shop")
+	api := h.API.SeedService(project.ID, "api")
+	worker := h.API.SeedService(project.ID, "worker")
+	h.API.SeedRuntimeLogs(api.ID, env.ID, model.Logs{
+		{Timestamp: ts, Message: "INFO GET /orders"},
+		{Timestamp: ts.Add(time.Second), Message: "level=debug msg=cache hit"},
+	})
+	h.API.SeedRuntimeLogs(worker.ID, env.ID, model.Logs{
+		{Timestamp: ts.Add(2 * time.Second), Message: "level=warn msg=slow job"},
+	})
+	return h, project, env
+}
+
+func readEntries(t *testing.T, path string) []logship.Entry {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []logship.Entry
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var e logship.Entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("line %q: %v", line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestShip_RuntimeLogsToFile(t *testing.T) {
+	h, project, _ := seed(t)
+	dir := t.TempDir()
+	out := filepath.Join(dir, "shop.log")
+
+	err := h.Run(ship.NewCmdShip(h.Factory), "--project-id", project.ID, "--sink", "file://"+out,
+		"--level", "info", "--batch-wait", "10ms", "--buffer-dir", filepath.Join(dir, "buffer"))
+	if err != nil {
+		t.Fatalf("ship: %v", err)
+	}
+
+	entries := readEntries(t, out)
+	if len(entries) != 2 {
+		t.Fatalf("shipped %+v, want the two lines at info or above", entries)
+	}
+	got := map[string]logship.Entry{}
+	for _, e := range entries {
+		got[e.Service] = e
+	}
+	if e := got["api"]; e.Message != "INFO GET /orders" || e.Project != "shop" || e.Environment != "production" || e.Type != "runtime" || !e.Timestamp.Equal(ts) {
+		t.Errorf("api entry = %+v", e)
+	}
+	if e := got["worker"]; e.Message != "level=warn msg=slow job" {
+		t.Errorf("worker entry = %+v", e)
+	}
+}
+
+// TestShip_BufferInUse refuses to share a buffer with another shipper,
+// which would ship and delete the same lines.
+func TestShip_BufferInUse(t *testing.T) {
+	h, project, _ := seed(t)
+	dir := t.TempDir()
+	sink := "file://" + filepath.Join(dir, "shop.log")
+
+	err := h.Run(ship.NewCmdShip(h.Factory), "--project-id", project.ID, "--sink", sink, "--sink", sink,
+		"--batch-wait", "10ms", "--buffer-dir", filepath.Join(dir, "buffer"))
+	if err == nil || !strings.Contains(err.Error(), "in use by another `zeabur logs ship`") {
+		t.Fatalf("err = %v, want the buffer in use", err)
+	}
+}
+
+// TestShip_ExitsWhenTokenIsRevoked exits with the error once every
+// subscription is refused, instead of resubscribing forever.
+func TestShip_ExitsWhenTokenIsRevoked(t *testing.T) {
+	h, project, _ := seed(t)
+	h.API.FailOn("WatchRuntimeLogs", fmt.Errorf("subscribe: %w", api.ErrUnauthorized))
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	cmd := ship.NewCmdShip(h.Factory)
+	cmd.SetContext(ctx)
+	err := h.Run(cmd, "--project-id", project.ID, "--sink", "file://"+filepath.Join(dir, "shop.log"),
+		"--batch-wait", "10ms", "--buffer-dir", filepath.Join(dir, "buffer"))
+	if ctx.Err() != nil {
+		t.Fatal("ship kept resubscribing")
+	}
+	if code := cmdutil.ExitCode(err); code != cmdutil.ExitUnauthorized {
+		t.Fatalf("exit code = %d for %v, want %d", code, err, cmdutil.ExitUnauthorized)
+	}
+}
+
+func TestShip_BuffersWhileSinkIsDown(t *testing.T) {
+	h, project, _ := seed(t)
+	dir := t.TempDir()
+
+	// a sink that is down, then comes back
+	var (
+		mu     sync.Mutex
+		pushes []int
+		up     atomic.Bool
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var push struct {
+			Streams []struct {
+				Values [][2]string `json:"values"`
+			} `json:"streams"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&push)
+		n := 0
+		for _, s := range push.Streams {
+			n += len(s.Values)
+		}
+		mu.Lock()
+		pushes = append(pushes, n)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	pushed := func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(pushes)
+	}
+	args := []string{"--project-id", project.ID, "--sink", "loki+" + srv.URL, "--batch-wait", "10ms", "--buffer-dir", dir}
+
+	// interrupted while the sink is down: the lines stay buffered
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	cmd := ship.NewCmdShip(h.Factory)
+	cmd.SetContext(ctx)
+	if err := h.Run(cmd, args...); err != nil {
+		t.Fatalf("ship: %v", err)
+	}
+	if len(pushed()) != 0 {
+		t.Fatalf("pushes = %v while the sink was down", pushed())
+	}
+
+	// the next run ships them, along with the new lines
+	up.Store(true)
+	if err := h.Run(ship.NewCmdShip(h.Factory), args...); err != nil {
+		t.Fatalf("ship: %v", err)
+	}
+	total := 0
+	for _, n := range pushed() {
+		total += n
+	}
+	if total != 6 {
+		t.Errorf("pushed %v lines, want the 3 buffered and the 3 replayed", pushed())
+	}
+}
+
+func TestShip_BuildLogs(t *testing.T) {
+	h, project, env := seed(t)
+	api := h.API.SeedService(project.ID, "web")
+	d := h.API.SeedDeployment(api.ID, env.ID, model.DeploymentStatusBuilding)
+	h.API.SeedBuildLogs(d.ID, model.Logs{{Timestamp: ts, Message: "#1 FROM node:20"}})
+	dir := t.TempDir()
+	out := filepath.Join(dir, "shop.log")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		cmd := ship.NewCmdShip(h.Factory)
+		cmd.SetContext(ctx)
+		done <- h.Run(cmd, "--project-id", project.ID, "--service", "web", "--type", "build", "--sink", "file://"+out,
+			"--batch-wait", "10ms", "--buffer-dir", filepath.Join(dir, "buffer"))
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, _ := os.ReadFile(out); len(data) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("ship: %v", err)
+	}
+
+	entries := readEntries(t, out)
+	if len(entries) != 1 || entries[0].Type != "build" || entries[0].DeploymentID != d.ID || entries[0].Message != "#1 FROM node:20" {
+		t.Errorf("shipped %+v, want the build line of the running build", entries)
+	}
+}
+
+func TestShip_Test(t *testing.T) {
+	h, project, _ := seed(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	out := filepath.Join(t.TempDir(), "test.log")
+
+	err := h.Run(ship.NewCmdShip(h.Factory), "--project-id", project.ID, "--sink", "file://"+out, "--sink", "otlp+"+srv.URL, "--test")
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 sink(s) failed") {
+		t.Fatalf("error = %v", err)
+	}
+	rows := h.Printer.LastTable().Rows
+	if len(rows) != 2 || rows[0][1] != "ok" || !strings.Contains(rows[1][1], "otlp responded 400") {
+		t.Errorf("rows = %v", rows)
+	}
+	if entries := readEntries(t, out); len(entries) != 1 || entries[0].Type != "test" || entries[0].Project != "shop" {
+		t.Errorf("test line = %+v", entries)
+	}
+	if calls := h.API.CallsTo("WatchRuntimeLogs"); len(calls) != 0 {
+		t.Error("--test followed logs")
+	}
+}
+
+func TestShip_Validation(t *testing.T) {
+	h, project, _ := seed(t)
+
+	for _, tc := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "--sink is required"},
+		{[]string{"--sink", "kafka://localhost:9092"}, "unknown sink"},
+		{[]string{"--sink", "loki+http://localhost:3100", "--type", "audit"}, `unknown --type "audit"`},
+		{[]string{"--sink", "loki+http://localhost:3100", "--max-buffer", "lots"}, "--max-buffer"},
+		{[]string{"--sink", "loki+http://localhost:3100", "--invert"}, "--invert requires --grep"},
+	} {
+		err := h.Run(ship.NewCmdShip(h.Factory), append([]string{"--project-id", project.ID}, tc.args...)...)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%v: error = %v, want %q", tc.args, err, tc.err)
+		}
+	}
+	if calls := h.API.CallsTo("GetProject"); len(calls) != 0 {
+		t.Error("called the API before checking the flags")
+	}
+}
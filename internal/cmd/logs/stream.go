@@ -0,0 +1,145 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/logfilter"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// line is a log line of a service.
+type line struct {
+	service *model.Service
+	log     model.Log
+	arrived time.Time
+}
+
+// stream merges the runtime logs of several services.
+type stream struct {
+	client        api.Client
+	log           *zap.SugaredLogger
+	projectID     string
+	environmentID string
+	filter        *logfilter.Filter
+	buffer        time.Duration
+}
+
+// run follows the logs of services and passes the lines to emit in time
+// order, until ctx is done or every subscription has ended. When every
+// subscription failed for good, their errors are returned.
+func (s *stream) run(ctx context.Context, services model.Services, emit func(line) error) error {
+	lines := make(chan line)
+	errs := make([]error, len(services))
+	var wg sync.WaitGroup
+	for i, svc := range services {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.follow(ctx, svc, lines)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+
+	if err := merge(ctx, lines, s.buffer, emit); err != nil {
+		return err
+	}
+	wg.Wait()
+	if slices.ContainsFunc(errs, func(err error) bool { return err == nil }) {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// follow sends the lines of svc that pass the filter to out, until ctx is
+// done or the subscription ends, and returns the error it failed with for
+// good, if any.
+func (s *stream) follow(ctx context.Context, svc *model.Service, out chan<- line) error {
+	watch := func(ctx context.Context) (<-chan model.Log, <-chan error) {
+		return s.client.WatchRuntimeLogs(ctx, s.projectID, svc.ID, s.environmentID, "")
+	}
+	emit := func(l model.Log) bool {
+		if !s.filter.Match(&l) {
+			return true
+		}
+		select {
+		case out <- line{service: svc, log: l, arrived: time.Now()}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	onError := func(err error, retryIn time.Duration) {
+		s.log.Warnf("The logs of %s failed, resubscribing in %s: %v", svc.Name, retryIn, err)
+	}
+
+	if err := util.FollowLogs(ctx, watch, emit, onError); err != nil {
+		s.log.Warnf("The logs of %s failed: %v", svc.Name, err)
+		return fmt.Errorf("the logs of %s: %w", svc.Name, err)
+	}
+	if ctx.Err() == nil {
+		s.log.Infof("The logs of %s ended", svc.Name)
+	}
+	return nil
+}
+
+// merge holds the lines from in for buffer after they arrive, and emits
+// them sorted by timestamp. A line arriving later than that with an earlier
+// timestamp is emitted out of order rather than held back.
+func merge(ctx context.Context, in <-chan line, buffer time.Duration, emit func(line) error) error {
+	var pending []line
+	flush := func(all bool) error {
+		n := len(pending)
+		if !all {
+			cutoff := time.Now().Add(-buffer)
+			n = slices.IndexFunc(pending, func(l line) bool { return l.arrived.After(cutoff) })
+			if n < 0 {
+				n = len(pending)
+			}
+		}
+		batch := pending[:n]
+		slices.SortStableFunc(batch, func(a, b line) int { return a.log.Timestamp.Compare(b.log.Timestamp) })
+		for _, l := range batch {
+			if err := emit(l); err != nil {
+				return err
+			}
+		}
+		pending = slices.Delete(pending, 0, n)
+		return nil
+	}
+
+	tick := time.NewTicker(max(buffer/4, 10*time.Millisecond))
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// interrupted: print what is held and end cleanly
+			return flush(true)
+		case l, ok := <-in:
+			if !ok {
+				return flush(true)
+			}
+			pending = append(pending, l)
+			if buffer == 0 {
+				if err := flush(true); err != nil {
+					return err
+				}
+			}
+		case <-tick.C:
+			if err := flush(false); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,21 @@
+// Package mcp contains the cmd for running the CLI as an MCP server
+package mcp
+
+import (
+	"github.com/spf13/cobra"
+
+	mcpServeCmd "github.com/zeabur/cli/internal/cmd/mcp/serve"
+	"github.com/zeabur/cli/internal/cmdutil"
+)
+
+// NewCmdMCP builds the `zeabur mcp` parent command.
+func NewCmdMCP(f *cmdutil.Factory, version string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp <command>",
+		Short: "Serve Zeabur operations to AI assistants over the Model Context Protocol",
+	}
+
+	cmd.AddCommand(mcpServeCmd.NewCmdServe(f, version))
+
+	return cmd
+}
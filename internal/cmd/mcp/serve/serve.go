@@ -0,0 +1,78 @@
+// Package serve implements `zeabur mcp serve`.
+package serve
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/mcp"
+)
+
+type Options struct {
+	readOnly bool
+}
+
+// NewCmdServe builds `zeabur mcp serve`.
+func NewCmdServe(f *cmdutil.Factory, version string) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run an MCP server on stdin and stdout",
+		Long: heredoc.Doc(`
+			Run a Model Context Protocol server on stdin and stdout, so that an AI
+			assistant can list projects and services, deploy the current directory,
+			read and watch logs, get and set variables, restart services and run
+			commands in them, as you.
+
+			Tools act on the service given in their arguments, or on the current
+			context or linked directory. With --workspace, the context is ignored
+			and tools need explicit IDs, as with every other command.
+
+			With --read-only, only the tools that change nothing are offered.
+		`),
+		Example: heredoc.Doc(`
+			# in the MCP configuration of your assistant
+			{"mcpServers": {"zeabur": {"command": "zeabur", "args": ["mcp", "serve", "--read-only"]}}}
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe(f, opts, version, cmd)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.readOnly, "read-only", false, "Only offer tools that do not modify anything")
+
+	return cmd
+}
+
+func runServe(f *cmdutil.Factory, opts *Options, version string, cmd *cobra.Command) error {
+	server := mcp.NewServer("zeabur", version)
+	server.ReadOnly = opts.readOnly
+	server.Instructions = instructions
+	for _, t := range newTools(f).list() {
+		server.AddTool(t)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if opts.readOnly {
+		f.Log.Info("Serving MCP on stdio, read-only")
+	} else {
+		f.Log.Info("Serving MCP on stdio")
+	}
+	return server.Serve(ctx, cmd.InOrStdin(), cmd.OutOrStdout())
+}
+
+const instructions = `Tools to manage Zeabur projects and services.
+Use list_projects and list_services to find IDs. Tools taking a service accept
+service_id, or service_name with project_id, and default to the service of the
+current context when neither is given. environment_id defaults to the
+service's environment.`
@@ -0,0 +1,125 @@
+package serve_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zeabur/cli/internal/cmd/mcp/serve"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/zcontext"
+)
+
+// call runs `zeabur mcp serve` with one tools/call request per entry of
+// calls on stdin, and returns the results in order.
+func call(t *testing.T, h *cmdtest.Harness, flags []string, calls ...string) []map[string]any {
+	t.Helper()
+
+	var in bytes.Buffer
+	for i, c := range calls {
+		in.WriteString(`{"jsonrpc":"2.0","id":` + strconv.Itoa(i+1) + `,"method":"tools/call","params":` + c + "}\n")
+	}
+	var out bytes.Buffer
+	cmd := serve.NewCmdServe(h.Factory, "dev")
+	cmd.SetIn(&in)
+	cmd.SetOut(&out)
+	if err := h.Run(cmd, flags...); err != nil {
+		t.Fatalf("mcp serve: %v", err)
+	}
+
+	// calls run concurrently, so responses are matched by ID
+	results := make([]map[string]any, len(calls))
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var msg struct {
+			ID     int            `json:"id"`
+			Result map[string]any `json:"result"`
+		}
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("invalid response %s: %v", line, err)
+		}
+		results[msg.ID-1] = msg.Result
+	}
+	return results
+}
+
+func structured(t *testing.T, result map[string]any) map[string]any {
+	t.Helper()
+
+	if result["isError"] == true {
+		t.Fatalf("tool failed: %v", result["content"])
+	}
+	return result["structuredContent"].(map[string]any)
+}
+
+func TestServe_DefaultsToCurrentService(t *testing.T) {
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "api")
+	web := h.API.SeedService(project.ID, "web")
+	h.API.SeedService(project.ID, "worker")
+	h.API.SeedVariables(web.ID, env.ID, map[string]string{"PORT": "8080", "DEBUG": "1"})
+	h.API.SeedRuntimeLogs(web.ID, env.ID, model.Logs{
+		{Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Message: "listening on :8080"},
+	})
+	h.Config.GetContext().SetProject(zcontext.NewBasicInfo(project.ID, project.Name))
+	h.Config.GetContext().SetService(zcontext.NewBasicInfo(web.ID, web.Name))
+
+	results := call(t, h, nil,
+		`{"name":"list_services"}`,
+		`{"name":"get_logs"}`,
+		`{"name":"set_variables","arguments":{"variables":{"PORT":"3000"},"unset":["DEBUG"]}}`,
+	)
+
+	if services := structured(t, results[0])["services"].([]any); len(services) != 2 {
+		t.Errorf("services = %v, want both services of the current project", services)
+	}
+	if logs := structured(t, results[1])["logs"].([]any); len(logs) != 1 {
+		t.Errorf("logs = %v", logs)
+	}
+	structured(t, results[2])
+	if got := h.API.Variables(web.ID, env.ID); len(got) != 1 || got["PORT"] != "3000" {
+		t.Errorf("variables = %v, want PORT updated and DEBUG removed", got)
+	}
+}
+
+func TestServe_ReadOnly(t *testing.T) {
+	h := cmdtest.New()
+	project, _ := h.API.SeedProject("", "api")
+	web := h.API.SeedService(project.ID, "web")
+
+	results := call(t, h, []string{"--read-only"},
+		`{"name":"restart_service","arguments":{"service_id":"`+web.ID+`"}}`,
+		`{"name":"list_projects"}`,
+	)
+	if results[0]["isError"] != true {
+		t.Errorf("restart_service = %v, want it refused", results[0])
+	}
+	if calls := h.API.CallsTo("RestartService"); len(calls) != 0 {
+		t.Errorf("RestartService called %d times in read-only mode", len(calls))
+	}
+	if projects := structured(t, results[1])["projects"].([]any); len(projects) != 1 {
+		t.Errorf("projects = %v", projects)
+	}
+}
+
+func TestServe_ExecByName(t *testing.T) {
+	h := cmdtest.New()
+	project, _ := h.API.SeedProject("", "api")
+	h.API.SeedService(project.ID, "web")
+	h.API.CommandResults["ls /app"] = &model.CommandResult{Output: "main.go\n", ExitCode: 0}
+
+	results := call(t, h, nil,
+		`{"name":"exec","arguments":{"project_id":"`+project.ID+`","service_name":"web","command":["ls","/app"]}}`,
+		`{"name":"exec","arguments":{"command":["ls"]}}`,
+	)
+	if out := structured(t, results[0]); out["output"] != "main.go\n" {
+		t.Errorf("exec = %v", out)
+	}
+	// no service given and none in the context
+	if results[1]["isError"] != true {
+		t.Errorf("exec without a service = %v, want an error", results[1])
+	}
+}
@@ -0,0 +1,538 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/mcp"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+const (
+	defaultLogLimit    = 200
+	defaultWatchPeriod = 30 * time.Second
+	maxWatchPeriod     = 5 * time.Minute
+	maxWatchLines      = 1000
+)
+
+// tools backs the MCP tools with the API client. Tool calls run
+// concurrently, so the workspace and context are read once up front rather
+// than through the Factory, whose lookups are not safe for concurrent use.
+type tools struct {
+	client api.Client
+
+	ownerID     string
+	workspace   string
+	projectID   string
+	serviceID   string
+	environment string
+}
+
+func newTools(f *cmdutil.Factory) *tools {
+	ws := f.CurrentWorkspace()
+	workspace := "personal"
+	if ws.IsTeam() {
+		workspace = ws.Name
+	}
+	return &tools{
+		client:      f.ApiClient,
+		ownerID:     f.CurrentOwnerID(),
+		workspace:   workspace,
+		projectID:   f.CurrentProjectID(),
+		serviceID:   f.CurrentServiceID(),
+		environment: f.CurrentEnvironmentID(),
+	}
+}
+
+// target selects a service; see the server instructions.
+type target struct {
+	ProjectID     string `json:"project_id"`
+	ServiceID     string `json:"service_id"`
+	ServiceName   string `json:"service_name"`
+	EnvironmentID string `json:"environment_id"`
+}
+
+var targetProperties = map[string]mcp.Schema{
+	"project_id":     mcp.String("Project ID, to find service_name in; defaults to the current project"),
+	"service_id":     mcp.String("Service ID; defaults to the current service"),
+	"service_name":   mcp.String("Service name, instead of service_id"),
+	"environment_id": mcp.String("Environment ID; defaults to the service's environment"),
+}
+
+// withTarget returns the target properties plus extra.
+func withTarget(extra map[string]mcp.Schema) map[string]mcp.Schema {
+	props := maps.Clone(targetProperties)
+	maps.Copy(props, extra)
+	return props
+}
+
+// resolve fills in the service and environment IDs of t.
+func (s *tools) resolve(ctx context.Context, t *target) error {
+	if t.ServiceID == "" && t.ServiceName != "" {
+		if t.ProjectID == "" {
+			t.ProjectID = s.projectID
+		}
+		svc, err := s.findService(ctx, t.ProjectID, t.ServiceName)
+		if err != nil {
+			return err
+		}
+		if svc == nil {
+			return fmt.Errorf("no service named %q in project %s", t.ServiceName, t.ProjectID)
+		}
+		t.ServiceID = svc.ID
+	}
+
+	if t.ServiceID == "" {
+		if s.serviceID == "" {
+			return errors.New("service_id or service_name is required: there is no current service")
+		}
+		t.ServiceID = s.serviceID
+		if t.EnvironmentID == "" {
+			t.EnvironmentID = s.environment
+		}
+	}
+
+	if t.EnvironmentID == "" || t.ProjectID == "" {
+		service, err := s.client.GetService(ctx, t.ServiceID, "", "", "")
+		if err != nil {
+			return err
+		}
+		if service.Project != nil && t.ProjectID == "" {
+			t.ProjectID = service.Project.ID
+		}
+	}
+	if t.EnvironmentID == "" {
+		envID, err := util.ResolveEnvironmentID(s.client, t.ProjectID)
+		if err != nil {
+			return err
+		}
+		t.EnvironmentID = envID
+	}
+	return nil
+}
+
+// findService returns the service named name in the project, or nil.
+func (s *tools) findService(ctx context.Context, projectID, name string) (*model.Service, error) {
+	if projectID == "" {
+		return nil, errors.New("project_id is required to find a service by name: there is no current project")
+	}
+	services, err := s.client.ListAllServices(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if i := slices.IndexFunc(services, func(svc *model.Service) bool { return svc.Name == name }); i >= 0 {
+		return services[i], nil
+	}
+	return nil, nil
+}
+
+func (s *tools) list() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "get_context",
+			Description: "Show the workspace and the current project, service and environment that tools default to.",
+			ReadOnly:    true,
+			Handler:     s.getContext,
+		},
+		{
+			Name:        "list_projects",
+			Description: "List the projects of the workspace.",
+			ReadOnly:    true,
+			Handler:     s.listProjects,
+		},
+		{
+			Name:        "list_services",
+			Description: "List the services of a project.",
+			InputSchema: mcp.Object(map[string]mcp.Schema{
+				"project_id": mcp.String("Project ID; defaults to the current project"),
+			}),
+			ReadOnly: true,
+			Handler:  s.listServices,
+		},
+		{
+			Name: "deploy",
+			Description: "Upload the directory the server runs in and deploy it to a service. " +
+				"A service_name that does not exist in the project is created, as is one named after the directory when there is no current service.",
+			InputSchema: mcp.Object(targetProperties),
+			Handler:     s.deploy,
+		},
+		{
+			Name:        "get_logs",
+			Description: "Get the latest runtime or build logs of a service or deployment.",
+			InputSchema: mcp.Object(withTarget(map[string]mcp.Schema{
+				"deployment_id": mcp.String("Deployment ID; defaults to the latest deployment"),
+				"type":          mcp.Enum("Log type, default runtime", "runtime", "build"),
+				"limit":         mcp.Integer(fmt.Sprintf("Return at most this many of the latest lines, default %d", defaultLogLimit)),
+			})),
+			ReadOnly: true,
+			Handler:  s.getLogs,
+		},
+		{
+			Name: "watch_logs",
+			Description: "Follow the runtime or build logs of a service for a while and return the lines received. " +
+				"Lines are also sent as progress notifications when the client asks for them.",
+			InputSchema: mcp.Object(withTarget(map[string]mcp.Schema{
+				"deployment_id": mcp.String("Deployment ID; defaults to the latest deployment"),
+				"type":          mcp.Enum("Log type, default runtime", "runtime", "build"),
+				"seconds":       mcp.Integer(fmt.Sprintf("How long to watch, default %d, at most %d", int(defaultWatchPeriod.Seconds()), int(maxWatchPeriod.Seconds()))),
+			})),
+			ReadOnly: true,
+			Handler:  s.watchLogs,
+		},
+		{
+			Name:        "get_variables",
+			Description: "Get the environment variables of a service, and the variables other services expose to it.",
+			InputSchema: mcp.Object(targetProperties),
+			ReadOnly:    true,
+			Handler:     s.getVariables,
+		},
+		{
+			Name:        "set_variables",
+			Description: "Set and unset environment variables of a service. Other variables are kept.",
+			InputSchema: mcp.Object(withTarget(map[string]mcp.Schema{
+				"variables": mcp.StringMap("Variables to set"),
+				"unset":     mcp.StringArray("Keys of variables to remove"),
+			})),
+			Handler: s.setVariables,
+		},
+		{
+			Name:        "restart_service",
+			Description: "Restart a service.",
+			InputSchema: mcp.Object(targetProperties),
+			Destructive: true,
+			Handler:     s.restartService,
+		},
+		{
+			Name:        "exec",
+			Description: "Run a command in a service's container and return its output and exit code.",
+			InputSchema: mcp.Object(withTarget(map[string]mcp.Schema{
+				"command": mcp.StringArray("Command and arguments, e.g. [\"ls\", \"-la\"]"),
+			}), "command"),
+			Destructive: true,
+			Handler:     s.exec,
+		},
+	}
+}
+
+func (s *tools) getContext(context.Context, *mcp.Call) (any, error) {
+	return map[string]string{
+		"workspace":      s.workspace,
+		"owner_id":       s.ownerID,
+		"project_id":     s.projectID,
+		"service_id":     s.serviceID,
+		"environment_id": s.environment,
+	}, nil
+}
+
+type project struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Region string `json:"region"`
+}
+
+func (s *tools) listProjects(ctx context.Context, _ *mcp.Call) (any, error) {
+	projects, err := s.client.ListAllProjects(ctx, s.ownerID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]project, 0, len(projects))
+	for _, p := range projects {
+		out = append(out, project{ID: p.ID, Name: p.Name, Region: p.Region.ID})
+	}
+	return map[string]any{"projects": out}, nil
+}
+
+type service struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (s *tools) listServices(ctx context.Context, call *mcp.Call) (any, error) {
+	var args struct {
+		ProjectID string `json:"project_id"`
+	}
+	if err := call.Bind(&args); err != nil {
+		return nil, err
+	}
+	if args.ProjectID == "" {
+		args.ProjectID = s.projectID
+	}
+	if args.ProjectID == "" {
+		return nil, errors.New("project_id is required: there is no current project")
+	}
+
+	services, err := s.client.ListAllServices(ctx, args.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]service, 0, len(services))
+	for _, svc := range services {
+		out = append(out, service{ID: svc.ID, Name: svc.Name})
+	}
+	return map[string]any{"project_id": args.ProjectID, "services": out}, nil
+}
+
+func (s *tools) deploy(ctx context.Context, call *mcp.Call) (any, error) {
+	var t target
+	if err := call.Bind(&t); err != nil {
+		return nil, err
+	}
+
+	zipBytes, dir, err := util.PackZip()
+	if err != nil {
+		return nil, fmt.Errorf("packing zip: %w", err)
+	}
+
+	// a named service that does not exist yet is created, as is one named
+	// after the directory when there is neither a name nor a current service
+	created := false
+	if t.ServiceID == "" && (t.ServiceName != "" || s.serviceID == "") {
+		if t.ProjectID == "" {
+			t.ProjectID = s.projectID
+		}
+		name := t.ServiceName
+		if name == "" {
+			name = filepath.Base(dir)
+		}
+		svc, err := s.findService(ctx, t.ProjectID, name)
+		if err != nil {
+			return nil, err
+		}
+		if svc == nil {
+			if svc, err = s.client.CreateEmptyService(ctx, t.ProjectID, name); err != nil {
+				return nil, err
+			}
+			created = true
+		}
+		t.ServiceID = svc.ID
+	}
+	if err := s.resolve(ctx, &t); err != nil {
+		return nil, err
+	}
+
+	call.Progress("Uploading " + dir)
+	if _, err := s.client.UploadZipToService(ctx, t.ProjectID, t.ServiceID, t.EnvironmentID, zipBytes); err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"status":          "uploaded",
+		"project_id":      t.ProjectID,
+		"service_id":      t.ServiceID,
+		"environment_id":  t.EnvironmentID,
+		"service_created": created,
+		"next":            "follow the build with watch_logs type=build",
+	}, nil
+}
+
+type logArgs struct {
+	target
+	DeploymentID string `json:"deployment_id"`
+	Type         string `json:"type"`
+	Limit        int    `json:"limit"`
+	Seconds      int    `json:"seconds"`
+}
+
+// bindLogs decodes and resolves the arguments of the log tools. Build logs
+// belong to a deployment, the latest one unless given.
+func (s *tools) bindLogs(ctx context.Context, call *mcp.Call) (*logArgs, error) {
+	var args logArgs
+	if err := call.Bind(&args); err != nil {
+		return nil, err
+	}
+	switch args.Type {
+	case "":
+		args.Type = "runtime"
+	case "runtime", "build":
+	default:
+		return nil, fmt.Errorf("unknown log type %q, want runtime or build", args.Type)
+	}
+
+	if err := s.resolve(ctx, &args.target); err != nil {
+		return nil, err
+	}
+	if args.Type == "build" && args.DeploymentID == "" {
+		deployment, exist, err := s.client.GetLatestDeployment(ctx, args.ServiceID, args.EnvironmentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest deployment: %w", err)
+		}
+		if !exist {
+			return nil, errors.New("no build logs available: the service has no deployment")
+		}
+		args.DeploymentID = deployment.ID
+	}
+	return &args, nil
+}
+
+func (s *tools) getLogs(ctx context.Context, call *mcp.Call) (any, error) {
+	args, err := s.bindLogs(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs model.Logs
+	if args.Type == "build" {
+		logs, err = s.client.GetBuildLogs(ctx, args.DeploymentID)
+	} else {
+		logs, err = s.client.GetRuntimeLogs(ctx, args.ServiceID, args.EnvironmentID, args.DeploymentID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultLogLimit
+	}
+	if len(logs) > limit {
+		logs = logs[len(logs)-limit:]
+	}
+	if logs == nil {
+		logs = model.Logs{}
+	}
+	return map[string]any{"deployment_id": args.DeploymentID, "logs": logs}, nil
+}
+
+func (s *tools) watchLogs(ctx context.Context, call *mcp.Call) (any, error) {
+	args, err := s.bindLogs(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	period := time.Duration(args.Seconds) * time.Second
+	if period <= 0 {
+		period = defaultWatchPeriod
+	}
+	period = min(period, maxWatchPeriod)
+	ctx, cancel := context.WithTimeout(ctx, period)
+	defer cancel()
+
+	var (
+		logChan <-chan model.Log
+		errChan <-chan error
+	)
+	if args.Type == "build" {
+		logChan, errChan = s.client.WatchBuildLogs(ctx, args.ProjectID, args.DeploymentID)
+	} else {
+		logChan, errChan = s.client.WatchRuntimeLogs(ctx, args.ProjectID, args.ServiceID, args.EnvironmentID, args.DeploymentID)
+	}
+
+	logs := model.Logs{}
+	truncated := false
+	for l := range logChan {
+		call.Progress(l.Message)
+		if len(logs) == maxWatchLines {
+			logs, truncated = logs[1:], true
+		}
+		logs = append(logs, &l)
+	}
+	// the stream ending because the watch period is over is not an error
+	if err := <-errChan; err != nil && ctx.Err() == nil {
+		return nil, err
+	}
+	return map[string]any{"logs": logs, "truncated": truncated}, nil
+}
+
+func (s *tools) getVariables(ctx context.Context, call *mcp.Call) (any, error) {
+	var t target
+	if err := call.Bind(&t); err != nil {
+		return nil, err
+	}
+	if err := s.resolve(ctx, &t); err != nil {
+		return nil, err
+	}
+
+	vars, shared, err := s.client.ListVariables(ctx, t.ServiceID, t.EnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"service_id": t.ServiceID,
+		"variables":  vars.ToMap(),
+		"exposed":    shared.ToMap(),
+	}, nil
+}
+
+func (s *tools) setVariables(ctx context.Context, call *mcp.Call) (any, error) {
+	var args struct {
+		target
+		Variables map[string]string `json:"variables"`
+		Unset     []string          `json:"unset"`
+	}
+	if err := call.Bind(&args); err != nil {
+		return nil, err
+	}
+	if len(args.Variables) == 0 && len(args.Unset) == 0 {
+		return nil, errors.New("variables or unset is required")
+	}
+	if err := s.resolve(ctx, &args.target); err != nil {
+		return nil, err
+	}
+
+	// the mutation replaces every variable, so merge into the current ones
+	current, _, err := s.client.ListVariables(ctx, args.ServiceID, args.EnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+	merged := current.ToMap()
+	maps.Copy(merged, args.Variables)
+	for _, k := range args.Unset {
+		delete(merged, k)
+	}
+
+	ok, err := s.client.UpdateVariables(ctx, args.ServiceID, args.EnvironmentID, merged)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("failed to update variables")
+	}
+	return map[string]any{
+		"service_id": args.ServiceID,
+		"set":        slices.Sorted(maps.Keys(args.Variables)),
+		"unset":      args.Unset,
+	}, nil
+}
+
+func (s *tools) restartService(ctx context.Context, call *mcp.Call) (any, error) {
+	var t target
+	if err := call.Bind(&t); err != nil {
+		return nil, err
+	}
+	if err := s.resolve(ctx, &t); err != nil {
+		return nil, err
+	}
+	if err := s.client.RestartService(ctx, t.ServiceID, t.EnvironmentID); err != nil {
+		return nil, err
+	}
+	return map[string]string{"status": "restarted", "service_id": t.ServiceID, "environment_id": t.EnvironmentID}, nil
+}
+
+func (s *tools) exec(ctx context.Context, call *mcp.Call) (any, error) {
+	var args struct {
+		target
+		Command []string `json:"command"`
+	}
+	if err := call.Bind(&args); err != nil {
+		return nil, err
+	}
+	if len(args.Command) == 0 {
+		return nil, errors.New("command is required")
+	}
+	if err := s.resolve(ctx, &args.target); err != nil {
+		return nil, err
+	}
+
+	result, err := s.client.ExecuteCommand(ctx, args.ServiceID, args.EnvironmentID, args.Command)
+	if err != nil {
+		return nil, fmt.Errorf("execute command failed: %w", err)
+	}
+	return map[string]any{"output": result.Output, "exit_code": result.ExitCode}, nil
+}
@@ -0,0 +1,21 @@
+// Package metrics contains the cmd for exporting service metrics
+package metrics
+
+import (
+	"github.com/spf13/cobra"
+
+	metricsServeCmd "github.com/zeabur/cli/internal/cmd/metrics/serve"
+	"github.com/zeabur/cli/internal/cmdutil"
+)
+
+// NewCmdMetrics builds the `zeabur metrics` parent command.
+func NewCmdMetrics(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metrics <command>",
+		Short: "Export the metrics of services to monitoring systems",
+	}
+
+	cmd.AddCommand(metricsServeCmd.NewCmdServe(f))
+
+	return cmd
+}
@@ -0,0 +1,20 @@
+package serve
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+)
+
+// NewExporter resolves the projects and environments the way `zeabur
+// metrics serve` does and returns the exporter, without serving it, and a
+// function that refreshes it.
+func NewExporter(ctx context.Context, f *cmdutil.Factory, projectIDs, environmentIDs []string) (http.Handler, func(context.Context), error) {
+	targets, err := resolveTargets(ctx, f, &Options{projectIDs: projectIDs, environmentIDs: environmentIDs})
+	if err != nil {
+		return nil, nil, err
+	}
+	e := newExporter(f.ApiClient, f.Log, targets)
+	return e, e.refresh, nil
+}
@@ -0,0 +1,206 @@
+package serve
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+const (
+	// metricWindow is how far back each refresh asks for points; only the
+	// latest one is exported.
+	metricWindow = 10 * time.Minute
+	// concurrency caps the ServiceMetric calls in flight during a refresh.
+	concurrency = 8
+)
+
+// gauges are the exported service metrics, in the units the API reports.
+var gauges = []struct {
+	metricType model.MetricType
+	name       string
+	help       string
+}{
+	{model.MetricTypeCPU, "zeabur_service_cpu_usage_ratio", "CPU usage of the service as a ratio, 1 being 100%."},
+	{model.MetricTypeMemory, "zeabur_service_memory_usage_megabytes", "Memory usage of the service in MB."},
+	{model.MetricTypeNetwork, "zeabur_service_network_megabytes", "Network traffic of the service in MB."},
+}
+
+// target is an environment of a project whose services are exported.
+type target struct {
+	project, projectID         string
+	environment, environmentID string
+}
+
+type sample struct {
+	metricType         model.MetricType
+	target             target
+	service, serviceID string
+	value              float64
+}
+
+// exporter polls the metrics of the services of its targets and serves the
+// latest values to Prometheus. Scrapes are answered from the last refresh,
+// so they never call the API.
+type exporter struct {
+	client  api.Client
+	log     *zap.SugaredLogger
+	targets []target
+
+	mu        sync.RWMutex
+	samples   []sample
+	refreshed time.Time
+	duration  time.Duration
+	errors    int
+}
+
+func newExporter(client api.Client, log *zap.SugaredLogger, targets []target) *exporter {
+	return &exporter{client: client, log: log, targets: targets}
+}
+
+// run refreshes the metrics every interval until ctx is done.
+func (e *exporter) run(ctx context.Context, interval time.Duration) {
+	for {
+		e.refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// refresh fetches the latest metrics of every service of the targets.
+// Services whose metrics cannot be fetched are left out until the next
+// refresh, rather than exported with stale values.
+func (e *exporter) refresh(ctx context.Context) {
+	began := time.Now()
+	var (
+		mu      sync.Mutex
+		samples []sample
+		failed  int
+		wg      sync.WaitGroup
+		slots   = make(chan struct{}, concurrency)
+	)
+	fail := func(format string, args ...any) {
+		mu.Lock()
+		failed++
+		mu.Unlock()
+		if ctx.Err() == nil {
+			e.log.Warnf(format, args...)
+		}
+	}
+
+	end := time.Now()
+	for _, t := range e.targets {
+		services, err := e.client.ListAllServicesDetailByEnvironment(ctx, t.projectID, t.environmentID)
+		if err != nil {
+			fail("list services of %s/%s: %v", t.project, t.environment, err)
+			continue
+		}
+		for _, svc := range services {
+			for _, g := range gauges {
+				wg.Add(1)
+				slots <- struct{}{}
+				go func() {
+					defer func() { <-slots; wg.Done() }()
+					metric, err := e.client.ServiceMetric(ctx, svc.ID, t.projectID, t.environmentID, string(g.metricType), end.Add(-metricWindow), end)
+					if err != nil {
+						fail("get %s metric of %s in %s/%s: %v", g.metricType, svc.Name, t.project, t.environment, err)
+						return
+					}
+					if len(metric.Metrics) == 0 {
+						return
+					}
+					mu.Lock()
+					defer mu.Unlock()
+					samples = append(samples, sample{
+						metricType: g.metricType,
+						target:     t,
+						service:    svc.Name,
+						serviceID:  svc.ID,
+						value:      metric.Metrics[len(metric.Metrics)-1].Value,
+					})
+				}()
+			}
+		}
+	}
+	wg.Wait()
+
+	// a stable order keeps the output diffable
+	slices.SortFunc(samples, func(a, b sample) int {
+		return strings.Compare(
+			strings.Join([]string{a.target.project, a.target.environment, a.service, a.serviceID}, "\x00"),
+			strings.Join([]string{b.target.project, b.target.environment, b.service, b.serviceID}, "\x00"),
+		)
+	})
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.samples = samples
+	e.refreshed = time.Now()
+	e.duration = time.Since(began)
+	e.errors += failed
+}
+
+// ServeHTTP writes the metrics of the last refresh in the Prometheus text
+// exposition format.
+func (e *exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = e.write(w)
+}
+
+func (e *exporter) write(w io.Writer) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var b strings.Builder
+	for _, g := range gauges {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", g.name, g.help, g.name)
+		for _, s := range e.samples {
+			if s.metricType != g.metricType {
+				continue
+			}
+			fmt.Fprintf(&b, "%s{project=%s,project_id=%s,environment=%s,environment_id=%s,service=%s,service_id=%s} %s\n",
+				g.name,
+				quote(s.target.project), quote(s.target.projectID),
+				quote(s.target.environment), quote(s.target.environmentID),
+				quote(s.service), quote(s.serviceID),
+				formatValue(s.value))
+		}
+	}
+
+	if !e.refreshed.IsZero() {
+		b.WriteString("# HELP zeabur_exporter_last_refresh_timestamp_seconds When the metrics were last fetched from Zeabur.\n")
+		b.WriteString("# TYPE zeabur_exporter_last_refresh_timestamp_seconds gauge\n")
+		fmt.Fprintf(&b, "zeabur_exporter_last_refresh_timestamp_seconds %s\n", formatValue(float64(e.refreshed.UnixMilli())/1000))
+		b.WriteString("# HELP zeabur_exporter_refresh_duration_seconds How long the last refresh took.\n")
+		b.WriteString("# TYPE zeabur_exporter_refresh_duration_seconds gauge\n")
+		fmt.Fprintf(&b, "zeabur_exporter_refresh_duration_seconds %s\n", formatValue(e.duration.Seconds()))
+	}
+	b.WriteString("# HELP zeabur_exporter_api_errors_total Failed Zeabur API calls while refreshing.\n")
+	b.WriteString("# TYPE zeabur_exporter_api_errors_total counter\n")
+	fmt.Fprintf(&b, "zeabur_exporter_api_errors_total %d\n", e.errors)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// quote quotes a label value, escaping as the exposition format requires.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,164 @@
+// Package serve implements `zeabur metrics serve`.
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/api"
+)
+
+type Options struct {
+	listen         string
+	projectIDs     []string
+	environmentIDs []string
+	interval       time.Duration
+}
+
+// NewCmdServe builds `zeabur metrics serve`.
+func NewCmdServe(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the CPU, memory and network usage of services to Prometheus",
+		Long: heredoc.Doc(`
+			Serve the latest CPU, memory and network usage of every service of one or
+			more projects on /metrics, in the Prometheus text format. Each value is a
+			gauge labeled with the project, environment and service, by name and ID.
+
+			Metrics are fetched from Zeabur every --interval and cached in between,
+			so scrapes never call the API, however often Prometheus scrapes.
+
+			Without --project-id, the current project is exported. Without --env-id,
+			every environment of the projects is.
+		`),
+		Example: heredoc.Doc(`
+			zeabur metrics serve --listen :9100 --project-id 65f0c1e2a3b4c5d6e7f80912
+
+			# in prometheus.yml
+			scrape_configs:
+			  - job_name: zeabur
+			    static_configs:
+			      - targets: ["localhost:9100"]
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe(cmd.Context(), f, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.listen, "listen", ":9100", "Address to serve /metrics on")
+	cmd.Flags().StringArrayVar(&opts.projectIDs, "project-id", nil, "Project to export, can be repeated; defaults to the current project")
+	cmd.Flags().StringArrayVar(&opts.environmentIDs, "env-id", nil, "Environment to export, can be repeated; defaults to every environment of the projects")
+	cmd.Flags().DurationVar(&opts.interval, "interval", time.Minute, "How often to fetch the metrics from Zeabur")
+
+	return cmd
+}
+
+func runServe(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
+	if opts.interval < time.Second {
+		return errors.New("--interval must be at least 1s")
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	targets, err := resolveTargets(ctx, f, opts)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", opts.listen)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", opts.listen, err)
+	}
+
+	exporter := newExporter(f.ApiClient, f.Log, targets)
+	go exporter.run(ctx, opts.interval)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", exporter)
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/metrics", http.StatusFound)
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	f.Log.Infof("Serving the metrics of %d environment(s) on http://%s/metrics, refreshed every %s", len(targets), listener.Addr(), opts.interval)
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// resolveTargets looks up the projects and environments to export.
+func resolveTargets(ctx context.Context, f *cmdutil.Factory, opts *Options) ([]target, error) {
+	projectIDs := opts.projectIDs
+	if len(projectIDs) == 0 {
+		if id := f.CurrentProjectID(); id != "" {
+			projectIDs = []string{id}
+		}
+	}
+	if len(projectIDs) == 0 {
+		return nil, errors.New("--project-id is required: there is no current project")
+	}
+
+	// the root command only strips ID prefixes from single-valued flags
+	environmentIDs := make([]string, 0, len(opts.environmentIDs))
+	for _, id := range opts.environmentIDs {
+		environmentIDs = append(environmentIDs, string(api.ObjectID(id)))
+	}
+
+	var targets []target
+	found := map[string]bool{}
+	for _, id := range projectIDs {
+		project, err := f.ApiClient.GetProject(ctx, string(api.ObjectID(id)), "", "")
+		if err != nil {
+			return nil, fmt.Errorf("get project %s: %w", id, err)
+		}
+		environments, err := f.ApiClient.ListEnvironments(ctx, project.ID)
+		if err != nil {
+			return nil, fmt.Errorf("list environments of %s: %w", project.Name, err)
+		}
+		for _, env := range environments {
+			if len(environmentIDs) > 0 && !slices.Contains(environmentIDs, env.ID) {
+				continue
+			}
+			found[env.ID] = true
+			targets = append(targets, target{
+				project:       project.Name,
+				projectID:     project.ID,
+				environment:   env.Name,
+				environmentID: env.ID,
+			})
+		}
+	}
+
+	for i, id := range environmentIDs {
+		if !found[id] {
+			return nil, fmt.Errorf("environment %s is not in any of the exported projects", opts.environmentIDs[i])
+		}
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("the projects have no environments to export")
+	}
+	return targets, nil
+}
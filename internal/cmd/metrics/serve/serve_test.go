@@ -0,0 +1,117 @@
+package serve_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zeabur/cli/internal/cmd/metrics/serve"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// seedMetric makes every metric of every service end with value.
+func seedMetric(t *testing.T, h *cmdtest.Harness, value float64) {
+	t.Helper()
+	h.API.Metrics = &model.ServiceMetric{}
+	data := fmt.Sprintf(`{"metrics": [{"timestamp": "2024-05-01T10:00:00Z", "value": 0}, {"timestamp": "2024-05-01T10:01:00Z", "value": %g}]}`, value)
+	if err := json.Unmarshal([]byte(data), h.API.Metrics); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func scrape(t *testing.T, handler http.Handler) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func TestExporter_ServesCachedGauges(t *testing.T) {
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "api")
+	h.API.SeedEnvironment(project.ID, "staging")
+	web := h.API.SeedService(project.ID, "web")
+	h.API.SeedService(project.ID, "worker")
+	seedMetric(t, h, 0.25)
+
+	ctx := context.Background()
+	handler, refresh, err := serve.NewExporter(ctx, h.Factory, []string{project.ID}, []string{"environment-" + env.ID})
+	if err != nil {
+		t.Fatalf("new exporter: %v", err)
+	}
+	refresh(ctx)
+
+	body := scrape(t, handler)
+	want := fmt.Sprintf(`zeabur_service_cpu_usage_ratio{project="api",project_id=%q,environment="production",environment_id=%q,service="web",service_id=%q} 0.25`, project.ID, env.ID, web.ID)
+	if !strings.Contains(body, want+"\n") {
+		t.Errorf("metrics lack\n%s\ngot:\n%s", want, body)
+	}
+	for _, name := range []string{"# TYPE zeabur_service_memory_usage_megabytes gauge", "# TYPE zeabur_service_network_megabytes gauge", `service="worker"`, "zeabur_exporter_api_errors_total 0"} {
+		if !strings.Contains(body, name) {
+			t.Errorf("metrics lack %q", name)
+		}
+	}
+	if strings.Contains(body, `environment="staging"`) {
+		t.Error("exported an environment left out by --env-id")
+	}
+
+	// scrapes are answered from the cache
+	scrape(t, handler)
+	scrape(t, handler)
+	if calls := h.API.CallsTo("ServiceMetric"); len(calls) != 6 {
+		t.Errorf("ServiceMetric called %d times, want 6: 2 services × 3 metrics, once", len(calls))
+	}
+}
+
+func TestExporter_CountsErrors(t *testing.T) {
+	h := cmdtest.New()
+	project, _ := h.API.SeedProject("", "api")
+	h.API.SeedService(project.ID, "web")
+	h.API.FailOn("ServiceMetric", errors.New("boom"))
+
+	ctx := context.Background()
+	handler, refresh, err := serve.NewExporter(ctx, h.Factory, []string{project.ID}, nil)
+	if err != nil {
+		t.Fatalf("new exporter: %v", err)
+	}
+	refresh(ctx)
+	refresh(ctx)
+
+	body := scrape(t, handler)
+	if !strings.Contains(body, "zeabur_exporter_api_errors_total 6\n") {
+		t.Errorf("errors not counted:\n%s", body)
+	}
+	if strings.Contains(body, "zeabur_service_cpu_usage_ratio{") {
+		t.Errorf("exported a service whose metrics failed:\n%s", body)
+	}
+}
+
+func TestExporter_UnknownEnvironment(t *testing.T) {
+	h := cmdtest.New()
+	project, _ := h.API.SeedProject("", "api")
+
+	_, _, err := serve.NewExporter(context.Background(), h.Factory, []string{project.ID}, []string{"65aa1234567890abcdef1234"})
+	if err == nil || !strings.Contains(err.Error(), "not in any of the exported projects") {
+		t.Fatalf("error = %v", err)
+	}
+}
+
+func TestServe_RequiresProject(t *testing.T) {
+	h := cmdtest.New()
+
+	err := h.Run(serve.NewCmdServe(h.Factory), "--listen", "127.0.0.1:0")
+	if err == nil || !strings.Contains(err.Error(), "--project-id is required") {
+		t.Fatalf("error = %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+// Package plan provides the plan command
+package plan
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/manifest"
+)
+
+type Options struct {
+	file string
+	diff manifest.DiffOptions
+}
+
+func NewCmdPlan(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Show the changes needed to converge a project to its manifest",
+		Long: heredoc.Doc(`
+			Compare a project manifest (zeabur.yaml) with the live project and
+			print the services, variables, domains, port-forwarding modes and
+			image tags that 'zeabur apply' would create, update or delete.
+			Variable values are never printed.
+
+			The current image tag of a service cannot be read, so image tags are
+			only planned for new services, or for all of them with
+			--force-image-tags.
+		`),
+		Example: heredoc.Doc(`
+			$ zeabur plan
+			$ zeabur plan -f deploy/production.yaml --prune
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPlan(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.file, "file", "f", manifest.DefaultFile, "Path to the manifest file")
+	AddDiffFlags(cmd, &opts.diff)
+
+	return cmd
+}
+
+func runPlan(f *cmdutil.Factory, opts *Options) error {
+	_, _, plan, err := Compute(f, opts.file, opts.diff)
+	if err != nil {
+		return err
+	}
+
+	if f.StructuredOutput() {
+		return f.Printer.Data(plan)
+	}
+
+	plan.Render(os.Stdout)
+	return nil
+}
+
+// AddDiffFlags adds the flags tuning the plan, shared with `zeabur apply`.
+func AddDiffFlags(cmd *cobra.Command, opts *manifest.DiffOptions) {
+	cmd.Flags().BoolVar(&opts.Prune, "prune", false, "Delete services that are not declared in the manifest")
+	cmd.Flags().BoolVar(&opts.ForceImageTags, "force-image-tags", false, "Re-apply the image tags of existing services")
+}
+
+// Compute loads the manifest at file, fetches the live state of the project
+// it targets and diffs the two. It is shared with `zeabur apply`, which
+// executes the returned plan.
+func Compute(f *cmdutil.Factory, file string, opts manifest.DiffOptions) (*manifest.Manifest, *manifest.State, *manifest.Plan, error) {
+	m, err := manifest.Load(file)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	projectID, environmentID, err := util.ResolveManifestTarget(f.ApiClient, f.CurrentOwnerID(), f.Config.GetUsername(), m)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	s := spinner.New(cmdutil.SpinnerCharSet, cmdutil.SpinnerInterval,
+		spinner.WithColor(cmdutil.SpinnerColor),
+		spinner.WithSuffix(" Fetching live state ..."),
+	)
+	s.Start()
+	state, err := manifest.FetchState(context.Background(), f.ApiClient, projectID, environmentID, m)
+	s.Stop()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("fetch live state: %w", err)
+	}
+
+	return m, state, manifest.Diff(m, state, opts), nil
+}
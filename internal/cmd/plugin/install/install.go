@@ -0,0 +1,87 @@
+// Package install implements `zeabur plugin install`.
+package install
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/plugin"
+	"github.com/zeabur/cli/pkg/printer"
+)
+
+type Options struct {
+	source string
+	name   string
+	force  bool
+}
+
+// NewCmdInstall builds `zeabur plugin install`.
+func NewCmdInstall(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "install <path>",
+		Short: "Install a plugin from an executable or a tarball",
+		Long: heredoc.Doc(`
+			Install a plugin into the plugins directory.
+
+			The path is an executable named zeabur-<name>, or any executable with
+			--name, or a .tar, .tar.gz or .tgz archive whose zeabur-* files are all
+			installed.
+		`),
+		Example: heredoc.Doc(`
+			$ zeabur plugin install ./zeabur-backup
+			$ zeabur plugin install ./backup.sh --name backup
+			$ zeabur plugin install zeabur-backup_linux_amd64.tar.gz
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.source = args[0]
+			return runInstall(f, opts, cmd.Root())
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.name, "name", "", "Install a single executable as zeabur-<name>")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Replace a plugin that is already installed")
+
+	return cmd
+}
+
+func runInstall(f *cmdutil.Factory, opts *Options, root *cobra.Command) error {
+	if opts.name != "" {
+		if err := plugin.ValidateName(opts.name); err != nil {
+			return err
+		}
+		if isBuiltin(root, opts.name) {
+			return fmt.Errorf("%q is a built-in command; choose another name", opts.name)
+		}
+	}
+
+	dir, err := plugin.DefaultDir()
+	if err != nil {
+		return err
+	}
+	installed, err := plugin.Install(dir, opts.source, opts.name, opts.force)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range installed {
+		if isBuiltin(root, p.Name) {
+			f.Log.Warnf("Plugin %q is installed but shadowed by the built-in command of the same name", p.Name)
+			continue
+		}
+		f.Log.Infof("Plugin %q installed, run it with `zeabur %s`", p.Name, p.Name)
+	}
+	return f.Printer.Data(printer.Result(installed))
+}
+
+// isBuiltin reports whether name is a command of the CLI itself rather
+// than an already registered plugin.
+func isBuiltin(root *cobra.Command, name string) bool {
+	sub, _, err := root.Find([]string{name})
+	return err == nil && sub != root && sub.GroupID != "plugin"
+}
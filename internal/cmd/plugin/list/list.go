@@ -0,0 +1,46 @@
+// Package list implements `zeabur plugin list`.
+package list
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/plugin"
+	"github.com/zeabur/cli/pkg/printer"
+)
+
+// NewCmdList builds `zeabur plugin list`.
+func NewCmdList(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List installed plugins and plugins on PATH",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(f)
+		},
+	}
+}
+
+func runList(f *cmdutil.Factory) error {
+	dir, err := plugin.DefaultDir()
+	if err != nil {
+		return err
+	}
+	plugins := plugin.Discover(dir)
+
+	if len(plugins) == 0 {
+		f.Log.Infof("No plugins found; install one with `zeabur plugin install`")
+	}
+
+	header := []string{"Name", "Source", "Path"}
+	rows := make([][]string, 0, len(plugins))
+	for _, p := range plugins {
+		source := "PATH"
+		if p.Installed {
+			source = "installed"
+		}
+		rows = append(rows, []string{p.Name, source, p.Path})
+	}
+	return f.Printer.Data(printer.WithTable(plugins, header, rows))
+}
@@ -0,0 +1,37 @@
+// Package plugin contains the cmd for managing zeabur-<name> plugins
+package plugin
+
+import (
+	"github.com/spf13/cobra"
+
+	pluginInstallCmd "github.com/zeabur/cli/internal/cmd/plugin/install"
+	pluginListCmd "github.com/zeabur/cli/internal/cmd/plugin/list"
+	pluginRemoveCmd "github.com/zeabur/cli/internal/cmd/plugin/remove"
+	"github.com/zeabur/cli/internal/cmdutil"
+)
+
+// NewCmdPlugin builds the `zeabur plugin` parent command.
+func NewCmdPlugin(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin <command>",
+		Short: "Manage CLI plugins",
+		Long: `Manage CLI plugins.
+
+A plugin is an executable named zeabur-<name>, found in the plugins
+directory or on PATH, that runs as ` + "`zeabur <name>`" + `. It receives the
+resolved token, API URL, workspace, project, environment and service in
+ZEABUR_* environment variables. Built-in commands take precedence over
+plugins of the same name.
+
+The plugins directory is "plugins" next to the config file, or
+$ZEABUR_PLUGIN_DIR.`,
+	}
+
+	cmdutil.DisableAuthCheck(cmd)
+
+	cmd.AddCommand(pluginInstallCmd.NewCmdInstall(f))
+	cmd.AddCommand(pluginListCmd.NewCmdList(f))
+	cmd.AddCommand(pluginRemoveCmd.NewCmdRemove(f))
+
+	return cmd
+}
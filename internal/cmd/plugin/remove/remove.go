@@ -0,0 +1,71 @@
+// Package remove implements `zeabur plugin remove`.
+package remove
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/plugin"
+	"github.com/zeabur/cli/pkg/printer"
+)
+
+type Options struct {
+	name string
+	yes  bool
+}
+
+// NewCmdRemove builds `zeabur plugin remove`.
+func NewCmdRemove(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:     "remove <name>",
+		Short:   "Remove an installed plugin",
+		Aliases: []string{"rm"},
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			return runRemove(f, opts)
+		},
+		ValidArgsFunction: func(*cobra.Command, []string, string) ([]cobra.Completion, cobra.ShellCompDirective) {
+			dir, _ := plugin.DefaultDir()
+			var names []cobra.Completion
+			for _, p := range plugin.Discover(dir) {
+				if p.Installed {
+					names = append(names, p.Name)
+				}
+			}
+			return names, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip confirmation")
+
+	return cmd
+}
+
+func runRemove(f *cmdutil.Factory, opts *Options) error {
+	if f.Interactive && !opts.yes {
+		confirm, err := f.Prompter.Confirm(fmt.Sprintf("Are you sure you want to remove plugin %q?", opts.name), false)
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			f.Log.Info("Remove plugin canceled")
+			return nil
+		}
+	}
+
+	dir, err := plugin.DefaultDir()
+	if err != nil {
+		return err
+	}
+	if err := plugin.Remove(dir, opts.name); err != nil {
+		return err
+	}
+
+	f.Log.Infof("Plugin %q removed", opts.name)
+	return f.Printer.Data(printer.Result(map[string]string{"removed": opts.name}))
+}
@@ -219,7 +219,7 @@ func doClone(f *cmdutil.Factory, opts *Options) error {
 		if completed {
 			f.Log.Infof("Project cloned successfully!")
 			f.Log.Infof("New project ID: %s", result.NewProjectID)
-			f.Log.Infof("Dashboard: https://dash.zeabur.com/projects/%s", result.NewProjectID)
+			f.Log.Infof("Dashboard: %s/projects/%s", f.EffectiveLegacyDashURL(), result.NewProjectID)
 			return nil
 		}
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
 	"github.com/zeabur/cli/pkg/zcontext"
 
 	"github.com/briandowns/spinner"
@@ -110,10 +111,8 @@ func createProject(f *cmdutil.Factory, projectRegion string, projectName *string
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(map[string]string{"status": "success", "id": project.ID, "name": project.Name, "message": "Project created"})
-	}
 	f.Log.Infof("Project %s created", project.Name)
+	result := printer.Result(map[string]string{"status": "success", "id": project.ID, "name": project.Name, "message": "Project created"})
 	// `--workspace` is a stateless one-shot override (PLA-1590 B+): writing
 	// the newly-created project (which belongs to the override workspace)
 	// into the persisted context would silently pin a team-B project under
@@ -122,14 +121,14 @@ func createProject(f *cmdutil.Factory, projectRegion string, projectName *string
 	// it explicitly after `workspace switch` if they want.
 	if f.HasWorkspaceOverride() {
 		f.Log.Infof("(persistent project context not modified — `--workspace` override is one-shot; run `zeabur workspace switch %s` to make it your default)", f.CurrentWorkspace().Name)
-		return nil
+		return f.Printer.Data(result)
 	}
 	err = setProject(f, project.ID, project.Name)
 	if err != nil {
 		f.Log.Error(err)
 		return err
 	}
-	return nil
+	return f.Printer.Data(result)
 }
 
 func paramCheck(opts *Options) error {
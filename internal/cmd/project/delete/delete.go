@@ -8,6 +8,7 @@ import (
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
 	"github.com/zeabur/cli/pkg/zcontext"
 )
 
@@ -114,11 +115,8 @@ func deleteProject(f *cmdutil.Factory, project *model.Project) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(map[string]string{"status": "success", "id": project.ID, "name": project.Name, "message": "Project deleted successfully"})
-	}
 	f.Log.Infof("Delete project %s (%s) successfully", project.Name, project.ID)
-	return nil
+	return f.Printer.Data(printer.Result(map[string]string{"status": "success", "id": project.ID, "name": project.Name, "message": "Project deleted successfully"}))
 }
 
 func checkParams(opts *Options) error {
@@ -35,11 +35,5 @@ func runList(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(projects)
-	}
-
-	f.Printer.Table(projects.Header(), projects.Rows())
-
-	return nil
+	return f.Printer.Data(projects)
 }
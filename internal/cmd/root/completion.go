@@ -0,0 +1,210 @@
+package root
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/completion"
+)
+
+// registerCompletions wires dynamic completion into every command below
+// root: the flags and positional arguments that name a resource (see
+// util.FlagCompletion and util.ArgsCompletion) complete from the API,
+// scoped by the current context and workspace.
+func registerCompletions(f *cmdutil.Factory, root *cobra.Command) {
+	c := &completer{f: f}
+
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		// Flags holds the command's local and persistent flags, so every
+		// flag, including root's --workspace, is seen exactly once
+		cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+			if kind := util.FlagCompletion(flag); kind != "" {
+				_ = cmd.RegisterFlagCompletionFunc(flag.Name, c.complete(kind))
+			}
+		})
+		if kind := util.ArgsCompletion(cmd); kind != "" && cmd.ValidArgsFunction == nil {
+			complete := c.complete(kind)
+			cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+				if len(args) > 0 {
+					return nil, cobra.ShellCompDirectiveNoFileComp
+				}
+				return complete(cmd, args, toComplete)
+			}
+		}
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+}
+
+// completer produces completion candidates. Completion runs as the hidden
+// __complete command, which skips the auth check of PersistentPreRunE, so
+// the client is set up lazily here, and never by opening a browser.
+type completer struct {
+	f     *cmdutil.Factory
+	cache *completion.Cache
+	ready bool
+}
+
+func (c *completer) complete(kind string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, _ []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		items, err := c.candidates(cmd, kind)
+		if err != nil {
+			cobra.CompDebugln("zeabur: "+err.Error(), true)
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var out []cobra.Completion
+		for _, item := range items {
+			if strings.HasPrefix(item, toComplete) {
+				out = append(out, item)
+			}
+		}
+		return out, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// candidates returns the values of kind, as "value\tdescription" where a
+// description helps, e.g. the name of a project ID.
+func (c *completer) candidates(cmd *cobra.Command, kind string) ([]string, error) {
+	f := c.f
+	if kind == util.CompleteProfile {
+		return f.Config.ListProfiles(), nil
+	}
+	if !c.prepare() {
+		return nil, nil
+	}
+
+	projectID := ""
+	switch kind {
+	case util.CompleteService, util.CompleteServiceName, util.CompleteEnvironment:
+		if flag := cmd.Flags().Lookup("project-id"); flag != nil && flag.Changed {
+			projectID = flag.Value.String()
+		} else {
+			projectID = f.CurrentProjectID()
+		}
+		if projectID == "" {
+			return nil, nil
+		}
+	}
+
+	ownerID := f.CurrentOwnerID()
+	fetch := func() ([]string, error) {
+		return c.fetch(kind, ownerID, projectID)
+	}
+	if c.cache == nil {
+		return fetch()
+	}
+	// the token is part of the key so that another login never sees the
+	// previous account's resources; the key is hashed on disk
+	key := strings.Join([]string{f.EffectiveAPIURL(), f.Config.GetTokenString(), ownerID, kind, projectID}, "\x00")
+	return c.cache.Get(key, fetch)
+}
+
+func (c *completer) fetch(kind, ownerID, projectID string) ([]string, error) {
+	ctx := context.Background()
+	client := c.f.ApiClient
+
+	var items []string
+	switch kind {
+	case util.CompleteProject, util.CompleteProjectName:
+		projects, err := client.ListAllProjects(ctx, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			if kind == util.CompleteProjectName {
+				items = append(items, p.Name)
+			} else {
+				items = append(items, p.ID+"\t"+p.Name)
+			}
+		}
+	case util.CompleteService, util.CompleteServiceName:
+		services, err := client.ListAllServices(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range services {
+			if kind == util.CompleteServiceName {
+				items = append(items, s.Name)
+			} else {
+				items = append(items, s.ID+"\t"+s.Name)
+			}
+		}
+	case util.CompleteEnvironment:
+		envs, err := client.ListEnvironments(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range envs {
+			items = append(items, e.ID+"\t"+e.Name)
+		}
+	case util.CompleteServer:
+		servers, err := client.ListServers(ctx, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range servers {
+			items = append(items, s.ID+"\t"+s.Name)
+		}
+	case util.CompleteRegisteredDomain, util.CompleteRegisteredDomainName:
+		domains, err := client.ListRegisteredDomains(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range domains {
+			if kind == util.CompleteRegisteredDomainName {
+				items = append(items, d.Domain)
+			} else {
+				items = append(items, d.ID+"\t"+d.Domain)
+			}
+		}
+	case util.CompleteWorkspace:
+		teams, err := c.f.ListTeams(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range teams {
+			items = append(items, t.Name+"\t"+t.ID)
+		}
+	}
+	return items, nil
+}
+
+// prepare sets up what PersistentPreRunE would have: the auth profile, the
+// API client and the --workspace override. It reports false when there is
+// nothing to complete from, e.g. when the user is not logged in.
+func (c *completer) prepare() bool {
+	if c.ready {
+		return true
+	}
+	f := c.f
+
+	if f.ApiClient == nil {
+		if err := resolveProfileFlag(f); err != nil {
+			cobra.CompDebugln("zeabur: "+err.Error(), true)
+			return false
+		}
+		if !f.LoggedIn() {
+			return false
+		}
+		f.ApiClient = f.NewApiClient(f.Config.GetTokenString())
+	}
+	if err := resolveWorkspaceFlag(f); err != nil {
+		cobra.CompDebugln("zeabur: "+err.Error(), true)
+		return false
+	}
+
+	if dir, err := completion.DefaultDir(); err == nil {
+		c.cache = completion.NewCache(dir, completion.DefaultTTL)
+	}
+	c.ready = true
+	return true
+}
@@ -0,0 +1,80 @@
+package root_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zeabur/cli/internal/cmd/root"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/pkg/zcontext"
+)
+
+// complete runs the hidden __complete command the shell scripts call and
+// returns the candidates it printed.
+func complete(t *testing.T, h *cmdtest.Harness, args ...string) []string {
+	t.Helper()
+
+	cmd, err := root.NewCmdRoot(h.Factory, "dev", "none", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := h.Run(cmd, append([]string{"__complete"}, args...)...); err != nil {
+		t.Fatalf("__complete %v: %v", args, err)
+	}
+
+	var candidates []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if !strings.HasPrefix(line, ":") {
+			candidates = append(candidates, line)
+		}
+	}
+	return candidates
+}
+
+func TestCompletion_ServicesOfCurrentProject(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	h := cmdtest.New()
+	project, _ := h.API.SeedProject("", "api")
+	web := h.API.SeedService(project.ID, "web")
+	h.API.SeedService(project.ID, "worker")
+	other, _ := h.API.SeedProject("", "other")
+	h.API.SeedService(other.ID, "elsewhere")
+	h.Config.GetContext().SetProject(zcontext.NewBasicInfo(project.ID, project.Name))
+
+	got := complete(t, h, "service", "get", "--id", "")
+	if len(got) != 2 || got[0] != web.ID+"\tweb" {
+		t.Fatalf("candidates = %q, want the two services of the current project", got)
+	}
+
+	got = complete(t, h, "service", "get", "--name", "w")
+	if len(got) != 2 {
+		t.Fatalf("candidates = %q, want web and worker", got)
+	}
+
+	// --project-id scopes the candidates instead of the context
+	got = complete(t, h, "link", "--project-id", other.ID, "--service-id", "")
+	if len(got) != 1 || !strings.HasSuffix(got[0], "\telsewhere") {
+		t.Fatalf("candidates = %q, want the service of the flagged project", got)
+	}
+}
+
+func TestCompletion_CachesResults(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	h := cmdtest.New()
+	h.API.SeedProject("", "api")
+	h.API.SeedProject("", "web")
+
+	for range 2 {
+		if got := complete(t, h, "project", "get", "--name", ""); len(got) != 2 {
+			t.Fatalf("candidates = %q, want both projects", got)
+		}
+	}
+	if calls := h.API.CallsTo("ListAllProjects"); len(calls) != 1 {
+		t.Fatalf("ListAllProjects called %d times, want 1 thanks to the cache", len(calls))
+	}
+}
@@ -0,0 +1,166 @@
+package root
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/plugin"
+)
+
+const pluginGroup = "plugin"
+
+// addPluginCommands adds a command for every zeabur-<name> plugin on PATH
+// or in the plugins directory. Built-in commands win over plugins of the
+// same name.
+func addPluginCommands(f *cmdutil.Factory, root *cobra.Command) {
+	dir, _ := plugin.DefaultDir()
+
+	var added bool
+	for _, p := range plugin.Discover(dir) {
+		if sub, _, err := root.Find([]string{p.Name}); err == nil && sub != root {
+			continue
+		}
+		if !added {
+			root.AddGroup(&cobra.Group{ID: pluginGroup, Title: "Plugin commands:"})
+			added = true
+		}
+		root.AddCommand(newPluginCmd(f, p))
+	}
+}
+
+func newPluginCmd(f *cmdutil.Factory, p plugin.Plugin) *cobra.Command {
+	var pluginArgs []string
+
+	return &cobra.Command{
+		Use:     p.Name,
+		Short:   "Plugin " + p.Path,
+		GroupID: pluginGroup,
+		// every argument belongs to the plugin, --help included
+		DisableFlagParsing: true,
+		// global flags that lead the arguments are zeabur's; they have to
+		// be applied before the root hook resolves the profile, token and
+		// workspace
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if pluginArgs, err = parseLeadingFlags(cmd.Root().PersistentFlags(), args); err != nil {
+				return err
+			}
+			return cmd.Root().PersistentPreRunE(cmd, pluginArgs)
+		},
+		RunE: func(*cobra.Command, []string) error {
+			return runPlugin(f, p, pluginArgs)
+		},
+		// the plugin may change the config itself, e.g. by running
+		// `zeabur context set`; runPlugin saves ours before it starts
+		PersistentPostRunE: func(*cobra.Command, []string) error { return nil },
+	}
+}
+
+// parseLeadingFlags applies the flags of fs found at the start of args,
+// up to the first argument that is not one of them or "--", and returns
+// the rest.
+func parseLeadingFlags(fs *pflag.FlagSet, args []string) ([]string, error) {
+	for len(args) > 0 {
+		arg := args[0]
+		if arg == "--" {
+			return args[1:], nil
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		var flag *pflag.Flag
+		if strings.HasPrefix(arg, "--") {
+			flag = fs.Lookup(name)
+		} else if len(name) == 1 {
+			flag = fs.ShorthandLookup(name)
+		}
+		if flag == nil {
+			break
+		}
+
+		consumed := 1
+		if !hasValue {
+			if flag.NoOptDefVal != "" {
+				value = flag.NoOptDefVal
+			} else if len(args) > 1 {
+				value, consumed = args[1], 2
+			} else {
+				return nil, fmt.Errorf("flag needs an argument: %s", arg)
+			}
+		}
+		if err := fs.Set(flag.Name, value); err != nil {
+			return nil, fmt.Errorf("invalid argument %q for %s: %w", value, arg, err)
+		}
+		args = args[consumed:]
+	}
+	return args, nil
+}
+
+// runPlugin runs the plugin with the resolved token, workspace and context
+// in its environment, and exits with its exit code.
+func runPlugin(f *cmdutil.Factory, p plugin.Plugin, args []string) error {
+	// persist what the root hook resolved, such as a fresh login, before
+	// the plugin gets a chance to run zeabur itself
+	if err := f.Config.Write(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	cmd := exec.Command(p.Path, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.Env = append(os.Environ(), pluginEnv(f)...)
+
+	// Ctrl-C reaches the plugin through the terminal; let it decide
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	}
+	if err != nil {
+		return fmt.Errorf("run plugin %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+// pluginEnv returns the environment handed to plugins. Unset values are
+// passed empty so that a variable inherited from the caller's shell cannot
+// masquerade as the resolved one.
+func pluginEnv(f *cmdutil.Factory) []string {
+	ws := f.CurrentWorkspace()
+
+	var envName, serviceName string
+	if !f.HasWorkspaceOverride() {
+		ctx := f.EffectiveContext()
+		envName = ctx.GetEnvironment().GetName()
+		serviceName = ctx.GetService().GetName()
+	}
+
+	self, _ := os.Executable()
+	return []string{
+		"ZEABUR_CLI=" + self,
+		"ZEABUR_TOKEN=" + f.Config.GetTokenString(),
+		"ZEABUR_PROFILE=" + f.Config.GetProfile(),
+		"ZEABUR_API_URL=" + f.EffectiveAPIURL(),
+		"ZEABUR_WORKSPACE_ID=" + ws.ID,
+		"ZEABUR_WORKSPACE_NAME=" + ws.Name,
+		"ZEABUR_PROJECT_ID=" + f.CurrentProjectID(),
+		"ZEABUR_PROJECT_NAME=" + f.CurrentProjectName(),
+		"ZEABUR_ENVIRONMENT_ID=" + f.CurrentEnvironmentID(),
+		"ZEABUR_ENVIRONMENT_NAME=" + envName,
+		"ZEABUR_SERVICE_ID=" + f.CurrentServiceID(),
+		"ZEABUR_SERVICE_NAME=" + serviceName,
+	}
+}
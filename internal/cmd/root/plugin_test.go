@@ -0,0 +1,79 @@
+package root_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/zeabur/cli/internal/cmd/root"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/pkg/plugin"
+	"github.com/zeabur/cli/pkg/zcontext"
+)
+
+func TestPlugin_ReceivesContextAndArgs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test plugin is a shell script")
+	}
+	dir := t.TempDir()
+	t.Setenv(plugin.DirEnv, dir)
+	t.Setenv("PATH", "")
+	t.Setenv("ZEABUR_TOKEN", "")
+
+	out := filepath.Join(t.TempDir(), "out")
+	script := "#!/bin/sh\n" +
+		`printf '%s\n' "$ZEABUR_TOKEN" "$ZEABUR_PROJECT_ID" "$ZEABUR_PROJECT_NAME" "$ZEABUR_SERVICE_NAME" "$@" > ` + out + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "zeabur-hello"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	h := cmdtest.New()
+	h.Config.GetContext().SetProject(zcontext.NewBasicInfo("p1", "api"))
+	h.Config.GetContext().SetService(zcontext.NewBasicInfo("s1", "web"))
+
+	cmd, err := root.NewCmdRoot(h.Factory, "dev", "none", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// --debug is zeabur's, everything after the first plugin argument is
+	// the plugin's, flags included
+	if err := h.Run(cmd, "hello", "--debug", "world", "--debug", "-i"); err != nil {
+		t.Fatalf("zeabur hello: %v", err)
+	}
+	if !h.Factory.Debug {
+		t.Error("leading --debug was not applied to zeabur")
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Split(strings.TrimSpace(string(data)), "\n")
+	want := []string{"test-token", "p1", "api", "web", "world", "--debug", "-i"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("plugin saw %q, want %q", got, want)
+	}
+}
+
+func TestPlugin_BuiltinsWin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test plugin is a shell script")
+	}
+	dir := t.TempDir()
+	t.Setenv(plugin.DirEnv, dir)
+	t.Setenv("PATH", "")
+	if err := os.WriteFile(filepath.Join(dir, "zeabur-version"), []byte("#!/bin/sh\nexit 3\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, err := root.NewCmdRoot(cmdtest.New().Factory, "dev", "none", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, _, err := cmd.Find([]string{"version"})
+	if err != nil || sub.GroupID != "" {
+		t.Fatalf("version resolves to %q in group %q, want the built-in", sub.Name(), sub.GroupID)
+	}
+}
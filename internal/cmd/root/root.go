@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/MakeNowJust/heredoc"
@@ -12,35 +13,47 @@ import (
 	"github.com/spf13/pflag"
 
 	aihubCmd "github.com/zeabur/cli/internal/cmd/ai-hub"
+	apiCmd "github.com/zeabur/cli/internal/cmd/api"
+	applyCmd "github.com/zeabur/cli/internal/cmd/apply"
 	authCmd "github.com/zeabur/cli/internal/cmd/auth"
 	completionCmd "github.com/zeabur/cli/internal/cmd/completion"
-	helpCmd "github.com/zeabur/cli/internal/cmd/help"
 	contextCmd "github.com/zeabur/cli/internal/cmd/context"
+	dashboardCmd "github.com/zeabur/cli/internal/cmd/dashboard"
 	deployCmd "github.com/zeabur/cli/internal/cmd/deploy"
 	deploymentCmd "github.com/zeabur/cli/internal/cmd/deployment"
 	domainCmd "github.com/zeabur/cli/internal/cmd/domain"
 	emailCmd "github.com/zeabur/cli/internal/cmd/email"
 	fileCmd "github.com/zeabur/cli/internal/cmd/file"
+	helpCmd "github.com/zeabur/cli/internal/cmd/help"
+	linkCmd "github.com/zeabur/cli/internal/cmd/link"
+	logsCmd "github.com/zeabur/cli/internal/cmd/logs"
+	mcpCmd "github.com/zeabur/cli/internal/cmd/mcp"
+	metricsCmd "github.com/zeabur/cli/internal/cmd/metrics"
+	planCmd "github.com/zeabur/cli/internal/cmd/plan"
+	pluginCmd "github.com/zeabur/cli/internal/cmd/plugin"
 	profileCmd "github.com/zeabur/cli/internal/cmd/profile"
 	projectCmd "github.com/zeabur/cli/internal/cmd/project"
 	serverCmd "github.com/zeabur/cli/internal/cmd/server"
 	serviceCmd "github.com/zeabur/cli/internal/cmd/service"
 	templateCmd "github.com/zeabur/cli/internal/cmd/template"
+	unlinkCmd "github.com/zeabur/cli/internal/cmd/unlink"
 	uploadCmd "github.com/zeabur/cli/internal/cmd/upload"
 	variableCmd "github.com/zeabur/cli/internal/cmd/variable"
 	versionCmd "github.com/zeabur/cli/internal/cmd/version"
 	workspaceCmd "github.com/zeabur/cli/internal/cmd/workspace"
 	"github.com/zeabur/cli/internal/cmdutil"
-	"github.com/zeabur/cli/pkg/api"
 	"github.com/zeabur/cli/pkg/config"
 	"github.com/zeabur/cli/pkg/fill"
 	"github.com/zeabur/cli/pkg/log"
+	"github.com/zeabur/cli/pkg/printer"
 	"github.com/zeabur/cli/pkg/selector"
 	"github.com/zeabur/cli/pkg/zcontext"
 )
 
 // NewCmdRoot creates the root command
 func NewCmdRoot(f *cmdutil.Factory, version, commit, date string) (*cobra.Command, error) {
+	var jsonOutput bool
+
 	cmd := &cobra.Command{
 		Use:   "zeabur",
 		Short: "Zeabur CLI",
@@ -51,8 +64,21 @@ func NewCmdRoot(f *cmdutil.Factory, version, commit, date string) (*cobra.Comman
 			$ zeabur service create
 		`),
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// resolve the output format; --json is kept as a shorthand for -o json
+			if jsonOutput {
+				if cmd.Flags().Changed("output") && f.Output != string(printer.FormatJSON) {
+					return fmt.Errorf("--json conflicts with --output %s", f.Output)
+				}
+				f.Output = string(printer.FormatJSON)
+			}
+			output, err := printer.ParseOutput(f.Output)
+			if err != nil {
+				return err
+			}
+			f.Printer.SetOutput(output)
+
 			// set up logging
-			if f.JSON {
+			if f.MachineReadableOutput() {
 				f.Log = log.NewSilent()
 			} else if f.Debug {
 				f.Log = log.NewDebugLevel()
@@ -60,6 +86,19 @@ func NewCmdRoot(f *cmdutil.Factory, version, commit, date string) (*cobra.Comman
 				f.Log = log.NewInfoLevel()
 			}
 
+			// inside a linked checkout, never fall back to the global
+			// context because the link file is broken
+			if cmdutil.IsLinkCheckEnabled(cmd) {
+				if _, err := f.Link(); err != nil {
+					return err
+				}
+			}
+
+			// select the auth profile before anything reads the token
+			if err := resolveProfileFlag(f); err != nil {
+				return err
+			}
+
 			// normalize ID flags: strip prefix from prefixed ObjectIDs
 			// e.g. "service-662e24fca7d5..." → "662e24fca7d5..."
 			var normalizeErr error
@@ -78,9 +117,17 @@ func NewCmdRoot(f *cmdutil.Factory, version, commit, date string) (*cobra.Comman
 
 			// require that the user is authenticated before running most commands
 			if cmdutil.IsAuthCheckEnabled(cmd) {
-				// in JSON mode, fail fast if not authenticated instead of opening a browser
-				if f.JSON && !f.LoggedIn() {
-					return fmt.Errorf("not authenticated: run `zeabur auth login` before using --json")
+				// an unreadable credential store is not the same as being
+				// logged out; do not send the user through login again
+				if !f.LoggedIn() {
+					if backend, err := f.Config.GetCredentialStore(); err != nil {
+						return fmt.Errorf("read token from the %s credential store: %w", backend, err)
+					}
+				}
+
+				// with machine-readable output, fail fast if not authenticated instead of opening a browser
+				if f.MachineReadableOutput() && !f.LoggedIn() {
+					return fmt.Errorf("not authenticated: run `zeabur auth login` before using --output %s", output.Format)
 				}
 
 				// do not return error, guide user to login instead
@@ -99,7 +146,7 @@ func NewCmdRoot(f *cmdutil.Factory, version, commit, date string) (*cobra.Comman
 					f.Config.SetTokenString(tokenString)
 				}
 				// set up the client
-				f.ApiClient = api.New(f.Config.GetTokenString())
+				f.ApiClient = f.NewApiClient(f.Config.GetTokenString())
 
 				// Resolve the --workspace flag (one-shot override) and lazy-
 				// verify the persisted workspace. Both steps are best-effort
@@ -160,9 +207,15 @@ func NewCmdRoot(f *cmdutil.Factory, version, commit, date string) (*cobra.Comman
 	cmd.PersistentFlags().BoolVar(&f.Debug, "debug", false, "Enable debug logging")
 	cmd.PersistentFlags().BoolVarP(&f.Interactive, config.KeyInteractive, "i", true, "use interactive mode")
 	cmd.PersistentFlags().BoolVar(&f.AutoCheckUpdate, config.KeyAutoCheckUpdate, true, "automatically check update")
-	cmd.PersistentFlags().BoolVar(&f.JSON, "json", false, "output in JSON format")
+	cmd.PersistentFlags().StringVarP(&f.Output, "output", "o", "",
+		"output format: "+strings.Join(printer.Formats, "|"))
+	cmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "output in JSON format, shorthand for --output json")
 	cmd.PersistentFlags().StringVar(&f.Workspace, "workspace", "",
 		"one-shot workspace override (team name or ID); to return to personal use 'zeabur workspace clear'")
+	cmd.PersistentFlags().StringVar(&f.Profile, "profile", "",
+		"one-shot auth profile override (env: "+config.ProfileEnv+"); to switch profiles use 'zeabur auth profile use'")
+	cmd.PersistentFlags().StringVar(&f.APIURL, "api-url", "",
+		"one-shot API endpoint override, e.g. a staging cluster or local server (env: ZEABUR_API_URL)")
 
 	// Child commands
 	cmd.AddCommand(deployCmd.NewCmdDeploy(f))
@@ -177,16 +230,32 @@ func NewCmdRoot(f *cmdutil.Factory, version, commit, date string) (*cobra.Comman
 	cmd.AddCommand(domainCmd.NewCmdDomain(f))
 	cmd.AddCommand(profileCmd.NewCmdProfile(f))
 	cmd.AddCommand(contextCmd.NewCmdContext(f))
+	cmd.AddCommand(linkCmd.NewCmdLink(f))
+	cmd.AddCommand(unlinkCmd.NewCmdUnlink(f))
 	cmd.AddCommand(completionCmd.NewCmdCompletion(f))
 	cmd.AddCommand(variableCmd.NewCmdVariable(f))
 	cmd.AddCommand(emailCmd.NewCmdEmail(f))
 	cmd.AddCommand(fileCmd.NewCmdFile(f))
 	cmd.AddCommand(aihubCmd.NewCmdAIHub(f))
 	cmd.AddCommand(workspaceCmd.NewCmdWorkspace(f))
+	cmd.AddCommand(planCmd.NewCmdPlan(f))
+	cmd.AddCommand(applyCmd.NewCmdApply(f))
+	cmd.AddCommand(apiCmd.NewCmdAPI(f))
+	cmd.AddCommand(pluginCmd.NewCmdPlugin(f))
+	cmd.AddCommand(mcpCmd.NewCmdMCP(f, version))
+	cmd.AddCommand(dashboardCmd.NewCmdDashboard(f))
+	cmd.AddCommand(metricsCmd.NewCmdMetrics(f))
+	cmd.AddCommand(logsCmd.NewCmdLogs(f))
 
 	// replace default help command with our custom one that supports --all
 	cmd.SetHelpCommand(helpCmd.NewCmdHelp(cmd))
 
+	// dispatch unknown commands to zeabur-<name> plugins
+	addPluginCommands(f, cmd)
+
+	// complete resource IDs and names from the API
+	registerCompletions(f, cmd)
+
 	return cmd, nil
 }
 
@@ -220,6 +289,23 @@ func resolveWorkspaceFlag(f *cmdutil.Factory) error {
 	return nil
 }
 
+// resolveProfileFlag activates the auth profile named by --profile, or by
+// ZEABUR_PROFILE when the flag is not given, for this invocation only.
+// Unlike --workspace it needs no backend call: profiles are local.
+func resolveProfileFlag(f *cmdutil.Factory) error {
+	name, source := strings.TrimSpace(f.Profile), "--profile"
+	if name == "" {
+		name, source = strings.TrimSpace(os.Getenv(config.ProfileEnv)), config.ProfileEnv
+	}
+	if name == "" {
+		return nil
+	}
+	if err := f.SetProfileOverride(name); err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+	return nil
+}
+
 // verifyPersistedWorkspace warns and falls back to personal when the
 // persisted workspace is no longer a team the caller belongs to (team
 // deleted, caller removed, etc.). Best-effort: any transport error leaves
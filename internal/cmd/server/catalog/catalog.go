@@ -84,7 +84,7 @@ func runCatalog(f *cmdutil.Factory, opts *Options) error {
 	}
 
 	if len(providers) == 0 {
-		return f.Printer.JSON(output)
+		return f.Printer.Data(output)
 	}
 
 	var wg sync.WaitGroup
@@ -194,5 +194,5 @@ func runCatalog(f *cmdutil.Factory, opts *Options) error {
 	}
 	output.Providers = nonEmpty
 
-	return f.Printer.JSON(output)
+	return f.Printer.Data(output)
 }
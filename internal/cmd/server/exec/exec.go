@@ -21,6 +21,7 @@ import (
 	"golang.org/x/crypto/ssh"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
 )
 
 type Options struct {
@@ -74,6 +75,8 @@ argument to keep it intact.`,
 	}
 
 	cmd.Flags().StringVar(&opts.id, "id", "", "Server ID")
+	util.SetFlagCompletion(cmd, "id", util.CompleteServer)
+	util.SetArgsCompletion(cmd, util.CompleteServer)
 
 	return cmd
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
 )
 
 type Options struct {
@@ -31,6 +32,8 @@ func NewCmdGet(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.id, "id", "", "Server ID")
+	util.SetFlagCompletion(cmd, "id", util.CompleteServer)
+	util.SetArgsCompletion(cmd, util.CompleteServer)
 
 	return cmd
 }
@@ -83,11 +86,5 @@ func runGetNonInteractive(f *cmdutil.Factory, opts *Options) error {
 		return fmt.Errorf("get server failed: %w", err)
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(server)
-	}
-
-	f.Printer.Table(server.Header(), server.Rows())
-
-	return nil
+	return f.Printer.Data(server)
 }
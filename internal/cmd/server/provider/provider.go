@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 func NewCmdProvider(f *cmdutil.Factory) *cobra.Command {
@@ -21,15 +22,7 @@ func NewCmdProvider(f *cmdutil.Factory) *cobra.Command {
 			}
 
 			if len(providers) == 0 {
-				if f.JSON {
-					return f.Printer.JSON([]any{})
-				}
 				f.Log.Infof("No providers available")
-				return nil
-			}
-
-			if f.JSON {
-				return f.Printer.JSON(providers)
 			}
 
 			header := []string{"Code", "Name"}
@@ -37,9 +30,7 @@ func NewCmdProvider(f *cmdutil.Factory) *cobra.Command {
 			for i, p := range providers {
 				rows[i] = []string{p.Code, p.Name}
 			}
-			f.Printer.Table(header, rows)
-
-			return nil
+			return f.Printer.Data(printer.WithTable(providers, header, rows))
 		},
 	}
 
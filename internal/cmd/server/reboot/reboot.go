@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
 )
 
 type Options struct {
@@ -32,6 +33,8 @@ func NewCmdReboot(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.id, "id", "", "Server ID")
+	util.SetFlagCompletion(cmd, "id", util.CompleteServer)
+	util.SetArgsCompletion(cmd, util.CompleteServer)
 	cmd.Flags().BoolVarP(&opts.skipConfirm, "yes", "y", false, "Skip confirmation")
 
 	return cmd
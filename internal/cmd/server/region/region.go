@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -60,15 +61,7 @@ func runRegion(f *cmdutil.Factory, opts *Options) error {
 	}
 
 	if len(regions) == 0 {
-		if f.JSON {
-			return f.Printer.JSON([]any{})
-		}
 		f.Log.Infof("No regions available for provider %s", opts.provider)
-		return nil
-	}
-
-	if f.JSON {
-		return f.Printer.JSON(regions)
 	}
 
 	header := []string{"ID", "Name", "City", "Country", "Continent"}
@@ -76,7 +69,5 @@ func runRegion(f *cmdutil.Factory, opts *Options) error {
 	for i, r := range regions {
 		rows[i] = []string{r.ID, r.Name, r.City, r.Country, r.Continent}
 	}
-	f.Printer.Table(header, rows)
-
-	return nil
+	return f.Printer.Data(printer.WithTable(regions, header, rows))
 }
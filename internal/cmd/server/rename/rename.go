@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
 )
 
 type Options struct {
@@ -33,6 +34,8 @@ func NewCmdRename(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.id, "id", "", "Server ID")
+	util.SetFlagCompletion(cmd, "id", util.CompleteServer)
+	util.SetArgsCompletion(cmd, util.CompleteServer)
 	cmd.Flags().StringVar(&opts.name, "name", "", "New server name")
 
 	return cmd
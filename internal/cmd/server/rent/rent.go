@@ -163,7 +163,7 @@ func runRentNonInteractive(f *cmdutil.Factory, opts *Options) error {
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "bind a credit card or recharge credits") || strings.Contains(errMsg, "insufficient balance") {
 			f.Log.Errorf("Rent server failed: %s", errMsg)
-			f.Log.Infof("Please bind a credit card or top up your balance at: https://zeabur.com/account/billing")
+			f.Log.Infof("Please bind a credit card or top up your balance at: %s/account/billing", f.EffectiveDashURL())
 			return fmt.Errorf("payment required")
 		}
 		return fmt.Errorf("rent server failed: %w", err)
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
 )
 
 type Options struct {
@@ -34,6 +35,8 @@ func NewCmdSSH(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.id, "id", "", "Server ID")
+	util.SetFlagCompletion(cmd, "id", util.CompleteServer)
+	util.SetArgsCompletion(cmd, util.CompleteServer)
 
 	return cmd
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/fill"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -93,10 +94,6 @@ func runDeleteNonInteractive(f *cmdutil.Factory, opts *Options) error {
 		return fmt.Errorf("delete service failed: %w", err)
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(map[string]string{"status": "success", "id": opts.id, "message": "Service deleted successfully"})
-	}
 	f.Log.Infof("Service <%s> deleted successfully", idOrName)
-
-	return nil
+	return f.Printer.Data(printer.Result(map[string]string{"status": "success", "id": opts.id, "message": "Service deleted successfully"}))
 }
@@ -8,7 +8,7 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
-	"github.com/zeabur/cli/pkg/constant"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -71,22 +71,16 @@ func runDeployNonInteractive(f *cmdutil.Factory, opts *Options) error {
 			return fmt.Errorf("create prebuilt service failed: %w", err)
 		}
 
-		if f.JSON {
-			return f.Printer.JSON(map[string]string{"status": "success", "id": service.ID, "name": service.Name, "message": "Service created"})
-		}
 		f.Log.Infof("Service %s created", service.Name)
-		return nil
+		return f.Printer.Data(printer.Result(map[string]string{"status": "success", "id": service.ID, "name": service.Name, "message": "Service created"}))
 	case "GIT":
 		service, err := f.ApiClient.CreateService(context.Background(), opts.projectID, opts.name, opts.repoID, opts.branchName)
 		if err != nil {
 			return fmt.Errorf("create service failed: %w", err)
 		}
 
-		if f.JSON {
-			return f.Printer.JSON(map[string]string{"status": "success", "id": service.ID, "name": service.Name, "message": "Service created"})
-		}
 		f.Log.Infof("Service %s created", opts.name)
-		return nil
+		return f.Printer.Data(printer.Result(map[string]string{"status": "success", "id": service.ID, "name": service.Name, "message": "Service created"}))
 	default:
 		return fmt.Errorf("unsupported service template %s", opts.template)
 	}
@@ -156,7 +150,7 @@ func runDeployInteractive(f *cmdutil.Factory, opts *Options) error {
 		s.Stop()
 
 		fmt.Printf("%s Service %s created 🚀\n", cmdutil.SuccessIcon, service.Name)
-		fmt.Printf("%s/projects/%s/services/%s", constant.ZeaburDashURL, opts.projectID, service.ID)
+		fmt.Printf("%s/projects/%s/services/%s", f.EffectiveDashURL(), opts.projectID, service.ID)
 
 		return nil
 
@@ -85,13 +85,7 @@ func runGetNonInteractive(f *cmdutil.Factory, opts *Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(t)
-	}
-
-	f.Printer.Table(t.Header(), t.Rows())
-
-	return nil
+	return f.Printer.Data(t)
 }
 
 func getServiceBrief(client api.ServiceAPI, id string) (t model.Tabler, err error) {
@@ -70,20 +70,10 @@ func listServicesBrief(f *cmdutil.Factory, projectID string) error {
 	}
 
 	if len(services) == 0 {
-		if f.JSON {
-			return f.Printer.JSON([]any{})
-		}
 		f.Log.Infof("No services found")
-		return nil
-	}
-
-	if f.JSON {
-		return f.Printer.JSON(services)
 	}
 
-	f.Printer.Table(services.Header(), services.Rows())
-
-	return nil
+	return f.Printer.Data(services)
 }
 
 func listServicesDetailByEnvironment(f *cmdutil.Factory, projectID, environmentID string) error {
@@ -93,18 +83,8 @@ func listServicesDetailByEnvironment(f *cmdutil.Factory, projectID, environmentI
 	}
 
 	if len(services) == 0 {
-		if f.JSON {
-			return f.Printer.JSON([]any{})
-		}
 		f.Log.Infof("No services found")
-		return nil
-	}
-
-	if f.JSON {
-		return f.Printer.JSON(services)
 	}
 
-	f.Printer.Table(services.Header(), services.Rows())
-
-	return nil
+	return f.Printer.Data(services)
 }
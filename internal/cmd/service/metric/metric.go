@@ -3,7 +3,13 @@ package metric
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -11,32 +17,64 @@ import (
 	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/fill"
 	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
+var metricTypes = []model.MetricType{model.MetricTypeCPU, model.MetricTypeMemory, model.MetricTypeNetwork}
+
 type Options struct {
 	id            string
 	name          string
 	environmentID string
 	metricType    string
+	types         []model.MetricType
 	hour          uint
+	since         string
+	until         string
+	watch         bool
+	interval      time.Duration
+
+	out io.Writer
 }
 
 func NewCmdMetric(f *cmdutil.Factory) *cobra.Command {
 	opts := &Options{}
 
 	cmd := &cobra.Command{
-		Use:   "metric [CPU|MEMORY|NETWORK]",
+		Use:   "metric [CPU|MEMORY|NETWORK]...",
 		Short: "Show metric of a service",
-		Long:  `Show metric of a service`,
-		Args:  cobra.ExactArgs(1),
+		Long: `Show the CPU, memory and network usage of a service as charts, with the
+minimum, average, 95th percentile and maximum of each metric. Without a
+metric type, all three are shown side by side.
+
+With --output csv, tsv or plain, the raw series are printed instead, one
+row per point, ready for a spreadsheet; CPU values are fractions of a
+core and memory and network values are in MB.
+
+With --output json or yaml, a single metric type prints its Sum, Avg, Max
+and Min with units; several print each series with its summary and points.`,
+		Example: `  # CPU, memory and network over the last 2 hours
+  zeabur service metric --name web
+
+  # memory over the last day, refreshed every 30 seconds
+  zeabur service metric MEMORY --name web --since 24h --watch
+
+  # export the raw series of a time range
+  zeabur service metric --name web --since 2024-05-01 --until 2024-05-02 -o csv > metrics.csv`,
+		Args: cobra.MaximumNArgs(len(metricTypes)),
 		ValidArgs: []string{
 			string(model.MetricTypeCPU),
 			string(model.MetricTypeMemory),
 			string(model.MetricTypeNetwork),
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.metricType = args[0]
-			return runMetric(f, opts)
+			types, err := parseTypes(opts.metricType, args)
+			if err != nil {
+				return err
+			}
+			opts.types = types
+			opts.out = cmd.OutOrStdout()
+			return runMetric(cmd.Context(), f, opts)
 		},
 	}
 
@@ -44,19 +82,49 @@ func NewCmdMetric(f *cmdutil.Factory) *cobra.Command {
 	util.AddEnvOfServiceParam(cmd, &opts.environmentID)
 	cmd.Flags().StringVarP(&opts.metricType, "metric-type", "t", "", "Metric type, one of CPU, MEMORY, NETWORK")
 	cmd.Flags().UintVarP(&opts.hour, "hour", "H", 2, "Metric history in hour")
+	cmd.Flags().StringVar(&opts.since, "since", "", "Show metrics since a duration ago (e.g. 30m, 24h), an RFC 3339 timestamp or a date")
+	cmd.Flags().StringVar(&opts.until, "until", "", "Show metrics until a duration ago, an RFC 3339 timestamp or a date (default now)")
+	cmd.Flags().BoolVarP(&opts.watch, "watch", "w", false, "Redraw the charts every --interval until interrupted")
+	cmd.Flags().DurationVar(&opts.interval, "interval", 30*time.Second, "How often to refresh the charts with --watch")
+	cmd.MarkFlagsMutuallyExclusive("hour", "since")
 
 	return cmd
 }
 
-func runMetric(f *cmdutil.Factory, opts *Options) error {
+// parseTypes returns the metric types named by --metric-type and the
+// arguments, in the canonical order, or all of them when none is named.
+func parseTypes(flag string, args []string) ([]model.MetricType, error) {
+	names := args
+	if flag != "" {
+		names = append([]string{flag}, args...)
+	}
+	if len(names) == 0 {
+		return metricTypes, nil
+	}
+
+	var types []model.MetricType
+	for _, mt := range metricTypes {
+		if slices.ContainsFunc(names, func(name string) bool { return strings.EqualFold(name, string(mt)) }) {
+			types = append(types, mt)
+		}
+	}
+	for _, name := range names {
+		if !slices.Contains(types, model.MetricType(strings.ToUpper(name))) {
+			return nil, fmt.Errorf("unknown metric type %q, must be one of CPU, MEMORY, NETWORK", name)
+		}
+	}
+	return types, nil
+}
+
+func runMetric(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
 	if f.Interactive {
-		return runMetricInteractive(f, opts)
+		return runMetricInteractive(ctx, f, opts)
 	} else {
-		return runMetricNonInteractive(f, opts)
+		return runMetricNonInteractive(ctx, f, opts)
 	}
 }
 
-func runMetricInteractive(f *cmdutil.Factory, opts *Options) error {
+func runMetricInteractive(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
 	zctx := f.EffectiveContext()
 	if _, err := f.ParamFiller.ServiceByNameWithEnvironment(fill.ServiceByNameWithEnvironmentOptions{
 		ProjectCtx:    zctx,
@@ -68,10 +136,23 @@ func runMetricInteractive(f *cmdutil.Factory, opts *Options) error {
 		return err
 	}
 
-	return runMetricNonInteractive(f, opts)
+	return runMetricNonInteractive(ctx, f, opts)
 }
 
-func runMetricNonInteractive(f *cmdutil.Factory, opts *Options) error {
+func runMetricNonInteractive(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
+	if opts.watch {
+		if f.MachineReadableOutput() {
+			return fmt.Errorf("--watch only works with the table output")
+		}
+		if opts.interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+	}
+	// validate the time range before any request
+	if _, _, err := opts.window(time.Now()); err != nil {
+		return err
+	}
+
 	if opts.id == "" && opts.name != "" {
 		service, err := util.GetServiceByName(f.ApiClient, f.CurrentOwnerID(), f.Config.GetUsername(), f.CurrentProjectName(), f.CurrentProjectID(), opts.name)
 		if err != nil {
@@ -85,7 +166,7 @@ func runMetricNonInteractive(f *cmdutil.Factory, opts *Options) error {
 	}
 
 	// Resolve environment and project from the service
-	service, err := f.ApiClient.GetService(context.Background(), opts.id, "", "", "")
+	service, err := f.ApiClient.GetService(ctx, opts.id, "", "", "")
 	if err != nil {
 		return fmt.Errorf("get service failed: %w", err)
 	}
@@ -103,65 +184,113 @@ func runMetricNonInteractive(f *cmdutil.Factory, opts *Options) error {
 		opts.environmentID = envID
 	}
 
-	if opts.metricType == "" {
-		return fmt.Errorf("metric type is required")
-	}
-
-	upperCaseMetricType := strings.ToUpper(opts.metricType)
-	mt := model.MetricType(upperCaseMetricType)
-
-	startTime := time.Now().Add(-time.Duration(opts.hour) * time.Hour)
-	endTime := time.Now()
-
-	metrics, err := f.ApiClient.ServiceMetric(context.Background(), opts.id, projectID, opts.environmentID, upperCaseMetricType, startTime, endTime)
-	if err != nil {
-		return fmt.Errorf("get service metric failed: %w", err)
+	fetch := func() (*report, error) {
+		start, end, err := opts.window(time.Now())
+		if err != nil {
+			return nil, err
+		}
+		r := &report{Service: service.Name, Start: start, End: end}
+		for _, mt := range opts.types {
+			metrics, err := f.ApiClient.ServiceMetric(ctx, opts.id, projectID, opts.environmentID, string(mt), start, end)
+			if err != nil {
+				return nil, fmt.Errorf("get %s metric failed: %w", mt, err)
+			}
+			r.Series = append(r.Series, newSeries(mt, metrics))
+		}
+		return r, nil
 	}
 
-	if len(metrics.Metrics) == 0 {
-		if f.JSON {
-			return f.Printer.JSON([]any{})
+	if !opts.watch {
+		r, err := fetch()
+		if err != nil {
+			return err
 		}
-		f.Log.Infof("no metric history found")
-		return nil
+		return printReport(f, opts.out, r)
 	}
 
-	sum, avg, max := 0.0, 0.0, 0.0
-	min := metrics.Metrics[0].Value
-
-	for _, metric := range metrics.Metrics {
-		sum += metric.Value
-		if metric.Value > max {
-			max = metric.Value
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	for {
+		r, err := fetch()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
 		}
-		if metric.Value < min {
-			min = metric.Value
+		// move the cursor home and clear the screen before redrawing
+		_, _ = fmt.Fprint(opts.out, "\x1b[H\x1b[2J")
+		_, _ = fmt.Fprintf(opts.out, "Every %s, updated %s. Press Ctrl-C to stop.\n", opts.interval, time.Now().Format("15:04:05"))
+		if err := printReport(f, opts.out, r); err != nil {
+			return err
 		}
 
-		if f.Debug {
-			f.Log.Debugf("Metric: %s, value: %s, timestamp: %s",
-				opts.metricType, mt.WithMeasureUnit(metric.Value), metric.Timestamp.Format(time.RFC3339))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.interval):
 		}
 	}
+}
 
-	avg = sum / float64(len(metrics.Metrics))
-
-	f.Log.Infof("Metric type: %s, start time: %s, end time: %s\n",
-		opts.metricType, startTime.Format(time.RFC3339), endTime.Format(time.RFC3339))
+// window returns the time range to show: --since (or --hour before the end)
+// to --until (or now).
+func (opts *Options) window(now time.Time) (start, end time.Time, err error) {
+	end = now
+	if opts.until != "" {
+		if end, err = util.ParseTime(opts.until, now); err != nil {
+			return start, end, fmt.Errorf("--until: %w", err)
+		}
+	}
+	start = end.Add(-time.Duration(opts.hour) * time.Hour)
+	if opts.since != "" {
+		if start, err = util.ParseTime(opts.since, now); err != nil {
+			return start, end, fmt.Errorf("--since: %w", err)
+		}
+	}
+	if !start.Before(end) {
+		return start, end, fmt.Errorf("the start of the time range (%s) must be before its end (%s)", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+	return start, end, nil
+}
 
-	header := []string{"Sum", "Avg", "Max", "Min"}
-	data := [][]string{{mt.WithMeasureUnit(sum), mt.WithMeasureUnit(avg), mt.WithMeasureUnit(max), mt.WithMeasureUnit(min)}}
+func printReport(f *cmdutil.Factory, out io.Writer, r *report) error {
+	if f.MachineReadableOutput() {
+		// the chart is for people: the other formats get every point
+		var v any = r.Series
+		if len(r.Series) == 1 {
+			v = r.Series[0].summary()
+		}
+		header := []string{"Timestamp", "Metric", "Value"}
+		var rows [][]string
+		for _, s := range r.Series {
+			for _, p := range s.Points {
+				rows = append(rows, []string{p.Timestamp.Format(time.RFC3339), string(s.Type), strconv.FormatFloat(p.Value, 'f', -1, 64)})
+			}
+		}
+		return f.Printer.Data(printer.WithTable(v, header, rows))
+	}
 
-	if f.JSON {
-		return f.Printer.JSON(map[string]string{
-			"Sum": mt.WithMeasureUnit(sum),
-			"Avg": mt.WithMeasureUnit(avg),
-			"Max": mt.WithMeasureUnit(max),
-			"Min": mt.WithMeasureUnit(min),
-		})
+	if r.empty() {
+		f.Log.Infof("no metric history found")
+		return nil
 	}
 
-	f.Printer.Table(header, data)
+	for _, line := range r.render(terminalWidth(out)) {
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return err
+		}
+	}
 
+	header := []string{"Metric", "Sum", "Min", "Avg", "P95", "Max"}
+	rows := make([][]string, 0, len(r.Series))
+	for _, s := range r.Series {
+		if len(s.Points) == 0 {
+			rows = append(rows, []string{string(s.Type), "-", "-", "-", "-", "-"})
+			continue
+		}
+		rows = append(rows, []string{string(s.Type), format(s.Type, s.Sum), format(s.Type, s.Min), format(s.Type, s.Avg), format(s.Type, s.P95), format(s.Type, s.Max)})
+	}
+	f.Printer.Table(header, rows)
 	return nil
 }
@@ -0,0 +1,252 @@
+package metric_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zeabur/cli/internal/cmd/service/metric"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// seedMetrics makes the fake return the values 1 to n, one a minute, for
+// every metric.
+func seedMetrics(t *testing.T, h *cmdtest.Harness, n int) {
+	t.Helper()
+	type point struct {
+		Timestamp time.Time `json:"timestamp"`
+		Value     float64   `json:"value"`
+	}
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	points := make([]point, 0, n)
+	for i := range n {
+		points = append(points, point{start.Add(time.Duration(i) * time.Minute), float64(i + 1)})
+	}
+	data, err := json.Marshal(map[string]any{"metrics": points})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.API.Metrics = &model.ServiceMetric{}
+	if err := json.Unmarshal(data, h.API.Metrics); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMetric_ChartsAllTypes(t *testing.T) {
+	h := cmdtest.New()
+	project, _ := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+	seedMetrics(t, h, 20)
+
+	var out bytes.Buffer
+	cmd := metric.NewCmdMetric(h.Factory)
+	cmd.SetOut(&out)
+	if err := h.Run(cmd, "--id", svc.ID); err != nil {
+		t.Fatalf("service metric: %v", err)
+	}
+
+	var types []string
+	for _, call := range h.API.CallsTo("ServiceMetric") {
+		types = append(types, call.Args[3].(string))
+	}
+	if strings.Join(types, ",") != "CPU,MEMORY,NETWORK" {
+		t.Errorf("fetched metrics %v, want CPU, MEMORY and NETWORK", types)
+	}
+	for _, want := range []string{"web", "CPU  now 2000.00%", "MEMORY  now 20.00 MB", "NETWORK", "█"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("charts lack %q:\n%s", want, out.String())
+		}
+	}
+
+	table := h.Printer.LastTable()
+	if strings.Join(table.Header, ",") != "Metric,Sum,Min,Avg,P95,Max" || len(table.Rows) != 3 {
+		t.Fatalf("summary = %+v", table)
+	}
+	if got := strings.Join(table.Rows[1], ","); got != "MEMORY,210.00 MB,1.00 MB,10.50 MB,19.00 MB,20.00 MB" {
+		t.Errorf("memory summary = %s", got)
+	}
+}
+
+func TestMetric_CSVExportsRawSeries(t *testing.T) {
+	h := cmdtest.New()
+	h.Factory.Output = "csv"
+	project, _ := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+	seedMetrics(t, h, 3)
+
+	if err := h.Run(metric.NewCmdMetric(h.Factory), "--id", svc.ID, "memory"); err != nil {
+		t.Fatalf("service metric: %v", err)
+	}
+
+	table := h.Printer.LastTable()
+	if strings.Join(table.Header, ",") != "Timestamp,Metric,Value" {
+		t.Fatalf("header = %v", table.Header)
+	}
+	want := []string{
+		"2024-05-01T10:00:00Z,MEMORY,1",
+		"2024-05-01T10:01:00Z,MEMORY,2",
+		"2024-05-01T10:02:00Z,MEMORY,3",
+	}
+	if len(table.Rows) != len(want) {
+		t.Fatalf("rows = %v, want %v", table.Rows, want)
+	}
+	for i, row := range table.Rows {
+		if got := strings.Join(row, ","); got != want[i] {
+			t.Errorf("row %d = %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+// TestMetric_JSONSummary keeps the summary object of a single metric type.
+func TestMetric_JSONSummary(t *testing.T) {
+	h := cmdtest.New()
+	h.Factory.Output = "json"
+	project, _ := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+	seedMetrics(t, h, 20)
+
+	if err := h.Run(metric.NewCmdMetric(h.Factory), "--id", svc.ID, "-t", "MEMORY"); err != nil {
+		t.Fatalf("service metric: %v", err)
+	}
+
+	var out map[string]string
+	if err := h.Printer.DecodeLastJSON(&out); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	want := map[string]string{"Sum": "210.000000MB", "Avg": "10.500000MB", "Max": "20.000000MB", "Min": "1.000000MB"}
+	if !maps.Equal(out, want) {
+		t.Errorf("output = %v, want %v", out, want)
+	}
+}
+
+func TestMetric_JSONSummaryWithoutData(t *testing.T) {
+	h := cmdtest.New()
+	h.Factory.Output = "json"
+	project, _ := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+
+	if err := h.Run(metric.NewCmdMetric(h.Factory), "--id", svc.ID, "CPU"); err != nil {
+		t.Fatalf("service metric: %v", err)
+	}
+
+	var out []any
+	if err := h.Printer.DecodeLastJSON(&out); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if len(out) != 0 {
+		t.Errorf("output = %v, want an empty list", out)
+	}
+}
+
+// TestMetric_JSONSeries prints each series of several metric types.
+func TestMetric_JSONSeries(t *testing.T) {
+	h := cmdtest.New()
+	h.Factory.Output = "json"
+	project, _ := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+	seedMetrics(t, h, 20)
+
+	if err := h.Run(metric.NewCmdMetric(h.Factory), "--id", svc.ID, "CPU", "MEMORY"); err != nil {
+		t.Fatalf("service metric: %v", err)
+	}
+
+	var out []struct {
+		Type   string  `json:"type"`
+		Sum    float64 `json:"sum"`
+		Min    float64 `json:"min"`
+		Avg    float64 `json:"avg"`
+		P95    float64 `json:"p95"`
+		Max    float64 `json:"max"`
+		Points []any   `json:"points"`
+	}
+	if err := h.Printer.DecodeLastJSON(&out); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if len(out) != 2 || out[0].Type != "CPU" || out[1].Type != "MEMORY" || len(out[0].Points) != 20 {
+		t.Fatalf("output = %+v", out)
+	}
+	if s := out[0]; s.Sum != 210 || s.Min != 1 || s.Avg != 10.5 || s.P95 != 19 || s.Max != 20 {
+		t.Errorf("summary = %+v, want sum 210, min 1, avg 10.5, p95 19, max 20", s)
+	}
+}
+
+func TestMetric_SinceUntil(t *testing.T) {
+	h := cmdtest.New()
+	project, _ := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+
+	err := h.Run(metric.NewCmdMetric(h.Factory), "--id", svc.ID, "CPU",
+		"--since", "2024-05-01T00:00:00Z", "--until", "2024-05-02T00:00:00Z")
+	if err != nil {
+		t.Fatalf("service metric: %v", err)
+	}
+
+	calls := h.API.CallsTo("ServiceMetric")
+	if len(calls) != 1 {
+		t.Fatalf("ServiceMetric called %d times, want once", len(calls))
+	}
+	start, end := calls[0].Args[4].(time.Time), calls[0].Args[5].(time.Time)
+	if !start.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("time range = %s to %s", start, end)
+	}
+}
+
+func TestMetric_InvalidFlags(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		output string
+		args   []string
+		want   string
+	}{
+		{"unknown type", "", []string{"DISK"}, `unknown metric type "DISK"`},
+		{"reversed range", "", []string{"--since", "1h", "--until", "2h"}, "must be before its end"},
+		{"hour and since", "", []string{"--hour", "3", "--since", "1h"}, "none of the others can be"},
+		{"watch csv", "csv", []string{"--watch"}, "--watch only works with the table output"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := cmdtest.New()
+			h.Factory.Output = tc.output
+			project, _ := h.API.SeedProject("", "api")
+			svc := h.API.SeedService(project.ID, "web")
+
+			err := h.Run(metric.NewCmdMetric(h.Factory), append([]string{"--id", svc.ID}, tc.args...)...)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("error = %v, want %q", err, tc.want)
+			}
+			if calls := h.API.CallsTo("ServiceMetric"); len(calls) != 0 {
+				t.Errorf("ServiceMetric called %d times, want none", len(calls))
+			}
+		})
+	}
+}
+
+// TestMetric_Watch redraws until the context ends, which stands in for
+// Ctrl-C.
+func TestMetric_Watch(t *testing.T) {
+	h := cmdtest.New()
+	project, _ := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+	seedMetrics(t, h, 5)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	var out bytes.Buffer
+	cmd := metric.NewCmdMetric(h.Factory)
+	cmd.SetOut(&out)
+	cmd.SetContext(ctx)
+	if err := h.Run(cmd, "--id", svc.ID, "CPU", "--watch", "--interval", "20ms"); err != nil {
+		t.Fatalf("service metric --watch: %v", err)
+	}
+
+	if redraws := strings.Count(out.String(), "\x1b[2J"); redraws < 2 {
+		t.Errorf("redrew %d times, want at least 2", redraws)
+	}
+	if calls := h.API.CallsTo("ServiceMetric"); len(calls) < 2 {
+		t.Errorf("ServiceMetric called %d times, want a call per redraw", len(calls))
+	}
+}
@@ -0,0 +1,191 @@
+package metric
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"golang.org/x/term"
+
+	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/tui"
+)
+
+const (
+	chartHeight   = 8
+	panelGap      = 3
+	minPanelWidth = 24
+)
+
+// fatih/color leaves the text plain when stdout is not a terminal
+var (
+	bold  = color.New(color.Bold).SprintFunc()
+	faint = color.New(color.Faint).SprintFunc()
+	green = color.New(color.FgGreen).SprintFunc()
+)
+
+// report is the metrics of a service over a time range.
+type report struct {
+	Service    string
+	Start, End time.Time
+	Series     []series
+}
+
+// series is one metric of a report. Values are as the API returns them:
+// CPU as a fraction of a core, memory and network in MB.
+type series struct {
+	Type   model.MetricType `json:"type"`
+	Sum    float64          `json:"sum"`
+	Min    float64          `json:"min"`
+	Avg    float64          `json:"avg"`
+	P95    float64          `json:"p95"`
+	Max    float64          `json:"max"`
+	Points []point          `json:"points"`
+}
+
+type point struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+func newSeries(mt model.MetricType, m *model.ServiceMetric) series {
+	s := series{Type: mt, Points: make([]point, 0, len(m.Metrics))}
+	for _, p := range m.Metrics {
+		s.Points = append(s.Points, point{Timestamp: p.Timestamp, Value: p.Value})
+	}
+	if len(s.Points) == 0 {
+		return s
+	}
+
+	values := s.values()
+	slices.Sort(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	s.Min, s.Max = values[0], values[len(values)-1]
+	s.Sum, s.Avg = sum, sum/float64(len(values))
+	// nearest-rank percentile
+	s.P95 = values[int(math.Ceil(0.95*float64(len(values))))-1]
+	return s
+}
+
+// summary is the JSON of a single metric type, as printed before the series
+// were: its summary with units, or an empty list without data.
+func (s series) summary() any {
+	if len(s.Points) == 0 {
+		return []any{}
+	}
+	return map[string]string{
+		"Sum": s.Type.WithMeasureUnit(s.Sum),
+		"Avg": s.Type.WithMeasureUnit(s.Avg),
+		"Max": s.Type.WithMeasureUnit(s.Max),
+		"Min": s.Type.WithMeasureUnit(s.Min),
+	}
+}
+
+func (s series) values() []float64 {
+	values := make([]float64, 0, len(s.Points))
+	for _, p := range s.Points {
+		values = append(values, p.Value)
+	}
+	return values
+}
+
+func (r *report) empty() bool {
+	for _, s := range r.Series {
+		if len(s.Points) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// render draws a chart for each series, side by side when they fit in
+// width columns and stacked otherwise.
+func (r *report) render(width int) []string {
+	layout := "15:04"
+	if r.End.Sub(r.Start) > 24*time.Hour {
+		layout = "01-02 15:04"
+	}
+	lines := []string{
+		bold(r.Service) + faint(fmt.Sprintf(" · %s → %s", r.Start.Local().Format(layout), r.End.Local().Format(layout))),
+		"",
+	}
+
+	perRow := len(r.Series)
+	for perRow > 1 && (width-panelGap*(perRow-1))/perRow < minPanelWidth {
+		perRow--
+	}
+	panelWidth := max(minPanelWidth, (width-panelGap*(perRow-1))/perRow)
+	if perRow == 1 {
+		panelWidth = max(width, minPanelWidth)
+	}
+
+	for first := 0; first < len(r.Series); first += perRow {
+		row := r.Series[first:min(first+perRow, len(r.Series))]
+		panels := make([][]string, 0, len(row))
+		for _, s := range row {
+			panels = append(panels, s.panel(panelWidth, r.Start.Local().Format(layout), r.End.Local().Format(layout)))
+		}
+		for i := range panels[0] {
+			parts := make([]string, 0, len(panels))
+			for _, panel := range panels {
+				parts = append(parts, tui.Pad(panel[i], panelWidth))
+			}
+			lines = append(lines, strings.TrimRight(strings.Join(parts, strings.Repeat(" ", panelGap)), " "))
+		}
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// panel is the chart of s with a title above it and the time range below.
+func (s series) panel(width int, start, end string) []string {
+	title := string(s.Type)
+	if n := len(s.Points); n > 0 {
+		title += fmt.Sprintf("  now %s  max %s", format(s.Type, s.Points[n-1].Value), format(s.Type, s.Max))
+	}
+	lines := []string{bold(tui.Fit(title, width))}
+
+	if len(s.Points) == 0 {
+		lines = append(lines, faint(tui.Fit("no data", width)))
+		for len(lines) < chartHeight+1 {
+			lines = append(lines, "")
+		}
+	} else {
+		for _, line := range tui.Chart(s.values(), width, chartHeight) {
+			lines = append(lines, green(line))
+		}
+	}
+
+	axis := start
+	if gap := width - tui.Width(start) - tui.Width(end); gap > 0 {
+		axis += strings.Repeat(" ", gap) + end
+	}
+	return append(lines, faint(axis))
+}
+
+// format formats v of the metric type mt for people.
+func format(mt model.MetricType, v float64) string {
+	if mt == model.MetricTypeCPU {
+		return fmt.Sprintf("%.2f%%", v*100)
+	}
+	return fmt.Sprintf("%.2f MB", v)
+}
+
+// terminalWidth returns the width of w when it is a terminal, and 80
+// columns otherwise.
+func terminalWidth(w io.Writer) int {
+	if file, ok := w.(*os.File); ok && term.IsTerminal(int(file.Fd())) {
+		if width, _, err := term.GetSize(int(file.Fd())); err == nil {
+			return width
+		}
+	}
+	return 80
+}
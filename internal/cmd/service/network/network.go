@@ -10,6 +10,7 @@ import (
 	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/fill"
 	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -114,34 +115,31 @@ func runNetwork(f *cmdutil.Factory, opts *Options) error {
 
 	s.Stop()
 
-	if f.JSON {
-		result := map[string]interface{}{
-			"dnsName": dnsName + ".zeabur.internal",
-		}
-		if opts.environmentID != "" {
-			portList := make([]map[string]interface{}, 0, len(ports))
-			for _, p := range ports {
-				pm := map[string]interface{}{
-					"id":   p.ID,
-					"port": p.Port,
-					"type": p.Type,
-				}
-				if p.ForwardedPort != nil {
-					pm["forwardedPort"] = *p.ForwardedPort
-				}
-				portList = append(portList, pm)
+	internalHost := dnsName + ".zeabur.internal"
+
+	result := map[string]interface{}{
+		"dnsName": internalHost,
+	}
+	if opts.environmentID != "" {
+		portList := make([]map[string]interface{}, 0, len(ports))
+		for _, p := range ports {
+			pm := map[string]interface{}{
+				"id":   p.ID,
+				"port": p.Port,
+				"type": p.Type,
 			}
-			result["ports"] = portList
-			result["portForwardingMode"] = string(portForwardingMode)
-			if portForwardingMode == model.PortForwardingModeEnabled {
-				result["portForwardedHost"] = portForwardedHost
+			if p.ForwardedPort != nil {
+				pm["forwardedPort"] = *p.ForwardedPort
 			}
+			portList = append(portList, pm)
+		}
+		result["ports"] = portList
+		result["portForwardingMode"] = string(portForwardingMode)
+		if portForwardingMode == model.PortForwardingModeEnabled {
+			result["portForwardedHost"] = portForwardedHost
 		}
-		return f.Printer.JSON(result)
 	}
 
-	internalHost := dnsName + ".zeabur.internal"
-
 	if len(ports) == 0 {
 		f.Log.Infof("Private DNS name: %s", internalHost)
 		if opts.environmentID == "" {
@@ -149,7 +147,7 @@ func runNetwork(f *cmdutil.Factory, opts *Options) error {
 		} else {
 			f.Log.Warnf("No ports configured for this service")
 		}
-		return nil
+		return f.Printer.Data(printer.Result(result))
 	}
 
 	f.Log.Infof("Private Networking (between services on Zeabur)")
@@ -157,7 +155,6 @@ func runNetwork(f *cmdutil.Factory, opts *Options) error {
 		f.Log.Infof("  %s (%s): %s:%d", p.ID, p.Type, internalHost, p.Port)
 	}
 
-	fmt.Println()
 	f.Log.Infof("Public Networking (connect from outside Zeabur)")
 	if opts.environmentID != "" {
 		for _, p := range ports {
@@ -172,5 +169,5 @@ func runNetwork(f *cmdutil.Factory, opts *Options) error {
 		}
 	}
 
-	return nil
+	return f.Printer.Data(printer.Result(result))
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/fill"
 	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -155,27 +156,24 @@ func runPortForwardNonInteractive(f *cmdutil.Factory, opts *Options) error {
 
 	s.Stop()
 
-	if f.JSON {
-		result := map[string]interface{}{
-			"portForwardingMode": string(mode),
-		}
-		if mode == model.PortForwardingModeEnabled {
-			result["portForwardedHost"] = host
-			portList := make([]map[string]interface{}, 0, len(ports))
-			for _, p := range ports {
-				pm := map[string]interface{}{
-					"id":   p.ID,
-					"port": p.Port,
-					"type": p.Type,
-				}
-				if p.ForwardedPort != nil {
-					pm["forwardedPort"] = *p.ForwardedPort
-				}
-				portList = append(portList, pm)
+	result := map[string]interface{}{
+		"portForwardingMode": string(mode),
+	}
+	if mode == model.PortForwardingModeEnabled {
+		result["portForwardedHost"] = host
+		portList := make([]map[string]interface{}, 0, len(ports))
+		for _, p := range ports {
+			pm := map[string]interface{}{
+				"id":   p.ID,
+				"port": p.Port,
+				"type": p.Type,
+			}
+			if p.ForwardedPort != nil {
+				pm["forwardedPort"] = *p.ForwardedPort
 			}
-			result["ports"] = portList
+			portList = append(portList, pm)
 		}
-		return f.Printer.JSON(result)
+		result["ports"] = portList
 	}
 
 	f.Log.Infof("Port forwarding: %s", mode)
@@ -187,5 +185,5 @@ func runPortForwardNonInteractive(f *cmdutil.Factory, opts *Options) error {
 		}
 	}
 
-	return nil
+	return f.Printer.Data(printer.Result(result))
 }
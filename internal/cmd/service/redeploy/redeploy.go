@@ -8,6 +8,7 @@ import (
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/fill"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -104,10 +105,6 @@ func runRedeployNonInteractive(f *cmdutil.Factory, opts *Options) error {
 		return fmt.Errorf("redeploy service failed: %w", err)
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(map[string]string{"status": "success", "id": opts.id, "message": "Service redeployed successfully"})
-	}
 	f.Log.Infof("Service <%s> redeployed successfully", idOrName)
-
-	return nil
+	return f.Printer.Data(printer.Result(map[string]string{"status": "success", "id": opts.id, "message": "Service redeployed successfully"}))
 }
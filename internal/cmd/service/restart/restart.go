@@ -8,6 +8,7 @@ import (
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/fill"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -104,10 +105,6 @@ func runRestartNonInteractive(f *cmdutil.Factory, opts *Options) error {
 		return fmt.Errorf("restart service failed: %w", err)
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(map[string]string{"status": "success", "id": opts.id, "message": "Service restarted successfully"})
-	}
 	f.Log.Infof("Service <%s> restarted successfully", idOrName)
-
-	return nil
+	return f.Printer.Data(printer.Result(map[string]string{"status": "success", "id": opts.id, "message": "Service restarted successfully"}))
 }
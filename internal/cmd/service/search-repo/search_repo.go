@@ -2,10 +2,12 @@ package searchrepo
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 // NewCmdSearchRepo creates the service search-repo command.
@@ -47,20 +49,12 @@ func runSearchRepo(cmd *cobra.Command, f *cmdutil.Factory, keyword string) error
 	}
 
 	if len(repos) == 0 {
-		if f.JSON {
-			return f.Printer.JSON([]any{})
-		}
 		f.Log.Infof("No repositories found for %q", keyword)
-		return nil
-	}
-
-	if f.JSON {
-		return f.Printer.JSON(repos)
 	}
 
+	rows := make([][]string, 0, len(repos))
 	for _, repo := range repos {
-		fmt.Printf("%s/%s (ID: %d)\n", repo.Owner, repo.Name, repo.ID)
+		rows = append(rows, []string{repo.Owner + "/" + repo.Name, strconv.Itoa(repo.ID)})
 	}
-
-	return nil
+	return f.Printer.Data(printer.WithTable(repos, []string{"Repository", "ID"}, rows))
 }
@@ -13,7 +13,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/zeabur/cli/internal/cmdutil"
-	"github.com/zeabur/cli/pkg/constant"
+	"github.com/zeabur/cli/pkg/printer"
 	"github.com/zeabur/cli/pkg/util"
 )
 
@@ -104,9 +104,6 @@ func runCreate(f *cmdutil.Factory, opts Options) error {
 		return err
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(map[string]string{"status": "success", "id": t.Code, "name": t.Name, "code": t.Code, "message": "Template created"})
-	}
-	f.Log.Infof("Template %q (%s/templates/%s) created", t.Name, constant.ZeaburDashURL, t.Code)
-	return nil
+	f.Log.Infof("Template %q (%s/templates/%s) created", t.Name, f.EffectiveDashURL(), t.Code)
+	return f.Printer.Data(printer.Result(map[string]string{"status": "success", "id": t.Code, "name": t.Name, "code": t.Code, "message": "Template created"}))
 }
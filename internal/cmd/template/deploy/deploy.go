@@ -15,8 +15,8 @@ import (
 	"github.com/hasura/go-graphql-client"
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
-	"github.com/zeabur/cli/pkg/constant"
 	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
 	"github.com/zeabur/cli/pkg/util"
 	"gopkg.in/yaml.v3"
 )
@@ -290,15 +290,12 @@ func runDeploy(f *cmdutil.Factory, opts *Options) error {
 		return err
 	}
 
-	if f.JSON {
-		result := map[string]string{"status": "success", "project_id": res.ID, "project_name": res.Name, "message": "Template deployed"}
-		if d, ok := vars["PUBLIC_DOMAIN"]; ok && project.Region.ID != "sha1" {
-			result["domain"] = d + ".zeabur.app"
-		}
-		return f.Printer.JSON(result)
+	result := map[string]string{"status": "success", "project_id": res.ID, "project_name": res.Name, "message": "Template deployed"}
+	if d, ok := vars["PUBLIC_DOMAIN"]; ok && project.Region.ID != "sha1" {
+		result["domain"] = d + ".zeabur.app"
 	}
 
-	f.Log.Infof("Template successfully deployed into project %q (%s/projects/%s).", res.Name, constant.ZeaburDashURL, res.ID)
+	f.Log.Infof("Template successfully deployed into project %q (%s/projects/%s).", res.Name, f.EffectiveDashURL(), res.ID)
 
 	if d, ok := vars["PUBLIC_DOMAIN"]; ok && project.Region.ID != "sha1" {
 		s = spinner.New(cmdutil.SpinnerCharSet, cmdutil.SpinnerInterval,
@@ -312,7 +309,7 @@ func runDeploy(f *cmdutil.Factory, opts *Options) error {
 		for {
 			if time.Since(start) > 2*time.Minute {
 				s.Stop()
-				return fmt.Errorf("failed to wait service ready, check logs in %s/projects/%s", constant.ZeaburDashURL, res.ID)
+				return fmt.Errorf("failed to wait service ready, check logs in %s/projects/%s", f.EffectiveDashURL(), res.ID)
 			}
 
 			time.Sleep(2 * time.Second)
@@ -332,7 +329,7 @@ func runDeploy(f *cmdutil.Factory, opts *Options) error {
 		}
 	}
 
-	return nil
+	return f.Printer.Data(printer.Result(result))
 }
 
 func paramCheck(f *cmdutil.Factory, opts *Options) error {
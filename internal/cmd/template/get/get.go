@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -90,18 +91,13 @@ func getTemplate(f *cmdutil.Factory, opts Options) error {
 	s.Stop()
 
 	if template == nil || template.Code == "" {
-		if f.JSON {
-			return f.Printer.JSON([]any{})
-		}
-		fmt.Println("Template not found")
-	} else {
-		if f.JSON {
-			return f.Printer.JSON(template)
-		}
-		f.Printer.Table([]string{"Code", "Name", "Description"}, [][]string{{template.Code, template.Name, template.Description}})
+		f.Log.Infof("Template not found")
+		return f.Printer.Data(printer.Result([]any{}))
 	}
-
-	return nil
+	return f.Printer.Data(printer.WithTable(template,
+		[]string{"Code", "Name", "Description"},
+		[][]string{{template.Code, template.Name, template.Description}},
+	))
 }
 
 func getTemplateRaw(code string) error {
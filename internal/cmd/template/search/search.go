@@ -12,6 +12,7 @@ import (
 
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -100,28 +101,16 @@ func runSearch(f *cmdutil.Factory, opts Options) error {
 	})
 
 	if len(matched) == 0 {
-		if f.JSON {
-			return f.Printer.JSON([]any{})
-		}
 		fmt.Println("No templates found")
-		return nil
-	}
-
-	if f.JSON {
-		templates := make([]*model.Template, 0, len(matched))
-		for _, m := range matched {
-			templates = append(templates, m.template)
-		}
-		return f.Printer.JSON(templates)
 	}
 
+	templates := make([]*model.Template, 0, len(matched))
 	header := []string{"Code", "Name", "Description", "Deployments"}
 	rows := make([][]string, 0, len(matched))
 	for _, m := range matched {
 		t := m.template
+		templates = append(templates, t)
 		rows = append(rows, []string{t.Code, t.Name, t.Description, strconv.Itoa(t.DeploymentCnt)})
 	}
-	f.Printer.Table(header, rows)
-
-	return nil
+	return f.Printer.Data(printer.WithTable(templates, header, rows))
 }
@@ -0,0 +1,66 @@
+// Package unlink contains the cmd for removing a directory link
+package unlink
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
+	"github.com/zeabur/cli/pkg/zcontext"
+)
+
+type Options struct {
+	dir string
+}
+
+func NewCmdUnlink(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "unlink",
+		Short: "Remove the link of the current directory",
+		Long:  "Remove the link written by `zeabur link` for the current directory or its nearest linked parent.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUnlink(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.dir, "dir", ".", "Directory to unlink")
+
+	cmdutil.DisableLinkCheck(cmd)
+
+	return cmd
+}
+
+func runUnlink(f *cmdutil.Factory, opts *Options) error {
+	link, err := zcontext.FindLink(opts.dir)
+	if err != nil {
+		// a broken link file can still be removed
+		path := zcontext.LinkPath(opts.dir)
+		if _, statErr := os.Stat(path); statErr != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		f.ResetLink()
+		f.Log.Infof("Removed %s", path)
+		return nil
+	}
+	if link == nil {
+		return errors.New("no link found in this directory or its parents")
+	}
+
+	if err := zcontext.RemoveLink(link); err != nil {
+		return fmt.Errorf("remove link: %w", err)
+	}
+	f.ResetLink()
+
+	f.Log.Infof("Unlinked %s from project <%s>", link.Dir(), link.Project.Name)
+	return f.Printer.Data(printer.Result(map[string]string{"path": link.Path()}))
+}
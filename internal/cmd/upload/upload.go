@@ -13,10 +13,8 @@ import (
 
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/internal/util"
-	"github.com/zeabur/cli/pkg/constant"
 	pkgutil "github.com/zeabur/cli/pkg/util"
 )
 
@@ -50,17 +48,19 @@ func runUpload(f *cmdutil.Factory, opts *Options) error {
 	)
 	s.Start()
 
-	uploadID, err := UploadZipToService(context.Background(), bytes)
+	uploadID, err := UploadZipToService(context.Background(), f.EffectiveAPIURL(), f.Config.GetTokenString(), bytes)
 	if err != nil {
 		return err
 	}
 	s.Stop()
 
-	fmt.Println(constant.ZeaburDashURL + "/uploads/" + uploadID)
+	fmt.Println(f.EffectiveDashURL() + "/uploads/" + uploadID)
 	return nil
 }
 
-func UploadZipToService(ctx context.Context, zipBytes []byte) (string, error) {
+// UploadZipToService uploads zipBytes as a new project through the upload
+// REST API at serverURL, authenticated with token, and returns the upload ID.
+func UploadZipToService(ctx context.Context, serverURL, token string, zipBytes []byte) (string, error) {
 	// Step 1: Calculate SHA256 hash of content
 	h := sha256.New()
 	if _, err := h.Write(zipBytes); err != nil {
@@ -84,12 +84,11 @@ func UploadZipToService(ctx context.Context, zipBytes []byte) (string, error) {
 		return "", fmt.Errorf("failed to marshal create upload request: %w", err)
 	}
 
-	createUploadResp, err := http.NewRequestWithContext(ctx, "POST", constant.ZeaburServerURL+"/v2/upload", bytes.NewReader(createUploadBody))
+	createUploadResp, err := http.NewRequestWithContext(ctx, "POST", serverURL+"/v2/upload", bytes.NewReader(createUploadBody))
 	if err != nil {
 		return "", fmt.Errorf("failed to create upload request: %w", err)
 	}
 
-	token := viper.GetString("token")
 	createUploadResp.Header.Set("Content-Type", "application/json")
 	createUploadResp.Header.Set("Cookie", "token="+token)
 
@@ -154,7 +153,7 @@ func UploadZipToService(ctx context.Context, zipBytes []byte) (string, error) {
 	}
 
 	prepareResp, err := http.NewRequestWithContext(ctx, "POST",
-		fmt.Sprintf("%s/v2/upload/%s/prepare", constant.ZeaburServerURL, uploadSession.UploadID),
+		fmt.Sprintf("%s/v2/upload/%s/prepare", serverURL, uploadSession.UploadID),
 		bytes.NewReader(prepareBody))
 	if err != nil {
 		return "", fmt.Errorf("failed to create prepare request: %w", err)
@@ -0,0 +1,369 @@
+package copy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	variableDiffCmd "github.com/zeabur/cli/internal/cmd/variable/diff"
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/envfile"
+	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/printer"
+)
+
+// Conflict policies: what to do with a variable the target already has
+// with another value.
+const (
+	conflictFail      = "fail"
+	conflictSkip      = "skip"
+	conflictOverwrite = "overwrite"
+)
+
+type Options struct {
+	fromProject string
+	fromService string
+	fromEnv     string
+	toProject   string
+	toService   string
+	toEnv       string
+
+	include        []string
+	exclude        []string
+	rename         map[string]string
+	onConflict     string
+	includeExposed bool
+	dryRun         bool
+	skipConfirm    bool
+
+	out io.Writer
+}
+
+func NewCmdCopyVariables(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "copy --from-service SERVICE --to-service SERVICE",
+		Short: "Copy variables between services and environments",
+		Long: heredoc.Doc(`
+			Copy the variables of a service to another service, or to the same service
+			in another environment, e.g. to promote configuration from staging to
+			production. Services and environments are given by name or ID; services
+			are looked up in --from-project and --to-project, by default the current
+			project.
+
+			--include and --exclude select the keys to copy with glob patterns such
+			as 'STRIPE_*', and --rename gives copied keys another name in the target.
+			Read-only variables exposed by other services are copied as ordinary
+			variables with --include-exposed.
+
+			A key the target already has with another value is a conflict: by default
+			nothing is copied (--on-conflict fail); 'skip' keeps the target's value and
+			'overwrite' replaces it. Variables of the target that are not copied are
+			left alone.
+
+			The changes are shown, with values masked, and confirmed before they are
+			applied; --dry-run stops after showing them, and --yes skips the
+			confirmation, which non-interactive mode requires.
+		`),
+		Example: heredoc.Doc(`
+			# promote the configuration of the API from staging to production
+			$ zeabur variable copy --from-service api --from-env staging --to-env production --on-conflict overwrite
+
+			# share the Stripe keys of one service with another, under other names
+			$ zeabur variable copy --from-service api --to-service worker --include 'STRIPE_*' \
+			    --rename STRIPE_SECRET_KEY=PAYMENTS_KEY --dry-run
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.out = cmd.OutOrStdout()
+			return runCopyVariables(cmd.Context(), f, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.fromProject, "from-project", "", "Project of the source service, by name or ID (default the current project)")
+	cmd.Flags().StringVar(&opts.fromService, "from-service", "", "Service to copy from, by name or ID")
+	cmd.Flags().StringVar(&opts.fromEnv, "from-env", "", "Environment to copy from, by name or ID")
+	cmd.Flags().StringVar(&opts.toProject, "to-project", "", "Project of the target service, by name or ID (default the source project)")
+	cmd.Flags().StringVar(&opts.toService, "to-service", "", "Service to copy to, by name or ID (default the source service)")
+	cmd.Flags().StringVar(&opts.toEnv, "to-env", "", "Environment to copy to, by name or ID")
+	cmd.Flags().StringSliceVar(&opts.include, "include", nil, "Only copy keys matching these glob patterns, comma-separated")
+	cmd.Flags().StringSliceVar(&opts.exclude, "exclude", nil, "Do not copy keys matching these glob patterns, comma-separated")
+	cmd.Flags().StringToStringVar(&opts.rename, "rename", nil, "Copy keys under other names, e.g. OLD=NEW")
+	cmd.Flags().StringVar(&opts.onConflict, "on-conflict", conflictFail, "What to do with keys the target has with another value: fail, skip or overwrite")
+	cmd.Flags().BoolVar(&opts.includeExposed, "include-exposed", false, "Also copy the read-only variables exposed by other services")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show the changes without applying them")
+	cmd.Flags().BoolVarP(&opts.skipConfirm, "yes", "y", false, "Skip confirmation")
+	util.SetFlagCompletion(cmd, "from-service", util.CompleteServiceName)
+	util.SetFlagCompletion(cmd, "to-service", util.CompleteServiceName)
+
+	return cmd
+}
+
+// endpoint is a service in an environment, the source or target of a copy.
+type endpoint struct {
+	projectID     string
+	service       string
+	serviceID     string
+	environment   string
+	environmentID string
+}
+
+func (e *endpoint) String() string {
+	return e.service + " (" + e.environment + ")"
+}
+
+func runCopyVariables(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
+	if err := opts.check(); err != nil {
+		return err
+	}
+
+	from, err := resolveEndpoint(ctx, f, opts.fromProject, opts.fromService, opts.fromEnv)
+	if err != nil {
+		return fmt.Errorf("source: %w", err)
+	}
+	toProject, toService := opts.toProject, opts.toService
+	if toProject == "" {
+		toProject = from.projectID
+	}
+	if toService == "" {
+		toService = from.serviceID
+	}
+	to, err := resolveEndpoint(ctx, f, toProject, toService, opts.toEnv)
+	if err != nil {
+		return fmt.Errorf("target: %w", err)
+	}
+	if from.serviceID == to.serviceID && from.environmentID == to.environmentID {
+		return errors.New("the source and the target are the same service in the same environment")
+	}
+
+	sourceVars, sourceExposed, err := f.ApiClient.ListVariables(ctx, from.serviceID, from.environmentID)
+	if err != nil {
+		return fmt.Errorf("list variables of %s: %w", from, err)
+	}
+	source := sourceVars.ToMap()
+	if opts.includeExposed {
+		for _, v := range sourceExposed {
+			if _, ok := source[v.Key]; !ok {
+				source[v.Key] = v.Value
+			}
+		}
+	}
+	copied, err := opts.selectKeys(source)
+	if err != nil {
+		return err
+	}
+	if len(copied) == 0 {
+		f.Log.Infof("No variables of %s to copy", from)
+		return nil
+	}
+
+	targetVars, targetExposed, err := f.ApiClient.ListVariables(ctx, to.serviceID, to.environmentID)
+	if err != nil {
+		return fmt.Errorf("list variables of %s: %w", to, err)
+	}
+	current := targetVars.ToMap()
+
+	var readonly []string
+	for _, v := range targetExposed {
+		if _, ok := copied[v.Key]; ok {
+			readonly = append(readonly, v.Key)
+			delete(copied, v.Key)
+		}
+	}
+	if len(readonly) > 0 {
+		slices.Sort(readonly)
+		f.Log.Warnf("Not copying %s: read-only in %s", strings.Join(readonly, ", "), to)
+	}
+
+	// only additions and updates: the target's other variables stay
+	var changes []envfile.Change
+	var conflicts []string
+	for _, c := range envfile.Diff(current, copied, false) {
+		if c.Kind == envfile.ChangeUpdate && opts.onConflict != conflictOverwrite {
+			conflicts = append(conflicts, c.Key)
+			continue
+		}
+		changes = append(changes, c)
+	}
+	if len(conflicts) > 0 {
+		if opts.onConflict == conflictFail {
+			return fmt.Errorf("%s already has %s with another value; use --on-conflict skip or overwrite", to, strings.Join(conflicts, ", "))
+		}
+		f.Log.Infof("Skipping %s: %s already has them with another value", strings.Join(conflicts, ", "), to)
+	}
+
+	masked := make([]envfile.Change, 0, len(changes))
+	for _, c := range changes {
+		masked = append(masked, c.Masked(util.MaskValue))
+	}
+
+	if len(changes) == 0 {
+		f.Log.Infof("Nothing to copy: %s already has the variables", to)
+		return f.Printer.Data(printer.Result(masked))
+	}
+
+	if !f.StructuredOutput() {
+		f.Log.Infof("Copying variables of %s to %s:", from, to)
+		if err := variableDiffCmd.PrintChanges(opts.out, masked); err != nil {
+			return err
+		}
+	}
+	if opts.dryRun {
+		return f.Printer.Data(printer.Result(masked))
+	}
+
+	if f.Interactive && !opts.skipConfirm {
+		confirm, err := f.Prompter.Confirm("Do you want to apply these changes?", false)
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			return nil
+		}
+	} else if !f.Interactive && !opts.skipConfirm {
+		return fmt.Errorf("copying variables requires --yes flag in non-interactive mode")
+	}
+
+	// UpdateVariables replaces the whole set, so send the current variables
+	// with the changes made
+	ok, err := f.ApiClient.UpdateVariables(ctx, to.serviceID, to.environmentID, envfile.Apply(current, changes))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("failed to update variables of %s", to)
+	}
+
+	f.Log.Infof("%s Copied %d variable(s) to %s. Restart the service manually to apply the changes.", cmdutil.SuccessIcon, len(changes), to)
+	return f.Printer.Data(printer.Result(masked))
+}
+
+func (opts *Options) check() error {
+	if opts.fromService == "" {
+		return errors.New("--from-service is required")
+	}
+	switch opts.onConflict {
+	case conflictFail, conflictSkip, conflictOverwrite:
+	default:
+		return fmt.Errorf("unknown --on-conflict %q, must be fail, skip or overwrite", opts.onConflict)
+	}
+	for _, pattern := range slices.Concat(opts.include, opts.exclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	for from, to := range opts.rename {
+		if from == "" || to == "" {
+			return fmt.Errorf("invalid --rename %s=%s: both names are required", from, to)
+		}
+	}
+	return nil
+}
+
+// selectKeys returns the source variables to copy, filtered by --include and
+// --exclude and renamed by --rename, keyed by their name in the target.
+func (opts *Options) selectKeys(source map[string]string) (map[string]string, error) {
+	matches := func(patterns []string, key string) bool {
+		return slices.ContainsFunc(patterns, func(p string) bool {
+			ok, _ := path.Match(p, key)
+			return ok
+		})
+	}
+
+	copied := map[string]string{}
+	from := map[string]string{}
+	for _, key := range slices.Sorted(maps.Keys(source)) {
+		if len(opts.include) > 0 && !matches(opts.include, key) || matches(opts.exclude, key) {
+			continue
+		}
+		name := key
+		if renamed, ok := opts.rename[key]; ok {
+			name = renamed
+		}
+		if other, ok := from[name]; ok {
+			return nil, fmt.Errorf("both %s and %s would be copied to %s", other, key, name)
+		}
+		from[name] = key
+		copied[name] = source[key]
+	}
+	for key := range opts.rename {
+		if !slices.Contains(slices.Collect(maps.Values(from)), key) {
+			return nil, fmt.Errorf("--rename %s: the source has no such variable to copy", key)
+		}
+	}
+	return copied, nil
+}
+
+// resolveEndpoint looks up a service and an environment by name or ID. The
+// project is only needed to find services by name.
+func resolveEndpoint(ctx context.Context, f *cmdutil.Factory, projectRef, serviceRef, envRef string) (*endpoint, error) {
+	e := &endpoint{}
+
+	switch {
+	case projectRef != "" && util.IsObjectID(string(api.ObjectID(projectRef))):
+		e.projectID = string(api.ObjectID(projectRef))
+	case projectRef != "":
+		project, err := util.GetProjectByName(f.ApiClient, f.CurrentOwnerID(), f.Config.GetUsername(), projectRef)
+		if err != nil {
+			return nil, err
+		}
+		e.projectID = project.ID
+	}
+
+	if id := string(api.ObjectID(serviceRef)); util.IsObjectID(id) {
+		service, err := f.ApiClient.GetService(ctx, id, "", "", "")
+		if err != nil {
+			return nil, fmt.Errorf("get service %s: %w", serviceRef, err)
+		}
+		e.service, e.serviceID = service.Name, service.ID
+		if e.projectID == "" && service.Project != nil {
+			e.projectID = service.Project.ID
+		}
+	} else {
+		if e.projectID == "" {
+			e.projectID = f.CurrentProjectID()
+		}
+		if e.projectID == "" {
+			return nil, fmt.Errorf("no project to find service %q in: pass a project or a service ID", serviceRef)
+		}
+		services, err := util.SelectServices(ctx, f.ApiClient, e.projectID, []string{serviceRef})
+		if err != nil {
+			return nil, err
+		}
+		e.service, e.serviceID = services[0].Name, services[0].ID
+	}
+	if e.projectID == "" {
+		return nil, fmt.Errorf("service %s has no project", e.service)
+	}
+
+	environments, err := f.ApiClient.ListEnvironments(ctx, e.projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list environments: %w", err)
+	}
+	if len(environments) == 0 {
+		return nil, fmt.Errorf("no environment found for project %s", e.projectID)
+	}
+	env := environments[0]
+	if envRef != "" {
+		i := slices.IndexFunc(environments, func(env *model.Environment) bool {
+			return env.Name == envRef || env.ID == string(api.ObjectID(envRef))
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("no environment %q in the project of %s", envRef, e.service)
+		}
+		env = environments[i]
+	}
+	e.environment, e.environmentID = env.Name, env.ID
+	return e, nil
+}
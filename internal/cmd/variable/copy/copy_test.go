@@ -0,0 +1,137 @@
+package copy_test
+
+import (
+	"bytes"
+	"maps"
+	"strings"
+	"testing"
+
+	variableCopy "github.com/zeabur/cli/internal/cmd/variable/copy"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/zcontext"
+)
+
+type fixture struct {
+	h                *cmdtest.Harness
+	api, worker      *model.Service
+	staging, product *model.Environment
+}
+
+// seed adds a project with staging and production environments, whose
+// current project is the one holding api and worker.
+func seed(t *testing.T) *fixture {
+	t.Helper()
+	h := cmdtest.New()
+	project, production := h.API.SeedProject("", "shop")
+	staging := h.API.SeedEnvironment(project.ID, "staging")
+	api := h.API.SeedService(project.ID, "api")
+	worker := h.API.SeedService(project.ID, "worker")
+	h.Config.GetContext().SetProject(zcontext.NewBasicInfo(project.ID, project.Name))
+
+	h.API.SeedVariables(api.ID, staging.ID, map[string]string{
+		"STRIPE_KEY":     "sk_test_new",
+		"STRIPE_WEBHOOK": "whsec_1",
+		"LOG_LEVEL":      "debug",
+		"FEATURE_X":      "on",
+	})
+	h.API.SeedVariables(api.ID, production.ID, map[string]string{
+		"STRIPE_KEY": "sk_live_old",
+		"LOG_LEVEL":  "info",
+		"PORT":       "8080",
+	})
+	return &fixture{h: h, api: api, worker: worker, staging: staging, product: production}
+}
+
+func TestCopyVariables_Promote(t *testing.T) {
+	fx := seed(t)
+
+	var out bytes.Buffer
+	cmd := variableCopy.NewCmdCopyVariables(fx.h.Factory)
+	cmd.SetOut(&out)
+	err := fx.h.Run(cmd, "--from-service", "api", "--from-env", "staging", "--to-env", "production",
+		"--exclude", "LOG_*", "--on-conflict", "overwrite", "--yes")
+	if err != nil {
+		t.Fatalf("variable copy: %v", err)
+	}
+
+	want := map[string]string{"STRIPE_KEY": "sk_test_new", "STRIPE_WEBHOOK": "whsec_1", "FEATURE_X": "on", "LOG_LEVEL": "info", "PORT": "8080"}
+	if got := fx.h.API.Variables(fx.api.ID, fx.product.ID); !maps.Equal(got, want) {
+		t.Errorf("production variables = %v, want %v", got, want)
+	}
+	if !strings.Contains(out.String(), "~ STRIPE_KEY: ******** → ********") || !strings.Contains(out.String(), "2 to add, 1 to change, 0 to remove.") {
+		t.Errorf("printed\n%s", out.String())
+	}
+}
+
+func TestCopyVariables_Conflicts(t *testing.T) {
+	fx := seed(t)
+	args := []string{"--from-service", fx.api.ID, "--from-env", "staging", "--to-env", fx.product.ID, "--include", "STRIPE_*", "--yes"}
+
+	err := fx.h.Run(variableCopy.NewCmdCopyVariables(fx.h.Factory), args...)
+	if err == nil || !strings.Contains(err.Error(), "already has STRIPE_KEY with another value") {
+		t.Fatalf("error = %v, want a conflict", err)
+	}
+	if n := len(fx.h.API.CallsTo("UpdateVariables")); n != 0 {
+		t.Fatalf("UpdateVariables called %d times after a conflict", n)
+	}
+
+	if err := fx.h.Run(variableCopy.NewCmdCopyVariables(fx.h.Factory), append(args, "--on-conflict", "skip")...); err != nil {
+		t.Fatalf("variable copy --on-conflict skip: %v", err)
+	}
+	got := fx.h.API.Variables(fx.api.ID, fx.product.ID)
+	if got["STRIPE_KEY"] != "sk_live_old" || got["STRIPE_WEBHOOK"] != "whsec_1" || got["FEATURE_X"] != "" {
+		t.Errorf("production variables = %v, want STRIPE_WEBHOOK added and STRIPE_KEY kept", got)
+	}
+}
+
+func TestCopyVariables_RenameAndDryRun(t *testing.T) {
+	fx := seed(t)
+	fx.h.Factory.Output = "json"
+
+	err := fx.h.Run(variableCopy.NewCmdCopyVariables(fx.h.Factory), "--from-service", "api", "--from-env", "staging",
+		"--to-service", "worker", "--include", "STRIPE_KEY", "--rename", "STRIPE_KEY=PAYMENTS_KEY", "--dry-run")
+	if err != nil {
+		t.Fatalf("variable copy: %v", err)
+	}
+	var changes []struct {
+		Kind string `json:"kind"`
+		Key  string `json:"key"`
+		New  string `json:"new"`
+	}
+	if err := fx.h.Printer.DecodeLastJSON(&changes); err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Kind != "add" || changes[0].Key != "PAYMENTS_KEY" || changes[0].New != "********" {
+		t.Errorf("changes = %+v", changes)
+	}
+	if n := len(fx.h.API.CallsTo("UpdateVariables")); n != 0 {
+		t.Errorf("UpdateVariables called %d times in a dry run", n)
+	}
+}
+
+func TestCopyVariables_Validation(t *testing.T) {
+	fx := seed(t)
+
+	for _, tc := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"--to-service", "worker"}, "--from-service is required"},
+		{[]string{"--from-service", "api", "--on-conflict", "merge"}, "unknown --on-conflict"},
+		{[]string{"--from-service", "api", "--include", "[A-"}, "invalid pattern"},
+		{[]string{"--from-service", "api", "--from-env", "staging", "--to-env", "staging"}, "are the same"},
+		{[]string{"--from-service", "api", "--from-env", "qa"}, `no environment "qa"`},
+		{[]string{"--from-service", "billing"}, `no service named "billing"`},
+		{[]string{"--from-service", "api", "--from-env", "staging", "--rename", "MISSING=X", "--yes"}, "--rename MISSING"},
+		{[]string{"--from-service", "api", "--from-env", "staging", "--include", "STRIPE_WEBHOOK"}, "requires --yes"},
+	} {
+		err := fx.h.Run(variableCopy.NewCmdCopyVariables(fx.h.Factory), tc.args...)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%v: error = %v, want %q", tc.args, err, tc.err)
+		}
+	}
+	if n := len(fx.h.API.CallsTo("UpdateVariables")); n != 0 {
+		t.Errorf("UpdateVariables called %d times", n)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/fill"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -42,6 +43,10 @@ func NewCmdCreateVariable(f *cmdutil.Factory) *cobra.Command {
 }
 
 func runCreateVariable(f *cmdutil.Factory, opts *Options) error {
+	if err := f.LinkedService(&opts.id, &opts.name, &opts.environmentID); err != nil {
+		return err
+	}
+
 	if f.Interactive {
 		return runCreateVariableInteractive(f, opts)
 	} else {
@@ -86,6 +91,11 @@ func runCreateVariableInteractive(f *cmdutil.Factory, opts *Options) error {
 }
 
 func runCreateVariableNonInteractive(f *cmdutil.Factory, opts *Options) error {
+	masker, err := util.NewMasker(f.Config.GetSecretPatterns(), nil)
+	if err != nil {
+		return err
+	}
+
 	if opts.id == "" && opts.name != "" {
 		service, err := util.GetServiceByName(f.ApiClient, f.CurrentOwnerID(), f.Config.GetUsername(), f.CurrentProjectName(), f.CurrentProjectID(), opts.name)
 		if err != nil {
@@ -136,21 +146,13 @@ func runCreateVariableNonInteractive(f *cmdutil.Factory, opts *Options) error {
 	}
 	s.Stop()
 
-	if f.JSON {
-		out := make([]map[string]string, 0, len(varMap))
-		for k, v := range varMap {
-			out = append(out, map[string]string{"Key": k, "Value": v})
-		}
-		return f.Printer.JSON(out)
-	}
-
 	f.Log.Infof("Successfully created variables of service: %s\n", opts.name)
 
+	out := make([]map[string]string, 0, len(varMap))
 	table := make([][]string, 0, len(varMap))
 	for k, v := range varMap {
-		table = append(table, []string{k, v})
+		out = append(out, map[string]string{"Key": k, "Value": masker.Value(k, v)})
+		table = append(table, []string{k, masker.Value(k, v)})
 	}
-	f.Printer.Table([]string{"Key", "Value"}, table)
-
-	return nil
+	return f.Printer.Data(printer.WithTable(out, []string{"Key", "Value"}, table))
 }
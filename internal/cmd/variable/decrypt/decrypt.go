@@ -0,0 +1,78 @@
+package decrypt
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/envcrypt"
+	"github.com/zeabur/cli/pkg/envfile"
+)
+
+type Options struct {
+	file    string
+	inPlace bool
+
+	out io.Writer
+}
+
+func NewCmdDecryptVariables(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "decrypt",
+		Short: "Decrypt a file encrypted with 'zeabur variable encrypt'",
+		Long: heredoc.Docf(`
+			Decrypt a variable file encrypted with 'zeabur variable encrypt', with the
+			age identities in $%s, in the file $%s names or in
+			~/.config/zeabur/age-keys.txt, or else with the passphrase, read from
+			$%s or asked for on the terminal.
+
+			The decrypted variables are printed unless --in-place is given. To change
+			an encrypted file, prefer 'zeabur variable edit', which never leaves the
+			values in clear on disk.
+		`, util.AgeKeyEnv, util.AgeKeyFileEnv, util.VariablesPassphraseEnv),
+		Example: heredoc.Doc(`
+			$ zeabur variable decrypt --file production.env
+			$ ZEABUR_AGE_KEY_FILE=keys.txt zeabur variable decrypt --file staging.yaml --in-place
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.out = cmd.OutOrStdout()
+			return runDecryptVariables(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.file, "file", "f", ".env", "Path to the encrypted .env, JSON or YAML file")
+	cmd.Flags().BoolVar(&opts.inPlace, "in-place", false, "Overwrite the file instead of printing the result")
+
+	return cmd
+}
+
+func runDecryptVariables(f *cmdutil.Factory, opts *Options) error {
+	vars, err := envfile.Read(opts.file)
+	if err != nil {
+		return err
+	}
+	if !envcrypt.IsEncrypted(vars) {
+		return fmt.Errorf("%s is not encrypted", opts.file)
+	}
+	decrypted, err := util.DecryptVariableFile(opts.file, vars)
+	if err != nil {
+		return err
+	}
+
+	format := envfile.FormatOf(opts.file)
+	if !opts.inPlace {
+		return envfile.Write(opts.out, decrypted.Vars, format)
+	}
+	if err := util.WriteVariableFile(opts.file, decrypted.Vars, format); err != nil {
+		return err
+	}
+	f.Log.Infof("Decrypted %d variable(s) in %s", len(decrypted.Vars), opts.file)
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/fill"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -43,6 +44,10 @@ func NewCmdDeleteVariable(f *cmdutil.Factory) *cobra.Command {
 }
 
 func runDeleteVariable(f *cmdutil.Factory, opts *Options) error {
+	if err := f.LinkedService(&opts.id, &opts.name, &opts.environmentID); err != nil {
+		return err
+	}
+
 	opts.keys = make(map[string]string)
 
 	if f.Interactive {
@@ -80,7 +85,7 @@ func runDeleteVariableInteractive(f *cmdutil.Factory, opts *Options) error {
 	selectTable := make([]string, 0, len(varMap))
 	for k, v := range varMap {
 		keyTable = append(keyTable, k)
-		selectTable = append(selectTable, fmt.Sprintf("%s = %s", k, v))
+		selectTable = append(selectTable, fmt.Sprintf("%s = %s", k, util.MaskValue(v)))
 		opts.keys[k] = v
 	}
 
@@ -105,6 +110,11 @@ func runDeleteVariableInteractive(f *cmdutil.Factory, opts *Options) error {
 }
 
 func runDeleteVariableNonInteractive(f *cmdutil.Factory, opts *Options) error {
+	masker, err := util.NewMasker(f.Config.GetSecretPatterns(), nil)
+	if err != nil {
+		return err
+	}
+
 	if opts.id == "" && opts.name != "" {
 		service, err := util.GetServiceByName(f.ApiClient, f.CurrentOwnerID(), f.Config.GetUsername(), f.CurrentProjectName(), f.CurrentProjectID(), opts.name)
 		if err != nil {
@@ -159,22 +169,16 @@ func runDeleteVariableNonInteractive(f *cmdutil.Factory, opts *Options) error {
 	}
 	s.Stop()
 
-	if f.JSON {
-		return f.Printer.JSON(map[string]any{
-			"status":      "success",
-			"id":          opts.id,
-			"message":     "Variables deleted successfully",
-			"deletedKeys": opts.deleteKeys,
-		})
-	}
-
 	f.Log.Infof("Successfully deleted variables of service: %s\n", opts.name)
 
 	table := make([][]string, 0, len(opts.keys))
 	for k, v := range opts.keys {
-		table = append(table, []string{k, v})
-	}
-	f.Printer.Table([]string{"Key", "Value"}, table)
-
-	return nil
+		table = append(table, []string{k, masker.Value(k, v)})
+	}
+	return f.Printer.Data(printer.WithTable(map[string]any{
+		"status":      "success",
+		"id":          opts.id,
+		"message":     "Variables deleted successfully",
+		"deletedKeys": opts.deleteKeys,
+	}, []string{"Key", "Value"}, table))
 }
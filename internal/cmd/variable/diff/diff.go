@@ -0,0 +1,164 @@
+package diff
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/envfile"
+	"github.com/zeabur/cli/pkg/fill"
+)
+
+type Options struct {
+	id            string
+	name          string
+	environmentID string
+	file          string
+	reveal        []string
+
+	out    io.Writer
+	errOut io.Writer
+}
+
+func NewCmdDiffVariables(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show how variables differ from a .env, JSON or YAML file",
+		Long: heredoc.Doc(`
+			Compare the variables of a service with a .env, JSON or YAML file: the
+			variables the file adds (+), changes (~) and lacks (-). 'zeabur variable
+			sync' applies the same changes, removing variables only with --prune.
+			Values are masked unless --reveal is given, for every key or only the
+			given ones.
+		`),
+		Example: heredoc.Doc(`
+			$ zeabur variable diff --name web --file .env
+			$ zeabur variable diff --name web --file variables.yaml --reveal
+			$ zeabur variable diff --name web --reveal LOG_LEVEL --reveal PORT
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.out = cmd.OutOrStdout()
+			opts.errOut = cmd.ErrOrStderr()
+			return runDiffVariables(cmd.Context(), f, opts)
+		},
+	}
+
+	util.AddServiceParam(cmd, &opts.id, &opts.name)
+	util.AddEnvOfServiceParam(cmd, &opts.environmentID)
+	cmd.Flags().StringVarP(&opts.file, "file", "f", ".env", "Path to the .env, JSON or YAML file")
+	util.AddRevealParam(cmd, &opts.reveal)
+
+	return cmd
+}
+
+func runDiffVariables(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
+	if err := f.LinkedService(&opts.id, &opts.name, &opts.environmentID); err != nil {
+		return err
+	}
+
+	if f.Interactive {
+		return runDiffVariablesInteractive(ctx, f, opts)
+	}
+	return runDiffVariablesNonInteractive(ctx, f, opts)
+}
+
+func runDiffVariablesInteractive(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
+	zctx := f.EffectiveContext()
+
+	if _, err := f.ParamFiller.ServiceByNameWithEnvironment(fill.ServiceByNameWithEnvironmentOptions{
+		ProjectCtx:    zctx,
+		ServiceID:     &opts.id,
+		ServiceName:   &opts.name,
+		EnvironmentID: &opts.environmentID,
+		CreateNew:     false,
+	}); err != nil {
+		return err
+	}
+
+	return runDiffVariablesNonInteractive(ctx, f, opts)
+}
+
+func runDiffVariablesNonInteractive(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
+	masker, err := util.NewMasker(f.Config.GetSecretPatterns(), opts.reveal)
+	if err != nil {
+		return err
+	}
+
+	if opts.id == "" && opts.name != "" {
+		service, err := util.GetServiceByName(f.ApiClient, f.CurrentOwnerID(), f.Config.GetUsername(), f.CurrentProjectName(), f.CurrentProjectID(), opts.name)
+		if err != nil {
+			return err
+		}
+		opts.id = service.ID
+	}
+
+	if opts.id == "" {
+		return fmt.Errorf("--id or --name is required")
+	}
+
+	if opts.environmentID == "" {
+		envID, err := util.ResolveEnvironmentIDByServiceID(f.ApiClient, opts.id)
+		if err != nil {
+			return err
+		}
+		opts.environmentID = envID
+	}
+
+	d, err := util.DiffVariables(ctx, f.ApiClient, opts.id, opts.environmentID, opts.file, true)
+	if err != nil {
+		return err
+	}
+	if len(d.ReadOnly) > 0 {
+		f.Log.Warnf("Ignoring read-only variable(s) in %s: %s", opts.file, strings.Join(d.ReadOnly, ", "))
+	}
+	if len(d.Masked) > 0 {
+		f.Log.Warnf("Leaving masked value(s) in %s unchanged: %s", opts.file, strings.Join(d.Masked, ", "))
+	}
+
+	changes := d.Changes
+	for i, c := range changes {
+		if !masker.Reveals(c.Key) {
+			changes[i] = c.Masked(util.MaskValue)
+		}
+	}
+	if !f.Interactive {
+		defer util.NoticeRevealed(opts.errOut, masker, f.Config.GetUsername(), fmt.Sprintf("service %s, environment %s", cmp.Or(opts.name, opts.id), opts.environmentID))
+	}
+
+	if f.StructuredOutput() {
+		if changes == nil {
+			changes = []envfile.Change{}
+		}
+		return f.Printer.Data(changes)
+	}
+
+	if len(changes) == 0 {
+		f.Log.Infof("No differences: the variables match %s", opts.file)
+		return nil
+	}
+	return PrintChanges(opts.out, changes)
+}
+
+// PrintChanges prints a line per change and a summary.
+func PrintChanges(w io.Writer, changes []envfile.Change) error {
+	counts := map[envfile.ChangeKind]int{}
+	for _, c := range changes {
+		counts[c.Kind]++
+		if _, err := fmt.Fprintln(w, "  "+c.String()); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n%d to add, %d to change, %d to remove.\n",
+		counts[envfile.ChangeAdd], counts[envfile.ChangeUpdate], counts[envfile.ChangeRemove])
+	return err
+}
@@ -0,0 +1,68 @@
+package diff_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zeabur/cli/internal/cmd/variable/diff"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/pkg/envfile"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+func seed(t *testing.T) (*cmdtest.Harness, string, string) {
+	t.Helper()
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+	h.API.SeedVariables(svc.ID, env.ID, map[string]string{"PORT": "8080", "API_KEY": "sk-old-secret", "LEGACY": "1"})
+	h.API.SeedExposedVariables(svc.ID, env.ID, model.Variables{{Key: "POSTGRES_HOST", Value: "db"}})
+
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "PORT=8080\nAPI_KEY=sk-new-secret\nDEBUG=true\nPOSTGRES_HOST=localhost\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return h, svc.ID, path
+}
+
+func TestDiffVariables_Masked(t *testing.T) {
+	h, serviceID, path := seed(t)
+
+	var out bytes.Buffer
+	cmd := diff.NewCmdDiffVariables(h.Factory)
+	cmd.SetOut(&out)
+	if err := h.Run(cmd, "--id", serviceID, "--file", path); err != nil {
+		t.Fatalf("variable diff: %v", err)
+	}
+
+	want := "  ~ API_KEY: ******** → ********\n  + DEBUG: ********\n  - LEGACY\n\n1 to add, 1 to change, 1 to remove.\n"
+	if out.String() != want {
+		t.Errorf("printed\n%s\nwant\n%s", out.String(), want)
+	}
+	if strings.Contains(out.String(), "POSTGRES_HOST") {
+		t.Error("diffed a read-only variable")
+	}
+	if n := len(h.API.CallsTo("UpdateVariables")); n != 0 {
+		t.Errorf("UpdateVariables called %d times, want 0", n)
+	}
+}
+
+func TestDiffVariables_Reveal(t *testing.T) {
+	h, serviceID, path := seed(t)
+	h.Factory.Output = "json"
+
+	if err := h.Run(diff.NewCmdDiffVariables(h.Factory), "--id", serviceID, "--file", path, "--reveal"); err != nil {
+		t.Fatalf("variable diff: %v", err)
+	}
+	var changes []envfile.Change
+	if err := h.Printer.DecodeLastJSON(&changes); err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 3 || changes[0] != (envfile.Change{Kind: envfile.ChangeUpdate, Key: "API_KEY", Old: "sk-old-secret", New: "sk-new-secret"}) {
+		t.Errorf("changes = %+v", changes)
+	}
+}
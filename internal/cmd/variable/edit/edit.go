@@ -0,0 +1,147 @@
+package edit
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/kballard/go-shellquote"
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/envcrypt"
+	"github.com/zeabur/cli/pkg/envfile"
+)
+
+type Options struct {
+	file string
+}
+
+func NewCmdEditVariables(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Edit a file encrypted with 'zeabur variable encrypt'",
+		Long: heredoc.Doc(`
+			Decrypt a variable file encrypted with 'zeabur variable encrypt' into a
+			private temporary file, open it in $VISUAL or $EDITOR (vi by default)
+			and encrypt it again for the same keys when the editor exits. Values
+			left unchanged keep their ciphertext, so the diff shows only what was
+			edited. If the edited file does not parse, the editor opens again when
+			you agree; otherwise the edits are kept in the temporary file, whose
+			path is printed.
+
+			The file is decrypted with the same keys as 'zeabur variable decrypt'.
+		`),
+		Example: heredoc.Doc(`
+			$ zeabur variable edit --file production.env
+			$ EDITOR="code --wait" zeabur variable edit --file staging.yaml
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEditVariables(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.file, "file", "f", ".env", "Path to the encrypted .env, JSON or YAML file")
+
+	return cmd
+}
+
+func runEditVariables(f *cmdutil.Factory, opts *Options) error {
+	vars, err := envfile.Read(opts.file)
+	if err != nil {
+		return err
+	}
+	if !envcrypt.IsEncrypted(vars) {
+		return fmt.Errorf("%s is not encrypted; encrypt it with 'zeabur variable encrypt' first", opts.file)
+	}
+	decrypted, err := util.DecryptVariableFile(opts.file, vars)
+	if err != nil {
+		return err
+	}
+
+	// the clear text lives only in a private directory, removed afterwards
+	// unless it holds edits that could not be encrypted
+	dir, err := os.MkdirTemp("", "zeabur-variables-")
+	if err != nil {
+		return err
+	}
+	keep := false
+	defer func() {
+		if !keep {
+			os.RemoveAll(dir)
+		}
+	}()
+
+	format := envfile.FormatOf(opts.file)
+	tmp := filepath.Join(dir, filepath.Base(opts.file))
+	if err := util.WriteVariableFile(tmp, decrypted.Vars, format); err != nil {
+		return err
+	}
+	if err := runEditor(tmp); err != nil {
+		return err
+	}
+
+	// a file that fails to parse is opened again, or kept for the user to
+	// fix when they decline or cannot be asked
+	edited, err := envfile.Read(tmp)
+	for err != nil {
+		reopen := false
+		if f.Interactive {
+			// an interrupted prompt declines
+			reopen, _ = f.Prompter.Confirm(fmt.Sprintf("%v. Edit the file again?", err), true)
+		}
+		if reopen {
+			if err = runEditor(tmp); err == nil {
+				edited, err = envfile.Read(tmp)
+				continue
+			}
+		}
+		keep = true
+		return fmt.Errorf("%w; %s is unchanged and the edits are kept in %s", err, opts.file, tmp)
+	}
+	if maps.Equal(edited, decrypted.Vars) {
+		f.Log.Infof("No changes to %s", opts.file)
+		return nil
+	}
+
+	encrypted, err := decrypted.Seal(edited)
+	if err != nil {
+		return err
+	}
+	if err := util.WriteVariableFile(opts.file, encrypted, format); err != nil {
+		return err
+	}
+
+	changes := envfile.Diff(decrypted.Vars, edited, true)
+	f.Log.Infof("Encrypted %s again with %d change(s)", opts.file, len(changes))
+	return nil
+}
+
+// runEditor opens path in the editor of the user and waits for it to exit.
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args, err := shellquote.Split(editor)
+	if err != nil || len(args) == 0 {
+		return fmt.Errorf("invalid editor %q", editor)
+	}
+
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("run %s: %w", editor, err)
+	}
+	return nil
+}
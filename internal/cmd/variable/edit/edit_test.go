@@ -0,0 +1,111 @@
+package edit_test
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/zeabur/cli/internal/cmd/variable/edit"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/envcrypt"
+	"github.com/zeabur/cli/pkg/envfile"
+)
+
+func TestEditVariables(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(util.AgeKeyEnv, id.String())
+
+	path := filepath.Join(t.TempDir(), "production.env")
+	before, err := envcrypt.Encrypt(map[string]string{"PORT": "8080", "DB_PASSWORD": "hunter2"}, id.Recipient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := util.WriteVariableFile(path, before, envfile.FormatDotenv); err != nil {
+		t.Fatal(err)
+	}
+
+	// the editor changes one value and adds a variable
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", `sed -i -e s/8080/9090/ -e '$a LOG_LEVEL=debug'`)
+	h := cmdtest.New()
+	if err := h.Run(edit.NewCmdEditVariables(h.Factory), "--file", path); err != nil {
+		t.Fatalf("variable edit: %v", err)
+	}
+
+	after, err := envfile.Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := envcrypt.Decrypt(after, id)
+	if err != nil {
+		t.Fatalf("decrypt the edited file: %v", err)
+	}
+	if want := map[string]string{"PORT": "9090", "DB_PASSWORD": "hunter2", "LOG_LEVEL": "debug"}; !maps.Equal(f.Vars, want) {
+		t.Errorf("edited variables = %v, want %v", f.Vars, want)
+	}
+	if after["DB_PASSWORD"] != before["DB_PASSWORD"] {
+		t.Error("an unchanged value was encrypted again")
+	}
+
+	// an editor that fails leaves the file alone
+	t.Setenv("EDITOR", "false")
+	if err := h.Run(edit.NewCmdEditVariables(h.Factory), "--file", path); err == nil || !strings.Contains(err.Error(), "run false") {
+		t.Errorf("failing editor: %v", err)
+	}
+	if again, _ := envfile.Read(path); !maps.Equal(again, after) {
+		t.Error("the file changed after the editor failed")
+	}
+}
+
+func TestEditVariables_NotEncrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("PORT=8080\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	h := cmdtest.New()
+	if err := h.Run(edit.NewCmdEditVariables(h.Factory), "--file", path); err == nil || !strings.Contains(err.Error(), "is not encrypted") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestEditVariables_KeepsEditsThatFailToParse(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(util.AgeKeyEnv, id.String())
+
+	path := filepath.Join(t.TempDir(), "production.json")
+	before, err := envcrypt.Encrypt(map[string]string{"PORT": "8080"}, id.Recipient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := util.WriteVariableFile(path, before, envfile.FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", `sh -c 'echo "{\"PORT\": 9090," > "$0"'`)
+	h := cmdtest.New()
+	err = h.Run(edit.NewCmdEditVariables(h.Factory), "--file", path)
+	if err == nil || !strings.Contains(err.Error(), "the edits are kept in ") {
+		t.Fatalf("error = %v", err)
+	}
+	kept := err.Error()[strings.LastIndex(err.Error(), " ")+1:]
+	t.Cleanup(func() { os.RemoveAll(filepath.Dir(kept)) })
+	if data, err := os.ReadFile(kept); err != nil || !strings.Contains(string(data), "9090") {
+		t.Errorf("kept edits = %q, %v", data, err)
+	}
+	if after, _ := envfile.Read(path); !maps.Equal(after, before) {
+		t.Error("the file changed after the edits failed to parse")
+	}
+}
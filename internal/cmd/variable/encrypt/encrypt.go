@@ -0,0 +1,104 @@
+package encrypt
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/envcrypt"
+	"github.com/zeabur/cli/pkg/envfile"
+)
+
+type Options struct {
+	file       string
+	age        []string
+	passphrase bool
+	inPlace    bool
+
+	out io.Writer
+}
+
+func NewCmdEncryptVariables(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt the values of a .env, JSON or YAML file",
+		Long: heredoc.Docf(`
+			Encrypt the values of a variable file so it can be committed, leaving the
+			keys readable. The file can be decrypted with any of the age keys given
+			with --age, as made by age-keygen, or with a passphrase when --passphrase
+			is set.
+
+			'zeabur variable sync' and 'zeabur variable env' decrypt the file with
+			the age identities in $%s, in the file $%s names or in
+			~/.config/zeabur/age-keys.txt, or else with the passphrase, read from
+			$%s or asked for on the terminal.
+		`, util.AgeKeyEnv, util.AgeKeyFileEnv, util.VariablesPassphraseEnv),
+		Example: heredoc.Doc(`
+			$ zeabur variable encrypt --file production.env --age age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p --in-place
+			$ zeabur variable encrypt --file staging.yaml --passphrase > staging.enc.yaml
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.out = cmd.OutOrStdout()
+			return runEncryptVariables(f, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.file, "file", "f", ".env", "Path to the .env, JSON or YAML file")
+	cmd.Flags().StringArrayVar(&opts.age, "age", nil, "age public key (age1...) to encrypt for; repeat for several")
+	cmd.Flags().BoolVar(&opts.passphrase, "passphrase", false, "Encrypt with a passphrase too")
+	cmd.Flags().BoolVar(&opts.inPlace, "in-place", false, "Overwrite the file instead of printing the result")
+
+	return cmd
+}
+
+func runEncryptVariables(f *cmdutil.Factory, opts *Options) error {
+	if len(opts.age) == 0 && !opts.passphrase {
+		return fmt.Errorf("--age or --passphrase is required")
+	}
+	var recipients []envcrypt.Recipient
+	for _, s := range opts.age {
+		r, err := envcrypt.ParseRecipient(s)
+		if err != nil {
+			return fmt.Errorf("--age: %w", err)
+		}
+		recipients = append(recipients, r)
+	}
+	if opts.passphrase {
+		recipients = append(recipients, envcrypt.NewPassphrase(util.VariablesPassphrase(true)))
+	}
+
+	vars, err := envfile.Read(opts.file)
+	if err != nil {
+		return err
+	}
+	if envcrypt.IsEncrypted(vars) {
+		return fmt.Errorf("%s is encrypted already; change it with 'zeabur variable edit'", opts.file)
+	}
+	encrypted, err := envcrypt.Encrypt(vars, recipients...)
+	if err != nil {
+		return err
+	}
+
+	format := envfile.FormatOf(opts.file)
+	if !opts.inPlace {
+		return envfile.Write(opts.out, encrypted, format)
+	}
+	if err := util.WriteVariableFile(opts.file, encrypted, format); err != nil {
+		return err
+	}
+
+	who := append([]string{}, opts.age...)
+	if opts.passphrase {
+		who = append(who, "a passphrase")
+	}
+	f.Log.Infof("Encrypted %d variable(s) in %s for %s", len(vars), opts.file, strings.Join(who, ", "))
+	return nil
+}
@@ -0,0 +1,88 @@
+package encrypt_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/zeabur/cli/internal/cmd/variable/decrypt"
+	"github.com/zeabur/cli/internal/cmd/variable/encrypt"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/envcrypt"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(util.AgeKeyEnv, "")
+	t.Setenv(util.AgeKeyFileEnv, "")
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := cmdtest.New()
+	path := filepath.Join(t.TempDir(), "production.env")
+	if err := os.WriteFile(path, []byte("PORT=8080\nDB_PASSWORD=hunter2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Run(encrypt.NewCmdEncryptVariables(h.Factory), "--file", path, "--age", id.Recipient().String(), "--in-place"); err != nil {
+		t.Fatalf("variable encrypt: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") || !strings.Contains(string(data), "DB_PASSWORD=\"ENC[") || !strings.Contains(string(data), envcrypt.MetadataKey+"=") {
+		t.Fatalf("encrypted file:\n%s", data)
+	}
+
+	err = h.Run(encrypt.NewCmdEncryptVariables(h.Factory), "--file", path, "--age", id.Recipient().String())
+	if err == nil || !strings.Contains(err.Error(), "encrypted already") {
+		t.Errorf("encrypting twice: %v", err)
+	}
+
+	// a passphrase is never asked for a file encrypted for age keys only
+	err = h.Run(decrypt.NewCmdDecryptVariables(h.Factory), "--file", path)
+	if err == nil || !strings.Contains(err.Error(), "no key can decrypt") {
+		t.Errorf("decrypt without a key: %v", err)
+	}
+
+	t.Setenv(util.AgeKeyEnv, id.String())
+	var out bytes.Buffer
+	cmd := decrypt.NewCmdDecryptVariables(h.Factory)
+	cmd.SetOut(&out)
+	if err := h.Run(cmd, "--file", path); err != nil {
+		t.Fatalf("variable decrypt: %v", err)
+	}
+	if want := "DB_PASSWORD=hunter2\nPORT=8080\n"; out.String() != want {
+		t.Errorf("decrypted\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestEncrypt_Passphrase(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(util.VariablesPassphraseEnv, "correct horse")
+	envcrypt.ScryptLogN = 10
+	h := cmdtest.New()
+	path := filepath.Join(t.TempDir(), "staging.yaml")
+	if err := os.WriteFile(path, []byte("PORT: 8080\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Run(encrypt.NewCmdEncryptVariables(h.Factory), "--file", path); err == nil || !strings.Contains(err.Error(), "--age or --passphrase is required") {
+		t.Errorf("encrypt without a recipient: %v", err)
+	}
+	if err := h.Run(encrypt.NewCmdEncryptVariables(h.Factory), "--file", path, "--passphrase", "--in-place"); err != nil {
+		t.Fatalf("variable encrypt: %v", err)
+	}
+	if err := h.Run(decrypt.NewCmdDecryptVariables(h.Factory), "--file", path, "--in-place"); err != nil {
+		t.Fatalf("variable decrypt: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "PORT: \"8080\"\n" {
+		t.Errorf("decrypted file:\n%s", data)
+	}
+}
@@ -6,11 +6,11 @@ import (
 	"os"
 
 	"github.com/briandowns/spinner"
-	"github.com/hashicorp/go-envparse"
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/fill"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -26,7 +26,7 @@ func NewCmdEnvVariable(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "env",
 		Short: "update variables from .env",
-		Long:  "overwrite variables from a .env file",
+		Long:  "overwrite variables from a .env file, decrypting it first when it was encrypted with 'zeabur variable encrypt'",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runUpdateVariableByEnv(f, opts)
 		},
@@ -48,6 +48,10 @@ func runUpdateVariableByEnv(f *cmdutil.Factory, opts *Options) error {
 		return fmt.Errorf("file cannot open: %s (%w)", opts.envFilename, err)
 	}
 
+	if err := f.LinkedService(&opts.id, &opts.name, &opts.environmentID); err != nil {
+		return err
+	}
+
 	if f.Interactive && opts.id == "" && opts.name == "" {
 		zctx := f.EffectiveContext()
 		if _, err := f.ParamFiller.ServiceByNameWithEnvironment(fill.ServiceByNameWithEnvironmentOptions{
@@ -65,6 +69,11 @@ func runUpdateVariableByEnv(f *cmdutil.Factory, opts *Options) error {
 }
 
 func runUpdateVariableNonInteractive(f *cmdutil.Factory, opts *Options) error {
+	masker, err := util.NewMasker(f.Config.GetSecretPatterns(), nil)
+	if err != nil {
+		return err
+	}
+
 	if opts.id == "" && opts.name != "" {
 		service, err := util.GetServiceByName(f.ApiClient, f.CurrentOwnerID(), f.Config.GetUsername(), f.CurrentProjectName(), f.CurrentProjectID(), opts.name)
 		if err != nil {
@@ -90,18 +99,10 @@ func runUpdateVariableNonInteractive(f *cmdutil.Factory, opts *Options) error {
 		spinner.WithSuffix(fmt.Sprintf(" Updating variables of service: %s...", opts.name)),
 	)
 
-	// read files from .env
-	envFile, err := os.Open(opts.envFilename)
+	// read the .env file, decrypting it when it is encrypted
+	envMap, err := util.ReadVariableFile(opts.envFilename)
 	if err != nil {
-		return fmt.Errorf("open file: %w", err)
-	}
-	defer func() {
-		_ = envFile.Close()
-	}()
-
-	envMap, err := envparse.Parse(envFile)
-	if err != nil {
-		return fmt.Errorf("parse env file: %w", err)
+		return err
 	}
 
 	createVarResult, err := f.ApiClient.UpdateVariables(context.Background(), opts.id, opts.environmentID, envMap)
@@ -115,21 +116,13 @@ func runUpdateVariableNonInteractive(f *cmdutil.Factory, opts *Options) error {
 	}
 	s.Stop()
 
-	if f.JSON {
-		out := make([]map[string]string, 0, len(envMap))
-		for k, v := range envMap {
-			out = append(out, map[string]string{"Key": k, "Value": v})
-		}
-		return f.Printer.JSON(out)
-	}
-
 	f.Log.Infof("Successfully updated variables of service: %s\n\tRestart your service manually to apply the changes.\n", opts.name)
 
+	out := make([]map[string]string, 0, len(envMap))
 	table := make([][]string, 0, len(envMap))
 	for k, v := range envMap {
-		table = append(table, []string{k, v})
+		out = append(out, map[string]string{"Key": k, "Value": masker.Value(k, v)})
+		table = append(table, []string{k, masker.Value(k, v)})
 	}
-	f.Printer.Table([]string{"Key", "Value"}, table)
-
-	return nil
+	return f.Printer.Data(printer.WithTable(out, []string{"Key", "Value"}, table))
 }
@@ -0,0 +1,157 @@
+package export
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/envfile"
+	"github.com/zeabur/cli/pkg/fill"
+)
+
+type Options struct {
+	id            string
+	name          string
+	environmentID string
+	file          string
+	format        string
+	reveal        []string
+
+	out    io.Writer
+	errOut io.Writer
+}
+
+func NewCmdExportVariables(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export variables to a .env, JSON or YAML file",
+		Long: heredoc.Doc(`
+			Export the variables of a service as a .env, JSON or YAML file, sorted by
+			key, or print them when no --file is given. The format follows the
+			extension of the file unless --format is set.
+
+			Read-only variables exposed by other services are left out, since they
+			cannot be set. Values of secrets are masked unless --reveal is given;
+			syncing the file back leaves masked values unchanged.
+		`),
+		Example: heredoc.Doc(`
+			$ zeabur variable export --name web --file .env
+			$ zeabur variable export --name web --format json > variables.json
+			$ zeabur variable export --name web --file .env --reveal
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.out = cmd.OutOrStdout()
+			opts.errOut = cmd.ErrOrStderr()
+			return runExportVariables(cmd.Context(), f, opts)
+		},
+	}
+
+	util.AddServiceParam(cmd, &opts.id, &opts.name)
+	util.AddEnvOfServiceParam(cmd, &opts.environmentID)
+	cmd.Flags().StringVarP(&opts.file, "file", "f", "", "File to write, instead of printing the variables")
+	cmd.Flags().StringVar(&opts.format, "format", "", "Format: env, json or yaml (default by the file extension, or env)")
+	util.AddRevealParam(cmd, &opts.reveal)
+
+	return cmd
+}
+
+func runExportVariables(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
+	if err := f.LinkedService(&opts.id, &opts.name, &opts.environmentID); err != nil {
+		return err
+	}
+
+	if f.Interactive {
+		return runExportVariablesInteractive(ctx, f, opts)
+	}
+	return runExportVariablesNonInteractive(ctx, f, opts)
+}
+
+func runExportVariablesInteractive(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
+	zctx := f.EffectiveContext()
+
+	if _, err := f.ParamFiller.ServiceByNameWithEnvironment(fill.ServiceByNameWithEnvironmentOptions{
+		ProjectCtx:    zctx,
+		ServiceID:     &opts.id,
+		ServiceName:   &opts.name,
+		EnvironmentID: &opts.environmentID,
+		CreateNew:     false,
+	}); err != nil {
+		return err
+	}
+
+	return runExportVariablesNonInteractive(ctx, f, opts)
+}
+
+func runExportVariablesNonInteractive(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
+	format := envfile.FormatOf(opts.file)
+	if opts.format != "" {
+		var err error
+		if format, err = envfile.ParseFormat(opts.format); err != nil {
+			return fmt.Errorf("--format: %w", err)
+		}
+	}
+
+	masker, err := util.NewMasker(f.Config.GetSecretPatterns(), opts.reveal)
+	if err != nil {
+		return err
+	}
+
+	if opts.id == "" && opts.name != "" {
+		service, err := util.GetServiceByName(f.ApiClient, f.CurrentOwnerID(), f.Config.GetUsername(), f.CurrentProjectName(), f.CurrentProjectID(), opts.name)
+		if err != nil {
+			return err
+		}
+		opts.id = service.ID
+	}
+
+	if opts.id == "" {
+		return fmt.Errorf("--id or --name is required")
+	}
+
+	if opts.environmentID == "" {
+		envID, err := util.ResolveEnvironmentIDByServiceID(f.ApiClient, opts.id)
+		if err != nil {
+			return err
+		}
+		opts.environmentID = envID
+	}
+
+	variables, _, err := f.ApiClient.ListVariables(ctx, opts.id, opts.environmentID)
+	if err != nil {
+		return fmt.Errorf("list variables: %w", err)
+	}
+
+	vars := variables.ToMap()
+	var buf bytes.Buffer
+	if err := envfile.Write(&buf, masker.Map(vars), format); err != nil {
+		return err
+	}
+	if n := masker.Masked(vars); n > 0 {
+		f.Log.Warnf("Masked the values of %d secret(s); pass --reveal to export them", n)
+	}
+	if !f.Interactive {
+		defer util.NoticeRevealed(opts.errOut, masker, f.Config.GetUsername(), fmt.Sprintf("service %s, environment %s", cmp.Or(opts.name, opts.id), opts.environmentID))
+	}
+
+	if opts.file == "" {
+		_, err := opts.out.Write(buf.Bytes())
+		return err
+	}
+	// the file holds secrets: keep it private
+	if err := os.WriteFile(opts.file, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", opts.file, err)
+	}
+	f.Log.Infof("Exported %d variable(s) to %s", len(variables), opts.file)
+	return nil
+}
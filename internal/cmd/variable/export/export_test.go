@@ -0,0 +1,85 @@
+package export_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zeabur/cli/internal/cmd/variable/export"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+func TestExportVariables(t *testing.T) {
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+	h.API.SeedVariables(svc.ID, env.ID, map[string]string{"PORT": "8080", "GREETING": "hello world"})
+	h.API.SeedExposedVariables(svc.ID, env.ID, model.Variables{{Key: "POSTGRES_HOST", Value: "db"}})
+
+	var out bytes.Buffer
+	cmd := export.NewCmdExportVariables(h.Factory)
+	cmd.SetOut(&out)
+	if err := h.Run(cmd, "--id", svc.ID); err != nil {
+		t.Fatalf("variable export: %v", err)
+	}
+	if want := "GREETING=\"hello world\"\nPORT=8080\n"; out.String() != want {
+		t.Errorf("printed\n%s\nwant\n%s", out.String(), want)
+	}
+
+	path := filepath.Join(t.TempDir(), "vars.yaml")
+	if err := h.Run(export.NewCmdExportVariables(h.Factory), "--id", svc.ID, "--file", path); err != nil {
+		t.Fatalf("variable export --file: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "GREETING: hello world\nPORT: \"8080\"\n"; string(data) != want {
+		t.Errorf("wrote\n%s\nwant\n%s", data, want)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	if err := h.Run(export.NewCmdExportVariables(h.Factory), "--id", svc.ID, "--format", "toml"); err == nil {
+		t.Error("exported in an unknown format")
+	}
+}
+
+func TestExportVariables_Secrets(t *testing.T) {
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+	h.API.SeedVariables(svc.ID, env.ID, map[string]string{"PORT": "8080", "API_TOKEN": "tok_123456"})
+
+	var out, stderr bytes.Buffer
+	cmd := export.NewCmdExportVariables(h.Factory)
+	cmd.SetOut(&out)
+	cmd.SetErr(&stderr)
+	if err := h.Run(cmd, "--id", svc.ID); err != nil {
+		t.Fatalf("variable export: %v", err)
+	}
+	if want := "API_TOKEN=\"********\"\nPORT=8080\n"; out.String() != want {
+		t.Errorf("printed\n%s\nwant\n%s", out.String(), want)
+	}
+	if stderr.Len() > 0 {
+		t.Errorf("noticed %q without revealing anything", stderr.String())
+	}
+
+	out.Reset()
+	cmd = export.NewCmdExportVariables(h.Factory)
+	cmd.SetOut(&out)
+	cmd.SetErr(&stderr)
+	if err := h.Run(cmd, "--id", svc.ID, "--reveal", "API_TOKEN"); err != nil {
+		t.Fatalf("variable export --reveal: %v", err)
+	}
+	if want := "API_TOKEN=tok_123456\nPORT=8080\n"; out.String() != want {
+		t.Errorf("printed\n%s\nwant\n%s", out.String(), want)
+	}
+	if !strings.Contains(stderr.String(), "notice: tester revealed the secret values of API_TOKEN in service "+svc.ID) {
+		t.Errorf("stderr = %q, want an audit notice", stderr.String())
+	}
+}
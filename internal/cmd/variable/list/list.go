@@ -1,43 +1,61 @@
 package list
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/fill"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
 	id            string
 	name          string
 	environmentID string
+	reveal        []string
+
+	errOut io.Writer
 }
 
 func NewCmdListVariables(f *cmdutil.Factory) *cobra.Command {
 	opts := &Options{}
 
 	cmd := &cobra.Command{
-		Use:     "list",
-		Short:   "list environment variables",
-		Long:    `List environment variables of a service`,
+		Use:   "list",
+		Short: "list environment variables",
+		Long: `List environment variables of a service.
+
+Values of secrets, whose keys look like *_PASSWORD, *_SECRET, *_TOKEN or
+*_KEY or match the secret_patterns setting, are masked unless --reveal is
+given.`,
+		Example: `  zeabur variable list --name web
+  zeabur variable list --name web --reveal DATABASE_PASSWORD`,
 		Args:    cobra.NoArgs,
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.errOut = cmd.ErrOrStderr()
 			return runListVariables(f, opts)
 		},
 	}
 
 	util.AddServiceParam(cmd, &opts.id, &opts.name)
 	util.AddEnvOfServiceParam(cmd, &opts.environmentID)
+	util.AddRevealParam(cmd, &opts.reveal)
 
 	return cmd
 }
 
 func runListVariables(f *cmdutil.Factory, opts *Options) error {
+	if err := f.LinkedService(&opts.id, &opts.name, &opts.environmentID); err != nil {
+		return err
+	}
+
 	if f.Interactive {
 		return runListVariablesInteractive(f, opts)
 	} else {
@@ -62,6 +80,11 @@ func runListVariablesInteractive(f *cmdutil.Factory, opts *Options) error {
 }
 
 func runListVariablesNonInteractive(f *cmdutil.Factory, opts *Options) error {
+	masker, err := util.NewMasker(f.Config.GetSecretPatterns(), opts.reveal)
+	if err != nil {
+		return err
+	}
+
 	if opts.id == "" && opts.name != "" {
 		service, err := util.GetServiceByName(f.ApiClient, f.CurrentOwnerID(), f.Config.GetUsername(), f.CurrentProjectName(), f.CurrentProjectID(), opts.name)
 		if err != nil {
@@ -94,25 +117,27 @@ func runListVariablesNonInteractive(f *cmdutil.Factory, opts *Options) error {
 	s.Stop()
 
 	if len(variableList) == 0 && len(readonlyVariableList) == 0 {
-		if f.JSON {
-			return f.Printer.JSON([]any{})
-		}
 		f.Log.Infof("No variables found")
-		return nil
+		return f.Printer.Data(printer.Result([]any{}))
 	}
 
-	if f.JSON {
-		return f.Printer.JSON(map[string]any{"variables": variableList, "readonlyVariables": readonlyVariableList})
+	variableList, readonlyVariableList = masker.Variables(variableList), masker.Variables(readonlyVariableList)
+	if !f.Interactive {
+		defer util.NoticeRevealed(opts.errOut, masker, f.Config.GetUsername(), fmt.Sprintf("service %s, environment %s", cmp.Or(opts.name, opts.id), opts.environmentID))
 	}
 
 	f.Log.Infof("Variables of service: %s\n", opts.name)
-	f.Printer.Table(variableList.Header(), variableList.Rows())
 
-	if len(readonlyVariableList) != 0 {
-		fmt.Println()
-		f.Log.Infof("Readonly variables of service: %s\n", opts.name)
-		f.Printer.Table(readonlyVariableList.Header(), readonlyVariableList.Rows())
+	// one table, with the readonly variables last
+	rows := make([][]string, 0, len(variableList)+len(readonlyVariableList))
+	for _, row := range variableList.Rows() {
+		rows = append(rows, append(row, ""))
 	}
-
-	return nil
+	for _, row := range readonlyVariableList.Rows() {
+		rows = append(rows, append(row, "yes"))
+	}
+	return f.Printer.Data(printer.WithTable(
+		map[string]any{"variables": variableList, "readonlyVariables": readonlyVariableList},
+		append(variableList.Header(), "Readonly"), rows,
+	))
 }
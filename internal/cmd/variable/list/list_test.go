@@ -0,0 +1,121 @@
+package list_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zeabur/cli/internal/cmd/variable/list"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/pkg/config"
+	"github.com/zeabur/cli/pkg/model"
+	"github.com/zeabur/cli/pkg/zcontext"
+)
+
+// TestListVariables_JSON pins the JSON shape scripts parse: own variables
+// and read-only ones exposed by other services, under separate keys.
+func TestListVariables_JSON(t *testing.T) {
+	h := cmdtest.New()
+	h.Factory.Output = "json"
+	project, env := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+	h.API.SeedVariables(svc.ID, env.ID, map[string]string{"PORT": "8080"})
+	h.API.SeedExposedVariables(svc.ID, env.ID, model.Variables{{Key: "POSTGRES_HOST", Value: "db", ServiceID: "65aa1234567890abcdef1234"}})
+
+	if err := h.Run(list.NewCmdListVariables(h.Factory), "--id", svc.ID); err != nil {
+		t.Fatalf("variable list: %v", err)
+	}
+
+	var out struct {
+		Variables         []model.Variable `json:"variables"`
+		ReadonlyVariables []model.Variable `json:"readonlyVariables"`
+	}
+	if err := h.Printer.DecodeLastJSON(&out); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if len(out.Variables) != 1 || out.Variables[0].Key != "PORT" || out.Variables[0].Value != "8080" {
+		t.Fatalf("variables = %+v", out.Variables)
+	}
+	if len(out.ReadonlyVariables) != 1 || out.ReadonlyVariables[0].Key != "POSTGRES_HOST" {
+		t.Fatalf("readonlyVariables = %+v", out.ReadonlyVariables)
+	}
+}
+
+// TestListVariables_ByName resolves the service through the persisted
+// project context, the way `zeabur variable list --name web` does.
+func TestListVariables_ByName(t *testing.T) {
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+	h.API.SeedVariables(svc.ID, env.ID, map[string]string{"A": "1", "B": "2"})
+	h.Config.GetContext().SetProject(zcontext.NewBasicInfo(project.ID, project.Name))
+
+	if err := h.Run(list.NewCmdListVariables(h.Factory), "--name", "web"); err != nil {
+		t.Fatalf("variable list: %v", err)
+	}
+
+	rows := h.Printer.LastTable().Rows
+	if len(rows) != 2 || rows[0][0] != "A" || rows[1][0] != "B" {
+		t.Fatalf("printed rows = %v, want A and B", rows)
+	}
+}
+
+func TestListVariables_MasksSecrets(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		args   []string
+		want   map[string]string
+		notice string
+	}{
+		{
+			name: "masked",
+			want: map[string]string{"PORT": "8080", "DB_PASSWORD": "********", "STRIPE_KEY": "********", "SENTRY_DSN": "********"},
+		},
+		{
+			name:   "reveal all",
+			args:   []string{"--reveal"},
+			want:   map[string]string{"PORT": "8080", "DB_PASSWORD": "hunter2", "STRIPE_KEY": "sk_live", "SENTRY_DSN": "https://sentry"},
+			notice: "tester revealed the secret values of DB_PASSWORD, SENTRY_DSN, STRIPE_KEY in service web",
+		},
+		{
+			name:   "reveal a key",
+			args:   []string{"--reveal", "STRIPE_KEY"},
+			want:   map[string]string{"PORT": "8080", "DB_PASSWORD": "********", "STRIPE_KEY": "sk_live", "SENTRY_DSN": "********"},
+			notice: "tester revealed the secret values of STRIPE_KEY in",
+		},
+		{
+			name: "reveal a key that is no secret",
+			args: []string{"--reveal=PORT"},
+			want: map[string]string{"PORT": "8080", "DB_PASSWORD": "********", "STRIPE_KEY": "********", "SENTRY_DSN": "********"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := cmdtest.New()
+			h.Config.Set(config.KeySecretPatterns, "*_DSN")
+			project, env := h.API.SeedProject("", "api")
+			svc := h.API.SeedService(project.ID, "web")
+			h.API.SeedVariables(svc.ID, env.ID, map[string]string{"PORT": "8080", "DB_PASSWORD": "hunter2", "STRIPE_KEY": "sk_live", "SENTRY_DSN": "https://sentry"})
+			h.Config.GetContext().SetProject(zcontext.NewBasicInfo(project.ID, project.Name))
+
+			var stderr bytes.Buffer
+			cmd := list.NewCmdListVariables(h.Factory)
+			cmd.SetErr(&stderr)
+			if err := h.Run(cmd, append([]string{"--name", "web"}, tc.args...)...); err != nil {
+				t.Fatalf("variable list: %v", err)
+			}
+
+			got := map[string]string{}
+			for _, row := range h.Printer.LastTable().Rows {
+				got[row[0]] = row[1]
+			}
+			for k, v := range tc.want {
+				if got[k] != v {
+					t.Errorf("%s = %q, want %q", k, got[k], v)
+				}
+			}
+			if tc.notice == "" && stderr.Len() > 0 || !strings.Contains(stderr.String(), tc.notice) {
+				t.Errorf("stderr = %q, want a notice containing %q", stderr.String(), tc.notice)
+			}
+		})
+	}
+}
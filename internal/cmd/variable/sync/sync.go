@@ -0,0 +1,166 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	variableDiffCmd "github.com/zeabur/cli/internal/cmd/variable/diff"
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/envfile"
+	"github.com/zeabur/cli/pkg/fill"
+	"github.com/zeabur/cli/pkg/printer"
+)
+
+type Options struct {
+	id            string
+	name          string
+	environmentID string
+	file          string
+	prune         bool
+	skipConfirm   bool
+
+	out io.Writer
+}
+
+func NewCmdSyncVariables(f *cmdutil.Factory) *cobra.Command {
+	opts := &Options{}
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Update variables to match a .env, JSON or YAML file",
+		Long: heredoc.Doc(`
+			Update the variables of a service to match a .env, JSON or YAML file,
+			changing only the variables that differ, as 'zeabur variable diff' shows
+			them. Variables missing from the file are kept, unless --prune is given.
+			Files encrypted with 'zeabur variable encrypt' are decrypted first.
+
+			The changes are confirmed before they are applied; use --yes to skip the
+			confirmation, which non-interactive mode requires. Restart the service
+			for the changes to take effect.
+		`),
+		Example: heredoc.Doc(`
+			$ zeabur variable sync --name web --file .env
+			$ zeabur variable sync --name web --file production.yaml --prune --yes -i=false
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.out = cmd.OutOrStdout()
+			return runSyncVariables(cmd.Context(), f, opts)
+		},
+	}
+
+	util.AddServiceParam(cmd, &opts.id, &opts.name)
+	util.AddEnvOfServiceParam(cmd, &opts.environmentID)
+	cmd.Flags().StringVarP(&opts.file, "file", "f", ".env", "Path to the .env, JSON or YAML file")
+	cmd.Flags().BoolVar(&opts.prune, "prune", false, "Remove variables missing from the file")
+	cmd.Flags().BoolVarP(&opts.skipConfirm, "yes", "y", false, "Skip confirmation")
+
+	return cmd
+}
+
+func runSyncVariables(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
+	if err := f.LinkedService(&opts.id, &opts.name, &opts.environmentID); err != nil {
+		return err
+	}
+
+	if f.Interactive {
+		return runSyncVariablesInteractive(ctx, f, opts)
+	}
+	return runSyncVariablesNonInteractive(ctx, f, opts)
+}
+
+func runSyncVariablesInteractive(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
+	zctx := f.EffectiveContext()
+
+	if _, err := f.ParamFiller.ServiceByNameWithEnvironment(fill.ServiceByNameWithEnvironmentOptions{
+		ProjectCtx:    zctx,
+		ServiceID:     &opts.id,
+		ServiceName:   &opts.name,
+		EnvironmentID: &opts.environmentID,
+		CreateNew:     false,
+	}); err != nil {
+		return err
+	}
+
+	return runSyncVariablesNonInteractive(ctx, f, opts)
+}
+
+func runSyncVariablesNonInteractive(ctx context.Context, f *cmdutil.Factory, opts *Options) error {
+	if opts.id == "" && opts.name != "" {
+		service, err := util.GetServiceByName(f.ApiClient, f.CurrentOwnerID(), f.Config.GetUsername(), f.CurrentProjectName(), f.CurrentProjectID(), opts.name)
+		if err != nil {
+			return err
+		}
+		opts.id = service.ID
+	}
+
+	if opts.id == "" {
+		return fmt.Errorf("--id or --name is required")
+	}
+
+	if opts.environmentID == "" {
+		envID, err := util.ResolveEnvironmentIDByServiceID(f.ApiClient, opts.id)
+		if err != nil {
+			return err
+		}
+		opts.environmentID = envID
+	}
+
+	d, err := util.DiffVariables(ctx, f.ApiClient, opts.id, opts.environmentID, opts.file, opts.prune)
+	if err != nil {
+		return err
+	}
+	if len(d.ReadOnly) > 0 {
+		f.Log.Warnf("Ignoring read-only variable(s) in %s: %s", opts.file, strings.Join(d.ReadOnly, ", "))
+	}
+	if len(d.Masked) > 0 {
+		f.Log.Warnf("Leaving masked value(s) in %s unchanged: %s", opts.file, strings.Join(d.Masked, ", "))
+	}
+
+	masked := make([]envfile.Change, 0, len(d.Changes))
+	for _, c := range d.Changes {
+		masked = append(masked, c.Masked(util.MaskValue))
+	}
+
+	if len(d.Changes) == 0 {
+		f.Log.Infof("Already in sync: the variables match %s", opts.file)
+		return f.Printer.Data(printer.Result(masked))
+	}
+
+	if !f.StructuredOutput() {
+		if err := variableDiffCmd.PrintChanges(opts.out, masked); err != nil {
+			return err
+		}
+	}
+
+	if f.Interactive && !opts.skipConfirm {
+		confirm, err := f.Prompter.Confirm("Do you want to apply these changes?", false)
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			return nil
+		}
+	} else if !f.Interactive && !opts.skipConfirm {
+		return fmt.Errorf("syncing variables requires --yes flag in non-interactive mode")
+	}
+
+	// UpdateVariables replaces the whole set, so send the current variables
+	// with the changes made
+	ok, err := f.ApiClient.UpdateVariables(ctx, opts.id, opts.environmentID, envfile.Apply(d.Current, d.Changes))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("failed to update variables of service: %s", opts.id)
+	}
+
+	f.Log.Infof("%s Synced %d variable(s) from %s. Restart your service manually to apply the changes.", cmdutil.SuccessIcon, len(d.Changes), opts.file)
+	return f.Printer.Data(printer.Result(masked))
+}
@@ -0,0 +1,135 @@
+package sync_test
+
+import (
+	"bytes"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/zeabur/cli/internal/cmd/variable/sync"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/envcrypt"
+	"github.com/zeabur/cli/pkg/envfile"
+)
+
+func seed(t *testing.T) (*cmdtest.Harness, string, string, string) {
+	t.Helper()
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+	h.API.SeedVariables(svc.ID, env.ID, map[string]string{"PORT": "8080", "DEBUG": "0", "LEGACY": "1"})
+
+	path := filepath.Join(t.TempDir(), "vars.json")
+	if err := os.WriteFile(path, []byte(`{"PORT": 8080, "DEBUG": "1", "NEW": "yes"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return h, svc.ID, env.ID, path
+}
+
+func TestSyncVariables(t *testing.T) {
+	h, serviceID, envID, path := seed(t)
+
+	var out bytes.Buffer
+	cmd := sync.NewCmdSyncVariables(h.Factory)
+	cmd.SetOut(&out)
+	if err := h.Run(cmd, "--id", serviceID, "--file", path, "--yes"); err != nil {
+		t.Fatalf("variable sync: %v", err)
+	}
+
+	want := map[string]string{"PORT": "8080", "DEBUG": "1", "NEW": "yes", "LEGACY": "1"}
+	if got := h.API.Variables(serviceID, envID); !maps.Equal(got, want) {
+		t.Errorf("variables = %v, want %v", got, want)
+	}
+	if !strings.Contains(out.String(), "1 to add, 1 to change, 0 to remove.") {
+		t.Errorf("printed\n%s", out.String())
+	}
+
+	// in sync now: nothing to update
+	if err := h.Run(sync.NewCmdSyncVariables(h.Factory), "--id", serviceID, "--file", path, "--yes"); err != nil {
+		t.Fatalf("variable sync: %v", err)
+	}
+	if n := len(h.API.CallsTo("UpdateVariables")); n != 1 {
+		t.Errorf("UpdateVariables called %d times, want 1", n)
+	}
+}
+
+func TestSyncVariables_Prune(t *testing.T) {
+	h, serviceID, envID, path := seed(t)
+
+	if err := h.Run(sync.NewCmdSyncVariables(h.Factory), "--id", serviceID, "--file", path, "--prune", "--yes"); err != nil {
+		t.Fatalf("variable sync: %v", err)
+	}
+	want := map[string]string{"PORT": "8080", "DEBUG": "1", "NEW": "yes"}
+	if got := h.API.Variables(serviceID, envID); !maps.Equal(got, want) {
+		t.Errorf("variables = %v, want %v", got, want)
+	}
+}
+
+func TestSyncVariables_RequiresYes(t *testing.T) {
+	h, serviceID, _, path := seed(t)
+
+	err := h.Run(sync.NewCmdSyncVariables(h.Factory), "--id", serviceID, "--file", path)
+	if err == nil || !strings.Contains(err.Error(), "requires --yes") {
+		t.Fatalf("error = %v", err)
+	}
+	if n := len(h.API.CallsTo("UpdateVariables")); n != 0 {
+		t.Errorf("UpdateVariables called %d times, want 0", n)
+	}
+}
+
+// TestSyncVariables_MaskedExport syncs a file exported without --reveal:
+// masked secrets must not overwrite the real values.
+func TestSyncVariables_MaskedExport(t *testing.T) {
+	h, serviceID, envID, _ := seed(t)
+	h.API.SeedVariables(serviceID, envID, map[string]string{"PORT": "8080", "DB_PASSWORD": "hunter2"})
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("PORT=9090\nDB_PASSWORD=\"********\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Run(sync.NewCmdSyncVariables(h.Factory), "--id", serviceID, "--file", path, "--yes"); err != nil {
+		t.Fatalf("variable sync: %v", err)
+	}
+
+	want := map[string]string{"PORT": "9090", "DB_PASSWORD": "hunter2"}
+	if got := h.API.Variables(serviceID, envID); !maps.Equal(got, want) {
+		t.Errorf("variables = %v, want %v", got, want)
+	}
+}
+
+func TestSyncVariables_Encrypted(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(util.AgeKeyEnv, "")
+	t.Setenv(util.AgeKeyFileEnv, "")
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, serviceID, envID, _ := seed(t)
+
+	encrypted, err := envcrypt.Encrypt(map[string]string{"PORT": "8080", "DB_PASSWORD": "hunter2"}, id.Recipient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "production.env")
+	if err := util.WriteVariableFile(path, encrypted, envfile.FormatDotenv); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Run(sync.NewCmdSyncVariables(h.Factory), "--id", serviceID, "--file", path, "--prune", "--yes"); err == nil || !strings.Contains(err.Error(), "no key can decrypt") {
+		t.Fatalf("sync without the key: %v", err)
+	}
+
+	t.Setenv(util.AgeKeyEnv, id.String())
+	if err := h.Run(sync.NewCmdSyncVariables(h.Factory), "--id", serviceID, "--file", path, "--prune", "--yes"); err != nil {
+		t.Fatalf("variable sync: %v", err)
+	}
+	want := map[string]string{"PORT": "8080", "DB_PASSWORD": "hunter2"}
+	if got := h.API.Variables(serviceID, envID); !maps.Equal(got, want) {
+		t.Errorf("variables = %v, want %v", got, want)
+	}
+}
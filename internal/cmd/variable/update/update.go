@@ -9,6 +9,7 @@ import (
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/fill"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 type Options struct {
@@ -21,15 +22,6 @@ type Options struct {
 	inputDone     bool
 }
 
-// maskValue masks a variable value for display, showing only the first 3
-// characters followed by asterisks. Short values are fully masked.
-func maskValue(v string) string {
-	if len(v) <= 3 {
-		return "***"
-	}
-	return v[:3] + "***"
-}
-
 func NewCmdUpdateVariable(f *cmdutil.Factory) *cobra.Command {
 	opts := &Options{}
 
@@ -51,6 +43,10 @@ func NewCmdUpdateVariable(f *cmdutil.Factory) *cobra.Command {
 }
 
 func runUpdateVariable(f *cmdutil.Factory, opts *Options) error {
+	if err := f.LinkedService(&opts.id, &opts.name, &opts.environmentID); err != nil {
+		return err
+	}
+
 	if f.Interactive {
 		opts.keys = make(map[string]string)
 		return runUpdateVariableInteractive(f, opts)
@@ -86,7 +82,7 @@ func runUpdateVariableInteractive(f *cmdutil.Factory, opts *Options) error {
 	selectTable := make([]string, 0, len(varMap))
 	for k, v := range varMap {
 		keyTable = append(keyTable, k)
-		selectTable = append(selectTable, fmt.Sprintf("%s = %s", k, maskValue(v)))
+		selectTable = append(selectTable, fmt.Sprintf("%s = %s", k, util.MaskValue(v)))
 		opts.keys[k] = v
 	}
 
@@ -188,21 +184,13 @@ func runUpdateVariableNonInteractive(f *cmdutil.Factory, opts *Options) error {
 	}
 	s.Stop()
 
-	if f.JSON {
-		out := make([]map[string]string, 0, len(opts.updatedKeys))
-		for _, k := range opts.updatedKeys {
-			out = append(out, map[string]string{"Key": k})
-		}
-		return f.Printer.JSON(out)
-	}
-
 	f.Log.Infof("Successfully updated variables of service: %s\n", opts.name)
 
+	out := make([]map[string]string, 0, len(opts.updatedKeys))
 	table := make([][]string, 0, len(opts.updatedKeys))
 	for _, k := range opts.updatedKeys {
+		out = append(out, map[string]string{"Key": k})
 		table = append(table, []string{k})
 	}
-	f.Printer.Table([]string{"Key"}, table)
-
-	return nil
+	return f.Printer.Data(printer.WithTable(out, []string{"Key"}, table))
 }
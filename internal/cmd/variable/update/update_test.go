@@ -0,0 +1,49 @@
+package update_test
+
+import (
+	"testing"
+
+	"github.com/zeabur/cli/internal/cmd/variable/update"
+	"github.com/zeabur/cli/internal/cmdutil/cmdtest"
+)
+
+// TestUpdateVariable_KeepsUnmentionedKeys drives `variable update` through
+// cobra against the in-memory API. UpdateVariables replaces the whole set
+// server-side, so the command must merge the flags into the existing
+// variables rather than send only the changed keys.
+func TestUpdateVariable_KeepsUnmentionedKeys(t *testing.T) {
+	h := cmdtest.New()
+	project, env := h.API.SeedProject("", "api")
+	svc := h.API.SeedService(project.ID, "web")
+	h.API.SeedVariables(svc.ID, env.ID, map[string]string{"PORT": "8080", "DEBUG": "0"})
+
+	err := h.Run(update.NewCmdUpdateVariable(h.Factory), "--id", svc.ID, "-k", "DEBUG=1", "-y")
+	if err != nil {
+		t.Fatalf("variable update: %v", err)
+	}
+
+	got := h.API.Variables(svc.ID, env.ID)
+	if got["PORT"] != "8080" || got["DEBUG"] != "1" || len(got) != 2 {
+		t.Fatalf("variables = %v, want PORT kept and DEBUG updated", got)
+	}
+	if n := len(h.API.CallsTo("UpdateVariables")); n != 1 {
+		t.Fatalf("UpdateVariables called %d times, want 1", n)
+	}
+
+	table := h.Printer.LastTable()
+	if len(table.Rows) != 1 || table.Rows[0][0] != "DEBUG" {
+		t.Fatalf("printed rows = %v, want only the updated key", table.Rows)
+	}
+}
+
+func TestUpdateVariable_RequiresService(t *testing.T) {
+	h := cmdtest.New()
+
+	err := h.Run(update.NewCmdUpdateVariable(h.Factory), "-k", "A=1")
+	if err == nil {
+		t.Fatalf("expected an error without --id or --name")
+	}
+	if n := len(h.API.CallsTo("UpdateVariables")); n != 0 {
+		t.Fatalf("UpdateVariables called %d times, want 0", n)
+	}
+}
@@ -3,10 +3,17 @@ package variable
 import (
 	"github.com/spf13/cobra"
 
+	variableCopyCmd "github.com/zeabur/cli/internal/cmd/variable/copy"
 	variableCreateCmd "github.com/zeabur/cli/internal/cmd/variable/create"
+	variableDecryptCmd "github.com/zeabur/cli/internal/cmd/variable/decrypt"
 	variableDeleteCmd "github.com/zeabur/cli/internal/cmd/variable/delete"
+	variableDiffCmd "github.com/zeabur/cli/internal/cmd/variable/diff"
+	variableEditCmd "github.com/zeabur/cli/internal/cmd/variable/edit"
+	variableEncryptCmd "github.com/zeabur/cli/internal/cmd/variable/encrypt"
 	variableEnvCmd "github.com/zeabur/cli/internal/cmd/variable/env"
+	variableExportCmd "github.com/zeabur/cli/internal/cmd/variable/export"
 	variableListCmd "github.com/zeabur/cli/internal/cmd/variable/list"
+	variableSyncCmd "github.com/zeabur/cli/internal/cmd/variable/sync"
 	variableUpdateCmd "github.com/zeabur/cli/internal/cmd/variable/update"
 	"github.com/zeabur/cli/internal/cmdutil"
 )
@@ -24,6 +31,13 @@ func NewCmdVariable(f *cmdutil.Factory) *cobra.Command {
 	cmd.AddCommand(variableUpdateCmd.NewCmdUpdateVariable(f))
 	cmd.AddCommand(variableDeleteCmd.NewCmdDeleteVariable(f))
 	cmd.AddCommand(variableEnvCmd.NewCmdEnvVariable(f))
+	cmd.AddCommand(variableExportCmd.NewCmdExportVariables(f))
+	cmd.AddCommand(variableDiffCmd.NewCmdDiffVariables(f))
+	cmd.AddCommand(variableSyncCmd.NewCmdSyncVariables(f))
+	cmd.AddCommand(variableCopyCmd.NewCmdCopyVariables(f))
+	cmd.AddCommand(variableEncryptCmd.NewCmdEncryptVariables(f))
+	cmd.AddCommand(variableDecryptCmd.NewCmdDecryptVariables(f))
+	cmd.AddCommand(variableEditCmd.NewCmdEditVariables(f))
 
 	return cmd
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/printer"
 )
 
 func NewCmdVersion(f *cmdutil.Factory, version, commit, date string) *cobra.Command {
@@ -13,11 +14,8 @@ func NewCmdVersion(f *cmdutil.Factory, version, commit, date string) *cobra.Comm
 		Short:   "Print the version number of Zeabur CLI",
 		Aliases: []string{"v", "ver"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if f.JSON {
-				return f.Printer.JSON(map[string]string{"version": version, "commit": commit, "date": date})
-			}
-			f.Printer.Table([]string{"Version", "Commit", "Date"}, [][]string{{version, commit, date}})
-			return nil
+			return f.Printer.Data(printer.WithTable(map[string]string{"version": version, "commit": commit, "date": date},
+				[]string{"Version", "Commit", "Date"}, [][]string{{version, commit, date}}))
 		},
 	}
 
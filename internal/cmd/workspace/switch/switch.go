@@ -9,12 +9,13 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/internal/util"
 	"github.com/zeabur/cli/pkg/zcontext"
 )
 
 // NewCmdSwitch builds `zeabur workspace switch`.
 func NewCmdSwitch(f *cmdutil.Factory) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "switch <name|id>",
 		Short: "Switch to a team workspace",
 		Long: `Switch the CLI's workspace to a team.
@@ -34,6 +35,9 @@ because resource IDs do not overlap between workspaces.`,
 			return run(f, args[0])
 		},
 	}
+	util.SetArgsCompletion(cmd, util.CompleteWorkspace)
+
+	return cmd
 }
 
 func run(f *cmdutil.Factory, arg string) error {
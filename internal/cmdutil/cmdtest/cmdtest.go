@@ -0,0 +1,166 @@
+// Package cmdtest builds a cmdutil.Factory backed by the in-memory fake API
+// client, an in-memory config and a recording printer, so cobra commands can
+// be executed end to end in tests.
+//
+//	h := cmdtest.New()
+//	project, env := h.API.SeedProject("", "api")
+//	svc := h.API.SeedService(project.ID, "web")
+//	h.API.SeedVariables(svc.ID, env.ID, map[string]string{"PORT": "8080"})
+//
+//	err := h.Run(list.NewCmdListVariables(h.Factory), "--id", svc.ID)
+//	rows := h.Printer.LastTable().Rows
+package cmdtest
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/api/apitest"
+	"github.com/zeabur/cli/pkg/config"
+	"github.com/zeabur/cli/pkg/printer"
+)
+
+// Harness bundles a Factory with the fakes behind it.
+type Harness struct {
+	Factory *cmdutil.Factory
+	API     *apitest.Fake
+	Config  *Config
+	Printer *Printer
+}
+
+// New returns a harness for a logged-in personal account in
+// non-interactive mode, the mode scripts run commands in.
+func New() *Harness {
+	fake := apitest.New()
+	cfg := NewConfig()
+	cfg.SetTokenString("test-token")
+	cfg.SetUsername(fake.User.Username)
+	cfg.SetUser(fake.User.Name)
+	p := &Printer{}
+
+	f := cmdutil.NewFactory()
+	f.Log = zap.NewNop().Sugar()
+	f.Printer = p
+	f.Config = cfg
+	f.ApiClient = fake
+	f.Interactive = false
+
+	return &Harness{Factory: f, API: fake, Config: cfg, Printer: p}
+}
+
+// Run executes cmd with args the way the root command would, but without
+// printing usage or errors; the error is returned instead.
+func (h *Harness) Run(cmd *cobra.Command, args ...string) error {
+	if args == nil {
+		args = []string{}
+	}
+	h.Printer.SetOutput(h.Factory.OutputFormat())
+	cmd.SetArgs(args)
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	return cmd.Execute()
+}
+
+// Config is an in-memory config.Config. Write is a no-op that counts
+// calls, so tests can assert whether a command persisted anything.
+type Config struct {
+	config.Config
+	v      *viper.Viper
+	Writes int
+}
+
+// NewConfig returns an empty in-memory config.
+func NewConfig() *Config {
+	v := viper.New()
+	return &Config{Config: config.FromViper(v, nil), v: v}
+}
+
+func (c *Config) Write() error { c.Writes++; return nil }
+
+// Set sets a raw config key, e.g. config.KeyAPIURL.
+func (c *Config) Set(key string, value any) { c.v.Set(key, value) }
+
+// Table is one table a command printed.
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// Printer is a printer.Printer that records its output instead of writing
+// to stdout. Data values are stored JSON-encoded, so tests compare what the
+// user would see rather than Go types; a printer.Tabler with rows is
+// recorded as a table too unless the output is structured.
+type Printer struct {
+	mu     sync.Mutex
+	Output printer.Output
+	Tables []Table
+	JSONs  []json.RawMessage
+	Events []json.RawMessage
+}
+
+func (p *Printer) SetOutput(o printer.Output) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Output = o
+}
+
+func (p *Printer) Table(header []string, rows [][]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Tables = append(p.Tables, Table{Header: header, Rows: rows})
+}
+
+func (p *Printer) Data(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if t, ok := v.(printer.Tabler); ok && !p.Output.Structured() && len(t.Rows()) > 0 {
+		p.Tables = append(p.Tables, Table{Header: t.Header(), Rows: t.Rows()})
+	}
+	p.JSONs = append(p.JSONs, data)
+	return nil
+}
+
+func (p *Printer) Event(v any, row []string) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Events = append(p.Events, data)
+	return nil
+}
+
+// LastTable returns the last printed table, or an empty one.
+func (p *Printer) LastTable() Table {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.Tables) == 0 {
+		return Table{}
+	}
+	return p.Tables[len(p.Tables)-1]
+}
+
+// DecodeLastJSON unmarshals the last printed JSON value into v.
+func (p *Printer) DecodeLastJSON(v any) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.JSONs) == 0 {
+		return json.Unmarshal([]byte("null"), v)
+	}
+	return json.Unmarshal(p.JSONs[len(p.JSONs)-1], v)
+}
+
+var (
+	_ config.Config   = (*Config)(nil)
+	_ printer.Printer = (*Printer)(nil)
+)
@@ -0,0 +1,97 @@
+package cmdutil
+
+import (
+	"strings"
+
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/constant"
+)
+
+// EffectiveAPIURL returns the base URL of the Zeabur API this invocation
+// talks to, without a trailing slash.
+//
+// Resolution order:
+//  1. --api-url flag (one-shot, never persisted)
+//  2. ZEABUR_API_URL env var, then `api_url` in the config file
+//  3. The production API (constant.ZeaburServerURL)
+func (f *Factory) EffectiveAPIURL() string {
+	if f.APIURL != "" {
+		return trimBaseURL(f.APIURL)
+	}
+	if f.Config != nil {
+		if u := f.Config.GetAPIURL(); u != "" {
+			return trimBaseURL(u)
+		}
+	}
+	return constant.ZeaburServerURL
+}
+
+// EffectiveWebsocketURL returns the base URL of the GraphQL subscription
+// endpoint. An explicit ZEABUR_WEBSOCKET_URL / `websocket_url` wins;
+// otherwise it is derived from EffectiveAPIURL by swapping the scheme, so
+// `--api-url http://localhost:8080` alone is enough to target a local server.
+func (f *Factory) EffectiveWebsocketURL() string {
+	if f.Config != nil {
+		if u := f.Config.GetWebsocketURL(); u != "" {
+			return trimBaseURL(u)
+		}
+	}
+	return websocketURLFor(f.EffectiveAPIURL())
+}
+
+// EffectiveDashURL returns the base URL of the dashboard that printed links
+// point to: ZEABUR_DASH_URL / `dash_url`, falling back to production.
+func (f *Factory) EffectiveDashURL() string {
+	if u := f.configuredDashURL(); u != "" {
+		return u
+	}
+	return constant.ZeaburDashURL
+}
+
+// EffectiveLegacyDashURL is EffectiveDashURL for the links production serves
+// from the older dashboard host. A configured dashboard replaces both.
+func (f *Factory) EffectiveLegacyDashURL() string {
+	if u := f.configuredDashURL(); u != "" {
+		return u
+	}
+	return constant.ZeaburLegacyDashURL
+}
+
+func (f *Factory) configuredDashURL() string {
+	if f.Config == nil {
+		return ""
+	}
+	return trimBaseURL(f.Config.GetDashURL())
+}
+
+// Endpoints returns the effective API endpoints as consumed by api.Client.
+func (f *Factory) Endpoints() api.Endpoints {
+	return api.Endpoints{
+		ServerURL:    f.EffectiveAPIURL(),
+		WebsocketURL: f.EffectiveWebsocketURL(),
+	}
+}
+
+// NewApiClient returns an api.Client authenticated with token against the
+// effective endpoints. Prefer it over api.New so the endpoint overrides are
+// honoured.
+func (f *Factory) NewApiClient(token string) api.Client {
+	return api.NewWithEndpoints(token, f.Endpoints())
+}
+
+func trimBaseURL(u string) string {
+	return strings.TrimRight(strings.TrimSpace(u), "/")
+}
+
+// websocketURLFor maps an http(s) base URL onto its ws(s) counterpart.
+// Anything without a recognised scheme is returned unchanged.
+func websocketURLFor(serverURL string) string {
+	switch {
+	case strings.HasPrefix(serverURL, "https://"):
+		return "wss://" + strings.TrimPrefix(serverURL, "https://")
+	case strings.HasPrefix(serverURL, "http://"):
+		return "ws://" + strings.TrimPrefix(serverURL, "http://")
+	default:
+		return serverURL
+	}
+}
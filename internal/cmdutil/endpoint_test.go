@@ -0,0 +1,74 @@
+package cmdutil_test
+
+import (
+	"testing"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/constant"
+)
+
+// TestFactory_Endpoints_Defaults: with nothing configured every endpoint
+// points at production, so existing users see no behaviour change.
+func TestFactory_Endpoints_Defaults(t *testing.T) {
+	for _, f := range []*cmdutil.Factory{{}, {Config: stubConfig{}}} {
+		if got := f.EffectiveAPIURL(); got != constant.ZeaburServerURL {
+			t.Errorf("EffectiveAPIURL = %q, want %q", got, constant.ZeaburServerURL)
+		}
+		if got := f.EffectiveWebsocketURL(); got != constant.WebsocketURL {
+			t.Errorf("EffectiveWebsocketURL = %q, want %q", got, constant.WebsocketURL)
+		}
+		if got := f.EffectiveDashURL(); got != constant.ZeaburDashURL {
+			t.Errorf("EffectiveDashURL = %q, want %q", got, constant.ZeaburDashURL)
+		}
+		if got := f.EffectiveLegacyDashURL(); got != constant.ZeaburLegacyDashURL {
+			t.Errorf("EffectiveLegacyDashURL = %q, want %q", got, constant.ZeaburLegacyDashURL)
+		}
+	}
+}
+
+// TestFactory_Endpoints_FlagBeatsConfig: --api-url is a one-shot override
+// and wins over the config / env value.
+func TestFactory_Endpoints_FlagBeatsConfig(t *testing.T) {
+	f := &cmdutil.Factory{Config: stubConfig{apiURL: "https://api.staging.example.com"}}
+	if got := f.EffectiveAPIURL(); got != "https://api.staging.example.com" {
+		t.Fatalf("config: got %q", got)
+	}
+	f.APIURL = "http://localhost:8080/"
+	if got := f.EffectiveAPIURL(); got != "http://localhost:8080" {
+		t.Fatalf("flag: got %q, want trailing slash trimmed", got)
+	}
+}
+
+// TestFactory_Endpoints_WebsocketDerived: overriding only the API URL must
+// move subscriptions along with it, otherwise `deployment log --watch`
+// would silently keep talking to production.
+func TestFactory_Endpoints_WebsocketDerived(t *testing.T) {
+	for _, tc := range []struct{ api, want string }{
+		{"https://api.staging.example.com", "wss://api.staging.example.com"},
+		{"http://localhost:8080", "ws://localhost:8080"},
+	} {
+		f := &cmdutil.Factory{PersistentFlags: cmdutil.PersistentFlags{APIURL: tc.api}}
+		if got := f.EffectiveWebsocketURL(); got != tc.want {
+			t.Errorf("api %q: websocket = %q, want %q", tc.api, got, tc.want)
+		}
+	}
+
+	f := &cmdutil.Factory{Config: stubConfig{apiURL: "http://localhost:8080", websocketURL: "ws://localhost:9090"}}
+	if got := f.EffectiveWebsocketURL(); got != "ws://localhost:9090" {
+		t.Errorf("explicit websocket_url must win, got %q", got)
+	}
+	if got := f.Endpoints(); got.ServerURL != "http://localhost:8080" || got.WebsocketURL != "ws://localhost:9090" {
+		t.Errorf("Endpoints() = %+v", got)
+	}
+}
+
+// TestFactory_Endpoints_DashURL: printed dashboard links follow the override.
+func TestFactory_Endpoints_DashURL(t *testing.T) {
+	f := &cmdutil.Factory{Config: stubConfig{dashURL: "https://staging.example.com/"}}
+	if got := f.EffectiveDashURL(); got != "https://staging.example.com" {
+		t.Fatalf("got %q", got)
+	}
+	if got := f.EffectiveLegacyDashURL(); got != "https://staging.example.com" {
+		t.Fatalf("legacy: got %q", got)
+	}
+}
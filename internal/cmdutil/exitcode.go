@@ -0,0 +1,39 @@
+package cmdutil
+
+import (
+	"errors"
+
+	"github.com/zeabur/cli/pkg/api"
+)
+
+// Exit codes of the CLI. Scripts can tell API failures apart by them
+// instead of parsing the error message.
+const (
+	ExitOK           = 0
+	ExitError        = 1 // any error without a more specific code
+	ExitValidation   = 2
+	ExitUnauthorized = 3
+	ExitForbidden    = 4
+	ExitNotFound     = 5
+	ExitRateLimited  = 6
+)
+
+// ExitCode returns the exit code the CLI should exit with after err.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, api.ErrValidation):
+		return ExitValidation
+	case errors.Is(err, api.ErrUnauthorized):
+		return ExitUnauthorized
+	case errors.Is(err, api.ErrForbidden):
+		return ExitForbidden
+	case errors.Is(err, api.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, api.ErrRateLimited):
+		return ExitRateLimited
+	default:
+		return ExitError
+	}
+}
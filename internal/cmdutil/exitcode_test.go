@@ -0,0 +1,37 @@
+package cmdutil_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zeabur/cli/internal/cmdutil"
+	"github.com/zeabur/cli/pkg/api"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, cmdutil.ExitOK},
+		{errors.New("boom"), cmdutil.ExitError},
+		{fmt.Errorf("get service: %w", api.ErrNotFound), cmdutil.ExitNotFound},
+		{fmt.Errorf("whoami: %w", api.ErrUnauthorized), cmdutil.ExitUnauthorized},
+		{api.ErrForbidden, cmdutil.ExitForbidden},
+		{api.ErrRateLimited, cmdutil.ExitRateLimited},
+		{api.ErrValidation, cmdutil.ExitValidation},
+	}
+	seen := map[int]bool{}
+	for _, tt := range tests {
+		if got := cmdutil.ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+		if seen[tt.want] {
+			t.Errorf("exit code %d is used twice", tt.want)
+		}
+		seen[tt.want] = true
+	}
+}
@@ -2,6 +2,8 @@ package cmdutil
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
 	"github.com/zeabur/cli/pkg/fill"
 	"github.com/zeabur/cli/pkg/printer"
@@ -55,6 +57,19 @@ type (
 		// depends on this). Reset between commands by virtue of being a
 		// per-Factory field; Factory itself is per-invocation.
 		ephemeralCtx zcontext.Context
+
+		// link is the link file found from the working directory (see
+		// Link), resolved once per invocation; linkCtx is the context
+		// EffectiveContext hands out for it, cached for the same
+		// `Set -> later Get` reason as ephemeralCtx.
+		link         *zcontext.Link
+		linkErr      error
+		linkResolved bool
+		linkCtx      zcontext.Context
+
+		// profileOverride is set when --profile / ZEABUR_PROFILE picked the
+		// auth profile for this invocation only.
+		profileOverride bool
 	}
 	// PersistentFlags are flags that are common to all commands
 	PersistentFlags struct {
@@ -62,8 +77,10 @@ type (
 		Interactive      bool   // interactive mode, default true
 		AutoRefreshToken bool   // auto refresh token, default true, only when token is from browser(OAuth2)
 		AutoCheckUpdate  bool   // auto check update, default true
-		JSON             bool   // output in JSON format, default false
+		Output           string // --output/-o format, see printer.ParseOutput; default table
 		Workspace        string // --workspace <name|id> one-shot override
+		Profile          string // --profile <name> one-shot auth profile override
+		APIURL           string // --api-url one-shot API endpoint override
 	}
 )
 
@@ -104,6 +121,28 @@ func (f *Factory) SetWorkspaceOverride(ws *zcontext.Workspace) {
 	f.workspaceOverride = ws
 }
 
+// SetProfileOverride activates the named auth profile for this invocation
+// without persisting it as the active profile, the same one-shot contract as
+// SetWorkspaceOverride. Called from PersistentPreRunE for --profile and
+// ZEABUR_PROFILE, before any token is read. The profile must exist.
+func (f *Factory) SetProfileOverride(name string) error {
+	if !slices.Contains(f.Config.ListProfiles(), name) {
+		return fmt.Errorf("profile %q not found; create it with `zeabur auth profile add %s`", name, name)
+	}
+	if err := f.Config.SetProfile(name, false); err != nil {
+		return err
+	}
+	f.profileOverride = true
+	return nil
+}
+
+// HasProfileOverride reports whether the auth profile was picked for this
+// invocation only. Commands that change which profile is active refuse to
+// run under it, since the override would hide the change.
+func (f *Factory) HasProfileOverride() bool {
+	return f.profileOverride
+}
+
 // HasWorkspaceOverride reports whether the caller invoked the command with a
 // `--workspace <name|id>` flag. Use this to gate any behaviour that should
 // only apply to "one-shot override" mode — most prominently, refusing to
@@ -125,20 +164,20 @@ func (f *Factory) HasWorkspaceOverride() bool {
 // fail-closed with an actionable error and the caller must pass an explicit
 // `--id` / `--service-id`.
 func (f *Factory) CurrentProjectID() string {
-	if f.HasWorkspaceOverride() || f.Config == nil {
+	if f.HasWorkspaceOverride() {
 		return ""
 	}
-	return f.Config.GetContext().GetProject().GetID()
+	return f.EffectiveContext().GetProject().GetID()
 }
 
 // CurrentProjectName mirrors CurrentProjectID. The personal path of the
 // service-by-name lookup uses the project name; return "" under override so
 // that path also refuses rather than reaches into the persisted context.
 func (f *Factory) CurrentProjectName() string {
-	if f.HasWorkspaceOverride() || f.Config == nil {
+	if f.HasWorkspaceOverride() {
 		return ""
 	}
-	return f.Config.GetContext().GetProject().GetName()
+	return f.EffectiveContext().GetProject().GetName()
 }
 
 // CurrentEnvironmentID — same rule as CurrentProjectID, applied to the
@@ -146,10 +185,10 @@ func (f *Factory) CurrentProjectName() string {
 // command consumes environment context implicitly, but the helper keeps the
 // override contract uniform across all three inner-context fields.
 func (f *Factory) CurrentEnvironmentID() string {
-	if f.HasWorkspaceOverride() || f.Config == nil {
+	if f.HasWorkspaceOverride() {
 		return ""
 	}
-	return f.Config.GetContext().GetEnvironment().GetID()
+	return f.EffectiveContext().GetEnvironment().GetID()
 }
 
 // CurrentServiceID — same rule as CurrentProjectID for the persisted service
@@ -157,10 +196,10 @@ func (f *Factory) CurrentEnvironmentID() string {
 // project + name) but exposed so future consumers stay on the same override
 // contract.
 func (f *Factory) CurrentServiceID() string {
-	if f.HasWorkspaceOverride() || f.Config == nil {
+	if f.HasWorkspaceOverride() {
 		return ""
 	}
-	return f.Config.GetContext().GetService().GetID()
+	return f.EffectiveContext().GetService().GetID()
 }
 
 // EffectiveContext returns the inner context (project / environment /
@@ -184,6 +223,14 @@ func (f *Factory) CurrentServiceID() string {
 // their writes.
 func (f *Factory) EffectiveContext() zcontext.Context {
 	if !f.HasWorkspaceOverride() {
+		// A broken link file already failed the command in root (see
+		// IsLinkCheckEnabled), except for link / unlink, which replace it.
+		if link, err := f.Link(); err == nil && link != nil {
+			if f.linkCtx == nil {
+				f.linkCtx = zcontext.NewLinkContext(link, f.CurrentWorkspace())
+			}
+			return f.linkCtx
+		}
 		if f.Config == nil {
 			return zcontext.NewEphemeralContext(nil)
 		}
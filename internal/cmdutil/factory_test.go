@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/zeabur/cli/internal/cmdutil"
 	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/config"
 	"github.com/zeabur/cli/pkg/model"
 	"github.com/zeabur/cli/pkg/zcontext"
 )
@@ -17,16 +18,27 @@ import (
 // them would notice the missing behavior.
 type stubConfig struct {
 	ctx zcontext.Context
+
+	apiURL, websocketURL, dashURL string
 }
 
-func (s stubConfig) GetTokenString() string       { return "" }
-func (s stubConfig) SetTokenString(string)        {}
-func (s stubConfig) GetUser() string              { return "" }
-func (s stubConfig) SetUser(string)               {}
-func (s stubConfig) GetUsername() string          { return "" }
-func (s stubConfig) SetUsername(string)           {}
-func (s stubConfig) GetContext() zcontext.Context { return s.ctx }
-func (s stubConfig) Write() error                 { return nil }
+func (s stubConfig) GetTokenString() string              { return "" }
+func (s stubConfig) SetTokenString(string)               {}
+func (s stubConfig) GetCredentialStore() (string, error) { return "plaintext", nil }
+func (s stubConfig) GetUser() string                     { return "" }
+func (s stubConfig) SetUser(string)                      {}
+func (s stubConfig) GetUsername() string                 { return "" }
+func (s stubConfig) SetUsername(string)                  {}
+func (s stubConfig) GetAPIURL() string                   { return s.apiURL }
+func (s stubConfig) GetWebsocketURL() string             { return s.websocketURL }
+func (s stubConfig) GetDashURL() string                  { return s.dashURL }
+func (s stubConfig) GetSecretPatterns() []string         { return nil }
+func (s stubConfig) GetContext() zcontext.Context        { return s.ctx }
+func (s stubConfig) GetProfile() string                  { return config.DefaultProfile }
+func (s stubConfig) SetProfile(string, bool) error       { return nil }
+func (s stubConfig) ListProfiles() []string              { return []string{config.DefaultProfile} }
+func (s stubConfig) RemoveProfile(string) error          { return nil }
+func (s stubConfig) Write() error                        { return nil }
 
 // TestFactory_PersonalUserInvariant guards the single most important
 // backward-compat rule of PLA-1590: a brand-new caller — one who has never
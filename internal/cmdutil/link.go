@@ -0,0 +1,98 @@
+package cmdutil
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/pkg/zcontext"
+)
+
+// Link returns the link file written by `zeabur link` for the working
+// directory or its nearest linked parent, or nil when there is none. A link
+// takes the place of the persisted inner context in EffectiveContext, so
+// each checkout keeps its own project / environment / service.
+//
+// The lookup runs once per invocation. A link file that cannot be read or
+// parsed is an error, never a silent fall back to the persisted context:
+// the command would act on another project than the checkout's. Only when
+// there is no link file at all is the persisted context used.
+func (f *Factory) Link() (*zcontext.Link, error) {
+	if !f.linkResolved {
+		f.linkResolved = true
+		if wd, err := os.Getwd(); err != nil {
+			f.linkErr = err
+		} else {
+			f.link, f.linkErr = zcontext.FindLink(wd)
+		}
+		if f.linkErr != nil {
+			f.linkErr = fmt.Errorf("read link file: %w; fix it, or run `zeabur unlink` to remove it", f.linkErr)
+		}
+	}
+	return f.link, f.linkErr
+}
+
+// DisableLinkCheck lets cmd run in a directory whose link file is broken,
+// for the commands that replace or remove it.
+func DisableLinkCheck(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+
+	cmd.Annotations["skipLinkCheck"] = "true"
+}
+
+// IsLinkCheckEnabled reports whether a broken link file fails cmd before it
+// runs, which keeps EffectiveContext from falling back to the persisted
+// context inside a linked checkout.
+func IsLinkCheckEnabled(cmd *cobra.Command) bool {
+	switch cmd.Name() {
+	case "help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return false
+	}
+
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Annotations != nil && c.Annotations["skipLinkCheck"] == "true" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ResetLink drops the cached link so the next Link call looks it up again;
+// `zeabur link` / `unlink` call it after changing the file.
+func (f *Factory) ResetLink() {
+	f.link, f.linkErr, f.linkResolved, f.linkCtx = nil, nil, false, nil
+}
+
+// LinkedService fills serviceID, serviceName and environmentID from the
+// link when the user named no service, so service-scoped commands run inside
+// a linked checkout need no flags. The environment is only filled when it is
+// empty. A broken link file is returned as an error.
+//
+// Like the rest of the inner context, the link is ignored under a
+// `--workspace` override.
+func (f *Factory) LinkedService(serviceID, serviceName, environmentID *string) error {
+	if f.HasWorkspaceOverride() || *serviceID != "" || *serviceName != "" {
+		return nil
+	}
+	link, err := f.Link()
+	if err != nil {
+		return err
+	}
+	if link == nil || link.Service.ID == "" {
+		return nil
+	}
+
+	*serviceID = link.Service.ID
+	*serviceName = link.Service.Name
+	if *environmentID == "" {
+		*environmentID = link.Environment.ID
+	}
+	if f.Log != nil {
+		f.Log.Debugf("Using service %s linked in %s", link.Service.ID, link.Path())
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package cmdutil
+
+import "github.com/zeabur/cli/pkg/printer"
+
+// OutputFormat returns the parsed --output flag. PersistentPreRunE rejects
+// invalid values up front, so an unparsable value here falls back to table.
+func (f *Factory) OutputFormat() printer.Output {
+	o, err := printer.ParseOutput(f.Output)
+	if err != nil {
+		return printer.Output{Format: printer.FormatTable}
+	}
+	return o
+}
+
+// StructuredOutput reports whether commands should hand the printer their
+// underlying value (json, yaml, template, jsonpath) instead of printing
+// a table and human-oriented messages. Commands that need a prompt should
+// treat it as non-interactive.
+func (f *Factory) StructuredOutput() bool {
+	return f.OutputFormat().Structured()
+}
+
+// MachineReadableOutput reports whether stdout is meant for another program:
+// every format except table and wide. Logging is silenced in that case.
+func (f *Factory) MachineReadableOutput() bool {
+	return f.OutputFormat().MachineReadable()
+}
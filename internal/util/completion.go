@@ -0,0 +1,64 @@
+package util
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Completion kinds: what a flag or positional argument names, so that the
+// root command can complete it from the API.
+const (
+	CompleteProject              = "project"
+	CompleteProjectName          = "project-name"
+	CompleteService              = "service"
+	CompleteServiceName          = "service-name"
+	CompleteEnvironment          = "environment"
+	CompleteServer               = "server"
+	CompleteRegisteredDomain     = "registered-domain"
+	CompleteRegisteredDomainName = "registered-domain-name"
+	CompleteWorkspace            = "workspace"
+	CompleteProfile              = "profile"
+)
+
+const completionAnnotation = "zeabur_completion"
+
+// flagCompletions are the kinds of flags whose name alone says what they
+// hold; other flags, such as --id, are annotated with SetFlagCompletion.
+var flagCompletions = map[string]string{
+	"project-id":     CompleteProject,
+	"service-id":     CompleteService,
+	"service-name":   CompleteServiceName,
+	"env-id":         CompleteEnvironment,
+	"environment-id": CompleteEnvironment,
+	"server-id":      CompleteServer,
+	"workspace":      CompleteWorkspace,
+	"profile":        CompleteProfile,
+}
+
+// SetFlagCompletion marks the named flag of cmd as holding a kind.
+func SetFlagCompletion(cmd *cobra.Command, name, kind string) {
+	_ = cmd.Flags().SetAnnotation(name, completionAnnotation, []string{kind})
+}
+
+// FlagCompletion returns the kind of flag, or "" when it is not completed.
+func FlagCompletion(flag *pflag.Flag) string {
+	if kinds := flag.Annotations[completionAnnotation]; len(kinds) > 0 {
+		return kinds[0]
+	}
+	return flagCompletions[flag.Name]
+}
+
+// SetArgsCompletion marks the first positional argument of cmd as holding
+// a kind.
+func SetArgsCompletion(cmd *cobra.Command, kind string) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[completionAnnotation] = kind
+}
+
+// ArgsCompletion returns the kind of the first positional argument of cmd,
+// or "" when it is not completed.
+func ArgsCompletion(cmd *cobra.Command) string {
+	return cmd.Annotations[completionAnnotation]
+}
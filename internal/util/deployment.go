@@ -0,0 +1,76 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// GetLatestDeploymentID returns the ID of the newest deployment of a service,
+// or "" if it has never been deployed.
+func GetLatestDeploymentID(ctx context.Context, client api.Client, serviceID, environmentID string) (string, error) {
+	deployment, exists, err := client.GetLatestDeployment(ctx, serviceID, environmentID)
+	if err != nil {
+		return "", fmt.Errorf("get latest deployment: %w", err)
+	}
+	if !exists {
+		return "", nil
+	}
+	return deployment.ID, nil
+}
+
+// WaitForNewDeployment polls until the newest deployment of a service is no
+// longer previousID, i.e. until the deployment triggered by an upload or a
+// redeploy shows up.
+func WaitForNewDeployment(ctx context.Context, client api.Client, serviceID, environmentID, previousID string, interval time.Duration) (*model.Deployment, error) {
+	for {
+		deployment, exists, err := client.GetLatestDeployment(ctx, serviceID, environmentID)
+		if err != nil {
+			return nil, fmt.Errorf("get latest deployment: %w", err)
+		}
+		if exists && deployment.ID != previousID {
+			return deployment, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for the new deployment to be created: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// WaitForDeployment polls a deployment until it reaches a terminal status
+// and returns its final state. onStatus, if not nil, is called every time
+// the status changes, including for the first status observed.
+//
+// A failed deployment is not an error here; callers check IsFailed on the
+// returned deployment. An error is only returned if polling itself fails or
+// ctx is done, in which case the last observed deployment is returned too.
+func WaitForDeployment(ctx context.Context, client api.Client, deploymentID string, interval time.Duration, onStatus func(*model.Deployment)) (*model.Deployment, error) {
+	var last *model.Deployment
+	for {
+		deployment, err := client.GetDeployment(ctx, deploymentID)
+		if err != nil {
+			return last, fmt.Errorf("get deployment<%s>: %w", deploymentID, err)
+		}
+
+		if onStatus != nil && (last == nil || last.Status != deployment.Status) {
+			onStatus(deployment)
+		}
+		last = deployment
+
+		if deployment.IsTerminal() {
+			return deployment, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, fmt.Errorf("waiting for deployment<%s> (last status: %s): %w", deploymentID, last.Status, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
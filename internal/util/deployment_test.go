@@ -0,0 +1,128 @@
+package util_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zeabur/cli/internal/util"
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// fakeDeploymentClient replays a fixed sequence of responses, repeating the
+// last one once it runs out.
+type fakeDeploymentClient struct {
+	api.Client
+
+	latest   []*model.Deployment
+	statuses []string
+
+	latestCalls int
+	getCalls    int
+}
+
+func (c *fakeDeploymentClient) GetLatestDeployment(_ context.Context, _, _ string) (*model.Deployment, bool, error) {
+	i := min(c.latestCalls, len(c.latest)-1)
+	c.latestCalls++
+	if c.latest[i] == nil {
+		return nil, false, nil
+	}
+	return c.latest[i], true, nil
+}
+
+func (c *fakeDeploymentClient) GetDeployment(_ context.Context, id string) (*model.Deployment, error) {
+	i := min(c.getCalls, len(c.statuses)-1)
+	c.getCalls++
+	return &model.Deployment{ID: id, Status: c.statuses[i]}, nil
+}
+
+func TestWaitForNewDeployment_SkipsPrevious(t *testing.T) {
+	c := &fakeDeploymentClient{latest: []*model.Deployment{
+		{ID: "old"},
+		{ID: "old"},
+		{ID: "new"},
+	}}
+
+	got, err := util.WaitForNewDeployment(context.Background(), c, "svc", "env", "old", time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ID != "new" {
+		t.Fatalf("got deployment %q, want new", got.ID)
+	}
+	if c.latestCalls != 3 {
+		t.Fatalf("expected 3 polls, got %d", c.latestCalls)
+	}
+}
+
+func TestWaitForNewDeployment_FirstDeployment(t *testing.T) {
+	c := &fakeDeploymentClient{latest: []*model.Deployment{nil, {ID: "first"}}}
+
+	got, err := util.WaitForNewDeployment(context.Background(), c, "svc", "env", "", time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ID != "first" {
+		t.Fatalf("got deployment %q, want first", got.ID)
+	}
+}
+
+func TestWaitForDeployment_ReportsStatusChanges(t *testing.T) {
+	c := &fakeDeploymentClient{statuses: []string{
+		model.DeploymentStatusPending,
+		model.DeploymentStatusBuilding,
+		model.DeploymentStatusBuilding,
+		model.DeploymentStatusDeploying,
+		model.DeploymentStatusRunning,
+	}}
+
+	var seen []string
+	got, err := util.WaitForDeployment(context.Background(), c, "dep", time.Millisecond, func(d *model.Deployment) {
+		seen = append(seen, d.Status)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != model.DeploymentStatusRunning || got.IsFailed() {
+		t.Fatalf("got status %q, want RUNNING", got.Status)
+	}
+
+	want := []string{"PENDING", "BUILDING", "DEPLOYING", "RUNNING"}
+	if len(seen) != len(want) {
+		t.Fatalf("status callbacks = %v, want %v", seen, want)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("status callbacks = %v, want %v", seen, want)
+		}
+	}
+}
+
+func TestWaitForDeployment_Failed(t *testing.T) {
+	c := &fakeDeploymentClient{statuses: []string{model.DeploymentStatusBuilding, model.DeploymentStatusFailed}}
+
+	got, err := util.WaitForDeployment(context.Background(), c, "dep", time.Millisecond, nil)
+	if err != nil {
+		t.Fatalf("a failed deployment must not be a polling error, got %v", err)
+	}
+	if !got.IsFailed() {
+		t.Fatalf("got status %q, want a failed deployment", got.Status)
+	}
+}
+
+func TestWaitForDeployment_Timeout(t *testing.T) {
+	c := &fakeDeploymentClient{statuses: []string{model.DeploymentStatusBuilding}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	got, err := util.WaitForDeployment(ctx, c, "dep", time.Millisecond, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if got == nil || got.Status != model.DeploymentStatusBuilding {
+		t.Fatalf("expected last observed deployment to be returned, got %+v", got)
+	}
+}
@@ -11,6 +11,7 @@ import (
 // AddEnvParam todo: support name
 func AddEnvParam(cmd *cobra.Command, id *string) {
 	cmd.Flags().StringVar(id, "id", "", "Environment ID")
+	SetFlagCompletion(cmd, "id", CompleteEnvironment)
 }
 
 func AddEnvOfServiceParam(cmd *cobra.Command, id *string) {
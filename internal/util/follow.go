@@ -0,0 +1,74 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/api/logstream"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+const (
+	followRetryDelay    = time.Second
+	followMaxRetryDelay = 30 * time.Second
+)
+
+// FollowLogs subscribes with watch and passes every line to emit, until ctx
+// is done, a subscription ends cleanly or emit returns false, which it does
+// when it gives up because ctx is done.
+//
+// A failed subscription is renewed after a growing delay, skipping the
+// lines the previous one had already passed on; onError, if not nil, is
+// told about each failure and the delay. A failure resubscribing cannot fix
+// (see PermanentLogError) is returned instead.
+func FollowLogs(
+	ctx context.Context,
+	watch func(ctx context.Context) (<-chan model.Log, <-chan error),
+	emit func(l model.Log) bool,
+	onError func(err error, retryIn time.Duration),
+) error {
+	var last, skipUntil time.Time
+	delay := followRetryDelay
+	for {
+		logChan, errChan := watch(ctx)
+		for l := range logChan {
+			if !l.Timestamp.After(skipUntil) {
+				continue
+			}
+			last, delay = l.Timestamp, followRetryDelay
+			if !emit(l) {
+				return nil
+			}
+		}
+
+		err := <-errChan
+		if err == nil || ctx.Err() != nil {
+			return nil
+		}
+		if PermanentLogError(err) {
+			return err
+		}
+		if onError != nil {
+			onError(err, delay)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, followMaxRetryDelay)
+		skipUntil = last
+	}
+}
+
+// PermanentLogError reports whether err ends a log subscription for good:
+// the stream gave up on it as terminal, or the token or the resource is
+// refused, which no resubscription changes.
+func PermanentLogError(err error) bool {
+	return logstream.IsTerminal(err) ||
+		errors.Is(err, api.ErrUnauthorized) ||
+		errors.Is(err, api.ErrForbidden) ||
+		errors.Is(err, api.ErrNotFound)
+}
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/pkg/logfilter"
+)
+
+// LogFilterFlags are the flags that select log lines, shared by the
+// commands that print logs.
+type LogFilterFlags struct {
+	Since  string
+	Until  string
+	Grep   string
+	Invert bool
+	Level  string
+}
+
+// AddLogFilterFlags adds --grep, --invert and --level to cmd, and --since
+// and --until when timeRange is set.
+func AddLogFilterFlags(cmd *cobra.Command, flags *LogFilterFlags, timeRange bool) {
+	if timeRange {
+		cmd.Flags().StringVar(&flags.Since, "since", "", "Only show logs since a duration ago (e.g. 30m), an RFC 3339 timestamp or a date")
+		cmd.Flags().StringVar(&flags.Until, "until", "", "Only show logs until a duration ago, an RFC 3339 timestamp or a date")
+	}
+	cmd.Flags().StringVar(&flags.Grep, "grep", "", "Only show logs matching a regular expression")
+	cmd.Flags().BoolVar(&flags.Invert, "invert", false, "Only show logs not matching --grep")
+	cmd.Flags().StringVar(&flags.Level, "level", "", "Only show logs at this level or above: trace, debug, info, warn, error or fatal")
+}
+
+// Filter checks the flags and returns the filter they select. Durations in
+// --since and --until count back from now.
+func (flags *LogFilterFlags) Filter(now time.Time) (logfilter.Filter, error) {
+	var filter logfilter.Filter
+
+	if flags.Since != "" {
+		since, err := ParseTime(flags.Since, now)
+		if err != nil {
+			return filter, fmt.Errorf("--since: %w", err)
+		}
+		filter.Since = since
+	}
+	if flags.Until != "" {
+		until, err := ParseTime(flags.Until, now)
+		if err != nil {
+			return filter, fmt.Errorf("--until: %w", err)
+		}
+		filter.Until = until
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && filter.Until.Before(filter.Since) {
+		return filter, errors.New("--until must not be before --since")
+	}
+
+	if flags.Grep != "" {
+		pattern, err := regexp.Compile(flags.Grep)
+		if err != nil {
+			return filter, fmt.Errorf("--grep: %w", err)
+		}
+		filter.Pattern = pattern
+	} else if flags.Invert {
+		return filter, errors.New("--invert requires --grep")
+	}
+	filter.Invert = flags.Invert
+
+	if flags.Level != "" {
+		level, err := logfilter.ParseLevel(flags.Level)
+		if err != nil {
+			return filter, fmt.Errorf("--level: %w", err)
+		}
+		filter.Level = level
+	}
+	return filter, nil
+}
@@ -0,0 +1,50 @@
+package util
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/manifest"
+)
+
+// ResolveManifestTarget resolves the project and environment a manifest
+// applies to. The manifest's `project` may be a project ID or a project name
+// within the active workspace (see GetProjectByName); a missing
+// `environment` resolves to the project's environment.
+func ResolveManifestTarget(client api.Client, ownerID, personalUsername string, m *manifest.Manifest) (projectID, environmentID string, err error) {
+	if IsObjectID(m.Project) {
+		project, err := client.GetProject(context.Background(), m.Project, "", "")
+		if err != nil {
+			return "", "", fmt.Errorf("get project<%s> failed: %w", m.Project, err)
+		}
+		projectID = project.ID
+	} else {
+		project, err := GetProjectByName(client, ownerID, personalUsername, m.Project)
+		if err != nil {
+			return "", "", err
+		}
+		projectID = project.ID
+	}
+
+	environmentID = m.Environment
+	if environmentID == "" {
+		environmentID, err = ResolveEnvironmentID(client, projectID)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return projectID, environmentID, nil
+}
+
+// IsObjectID reports whether s has the shape of an object ID: 24 hex
+// characters.
+func IsObjectID(s string) bool {
+	if len(s) != 24 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -47,4 +47,6 @@ func GetProjectByName(client api.Client, ownerID, personalUsername, projectName
 func AddProjectParam(cmd *cobra.Command, id, name *string) {
 	cmd.Flags().StringVar(id, "id", "", "Project ID")
 	cmd.Flags().StringVarP(name, "name", "n", "", "Project name")
+	SetFlagCompletion(cmd, "id", CompleteProject)
+	SetFlagCompletion(cmd, "name", CompleteProjectName)
 }
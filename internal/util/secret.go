@@ -0,0 +1,167 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// DefaultSecretPatterns are the keys whose values are masked unless
+// revealed, matched case-insensitively as globs. The secret_patterns
+// setting adds to them.
+var DefaultSecretPatterns = []string{
+	"*PASSWORD*",
+	"*PASSWD*",
+	"*SECRET*",
+	"*TOKEN*",
+	"*_KEY",
+	"*APIKEY*",
+	"*PRIVATE_KEY*",
+	"*CONNECTION_STRING*",
+}
+
+// revealAll is the value of a bare --reveal.
+const revealAll = "*"
+
+// AddRevealParam adds --reveal to a command printing variable values. Alone
+// it shows every value; --reveal KEY, repeated or comma-separated, shows
+// only the values of those keys. Since a bare --reveal takes no value, the
+// keys following it arrive as arguments, which are taken as keys instead
+// of being passed to the validation of cmd.Args.
+func AddRevealParam(cmd *cobra.Command, reveal *[]string) {
+	cmd.Flags().StringSliceVar(reveal, "reveal", nil, "Show secret values: all of them, or only those of the given keys (--reveal KEY)")
+	cmd.Flags().Lookup("reveal").NoOptDefVal = revealAll
+
+	validate := cmd.Args
+	cmd.Args = func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 && slices.Contains(*reveal, revealAll) {
+			*reveal = append(slices.DeleteFunc(*reveal, func(k string) bool { return k == revealAll }), args...)
+			return nil
+		}
+		if validate == nil {
+			return nil
+		}
+		return validate(cmd, args)
+	}
+}
+
+// Masker masks the values of secret variables for display, remembering
+// which secrets it was asked to reveal so they can be reported.
+type Masker struct {
+	patterns []string
+	all      bool
+	reveal   map[string]bool
+	revealed map[string]bool
+}
+
+// NewMasker returns a Masker for the keys matching the default patterns or
+// patterns, revealing the keys of reveal, or every key when it holds "*".
+func NewMasker(patterns, reveal []string) (*Masker, error) {
+	m := &Masker{reveal: map[string]bool{}, revealed: map[string]bool{}}
+	for _, p := range slices.Concat(DefaultSecretPatterns, patterns) {
+		p = strings.ToUpper(strings.TrimSpace(p))
+		if p == "" {
+			continue
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid secret pattern %q: %w", p, err)
+		}
+		m.patterns = append(m.patterns, p)
+	}
+	for _, k := range reveal {
+		if k == revealAll {
+			m.all = true
+		} else if k != "" {
+			m.reveal[k] = true
+		}
+	}
+	return m, nil
+}
+
+// Secret reports whether the value of key is a secret.
+func (m *Masker) Secret(key string) bool {
+	key = strings.ToUpper(key)
+	return slices.ContainsFunc(m.patterns, func(p string) bool {
+		ok, _ := path.Match(p, key)
+		return ok
+	})
+}
+
+// Reveals reports whether the value of key is to be shown, noting it when
+// key is a secret.
+func (m *Masker) Reveals(key string) bool {
+	if !m.all && !m.reveal[key] {
+		return false
+	}
+	if m.Secret(key) {
+		m.revealed[key] = true
+	}
+	return true
+}
+
+// Value returns value, masked when key is a secret that is not revealed.
+func (m *Masker) Value(key, value string) string {
+	if !m.Secret(key) || m.Reveals(key) {
+		return value
+	}
+	return MaskValue(value)
+}
+
+// Map returns a copy of vars with the values masked.
+func (m *Masker) Map(vars map[string]string) map[string]string {
+	masked := make(map[string]string, len(vars))
+	for k, v := range vars {
+		masked[k] = m.Value(k, v)
+	}
+	return masked
+}
+
+// Variables returns a copy of vars with the values masked.
+func (m *Masker) Variables(vars model.Variables) model.Variables {
+	masked := make(model.Variables, 0, len(vars))
+	for _, v := range vars {
+		c := *v
+		c.Value = m.Value(v.Key, v.Value)
+		masked = append(masked, &c)
+	}
+	return masked
+}
+
+// Masked returns how many values of the secrets of vars were masked.
+func (m *Masker) Masked(vars map[string]string) int {
+	n := 0
+	for k := range vars {
+		if m.Secret(k) && !m.all && !m.reveal[k] {
+			n++
+		}
+	}
+	return n
+}
+
+// Revealed returns the secrets whose values were shown, sorted.
+func (m *Masker) Revealed() []string {
+	return slices.Sorted(maps.Keys(m.revealed))
+}
+
+// NoticeRevealed writes a notice to w naming the secrets m revealed, if
+// any, and who revealed them where. Commands write it when they run
+// non-interactively, since their output then tends to end up in CI logs.
+func NoticeRevealed(w io.Writer, m *Masker, user, where string) {
+	keys := m.Revealed()
+	if len(keys) == 0 {
+		return
+	}
+	if user == "" {
+		user = "unknown user"
+	}
+	_, _ = fmt.Fprintf(w, "notice: %s revealed the secret values of %s in %s at %s\n",
+		user, strings.Join(keys, ", "), where, time.Now().UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,51 @@
+package util_test
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/zeabur/cli/internal/util"
+)
+
+func TestMasker(t *testing.T) {
+	t.Parallel()
+
+	m, err := util.NewMasker([]string{"*_dsn", " INTERNAL_*"}, []string{"GITHUB_TOKEN"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, secret := range map[string]bool{
+		"DB_PASSWORD":       true,
+		"jwt_secret":        true,
+		"GITHUB_TOKEN":      true,
+		"STRIPE_KEY":        true,
+		"SENTRY_DSN":        true,
+		"INTERNAL_URL":      true,
+		"PORT":              false,
+		"KEYCLOAK_HOSTNAME": false,
+	} {
+		if got := m.Secret(key); got != secret {
+			t.Errorf("Secret(%q) = %v, want %v", key, got, secret)
+		}
+	}
+
+	got := m.Map(map[string]string{"PORT": "8080", "DB_PASSWORD": "hunter2", "GITHUB_TOKEN": "ghp_abc"})
+	if got["PORT"] != "8080" || got["DB_PASSWORD"] != "********" || got["GITHUB_TOKEN"] != "ghp_abc" {
+		t.Errorf("Map = %v", got)
+	}
+	if revealed := m.Revealed(); !slices.Equal(revealed, []string{"GITHUB_TOKEN"}) {
+		t.Errorf("Revealed = %v", revealed)
+	}
+
+	var notice bytes.Buffer
+	util.NoticeRevealed(&notice, m, "alice", "service web")
+	if !strings.HasPrefix(notice.String(), "notice: alice revealed the secret values of GITHUB_TOKEN in service web at ") {
+		t.Errorf("notice = %q", notice.String())
+	}
+
+	if _, err := util.NewMasker([]string{"[A-"}, nil); err == nil {
+		t.Error("accepted an invalid pattern")
+	}
+}
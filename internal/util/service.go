@@ -2,7 +2,10 @@ package util
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/zeabur/cli/pkg/api"
@@ -49,4 +52,39 @@ func GetServiceByName(client api.Client, ownerID, personalUsername, projectName,
 func AddServiceParam(cmd *cobra.Command, id, name *string) {
 	cmd.Flags().StringVar(id, "id", "", "Service ID")
 	cmd.Flags().StringVarP(name, "name", "n", "", "Service name")
+	SetFlagCompletion(cmd, "id", CompleteService)
+	SetFlagCompletion(cmd, "name", CompleteServiceName)
+}
+
+// SelectServices returns the services of the project named by names, which
+// are service names or IDs, or all of them when names is empty; sorted by
+// name either way.
+func SelectServices(ctx context.Context, client api.Client, projectID string, names []string) (model.Services, error) {
+	all, err := client.ListAllServices(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list services: %w", err)
+	}
+	slices.SortFunc(all, func(a, b *model.Service) int { return strings.Compare(a.Name, b.Name) })
+
+	if len(names) == 0 {
+		if len(all) == 0 {
+			return nil, errors.New("the project has no services")
+		}
+		return all, nil
+	}
+
+	var selected model.Services
+	for _, name := range names {
+		i := slices.IndexFunc(all, func(svc *model.Service) bool {
+			return svc.Name == name || svc.ID == string(api.ObjectID(name))
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("no service named %q in this project", name)
+		}
+		if !slices.Contains(selected, all[i]) {
+			selected = append(selected, all[i])
+		}
+	}
+	slices.SortFunc(selected, func(a, b *model.Service) int { return strings.Compare(a.Name, b.Name) })
+	return selected, nil
 }
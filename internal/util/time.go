@@ -0,0 +1,27 @@
+package util
+
+import (
+	"fmt"
+	"time"
+)
+
+// ParseTime parses a --since or --until value: a duration before now such
+// as "90m" or "2h", an RFC 3339 timestamp, or a local date and time in the
+// form "2006-01-02" or "2006-01-02 15:04".
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("invalid time %q: the duration must not be negative", value)
+		}
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use a duration such as 2h, an RFC 3339 timestamp, or a date such as 2006-01-02", value)
+}
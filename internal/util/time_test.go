@@ -0,0 +1,39 @@
+package util_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zeabur/cli/internal/util"
+)
+
+func TestParseTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		value string
+		want  time.Time
+	}{
+		{"90m", now.Add(-90 * time.Minute)},
+		{"0s", now},
+		{"2024-05-01T08:30:00Z", time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)},
+		{"2024-05-01 08:30", time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)},
+		{"2024-05-01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		got, err := util.ParseTime(tc.value, now)
+		if err != nil {
+			t.Errorf("ParseTime(%q): %v", tc.value, err)
+			continue
+		}
+		if !got.Equal(tc.want) {
+			t.Errorf("ParseTime(%q) = %s, want %s", tc.value, got, tc.want)
+		}
+	}
+
+	for _, value := range []string{"", "yesterday", "-1h"} {
+		if _, err := util.ParseTime(value, now); err == nil {
+			t.Errorf("ParseTime(%q) succeeded, want an error", value)
+		}
+	}
+}
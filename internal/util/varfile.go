@@ -0,0 +1,156 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/zeabur/cli/pkg/config"
+	"github.com/zeabur/cli/pkg/envcrypt"
+	"github.com/zeabur/cli/pkg/envfile"
+)
+
+// Env vars with the keys of encrypted variable files, for CI.
+const (
+	// AgeKeyEnv holds age identities, one per line.
+	AgeKeyEnv = "ZEABUR_AGE_KEY"
+	// AgeKeyFileEnv names an age key file, instead of DefaultAgeKeyFile.
+	AgeKeyFileEnv = "ZEABUR_AGE_KEY_FILE"
+	// VariablesPassphraseEnv holds the passphrase of encrypted variable
+	// files, which is asked for on the terminal otherwise.
+	VariablesPassphraseEnv = "ZEABUR_VARIABLES_PASSPHRASE"
+)
+
+// DefaultAgeKeyFile returns the age key file read when AgeKeyFileEnv is not
+// set: age-keys.txt next to the config file.
+func DefaultAgeKeyFile() (string, error) {
+	path, err := config.DefaultConfigFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "age-keys.txt"), nil
+}
+
+// ReadVariableFile reads the variables in the file at path like
+// envfile.Read, decrypting them when the file is encrypted.
+func ReadVariableFile(path string) (map[string]string, error) {
+	vars, err := envfile.Read(path)
+	if err != nil || !envcrypt.IsEncrypted(vars) {
+		return vars, err
+	}
+	f, err := DecryptVariableFile(path, vars)
+	if err != nil {
+		return nil, err
+	}
+	return f.Vars, nil
+}
+
+// DecryptVariableFile decrypts the variables read from the encrypted file
+// at path with the keys of VariableFileIdentities.
+func DecryptVariableFile(path string, vars map[string]string) (*envcrypt.File, error) {
+	ids, err := VariableFileIdentities()
+	if err != nil {
+		return nil, err
+	}
+	f, err := envcrypt.Decrypt(vars, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// VariableFileIdentities returns the keys to decrypt variable files with:
+// the age identities of AgeKeyEnv and of the age key file, then a
+// passphrase, asked for only if no age key fits.
+func VariableFileIdentities() ([]envcrypt.Identity, error) {
+	var ids []envcrypt.Identity
+	if keys := os.Getenv(AgeKeyEnv); keys != "" {
+		parsed, err := envcrypt.ParseIdentities(strings.NewReader(keys))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", AgeKeyEnv, err)
+		}
+		ids = append(ids, parsed...)
+	}
+
+	path := os.Getenv(AgeKeyFileEnv)
+	if path == "" {
+		var err error
+		if path, err = DefaultAgeKeyFile(); err != nil {
+			return nil, err
+		}
+	}
+	file, err := os.Open(path)
+	switch {
+	case err == nil:
+		defer file.Close()
+		parsed, err := envcrypt.ParseIdentities(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		ids = append(ids, parsed...)
+	case !errors.Is(err, fs.ErrNotExist) || os.Getenv(AgeKeyFileEnv) != "":
+		return nil, err
+	}
+
+	return append(ids, envcrypt.NewPassphrase(VariablesPassphrase(false))), nil
+}
+
+// VariablesPassphrase returns a function reading the passphrase of variable
+// files from VariablesPassphraseEnv, or asking for it on the terminal
+// without echo, twice when confirm is set.
+func VariablesPassphrase(confirm bool) func() (string, error) {
+	return func() (string, error) {
+		if p := os.Getenv(VariablesPassphraseEnv); p != "" {
+			return p, nil
+		}
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return "", errors.New("the variable file needs a passphrase: set " + VariablesPassphraseEnv)
+		}
+		read := func(prompt string) (string, error) {
+			fmt.Fprint(os.Stderr, prompt)
+			p, err := term.ReadPassword(int(os.Stdin.Fd()))
+			fmt.Fprintln(os.Stderr)
+			if err != nil {
+				return "", fmt.Errorf("read passphrase: %w", err)
+			}
+			return string(p), nil
+		}
+		p, err := read("Passphrase for the variable file: ")
+		if err != nil || !confirm {
+			return p, err
+		}
+		again, err := read("Repeat the passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != p {
+			return "", errors.New("the passphrases do not match")
+		}
+		return p, nil
+	}
+}
+
+// WriteVariableFile writes vars to the file at path in format, readable
+// only by the user, through a temporary file so a crash never leaves it
+// half written.
+func WriteVariableFile(path string, vars map[string]string, format envfile.Format) error {
+	var buf bytes.Buffer
+	if err := envfile.Write(&buf, vars, format); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
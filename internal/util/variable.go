@@ -0,0 +1,64 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/envfile"
+)
+
+// SecretMask is what a masked variable value is shown as. It is the same
+// for every value, so it tells nothing about the secret, not even its length.
+const SecretMask = "********"
+
+// MaskValue masks a variable value for display.
+func MaskValue(string) string {
+	return SecretMask
+}
+
+// VariableDiff is how the variables of a service differ from a file.
+type VariableDiff struct {
+	// Current are the variables of the service, without the read-only
+	// ones other services expose to it.
+	Current map[string]string
+	Changes []envfile.Change
+	// ReadOnly are the keys of the file that are read-only in the service,
+	// left out of Changes.
+	ReadOnly []string
+	// Masked are the keys whose value in the file is their current value
+	// masked, as exported without --reveal; they are left unchanged.
+	Masked []string
+}
+
+// DiffVariables compares the variables of a service with the file at path;
+// variables missing from the file are removals only when prune is set.
+func DiffVariables(ctx context.Context, client api.Client, serviceID, environmentID, path string, prune bool) (*VariableDiff, error) {
+	want, err := ReadVariableFile(path)
+	if err != nil {
+		return nil, err
+	}
+	vars, readonly, err := client.ListVariables(ctx, serviceID, environmentID)
+	if err != nil {
+		return nil, fmt.Errorf("list variables: %w", err)
+	}
+
+	d := &VariableDiff{Current: vars.ToMap()}
+	for _, v := range readonly {
+		if _, ok := want[v.Key]; ok {
+			d.ReadOnly = append(d.ReadOnly, v.Key)
+			delete(want, v.Key)
+		}
+	}
+	slices.Sort(d.ReadOnly)
+	for k, v := range want {
+		if cur, ok := d.Current[k]; ok && cur != v && v == SecretMask {
+			d.Masked = append(d.Masked, k)
+			want[k] = cur
+		}
+	}
+	slices.Sort(d.Masked)
+	d.Changes = envfile.Diff(d.Current, want, prune)
+	return d, nil
+}
@@ -0,0 +1,100 @@
+package apitest
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+func (f *Fake) GetAIHubTenant(_ context.Context) (*model.AIHubTenant, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetAIHubTenant"); err != nil {
+		return nil, err
+	}
+	return f.AIHub, nil
+}
+
+// AddAIHubBalance credits the balance immediately; the real API goes
+// through a checkout first.
+func (f *Fake) AddAIHubBalance(_ context.Context, amount int, provider *string) (*model.AddAIHubBalanceResult, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("AddAIHubBalance", amount, provider); err != nil {
+		return nil, err
+	}
+
+	f.AIHub.Balance += amount
+	return &model.AddAIHubBalanceResult{NewBalance: f.AIHub.Balance}, nil
+}
+
+func (f *Fake) CreateAIHubKey(_ context.Context, alias *string) (*model.CreateAIHubKeyResult, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CreateAIHubKey", alias); err != nil {
+		return nil, err
+	}
+
+	key := model.AIHubKey{KeyID: f.newID()}
+	if alias != nil {
+		key.Alias = *alias
+	}
+	f.AIHub.Keys = append(f.AIHub.Keys, key)
+	return &model.CreateAIHubKeyResult{Key: key, APIKey: "sk-" + key.KeyID}, nil
+}
+
+func (f *Fake) DeleteAIHubKey(_ context.Context, keyID string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("DeleteAIHubKey", keyID); err != nil {
+		return err
+	}
+
+	n := len(f.AIHub.Keys)
+	f.AIHub.Keys = slices.DeleteFunc(f.AIHub.Keys, func(k model.AIHubKey) bool { return k.KeyID == keyID })
+	if len(f.AIHub.Keys) == n {
+		return notFound("AI Hub key", keyID)
+	}
+	return nil
+}
+
+func (f *Fake) UpdateAIHubAutoRechargeSettings(_ context.Context, threshold, amount int) (*model.UpdateAIHubAutoRechargeSettingsResult, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("UpdateAIHubAutoRechargeSettings", threshold, amount); err != nil {
+		return nil, err
+	}
+
+	f.AIHub.AutoRechargeThreshold, f.AIHub.AutoRechargeAmount = threshold, amount
+	return &model.UpdateAIHubAutoRechargeSettingsResult{AutoRechargeThreshold: threshold, AutoRechargeAmount: amount}, nil
+}
+
+// GetAIHubSpendLogs returns the seeded spend logs within [startDate, endDate];
+// a nil bound is open.
+func (f *Fake) GetAIHubSpendLogs(_ context.Context, startDate, endDate *time.Time) ([]model.AIHubSpendLog, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetAIHubSpendLogs", startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	var logs []model.AIHubSpendLog
+	for _, l := range f.AIHubSpendLogs {
+		if startDate != nil && l.Timestamp.Before(*startDate) {
+			continue
+		}
+		if endDate != nil && l.Timestamp.After(*endDate) {
+			continue
+		}
+		logs = append(logs, l)
+	}
+	return logs, nil
+}
+
+func (f *Fake) GetAIHubMonthlyUsage(_ context.Context, month *string) (*model.AIHubMonthlyUsage, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetAIHubMonthlyUsage", month); err != nil {
+		return nil, err
+	}
+
+	if f.AIHubUsage != nil {
+		return f.AIHubUsage, nil
+	}
+	return &model.AIHubMonthlyUsage{}, nil
+}
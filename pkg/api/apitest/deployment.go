@@ -0,0 +1,93 @@
+package apitest
+
+import (
+	"context"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+func (f *Fake) ListDeployments(_ context.Context, serviceID string, environmentID string, perPage int) (*model.DeploymentConnection, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListDeployments", serviceID, environmentID, perPage); err != nil {
+		return nil, err
+	}
+
+	deployments := f.serviceDeployments(serviceID, environmentID)
+	if perPage > 0 && len(deployments) > perPage {
+		deployments = deployments[:perPage]
+	}
+
+	conn := &model.DeploymentConnection{Edges: make([]*model.DeploymentEdge, 0, len(deployments))}
+	for _, d := range deployments {
+		conn.Edges = append(conn.Edges, &model.DeploymentEdge{Node: d, Cursor: d.ID})
+	}
+	return conn, nil
+}
+
+// ListAllDeployments returns at most the five newest deployments, like the
+// real client.
+func (f *Fake) ListAllDeployments(_ context.Context, serviceID string, environmentID string) (model.Deployments, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListAllDeployments", serviceID, environmentID); err != nil {
+		return nil, err
+	}
+
+	deployments := f.serviceDeployments(serviceID, environmentID)
+	return deployments[:min(len(deployments), 5)], nil
+}
+
+func (f *Fake) GetDeployment(_ context.Context, id string) (*model.Deployment, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetDeployment", id); err != nil {
+		return nil, err
+	}
+
+	for _, d := range f.deployments {
+		if d.ID == id {
+			clone := *d
+			return &clone, nil
+		}
+	}
+	return nil, notFound("deployment", id)
+}
+
+func (f *Fake) GetLatestDeployment(_ context.Context, serviceID string, environmentID string) (*model.Deployment, bool, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetLatestDeployment", serviceID, environmentID); err != nil {
+		return nil, false, err
+	}
+
+	deployments := f.serviceDeployments(serviceID, environmentID)
+	if len(deployments) == 0 {
+		return nil, false, nil
+	}
+	return deployments[0], true, nil
+}
+
+func (f *Fake) createDeployment(serviceID, environmentID, status string) *model.Deployment {
+	d := &model.Deployment{
+		ID:            f.newID(),
+		ServiceID:     serviceID,
+		EnvironmentID: environmentID,
+		CreatedAt:     f.tick(),
+		Status:        status,
+	}
+	if s := f.findService(serviceID); s != nil {
+		d.ProjectID = s.Project.ID
+	}
+	d.ScheduledAt, d.StartedAt = d.CreatedAt, d.CreatedAt
+	f.deployments = append(f.deployments, d)
+	return d
+}
+
+// serviceDeployments returns the deployments of a service, newest first.
+func (f *Fake) serviceDeployments(serviceID, environmentID string) model.Deployments {
+	deployments := model.Deployments{}
+	for i := len(f.deployments) - 1; i >= 0; i-- {
+		d := f.deployments[i]
+		if d.ServiceID == serviceID && d.EnvironmentID == environmentID {
+			deployments = append(deployments, d)
+		}
+	}
+	return deployments
+}
@@ -0,0 +1,111 @@
+package apitest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// generatedDomainSuffix is appended to generated domains, which are given
+// as a bare subdomain.
+const generatedDomainSuffix = ".zeabur.app"
+
+// AddDomain binds a domain to a service. The optional option is the
+// redirect target, as in the real client.
+func (f *Fake) AddDomain(_ context.Context, serviceID string, environmentID string, isGenerated bool, domain string, options ...string) (*string, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("AddDomain", serviceID, environmentID, isGenerated, domain, options); err != nil {
+		return nil, err
+	}
+
+	if f.findService(serviceID) == nil {
+		return nil, notFound("service", serviceID)
+	}
+	if isGenerated && !strings.HasSuffix(domain, generatedDomainSuffix) {
+		domain += generatedDomainSuffix
+	}
+	if f.findDomain(domain) != nil {
+		return nil, fmt.Errorf("domain %s is already in use", domain)
+	}
+
+	d := f.createDomain(serviceID, environmentID, domain, isGenerated)
+	if len(options) > 0 {
+		d.RedirectTo = options[0]
+	}
+	return &d.Domain, nil
+}
+
+func (f *Fake) ListDomains(_ context.Context, serviceID string, environmentID string) (model.Domains, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListDomains", serviceID, environmentID); err != nil {
+		return nil, err
+	}
+	return f.serviceDomains(serviceID, environmentID), nil
+}
+
+func (f *Fake) RemoveDomain(_ context.Context, domain string) (bool, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("RemoveDomain", domain); err != nil {
+		return false, err
+	}
+
+	if f.findDomain(domain) == nil {
+		return false, notFound("domain", domain)
+	}
+	f.domains = slices.DeleteFunc(f.domains, func(d *model.Domain) bool { return d.Domain == domain })
+	return true, nil
+}
+
+func (f *Fake) CheckDomainAvailable(_ context.Context, domain string, isGenerated bool, region string) (bool, string, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CheckDomainAvailable", domain, isGenerated, region); err != nil {
+		return false, "", err
+	}
+
+	if isGenerated && !strings.HasSuffix(domain, generatedDomainSuffix) {
+		domain += generatedDomainSuffix
+	}
+	if f.findDomain(domain) != nil {
+		return false, "domain is already in use", nil
+	}
+	return true, "", nil
+}
+
+func (f *Fake) createDomain(serviceID, environmentID, domain string, isGenerated bool) *model.Domain {
+	d := &model.Domain{
+		ID:            f.newID(),
+		Domain:        domain,
+		ServiceID:     serviceID,
+		EnvironmentID: environmentID,
+		Status:        "ACTIVE",
+		IsGenerated:   isGenerated,
+		CreatedAt:     f.tick(),
+	}
+	if s := f.findService(serviceID); s != nil {
+		d.ProjectID = s.Project.ID
+	}
+	f.domains = append(f.domains, d)
+	return d
+}
+
+func (f *Fake) findDomain(domain string) *model.Domain {
+	for _, d := range f.domains {
+		if d.Domain == domain {
+			return d
+		}
+	}
+	return nil
+}
+
+func (f *Fake) serviceDomains(serviceID, environmentID string) model.Domains {
+	domains := model.Domains{}
+	for _, d := range f.domains {
+		if d.ServiceID == serviceID && d.EnvironmentID == environmentID {
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
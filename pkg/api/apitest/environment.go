@@ -0,0 +1,36 @@
+package apitest
+
+import (
+	"context"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+func (f *Fake) ListEnvironments(_ context.Context, projectID string) (model.Environments, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListEnvironments", projectID); err != nil {
+		return nil, err
+	}
+
+	envs := model.Environments{}
+	for _, e := range f.environments {
+		if e.ProjectID == projectID {
+			envs = append(envs, e)
+		}
+	}
+	return envs, nil
+}
+
+func (f *Fake) GetEnvironment(_ context.Context, id string) (*model.Environment, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetEnvironment", id); err != nil {
+		return nil, err
+	}
+
+	for _, e := range f.environments {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return nil, notFound("environment", id)
+}
@@ -0,0 +1,295 @@
+// Package apitest provides an in-memory implementation of api.Client for
+// tests. The fake keeps real state: a service created through it shows up in
+// the next ListAllServices, variables written with UpdateVariables come back
+// from ListVariables, and so on. That lets a cobra command built from a
+// cmdutil.Factory be driven end to end without network access.
+//
+// Seed the fake with the Seed* helpers, run the command, then assert on the
+// resulting state or on the recorded calls:
+//
+//	fake := apitest.New()
+//	project, env := fake.SeedProject("", "api")
+//	svc := fake.SeedService(project.ID, "web")
+//	fake.SeedVariables(svc.ID, env.ID, map[string]string{"PORT": "8080"})
+//	...
+//	if got := fake.CallsTo("UpdateVariables"); len(got) != 1 { ... }
+package apitest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// ErrNotFound is returned (wrapped) when a method is asked for a resource
+// the fake does not hold. It is api.ErrNotFound, so code under test can
+// check it the same way as against the real client.
+var ErrNotFound = api.ErrNotFound
+
+// ErrNotSupported is returned by methods that cannot be faked meaningfully,
+// e.g. reading the local git checkout, unless an error or result is
+// injected for them.
+var ErrNotSupported = errors.New("not supported by the fake client")
+
+// Call is one recorded method invocation. Args holds the arguments after the
+// context, in declaration order.
+type Call struct {
+	Method string
+	Args   []any
+}
+
+// Fake is an in-memory api.Client. The zero value is not usable; create one
+// with New. All methods are safe for concurrent use.
+type Fake struct {
+	mu sync.Mutex
+
+	calls []Call
+	errs  map[string]error
+	seq   int
+	now   time.Time
+
+	// User is returned by GetUserInfo.
+	User *model.User
+	// Teams is returned by ListTeams.
+	Teams []model.Team
+	// Regions, Servers and GenericRegions are returned by the matching
+	// ProjectAPI lookups.
+	Regions        []model.Region
+	Servers        []model.Server
+	GenericRegions []model.GenericRegion
+
+	projects     []*project
+	environments []*model.Environment
+	services     []*service
+	variables    map[scope]map[string]string
+	exposed      map[scope]model.Variables
+	domains      model.Domains
+	deployments  model.Deployments
+	buildLogs    map[string]model.Logs
+	runtimeLogs  map[scope]model.Logs
+	portForward  map[scope]model.PortForwardingMode
+	imageTags    map[scope]string
+
+	// NewDeploymentStatus is the status given to deployments created by
+	// UploadZipToService and RedeployService. Defaults to RUNNING.
+	NewDeploymentStatus string
+	// CommandResults maps a command joined by spaces to the result
+	// ExecuteCommand returns for it. Unknown commands exit 0 with no output.
+	CommandResults map[string]*model.CommandResult
+	// PrebuiltItems is returned by GetPrebuiltItems.
+	PrebuiltItems []model.PrebuiltItem
+	// GitRepos is returned by SearchGitRepositories.
+	GitRepos []model.GitRepo
+	// Metrics is returned by ServiceMetric for every service.
+	Metrics *model.ServiceMetric
+	// Ports maps a service ID to the ports GetServicePorts returns.
+	Ports map[string][]model.ServicePort
+	// Instructions maps a service ID to its connection instructions.
+	Instructions map[string][]model.ServiceInstruction
+	// Exports maps a project ID to the template ExportProject returns.
+	Exports map[string]*model.ExportedTemplate
+
+	templates model.Templates
+
+	serverDetails []*model.ServerDetail
+	// ServerPasswords maps a server ID to its root password.
+	ServerPasswords map[string]string
+	// DedicatedProviders, DedicatedRegions and DedicatedPlans back the
+	// server rental lookups; regions and plans are keyed by provider and by
+	// "provider/region" respectively.
+	DedicatedProviders []model.CloudProvider
+	DedicatedRegions   map[string][]model.DedicatedServerRegion
+	DedicatedPlans     map[string]model.DedicatedServerPlans
+
+	// AIHub is the tenant returned by GetAIHubTenant.
+	AIHub *model.AIHubTenant
+	// AIHubSpendLogs and AIHubUsage back the AI Hub usage queries.
+	AIHubSpendLogs []model.AIHubSpendLog
+	AIHubUsage     *model.AIHubMonthlyUsage
+
+	// ZSend holds the Z-Send account state.
+	ZSend ZSendState
+
+	// GraphQL answers ExecRaw, Events are the events SubscribeRaw delivers
+	// and REST answers ZSendREST.
+	GraphQL func(query string, variables map[string]any) (json.RawMessage, error)
+	Events  []json.RawMessage
+	REST    func(method, path string, body json.RawMessage) (json.RawMessage, error)
+
+	registeredDomains  model.RegisteredDomains
+	dnsRecords         map[string]model.DNSRecords
+	registrantProfiles model.RegistrantProfiles
+	// DomainPrices maps a domain to its registration price; domains not in
+	// the map are reported as unavailable.
+	DomainPrices map[string]int
+
+	// UploadFiles maps an upload ID to its files, keyed by path.
+	UploadFiles map[string]map[string]string
+
+	// Repo is the local git checkout GetRepoInfo reports. RepoIDs maps
+	// "owner/name" to a GitHub repo ID; RepoBranches maps "owner/name" and
+	// RepoBranchesByID maps a repo ID to its branches.
+	Repo             *GitRepoFixture
+	RepoIDs          map[string]int
+	RepoBranches     map[string][]string
+	RepoBranchesByID map[int][]string
+}
+
+// GitRepoFixture describes the local git checkout the fake reports.
+type GitRepoFixture struct {
+	Owner string
+	Name  string
+}
+
+// ZSendState is the Z-Send part of the fake's state.
+type ZSendState struct {
+	Onboarding      model.ZSendOnboardingStatus
+	User            *model.ZSendUserStatus
+	Domains         []model.ZSendDomain
+	APIKeys         []model.ZSendAPIKey
+	Webhooks        []model.ZSendWebhook
+	Emails          []model.ZSendEmail
+	ScheduledEmails []model.ZSendScheduledEmail
+	BatchJobs       []model.ZSendBatchJob
+}
+
+type project struct {
+	*model.Project
+	ownerID string
+}
+
+type service struct {
+	*model.Service
+	status string
+}
+
+// scope identifies per-environment state of a service.
+type scope struct{ serviceID, environmentID string }
+
+// New returns an empty fake. Its clock starts at a fixed instant and moves
+// one second forward on every created resource, so ordering by CreatedAt
+// is deterministic.
+func New() *Fake {
+	return &Fake{
+		errs:                map[string]error{},
+		now:                 time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		User:                &model.User{ID: "000000000000000000000001", Name: "Test User", Username: "tester", Email: "tester@example.com"},
+		variables:           map[scope]map[string]string{},
+		exposed:             map[scope]model.Variables{},
+		buildLogs:           map[string]model.Logs{},
+		runtimeLogs:         map[scope]model.Logs{},
+		portForward:         map[scope]model.PortForwardingMode{},
+		imageTags:           map[scope]string{},
+		NewDeploymentStatus: model.DeploymentStatusRunning,
+		CommandResults:      map[string]*model.CommandResult{},
+		Ports:               map[string][]model.ServicePort{},
+		Instructions:        map[string][]model.ServiceInstruction{},
+		Exports:             map[string]*model.ExportedTemplate{},
+		ServerPasswords:     map[string]string{},
+		DedicatedRegions:    map[string][]model.DedicatedServerRegion{},
+		DedicatedPlans:      map[string]model.DedicatedServerPlans{},
+		AIHub:               &model.AIHubTenant{},
+		ZSend:               ZSendState{User: &model.ZSendUserStatus{Status: "ACTIVE"}},
+		dnsRecords:          map[string]model.DNSRecords{},
+		DomainPrices:        map[string]int{},
+		UploadFiles:         map[string]map[string]string{},
+		RepoIDs:             map[string]int{},
+		RepoBranches:        map[string][]string{},
+		RepoBranchesByID:    map[int][]string{},
+	}
+}
+
+// FailOn makes every later call to method return err, until cleared with a
+// nil err. method is the interface method name, e.g. "ListVariables".
+func (f *Fake) FailOn(method string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err == nil {
+		delete(f.errs, method)
+		return
+	}
+	f.errs[method] = err
+}
+
+// Calls returns every recorded call in order.
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.calls)
+}
+
+// CallsTo returns the recorded calls to one method, in order.
+func (f *Fake) CallsTo(method string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []Call
+	for _, c := range f.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// ResetCalls forgets the recorded calls, typically after seeding through
+// API methods so assertions only see what the command under test did.
+func (f *Fake) ResetCalls() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+}
+
+// begin locks the fake, records the call and returns the injected error for
+// the method, if any. Every api.Client method starts with it and must
+// unlock f.mu when done.
+func (f *Fake) begin(method string, args ...any) error {
+	f.mu.Lock()
+	f.calls = append(f.calls, Call{Method: method, Args: args})
+	return f.errs[method]
+}
+
+// newID returns a fresh 24-hex ObjectID, so IDs from the fake pass the same
+// validation as real ones.
+func (f *Fake) newID() string {
+	f.seq++
+	return fmt.Sprintf("65%022x", f.seq)
+}
+
+// tick advances the fake clock and returns the new time.
+func (f *Fake) tick() time.Time {
+	f.now = f.now.Add(time.Second)
+	return f.now
+}
+
+func notFound(kind, id string) error {
+	return fmt.Errorf("%s<%s>: %w", kind, id, ErrNotFound)
+}
+
+// page slices items the way the backend applies skip and limit, and wraps
+// the result in a connection.
+func page[T any](items []*T, skip, limit int) *model.Connection[T] {
+	total := len(items)
+	skip = min(max(skip, 0), total)
+	end := total
+	if limit > 0 {
+		end = min(skip+limit, total)
+	}
+
+	edges := make([]*model.Edge[T], 0, end-skip)
+	for i, item := range items[skip:end] {
+		edges = append(edges, &model.Edge[T]{Node: item, Cursor: fmt.Sprint(skip + i)})
+	}
+
+	return &model.Connection[T]{
+		PageInfo: &model.PageInfo{TotalCount: total, HasNextPage: end < total, HasPreviousPage: skip > 0},
+		Edges:    edges,
+	}
+}
+
+var _ api.Client = (*Fake)(nil)
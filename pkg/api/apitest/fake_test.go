@@ -0,0 +1,201 @@
+package apitest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zeabur/cli/pkg/api/apitest"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+func TestFake_ServicesAreScopedToProject(t *testing.T) {
+	ctx := context.Background()
+	fake := apitest.New()
+	p1, _ := fake.SeedProject("", "one")
+	p2, _ := fake.SeedProject("", "two")
+	fake.SeedService(p1.ID, "web")
+
+	created, err := fake.CreateEmptyService(ctx, p2.ID, "api")
+	if err != nil {
+		t.Fatalf("CreateEmptyService: %v", err)
+	}
+
+	services, err := fake.ListAllServices(ctx, p2.ID)
+	if err != nil {
+		t.Fatalf("ListAllServices: %v", err)
+	}
+	if len(services) != 1 || services[0].ID != created.ID {
+		t.Fatalf("services of p2 = %v, want only %s", services, created.ID)
+	}
+
+	got, err := fake.GetService(ctx, "", "tester", "one", "web")
+	if err != nil {
+		t.Fatalf("GetService by name: %v", err)
+	}
+	if got.Project.ID != p1.ID {
+		t.Fatalf("service project = %s, want %s", got.Project.ID, p1.ID)
+	}
+}
+
+func TestFake_ProjectsAreScopedToOwner(t *testing.T) {
+	fake := apitest.New()
+	fake.SeedProject("", "personal")
+	team, _ := fake.SeedProject("65cc1230000000000000000a", "team")
+
+	projects, err := fake.ListAllProjects(context.Background(), "65cc1230000000000000000a")
+	if err != nil {
+		t.Fatalf("ListAllProjects: %v", err)
+	}
+	if len(projects) != 1 || projects[0].ID != team.ID {
+		t.Fatalf("team projects = %v, want only %s", projects, team.ID)
+	}
+}
+
+func TestFake_UpdateVariablesReplacesSet(t *testing.T) {
+	ctx := context.Background()
+	fake := apitest.New()
+	project, env := fake.SeedProject("", "api")
+	svc := fake.SeedService(project.ID, "web")
+	fake.SeedVariables(svc.ID, env.ID, map[string]string{"A": "1", "B": "2"})
+
+	if _, err := fake.UpdateVariables(ctx, svc.ID, env.ID, map[string]string{"B": "3"}); err != nil {
+		t.Fatalf("UpdateVariables: %v", err)
+	}
+
+	vars, _, err := fake.ListVariables(ctx, svc.ID, env.ID)
+	if err != nil {
+		t.Fatalf("ListVariables: %v", err)
+	}
+	if got := vars.ToMap(); len(got) != 1 || got["B"] != "3" {
+		t.Fatalf("variables = %v, want map[B:3]", got)
+	}
+}
+
+func TestFake_DeploymentsNewestFirst(t *testing.T) {
+	ctx := context.Background()
+	fake := apitest.New()
+	project, env := fake.SeedProject("", "api")
+	svc := fake.SeedService(project.ID, "web")
+	fake.SeedDeployment(svc.ID, env.ID, model.DeploymentStatusFailed)
+	fake.NewDeploymentStatus = model.DeploymentStatusBuilding
+
+	if err := fake.RedeployService(ctx, svc.ID, env.ID); err != nil {
+		t.Fatalf("RedeployService: %v", err)
+	}
+
+	latest, ok, err := fake.GetLatestDeployment(ctx, svc.ID, env.ID)
+	if err != nil || !ok {
+		t.Fatalf("GetLatestDeployment: ok=%v err=%v", ok, err)
+	}
+	if latest.Status != model.DeploymentStatusBuilding || latest.ProjectID != project.ID {
+		t.Fatalf("latest deployment = %+v, want the BUILDING redeploy", latest)
+	}
+	if n := len(fake.Deployments(svc.ID, env.ID)); n != 2 {
+		t.Fatalf("got %d deployments, want 2", n)
+	}
+}
+
+func TestFake_DeleteServiceCascades(t *testing.T) {
+	ctx := context.Background()
+	fake := apitest.New()
+	project, env := fake.SeedProject("", "api")
+	svc := fake.SeedService(project.ID, "web")
+	fake.SeedDomain(svc.ID, env.ID, "web.example.com", false)
+
+	if err := fake.DeleteService(ctx, svc.ID); err != nil {
+		t.Fatalf("DeleteService: %v", err)
+	}
+	if _, err := fake.GetService(ctx, svc.ID, "", "", ""); !errors.Is(err, apitest.ErrNotFound) {
+		t.Fatalf("GetService after delete: err = %v, want ErrNotFound", err)
+	}
+	if ok, _, _ := fake.CheckDomainAvailable(ctx, "web.example.com", false, ""); !ok {
+		t.Fatalf("domain of a deleted service must be released")
+	}
+}
+
+func TestFake_GeneratedDomainSuffix(t *testing.T) {
+	ctx := context.Background()
+	fake := apitest.New()
+	project, env := fake.SeedProject("", "api")
+	svc := fake.SeedService(project.ID, "web")
+
+	got, err := fake.AddDomain(ctx, svc.ID, env.ID, true, "my-app")
+	if err != nil {
+		t.Fatalf("AddDomain: %v", err)
+	}
+	if *got != "my-app.zeabur.app" {
+		t.Fatalf("domain = %s, want my-app.zeabur.app", *got)
+	}
+	if _, err := fake.AddDomain(ctx, svc.ID, env.ID, true, "my-app"); err == nil {
+		t.Fatalf("adding a taken domain must fail")
+	}
+}
+
+func TestFake_FailOnAndCalls(t *testing.T) {
+	ctx := context.Background()
+	fake := apitest.New()
+	boom := errors.New("boom")
+	fake.FailOn("ListTeams", boom)
+
+	if _, err := fake.ListTeams(ctx); !errors.Is(err, boom) {
+		t.Fatalf("ListTeams err = %v, want injected error", err)
+	}
+	fake.FailOn("ListTeams", nil)
+	if _, err := fake.ListTeams(ctx); err != nil {
+		t.Fatalf("ListTeams after clearing: %v", err)
+	}
+
+	calls := fake.CallsTo("ListTeams")
+	if len(calls) != 2 {
+		t.Fatalf("recorded %d ListTeams calls, want 2", len(calls))
+	}
+	fake.ResetCalls()
+	if n := len(fake.Calls()); n != 0 {
+		t.Fatalf("recorded %d calls after reset, want 0", n)
+	}
+}
+
+func TestFake_ZSendRESTRequiresKey(t *testing.T) {
+	ctx := context.Background()
+	fake := apitest.New()
+	req := model.ZSendSendEmailRequest{From: "a@example.com", To: []string{"b@example.com"}, Subject: "hi"}
+
+	if _, err := fake.SendZSendEmail(ctx, "bogus", req); !errors.Is(err, apitest.ErrInvalidAPIKey) {
+		t.Fatalf("send with unknown key: err = %v, want ErrInvalidAPIKey", err)
+	}
+
+	key, err := fake.CreateZSendAPIKey(ctx, model.CreateZSendAPIKeyInput{Name: "ci", Permission: "SEND"})
+	if err != nil {
+		t.Fatalf("CreateZSendAPIKey: %v", err)
+	}
+	if _, err := fake.SendZSendEmail(ctx, *key.APIKey.Token, req); err != nil {
+		t.Fatalf("send with valid key: %v", err)
+	}
+
+	emails, err := fake.ListZSendEmails(ctx, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("ListZSendEmails: %v", err)
+	}
+	if emails.TotalCount != 1 || emails.Emails[0].APIKeyID != key.APIKey.ID {
+		t.Fatalf("emails = %+v, want one sent with key %s", emails, key.APIKey.ID)
+	}
+}
+
+func TestFake_WatchReplaysAndCloses(t *testing.T) {
+	fake := apitest.New()
+	fake.SeedBuildLogs("dep", model.Logs{{Message: "step 1"}, {Message: "step 2"}})
+	fake.FailOn("WatchBuildLogs", errors.New("stream dropped"))
+
+	ch, errs := fake.WatchBuildLogs(context.Background(), "proj", "dep")
+	var got []string
+	for l := range ch {
+		got = append(got, l.Message)
+	}
+	if len(got) != 2 || got[1] != "step 2" {
+		t.Fatalf("replayed %v, want both seeded lines", got)
+	}
+	if err := <-errs; err == nil || err.Error() != "stream dropped" {
+		t.Fatalf("stream error = %v, want the injected one after the replay", err)
+	}
+}
@@ -0,0 +1,86 @@
+package apitest
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ListUploadFiles lists the direct children of path in an upload.
+// Directories end with a slash, like in the real API.
+func (f *Fake) ListUploadFiles(_ context.Context, uploadID string, path *string) ([]string, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListUploadFiles", uploadID, path); err != nil {
+		return nil, err
+	}
+
+	files, ok := f.UploadFiles[uploadID]
+	if !ok {
+		return nil, notFound("upload", uploadID)
+	}
+
+	prefix := ""
+	if path != nil && strings.Trim(*path, "/") != "" {
+		prefix = strings.Trim(*path, "/") + "/"
+	}
+
+	var entries []string
+	for name := range files {
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		entry := rest
+		if dir, _, isDir := strings.Cut(rest, "/"); isDir {
+			entry = dir + "/"
+		}
+		if !slices.Contains(entries, entry) {
+			entries = append(entries, entry)
+		}
+	}
+	slices.Sort(entries)
+	return entries, nil
+}
+
+func (f *Fake) ReadUploadFile(_ context.Context, uploadID string, path string) (string, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ReadUploadFile", uploadID, path); err != nil {
+		return "", err
+	}
+
+	content, ok := f.UploadFiles[uploadID][strings.Trim(path, "/")]
+	if !ok {
+		return "", notFound("file", path)
+	}
+	return content, nil
+}
+
+// PullUploadFiles writes every file of an upload under targetDir. Unlike the
+// real client it never skips binary files, so the skipped count is always 0.
+func (f *Fake) PullUploadFiles(_ context.Context, uploadID string, targetDir string) (int, int, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("PullUploadFiles", uploadID, targetDir); err != nil {
+		return 0, 0, err
+	}
+
+	files, ok := f.UploadFiles[uploadID]
+	if !ok {
+		return 0, 0, notFound("upload", uploadID)
+	}
+
+	count := 0
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		dst := filepath.Join(targetDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return count, 0, err
+		}
+		if err := os.WriteFile(dst, []byte(files[name]), 0o644); err != nil {
+			return count, 0, err
+		}
+		count++
+	}
+	return count, 0, nil
+}
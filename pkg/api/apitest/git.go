@@ -0,0 +1,59 @@
+package apitest
+
+import (
+	"context"
+	"fmt"
+)
+
+func (f *Fake) GetRepoBranches(_ context.Context, repoOwner string, repoName string) ([]string, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetRepoBranches", repoOwner, repoName); err != nil {
+		return nil, err
+	}
+
+	branches, ok := f.RepoBranches[repoOwner+"/"+repoName]
+	if !ok {
+		return nil, notFound("repository", repoOwner+"/"+repoName)
+	}
+	return branches, nil
+}
+
+func (f *Fake) GetRepoID(repoOwner string, repoName string) (int, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetRepoID", repoOwner, repoName); err != nil {
+		return 0, err
+	}
+
+	id, ok := f.RepoIDs[repoOwner+"/"+repoName]
+	if !ok {
+		return 0, notFound("repository", repoOwner+"/"+repoName)
+	}
+	return id, nil
+}
+
+// GetRepoInfo reports Repo instead of reading the working directory's git
+// remote.
+func (f *Fake) GetRepoInfo() (string, string, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetRepoInfo"); err != nil {
+		return "", "", err
+	}
+
+	if f.Repo == nil {
+		return "", "", fmt.Errorf("git remote: %w", ErrNotSupported)
+	}
+	return f.Repo.Owner, f.Repo.Name, nil
+}
+
+func (f *Fake) GetRepoBranchesByRepoID(repoID int) ([]string, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetRepoBranchesByRepoID", repoID); err != nil {
+		return nil, err
+	}
+
+	branches, ok := f.RepoBranchesByID[repoID]
+	if !ok {
+		return nil, notFound("repository", fmt.Sprint(repoID))
+	}
+	return branches, nil
+}
@@ -0,0 +1,65 @@
+package apitest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+func (f *Fake) GetRuntimeLogs(_ context.Context, serviceID, environmentID, deploymentID string) (model.Logs, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetRuntimeLogs", serviceID, environmentID, deploymentID); err != nil {
+		return nil, err
+	}
+
+	if serviceID == "" {
+		return nil, fmt.Errorf("serviceID is required for runtime logs")
+	}
+	return f.runtimeLogs[scope{serviceID, environmentID}], nil
+}
+
+func (f *Fake) GetBuildLogs(_ context.Context, deploymentID string) (model.Logs, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetBuildLogs", deploymentID); err != nil {
+		return nil, err
+	}
+	return f.buildLogs[deploymentID], nil
+}
+
+// WatchRuntimeLogs replays the seeded runtime logs and closes the channels.
+// An error injected with FailOn ends the stream after the replay, the way a
+// subscription fails mid-stream.
+func (f *Fake) WatchRuntimeLogs(ctx context.Context, projectID, serviceID, environmentID, deploymentID string) (<-chan model.Log, <-chan error) {
+	defer f.mu.Unlock()
+	err := f.begin("WatchRuntimeLogs", projectID, serviceID, environmentID, deploymentID)
+	return replay(ctx, f.runtimeLogs[scope{serviceID, environmentID}], err)
+}
+
+// WatchBuildLogs replays the seeded build logs and closes the channels. An
+// error injected with FailOn ends the stream after the replay.
+func (f *Fake) WatchBuildLogs(ctx context.Context, projectID, deploymentID string) (<-chan model.Log, <-chan error) {
+	defer f.mu.Unlock()
+	err := f.begin("WatchBuildLogs", projectID, deploymentID)
+	return replay(ctx, f.buildLogs[deploymentID], err)
+}
+
+func replay(ctx context.Context, logs model.Logs, streamErr error) (<-chan model.Log, <-chan error) {
+	ch := make(chan model.Log)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(ch)
+		for _, l := range logs {
+			select {
+			case ch <- *l:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if streamErr != nil {
+			errs <- streamErr
+		}
+	}()
+	return ch, errs
+}
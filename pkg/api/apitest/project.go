@@ -0,0 +1,206 @@
+package apitest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+func (f *Fake) ListProjects(_ context.Context, ownerID string, skip, limit int) (*model.ProjectConnection, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListProjects", ownerID, skip, limit); err != nil {
+		return nil, err
+	}
+
+	conn := page(f.ownedProjects(ownerID), skip, limit)
+	edges := make([]*model.ProjectEdge, 0, len(conn.Edges))
+	for _, e := range conn.Edges {
+		edges = append(edges, &model.ProjectEdge{Node: e.Node, Cursor: e.Cursor})
+	}
+	return &model.ProjectConnection{PageInfo: conn.PageInfo, Edges: edges}, nil
+}
+
+func (f *Fake) ListAllProjects(_ context.Context, ownerID string) (model.Projects, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListAllProjects", ownerID); err != nil {
+		return nil, err
+	}
+	return f.ownedProjects(ownerID), nil
+}
+
+// GetProject looks a project up by ID, or by owner username and name when id
+// is empty. Every personal project belongs to f.User.
+func (f *Fake) GetProject(_ context.Context, id string, ownerName string, name string) (*model.Project, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetProject", id, ownerName, name); err != nil {
+		return nil, err
+	}
+
+	if id != "" {
+		if p := f.findProject(id); p != nil {
+			return p.Project, nil
+		}
+		return nil, notFound("project", id)
+	}
+
+	for _, p := range f.projects {
+		if p.ownerID == "" && ownerName == f.User.Username && p.Name == name {
+			return p.Project, nil
+		}
+	}
+	return nil, notFound("project", ownerName+"/"+name)
+}
+
+func (f *Fake) CreateProject(_ context.Context, ownerID, region string, name *string) (*model.Project, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CreateProject", ownerID, region, name); err != nil {
+		return nil, err
+	}
+
+	projectName := ""
+	if name != nil {
+		projectName = *name
+	}
+
+	r := model.Region{ID: region}
+	if i := slices.IndexFunc(f.Regions, func(r model.Region) bool { return r.ID == region }); i >= 0 {
+		r = f.Regions[i]
+	}
+
+	p := f.createProject(ownerID, projectName, r)
+	f.createEnvironment(p.ID, "production")
+	return p, nil
+}
+
+// DeleteProject removes a project along with its environments and services.
+func (f *Fake) DeleteProject(_ context.Context, id string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("DeleteProject", id); err != nil {
+		return err
+	}
+
+	if f.findProject(id) == nil {
+		return notFound("project", id)
+	}
+
+	f.projects = slices.DeleteFunc(f.projects, func(p *project) bool { return p.ID == id })
+	f.environments = slices.DeleteFunc(f.environments, func(e *model.Environment) bool { return e.ProjectID == id })
+	for _, s := range f.projectServices(id) {
+		f.deleteService(s.ID)
+	}
+	return nil
+}
+
+func (f *Fake) ExportProject(_ context.Context, id string, environmentID string) (*model.ExportedTemplate, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ExportProject", id, environmentID); err != nil {
+		return nil, err
+	}
+
+	if f.findProject(id) == nil {
+		return nil, notFound("project", id)
+	}
+	if t, ok := f.Exports[id]; ok {
+		return t, nil
+	}
+	return &model.ExportedTemplate{}, nil
+}
+
+func (f *Fake) GetRegions(_ context.Context) ([]model.Region, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetRegions"); err != nil {
+		return nil, err
+	}
+	return f.Regions, nil
+}
+
+func (f *Fake) GetServers(_ context.Context) ([]model.Server, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetServers"); err != nil {
+		return nil, err
+	}
+	return f.Servers, nil
+}
+
+func (f *Fake) GetGenericRegions(_ context.Context) ([]model.GenericRegion, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetGenericRegions"); err != nil {
+		return nil, err
+	}
+	return f.GenericRegions, nil
+}
+
+// CloneProject copies a project, its environments and its services into a
+// new project in targetRegion. The clone finishes immediately.
+func (f *Fake) CloneProject(_ context.Context, projectID, environmentID, targetRegion string, suspendOldProject bool) (*model.CloneProjectResult, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CloneProject", projectID, environmentID, targetRegion, suspendOldProject); err != nil {
+		return nil, err
+	}
+
+	src := f.findProject(projectID)
+	if src == nil {
+		return nil, notFound("project", projectID)
+	}
+
+	clone := f.createProject(src.ownerID, src.Name, model.Region{ID: targetRegion})
+	f.createEnvironment(clone.ID, "production")
+	for _, s := range f.projectServices(projectID) {
+		f.createService(clone.ID, s.Name, s.Template)
+	}
+	return &model.CloneProjectResult{NewProjectID: clone.ID}, nil
+}
+
+func (f *Fake) CloneProjectStatus(_ context.Context, newProjectID string) (*model.CloneProjectStatusResult, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CloneProjectStatus", newProjectID); err != nil {
+		return nil, err
+	}
+
+	if f.findProject(newProjectID) == nil {
+		return nil, notFound("project", newProjectID)
+	}
+	id := newProjectID
+	return &model.CloneProjectStatusResult{
+		NewProjectID: &id,
+		Events:       []model.CloneProjectEvent{{Type: "DONE", CreatedAt: f.now, Message: "Project cloned"}},
+	}, nil
+}
+
+func (f *Fake) createProject(ownerID, name string, region model.Region) *model.Project {
+	id := f.newID()
+	if name == "" {
+		name = fmt.Sprintf("project-%s", id[len(id)-6:])
+	}
+
+	p := &model.Project{ID: id, Name: name, CreatedAt: f.tick(), Region: region}
+	f.projects = append(f.projects, &project{Project: p, ownerID: ownerID})
+	return p
+}
+
+func (f *Fake) createEnvironment(projectID, name string) *model.Environment {
+	env := &model.Environment{ID: f.newID(), Name: name, ProjectID: projectID, CreatedAt: f.tick()}
+	f.environments = append(f.environments, env)
+	return env
+}
+
+func (f *Fake) findProject(id string) *project {
+	for _, p := range f.projects {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (f *Fake) ownedProjects(ownerID string) model.Projects {
+	projects := model.Projects{}
+	for _, p := range f.projects {
+		if p.ownerID == ownerID {
+			projects = append(projects, p.Project)
+		}
+	}
+	return projects
+}
@@ -0,0 +1,58 @@
+package apitest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ExecRaw answers with GraphQL, or ErrNotSupported when it is not set.
+func (f *Fake) ExecRaw(_ context.Context, query string, variables map[string]any) (json.RawMessage, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ExecRaw", query, variables); err != nil {
+		return nil, err
+	}
+	if f.GraphQL == nil {
+		return nil, fmt.Errorf("ExecRaw: %w", ErrNotSupported)
+	}
+	return f.GraphQL(query, variables)
+}
+
+// SubscribeRaw hands every event of Events to handler, then returns as if
+// the server completed the subscription.
+func (f *Fake) SubscribeRaw(ctx context.Context, query string, variables map[string]any, handler func(json.RawMessage) error) error {
+	f.mu.Lock()
+	f.calls = append(f.calls, Call{Method: "SubscribeRaw", Args: []any{query, variables}})
+	err := f.errs["SubscribeRaw"]
+	events := f.Events
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if err := ctx.Err(); err != nil {
+			return nil
+		}
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ZSendREST answers with REST once apiKey is checked against ZSend.APIKeys,
+// or ErrNotSupported when REST is not set.
+func (f *Fake) ZSendREST(_ context.Context, apiKey, method, path string, body json.RawMessage) (json.RawMessage, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ZSendREST", apiKey, method, path, body); err != nil {
+		return nil, err
+	}
+	if _, err := f.zsendKey(apiKey); err != nil {
+		return nil, err
+	}
+	if f.REST == nil {
+		return nil, fmt.Errorf("ZSendREST: %w", ErrNotSupported)
+	}
+	return f.REST(method, path, body)
+}
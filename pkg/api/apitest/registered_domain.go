@@ -0,0 +1,348 @@
+package apitest
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// CheckDomainRegistrationAvailability reports a domain as available when it
+// has an entry in DomainPrices and is not registered yet.
+func (f *Fake) CheckDomainRegistrationAvailability(_ context.Context, domain string) (*model.DomainSearchResult, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CheckDomainRegistrationAvailability", domain); err != nil {
+		return nil, err
+	}
+
+	result := &model.DomainSearchResult{Domain: domain, TLD: tld(domain)}
+	if price, ok := f.DomainPrices[domain]; ok && f.findRegisteredDomain(domain) == nil {
+		result.Available = true
+		result.Price = &price
+	}
+	return result, nil
+}
+
+func (f *Fake) PurchaseDomain(_ context.Context, domain, registrantProfileID string) (*model.PurchaseDomainResult, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("PurchaseDomain", domain, registrantProfileID); err != nil {
+		return nil, err
+	}
+
+	price, ok := f.DomainPrices[domain]
+	if !ok || f.findRegisteredDomain(domain) != nil {
+		return nil, notFound("available domain", domain)
+	}
+	profile := f.findRegistrantProfile(registrantProfileID)
+	if profile == nil {
+		return nil, notFound("registrant profile", registrantProfileID)
+	}
+
+	now := f.tick()
+	d := model.RegisteredDomain{
+		ID:                f.newID(),
+		Domain:            domain,
+		TLD:               tld(domain),
+		Status:            "ACTIVE",
+		AutoRenew:         true,
+		RegisteredAt:      now,
+		ExpiresAt:         now.AddDate(1, 0, 0),
+		PurchasePrice:     price,
+		RenewalPrice:      price,
+		RegistrantProfile: profile,
+	}
+	f.registeredDomains = append(f.registeredDomains, d)
+	return &model.PurchaseDomainResult{RegisteredDomain: d, PaymentAmountFromBalance: &price}, nil
+}
+
+func (f *Fake) ListRegisteredDomains(_ context.Context) (model.RegisteredDomains, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListRegisteredDomains"); err != nil {
+		return nil, err
+	}
+	return slices.Clone(f.registeredDomains), nil
+}
+
+func (f *Fake) GetRegisteredDomain(_ context.Context, id string) (*model.RegisteredDomain, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetRegisteredDomain", id); err != nil {
+		return nil, err
+	}
+
+	d := f.findRegisteredDomainByID(id)
+	if d == nil {
+		return nil, notFound("registered domain", id)
+	}
+	clone := *d
+	return &clone, nil
+}
+
+// RenewDomain extends the expiry by one year.
+func (f *Fake) RenewDomain(_ context.Context, id string) (*model.RegisteredDomain, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("RenewDomain", id); err != nil {
+		return nil, err
+	}
+
+	d := f.findRegisteredDomainByID(id)
+	if d == nil {
+		return nil, notFound("registered domain", id)
+	}
+	d.ExpiresAt = d.ExpiresAt.AddDate(1, 0, 0)
+	clone := *d
+	return &clone, nil
+}
+
+func (f *Fake) SetDomainAutoRenew(_ context.Context, id string, autoRenew bool) (*model.RegisteredDomain, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("SetDomainAutoRenew", id, autoRenew); err != nil {
+		return nil, err
+	}
+
+	d := f.findRegisteredDomainByID(id)
+	if d == nil {
+		return nil, notFound("registered domain", id)
+	}
+	d.AutoRenew = autoRenew
+	clone := *d
+	return &clone, nil
+}
+
+func (f *Fake) ListDNSRecords(_ context.Context, registeredDomainID string) (model.DNSRecords, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListDNSRecords", registeredDomainID); err != nil {
+		return nil, err
+	}
+
+	if f.findRegisteredDomainByID(registeredDomainID) == nil {
+		return nil, notFound("registered domain", registeredDomainID)
+	}
+	return slices.Clone(f.dnsRecords[registeredDomainID]), nil
+}
+
+func (f *Fake) CreateDNSRecord(_ context.Context, registeredDomainID string, input model.CreateDNSRecordInput) (*model.DNSRecord, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CreateDNSRecord", registeredDomainID, input); err != nil {
+		return nil, err
+	}
+
+	if f.findRegisteredDomainByID(registeredDomainID) == nil {
+		return nil, notFound("registered domain", registeredDomainID)
+	}
+
+	r := model.DNSRecord{ID: f.newID(), Type: string(input.Type), Name: input.Name, Content: input.Content, TTL: 3600}
+	if input.TTL != nil {
+		r.TTL = *input.TTL
+	}
+	if input.Priority != nil {
+		r.Priority = *input.Priority
+	}
+	if input.Proxied != nil {
+		r.Proxied = *input.Proxied
+	}
+	f.dnsRecords[registeredDomainID] = append(f.dnsRecords[registeredDomainID], r)
+	return &r, nil
+}
+
+func (f *Fake) UpdateDNSRecord(_ context.Context, registeredDomainID, recordID string, input model.UpdateDNSRecordInput) (*model.DNSRecord, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("UpdateDNSRecord", registeredDomainID, recordID, input); err != nil {
+		return nil, err
+	}
+
+	records := f.dnsRecords[registeredDomainID]
+	i := slices.IndexFunc(records, func(r model.DNSRecord) bool { return r.ID == recordID })
+	if i < 0 {
+		return nil, notFound("DNS record", recordID)
+	}
+
+	r := &records[i]
+	if input.Content != nil {
+		r.Content = *input.Content
+	}
+	if input.TTL != nil {
+		r.TTL = *input.TTL
+	}
+	if input.Priority != nil {
+		r.Priority = *input.Priority
+	}
+	if input.Proxied != nil {
+		r.Proxied = *input.Proxied
+	}
+	clone := *r
+	return &clone, nil
+}
+
+func (f *Fake) DeleteDNSRecord(_ context.Context, registeredDomainID, recordID string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("DeleteDNSRecord", registeredDomainID, recordID); err != nil {
+		return err
+	}
+
+	records := f.dnsRecords[registeredDomainID]
+	n := len(records)
+	records = slices.DeleteFunc(records, func(r model.DNSRecord) bool { return r.ID == recordID })
+	if len(records) == n {
+		return notFound("DNS record", recordID)
+	}
+	f.dnsRecords[registeredDomainID] = records
+	return nil
+}
+
+func (f *Fake) ListRegistrantProfiles(_ context.Context) (model.RegistrantProfiles, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListRegistrantProfiles"); err != nil {
+		return nil, err
+	}
+	return slices.Clone(f.registrantProfiles), nil
+}
+
+func (f *Fake) CreateRegistrantProfile(_ context.Context, input model.CreateRegistrantProfileInput) (*model.RegistrantProfile, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CreateRegistrantProfile", input); err != nil {
+		return nil, err
+	}
+
+	p := model.RegistrantProfile{
+		ID:         f.newID(),
+		FirstName:  input.FirstName,
+		LastName:   input.LastName,
+		Email:      input.Email,
+		Phone:      input.Phone,
+		Address1:   input.Address1,
+		City:       input.City,
+		State:      input.State,
+		Country:    input.Country,
+		PostalCode: input.PostalCode,
+		IsDefault:  len(f.registrantProfiles) == 0,
+	}
+	if input.Organization != nil {
+		p.Organization = *input.Organization
+	}
+	f.registrantProfiles = append(f.registrantProfiles, p)
+	return &p, nil
+}
+
+func (f *Fake) UpdateRegistrantProfile(_ context.Context, id string, input model.UpdateRegistrantProfileInput) (*model.RegistrantProfile, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("UpdateRegistrantProfile", id, input); err != nil {
+		return nil, err
+	}
+
+	p := f.findRegistrantProfile(id)
+	if p == nil {
+		return nil, notFound("registrant profile", id)
+	}
+
+	for dst, src := range map[*string]*string{
+		&p.FirstName:    input.FirstName,
+		&p.LastName:     input.LastName,
+		&p.Email:        input.Email,
+		&p.Phone:        input.Phone,
+		&p.Address1:     input.Address1,
+		&p.City:         input.City,
+		&p.State:        input.State,
+		&p.Country:      input.Country,
+		&p.PostalCode:   input.PostalCode,
+		&p.Organization: input.Organization,
+	} {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	clone := *p
+	return &clone, nil
+}
+
+func (f *Fake) DeleteRegistrantProfile(_ context.Context, id string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("DeleteRegistrantProfile", id); err != nil {
+		return err
+	}
+
+	n := len(f.registrantProfiles)
+	f.registrantProfiles = slices.DeleteFunc(f.registrantProfiles, func(p model.RegistrantProfile) bool { return p.ID == id })
+	if len(f.registrantProfiles) == n {
+		return notFound("registrant profile", id)
+	}
+	return nil
+}
+
+func (f *Fake) ResendRegistrantVerificationEmail(_ context.Context, registeredDomainID string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("ResendRegistrantVerificationEmail", registeredDomainID); err != nil {
+		return err
+	}
+
+	if f.findRegisteredDomainByID(registeredDomainID) == nil {
+		return notFound("registered domain", registeredDomainID)
+	}
+	return nil
+}
+
+// UpdateRegistrantContact replaces the registrant of a domain and resets its
+// verification status to PENDING.
+func (f *Fake) UpdateRegistrantContact(_ context.Context, registeredDomainID string, input model.UpdateRegistrantContactInput) error {
+	defer f.mu.Unlock()
+	if err := f.begin("UpdateRegistrantContact", registeredDomainID, input); err != nil {
+		return err
+	}
+
+	d := f.findRegisteredDomainByID(registeredDomainID)
+	if d == nil {
+		return notFound("registered domain", registeredDomainID)
+	}
+
+	contact := &model.RegistrantProfile{
+		FirstName:  input.FirstName,
+		LastName:   input.LastName,
+		Email:      input.Email,
+		Phone:      input.Phone,
+		Address1:   input.Address1,
+		City:       input.City,
+		State:      input.State,
+		Country:    input.Country,
+		PostalCode: input.PostalCode,
+	}
+	if input.Organization != nil {
+		contact.Organization = *input.Organization
+	}
+	pending := "PENDING"
+	d.RegistrantProfile, d.RegistrantVerificationStatus = contact, &pending
+	return nil
+}
+
+func (f *Fake) findRegisteredDomain(domain string) *model.RegisteredDomain {
+	for i := range f.registeredDomains {
+		if f.registeredDomains[i].Domain == domain {
+			return &f.registeredDomains[i]
+		}
+	}
+	return nil
+}
+
+func (f *Fake) findRegisteredDomainByID(id string) *model.RegisteredDomain {
+	for i := range f.registeredDomains {
+		if f.registeredDomains[i].ID == id {
+			return &f.registeredDomains[i]
+		}
+	}
+	return nil
+}
+
+func (f *Fake) findRegistrantProfile(id string) *model.RegistrantProfile {
+	for i := range f.registrantProfiles {
+		if f.registrantProfiles[i].ID == id {
+			return &f.registrantProfiles[i]
+		}
+	}
+	return nil
+}
+
+func tld(domain string) string {
+	if i := strings.LastIndex(domain, "."); i >= 0 {
+		return domain[i+1:]
+	}
+	return ""
+}
@@ -0,0 +1,200 @@
+package apitest
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// SeedProject adds a project owned by ownerID ("" for the personal account)
+// together with its "production" environment, which is returned too.
+func (f *Fake) SeedProject(ownerID, name string) (*model.Project, *model.Environment) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p := f.createProject(ownerID, name, model.Region{ID: "hkg1", Name: "Hong Kong"})
+	return p, f.createEnvironment(p.ID, "production")
+}
+
+// SeedEnvironment adds another environment to a project.
+func (f *Fake) SeedEnvironment(projectID, name string) *model.Environment {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.createEnvironment(projectID, name)
+}
+
+// SeedService adds an empty service to a project.
+func (f *Fake) SeedService(projectID, name string) *model.Service {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.createService(projectID, name, "")
+}
+
+// SeedServiceStatus sets the status ServiceDetail reports for a service.
+func (f *Fake) SeedServiceStatus(serviceID, status string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s := f.findService(serviceID); s != nil {
+		s.status = status
+	}
+}
+
+// SeedVariables replaces the variables of a service in an environment.
+func (f *Fake) SeedVariables(serviceID, environmentID string, vars map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.variables[scope{serviceID, environmentID}] = maps.Clone(vars)
+}
+
+// SeedExposedVariables sets the read-only variables other services expose
+// to this one; ListVariables returns them as its second result.
+func (f *Fake) SeedExposedVariables(serviceID, environmentID string, vars model.Variables) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.exposed[scope{serviceID, environmentID}] = vars
+}
+
+// SeedDomain binds a domain to a service.
+func (f *Fake) SeedDomain(serviceID, environmentID, domain string, isGenerated bool) *model.Domain {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.createDomain(serviceID, environmentID, domain, isGenerated)
+}
+
+// SeedDeployment adds a deployment with the given status. It becomes the
+// service's latest deployment.
+func (f *Fake) SeedDeployment(serviceID, environmentID, status string) *model.Deployment {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.createDeployment(serviceID, environmentID, status)
+}
+
+// SeedDeploymentStatus changes the status of an existing deployment.
+func (f *Fake) SeedDeploymentStatus(deploymentID, status string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, d := range f.deployments {
+		if d.ID == deploymentID {
+			d.Status = status
+		}
+	}
+}
+
+// SeedBuildLogs sets the build logs of a deployment.
+func (f *Fake) SeedBuildLogs(deploymentID string, logs model.Logs) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.buildLogs[deploymentID] = logs
+}
+
+// SeedRuntimeLogs sets the runtime logs of a service in an environment.
+func (f *Fake) SeedRuntimeLogs(serviceID, environmentID string, logs model.Logs) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.runtimeLogs[scope{serviceID, environmentID}] = logs
+}
+
+// SeedTemplate adds a template owned by the caller.
+func (f *Fake) SeedTemplate(t *model.Template) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = f.tick()
+	}
+	f.templates = append(f.templates, t)
+}
+
+// SeedServer adds a dedicated server.
+func (f *Fake) SeedServer(s *model.ServerDetail) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s.ID == "" {
+		s.ID = f.newID()
+	}
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = f.tick()
+	}
+	f.serverDetails = append(f.serverDetails, s)
+}
+
+// SeedRegisteredDomain adds a domain registered through Zeabur.
+func (f *Fake) SeedRegisteredDomain(d model.RegisteredDomain) *model.RegisteredDomain {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if d.ID == "" {
+		d.ID = f.newID()
+	}
+	f.registeredDomains = append(f.registeredDomains, d)
+	return &f.registeredDomains[len(f.registeredDomains)-1]
+}
+
+// SeedRegistrantProfile adds a registrant profile.
+func (f *Fake) SeedRegistrantProfile(p model.RegistrantProfile) *model.RegistrantProfile {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if p.ID == "" {
+		p.ID = f.newID()
+	}
+	f.registrantProfiles = append(f.registrantProfiles, p)
+	return &f.registrantProfiles[len(f.registrantProfiles)-1]
+}
+
+// Projects returns every project the fake holds.
+func (f *Fake) Projects() model.Projects {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	projects := make(model.Projects, 0, len(f.projects))
+	for _, p := range f.projects {
+		projects = append(projects, p.Project)
+	}
+	return projects
+}
+
+// Services returns the services of a project.
+func (f *Fake) Services(projectID string) model.Services {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.projectServices(projectID)
+}
+
+// Variables returns a copy of the variables of a service in an environment.
+func (f *Fake) Variables(serviceID, environmentID string) map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return maps.Clone(f.variables[scope{serviceID, environmentID}])
+}
+
+// Domains returns the domains bound to a service in an environment.
+func (f *Fake) Domains(serviceID, environmentID string) model.Domains {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.serviceDomains(serviceID, environmentID)
+}
+
+// Deployments returns the deployments of a service, newest first.
+func (f *Fake) Deployments(serviceID, environmentID string) model.Deployments {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.serviceDeployments(serviceID, environmentID)
+}
+
+// PortForwarding returns the port forwarding mode of a service.
+func (f *Fake) PortForwarding(serviceID, environmentID string) model.PortForwardingMode {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.portForwardingMode(serviceID, environmentID)
+}
+
+// ImageTag returns the image tag last set on a service, or "".
+func (f *Fake) ImageTag(serviceID, environmentID string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.imageTags[scope{serviceID, environmentID}]
+}
+
+// Templates returns every template.
+func (f *Fake) Templates() model.Templates {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.templates)
+}
@@ -0,0 +1,137 @@
+package apitest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// ListServers returns every seeded server; the fake does not track server
+// ownership, so ownerID is only recorded.
+func (f *Fake) ListServers(_ context.Context, ownerID string) (model.ServerListItems, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListServers", ownerID); err != nil {
+		return nil, err
+	}
+
+	items := make(model.ServerListItems, 0, len(f.serverDetails))
+	for _, s := range f.serverDetails {
+		items = append(items, model.ServerListItem{
+			ID:                 s.ID,
+			Name:               s.Name,
+			IP:                 s.IP,
+			Country:            s.Country,
+			City:               s.City,
+			ProvisioningStatus: s.ProvisioningStatus,
+			Status:             s.Status,
+			ProviderInfo:       s.ProviderInfo,
+		})
+	}
+	return items, nil
+}
+
+func (f *Fake) GetServer(_ context.Context, id string) (*model.ServerDetail, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetServer", id); err != nil {
+		return nil, err
+	}
+
+	if s := f.findServer(id); s != nil {
+		return s, nil
+	}
+	return nil, notFound("server", id)
+}
+
+func (f *Fake) RebootServer(_ context.Context, id string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("RebootServer", id); err != nil {
+		return err
+	}
+
+	s := f.findServer(id)
+	if s == nil {
+		return notFound("server", id)
+	}
+	s.Events = append(s.Events, model.ServerEvent{Message: "Server rebooted", Time: f.tick(), Severity: "INFO"})
+	return nil
+}
+
+func (f *Fake) RenameServer(_ context.Context, id, name string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("RenameServer", id, name); err != nil {
+		return err
+	}
+
+	s := f.findServer(id)
+	if s == nil {
+		return notFound("server", id)
+	}
+	s.Name = name
+	return nil
+}
+
+func (f *Fake) ListDedicatedServerProviders(_ context.Context) ([]model.CloudProvider, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListDedicatedServerProviders"); err != nil {
+		return nil, err
+	}
+	return f.DedicatedProviders, nil
+}
+
+func (f *Fake) ListDedicatedServerRegions(_ context.Context, provider string) ([]model.DedicatedServerRegion, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListDedicatedServerRegions", provider); err != nil {
+		return nil, err
+	}
+	return f.DedicatedRegions[provider], nil
+}
+
+func (f *Fake) ListDedicatedServerPlans(_ context.Context, provider, region string) (model.DedicatedServerPlans, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListDedicatedServerPlans", provider, region); err != nil {
+		return nil, err
+	}
+	return f.DedicatedPlans[provider+"/"+region], nil
+}
+
+// RentServer adds a server in the PROVISIONING state and returns its ID.
+func (f *Fake) RentServer(_ context.Context, provider, region, plan string) (string, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("RentServer", provider, region, plan); err != nil {
+		return "", err
+	}
+
+	provisioning := "PROVISIONING"
+	s := &model.ServerDetail{
+		ID:                 f.newID(),
+		Name:               fmt.Sprintf("%s-%s-%s", provider, region, plan),
+		IsManaged:          true,
+		ProvisioningStatus: &provisioning,
+		CreatedAt:          f.tick(),
+		ProviderInfo:       &model.ServerProviderInfo{Code: provider, Name: provider},
+	}
+	f.serverDetails = append(f.serverDetails, s)
+	return s.ID, nil
+}
+
+func (f *Fake) RevealServerPassword(_ context.Context, serverID string) (string, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("RevealServerPassword", serverID); err != nil {
+		return "", err
+	}
+
+	if f.findServer(serverID) == nil {
+		return "", notFound("server", serverID)
+	}
+	return f.ServerPasswords[serverID], nil
+}
+
+func (f *Fake) findServer(id string) *model.ServerDetail {
+	for _, s := range f.serverDetails {
+		if s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,443 @@
+package apitest
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+func (f *Fake) ListServices(_ context.Context, projectID string, skip, limit int) (*model.Connection[model.Service], error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListServices", projectID, skip, limit); err != nil {
+		return nil, err
+	}
+	return page(f.projectServices(projectID), skip, limit), nil
+}
+
+func (f *Fake) ListAllServices(_ context.Context, projectID string) (model.Services, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListAllServices", projectID); err != nil {
+		return nil, err
+	}
+	return f.projectServices(projectID), nil
+}
+
+func (f *Fake) ListServicesDetailByEnvironment(_ context.Context, projectID, environmentID string, skip, limit int) (*model.Connection[model.ServiceDetail], error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListServicesDetailByEnvironment", projectID, environmentID, skip, limit); err != nil {
+		return nil, err
+	}
+	return page(f.projectServiceDetails(projectID, environmentID), skip, limit), nil
+}
+
+func (f *Fake) ListAllServicesDetailByEnvironment(_ context.Context, projectID, environmentID string) (model.ServiceDetails, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListAllServicesDetailByEnvironment", projectID, environmentID); err != nil {
+		return nil, err
+	}
+	return f.projectServiceDetails(projectID, environmentID), nil
+}
+
+// GetService looks a service up by ID, or by owner username, project name
+// and service name when id is empty.
+func (f *Fake) GetService(_ context.Context, id, ownerName, projectName, name string) (*model.Service, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetService", id, ownerName, projectName, name); err != nil {
+		return nil, err
+	}
+
+	s, err := f.lookupService(id, ownerName, projectName, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.Service, nil
+}
+
+func (f *Fake) GetServiceDetailByEnvironment(_ context.Context, id, ownerName, projectName, name, environmentID string) (*model.ServiceDetail, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetServiceDetailByEnvironment", id, ownerName, projectName, name, environmentID); err != nil {
+		return nil, err
+	}
+
+	s, err := f.lookupService(id, ownerName, projectName, name)
+	if err != nil {
+		return nil, err
+	}
+	return f.serviceDetail(s, environmentID), nil
+}
+
+func (f *Fake) ServiceMetric(_ context.Context, id, projectID, environmentID, metricType string, startTime, endTime time.Time) (*model.ServiceMetric, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ServiceMetric", id, projectID, environmentID, metricType, startTime, endTime); err != nil {
+		return nil, err
+	}
+
+	if f.findService(id) == nil {
+		return nil, notFound("service", id)
+	}
+	if f.Metrics != nil {
+		return f.Metrics, nil
+	}
+	return &model.ServiceMetric{}, nil
+}
+
+func (f *Fake) ServiceInstructions(_ context.Context, id, environmentID string) ([]model.ServiceInstruction, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ServiceInstructions", id, environmentID); err != nil {
+		return nil, err
+	}
+	return f.Instructions[id], nil
+}
+
+func (f *Fake) GetPrebuiltItems(_ context.Context) ([]model.PrebuiltItem, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetPrebuiltItems"); err != nil {
+		return nil, err
+	}
+	return f.PrebuiltItems, nil
+}
+
+func (f *Fake) SearchGitRepositories(_ context.Context, keyword *string) ([]model.GitRepo, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("SearchGitRepositories", keyword); err != nil {
+		return nil, err
+	}
+
+	if keyword == nil || *keyword == "" {
+		return f.GitRepos, nil
+	}
+	var repos []model.GitRepo
+	for _, r := range f.GitRepos {
+		if strings.Contains(r.Name, *keyword) || strings.Contains(r.Owner, *keyword) {
+			repos = append(repos, r)
+		}
+	}
+	return repos, nil
+}
+
+func (f *Fake) RestartService(_ context.Context, id string, environmentID string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("RestartService", id, environmentID); err != nil {
+		return err
+	}
+
+	s := f.findService(id)
+	if s == nil {
+		return notFound("service", id)
+	}
+	s.status = "RUNNING"
+	return nil
+}
+
+// RedeployService creates a new deployment with NewDeploymentStatus.
+func (f *Fake) RedeployService(_ context.Context, id string, environmentID string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("RedeployService", id, environmentID); err != nil {
+		return err
+	}
+
+	if f.findService(id) == nil {
+		return notFound("service", id)
+	}
+	f.createDeployment(id, environmentID, f.NewDeploymentStatus)
+	return nil
+}
+
+func (f *Fake) SuspendService(_ context.Context, id string, environmentID string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("SuspendService", id, environmentID); err != nil {
+		return err
+	}
+
+	s := f.findService(id)
+	if s == nil {
+		return notFound("service", id)
+	}
+	s.status = "SUSPENDED"
+	return nil
+}
+
+// CreatePrebuiltService names the service after the marketplace item, the
+// same as the backend does.
+func (f *Fake) CreatePrebuiltService(_ context.Context, projectID string, marketplaceCode string) (*model.Service, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CreatePrebuiltService", projectID, marketplaceCode); err != nil {
+		return nil, err
+	}
+
+	if f.findProject(projectID) == nil {
+		return nil, notFound("project", projectID)
+	}
+	s := f.createService(projectID, marketplaceCode, string(model.ServiceTemplateMarketplace))
+	s.MarketItemCode = &marketplaceCode
+	return s, nil
+}
+
+func (f *Fake) CreatePrebuiltServiceCustom(_ context.Context, projectID string, schema model.ServiceSpecSchemaInput) (*model.Service, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CreatePrebuiltServiceCustom", projectID, schema); err != nil {
+		return nil, err
+	}
+
+	if f.findProject(projectID) == nil {
+		return nil, notFound("project", projectID)
+	}
+	return f.createService(projectID, schema.Name, string(model.ServiceTemplateMarketplace)), nil
+}
+
+func (f *Fake) CreatePrebuiltServiceRaw(_ context.Context, projectID string, rawSchema string) (*model.Service, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CreatePrebuiltServiceRaw", projectID, rawSchema); err != nil {
+		return nil, err
+	}
+
+	if f.findProject(projectID) == nil {
+		return nil, notFound("project", projectID)
+	}
+	return f.createService(projectID, "", string(model.ServiceTemplateMarketplace)), nil
+}
+
+func (f *Fake) CreateService(_ context.Context, projectID string, name string, repoID int, branchName string) (*model.Service, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CreateService", projectID, name, repoID, branchName); err != nil {
+		return nil, err
+	}
+
+	if f.findProject(projectID) == nil {
+		return nil, notFound("project", projectID)
+	}
+	return f.createService(projectID, name, string(model.ServiceTemplateGit)), nil
+}
+
+func (f *Fake) CreateEmptyService(_ context.Context, projectID string, name string) (*model.Service, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CreateEmptyService", projectID, name); err != nil {
+		return nil, err
+	}
+
+	if f.findProject(projectID) == nil {
+		return nil, notFound("project", projectID)
+	}
+	return f.createService(projectID, name, ""), nil
+}
+
+// UploadZipToService records the upload size instead of the bytes and
+// creates a new deployment with NewDeploymentStatus.
+func (f *Fake) UploadZipToService(_ context.Context, projectID string, serviceID string, environmentID string, zipBytes []byte) (*model.Service, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("UploadZipToService", projectID, serviceID, environmentID, len(zipBytes)); err != nil {
+		return nil, err
+	}
+
+	s := f.findService(serviceID)
+	if s == nil {
+		return nil, notFound("service", serviceID)
+	}
+	f.createDeployment(serviceID, environmentID, f.NewDeploymentStatus)
+	return s.Service, nil
+}
+
+func (f *Fake) GetDNSName(_ context.Context, serviceID string) (string, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetDNSName", serviceID); err != nil {
+		return "", err
+	}
+
+	s := f.findService(serviceID)
+	if s == nil {
+		return "", notFound("service", serviceID)
+	}
+	return s.Name + ".zeabur.internal", nil
+}
+
+func (f *Fake) GetPortForwardingMode(_ context.Context, serviceID string, environmentID string) (model.PortForwardingMode, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetPortForwardingMode", serviceID, environmentID); err != nil {
+		return model.PortForwardingModeUnknown, err
+	}
+
+	if f.findService(serviceID) == nil {
+		return model.PortForwardingModeUnknown, notFound("service", serviceID)
+	}
+	return f.portForwardingMode(serviceID, environmentID), nil
+}
+
+func (f *Fake) UpdatePortForwardingMode(_ context.Context, serviceID string, environmentID string, mode model.PortForwardingMode) error {
+	defer f.mu.Unlock()
+	if err := f.begin("UpdatePortForwardingMode", serviceID, environmentID, mode); err != nil {
+		return err
+	}
+
+	if f.findService(serviceID) == nil {
+		return notFound("service", serviceID)
+	}
+	f.portForward[scope{serviceID, environmentID}] = mode
+	return nil
+}
+
+func (f *Fake) GetServicePorts(_ context.Context, serviceID string, environmentID string) ([]model.ServicePort, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetServicePorts", serviceID, environmentID); err != nil {
+		return nil, err
+	}
+	return f.Ports[serviceID], nil
+}
+
+func (f *Fake) GetPortForwardedHost(_ context.Context, serviceID string) (string, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetPortForwardedHost", serviceID); err != nil {
+		return "", err
+	}
+
+	if f.findService(serviceID) == nil {
+		return "", notFound("service", serviceID)
+	}
+	return "hkg1.clusters.zeabur.com", nil
+}
+
+func (f *Fake) UpdateImageTag(_ context.Context, serviceID string, environmentID string, tag string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("UpdateImageTag", serviceID, environmentID, tag); err != nil {
+		return err
+	}
+
+	if f.findService(serviceID) == nil {
+		return notFound("service", serviceID)
+	}
+	f.imageTags[scope{serviceID, environmentID}] = tag
+	return nil
+}
+
+// DeleteService removes a service along with its variables, domains and
+// deployments.
+func (f *Fake) DeleteService(_ context.Context, id string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("DeleteService", id); err != nil {
+		return err
+	}
+
+	if f.findService(id) == nil {
+		return notFound("service", id)
+	}
+	f.deleteService(id)
+	return nil
+}
+
+func (f *Fake) ExecuteCommand(_ context.Context, serviceID string, environmentID string, command []string) (*model.CommandResult, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ExecuteCommand", serviceID, environmentID, command); err != nil {
+		return nil, err
+	}
+
+	if f.findService(serviceID) == nil {
+		return nil, notFound("service", serviceID)
+	}
+	if r, ok := f.CommandResults[strings.Join(command, " ")]; ok {
+		return r, nil
+	}
+	return &model.CommandResult{}, nil
+}
+
+func (f *Fake) createService(projectID, name, template string) *model.Service {
+	id := f.newID()
+	if name == "" {
+		name = "service-" + id[len(id)-6:]
+	}
+
+	s := &model.Service{
+		ID:   id,
+		Name: name,
+		Project: &struct {
+			ID string `graphql:"_id"`
+		}{ID: projectID},
+		CreatedAt: f.tick(),
+		Template:  template,
+	}
+	f.services = append(f.services, &service{Service: s})
+	return s
+}
+
+func (f *Fake) deleteService(id string) {
+	f.services = slices.DeleteFunc(f.services, func(s *service) bool { return s.ID == id })
+	f.domains = slices.DeleteFunc(f.domains, func(d *model.Domain) bool { return d.ServiceID == id })
+	f.deployments = slices.DeleteFunc(f.deployments, func(d *model.Deployment) bool { return d.ServiceID == id })
+	for k := range f.variables {
+		if k.serviceID == id {
+			delete(f.variables, k)
+		}
+	}
+}
+
+func (f *Fake) findService(id string) *service {
+	for _, s := range f.services {
+		if s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+func (f *Fake) lookupService(id, ownerName, projectName, name string) (*service, error) {
+	if id != "" {
+		if s := f.findService(id); s != nil {
+			return s, nil
+		}
+		return nil, notFound("service", id)
+	}
+
+	for _, p := range f.projects {
+		if p.ownerID != "" || ownerName != f.User.Username || p.Name != projectName {
+			continue
+		}
+		for _, s := range f.services {
+			if s.Project.ID == p.ID && s.Name == name {
+				return s, nil
+			}
+		}
+	}
+	return nil, notFound("service", ownerName+"/"+projectName+"/"+name)
+}
+
+func (f *Fake) projectServices(projectID string) model.Services {
+	services := model.Services{}
+	for _, s := range f.services {
+		if s.Project.ID == projectID {
+			services = append(services, s.Service)
+		}
+	}
+	return services
+}
+
+func (f *Fake) projectServiceDetails(projectID, environmentID string) model.ServiceDetails {
+	details := model.ServiceDetails{}
+	for _, s := range f.services {
+		if s.Project.ID == projectID {
+			details = append(details, f.serviceDetail(s, environmentID))
+		}
+	}
+	return details
+}
+
+func (f *Fake) serviceDetail(s *service, environmentID string) *model.ServiceDetail {
+	status := s.status
+	if status == "" {
+		status = "RUNNING"
+	}
+
+	domains := make([]model.Domain, 0)
+	for _, d := range f.serviceDomains(s.ID, environmentID) {
+		domains = append(domains, *d)
+	}
+
+	return &model.ServiceDetail{Service: *s.Service, Status: status, Domains: domains}
+}
+
+func (f *Fake) portForwardingMode(serviceID, environmentID string) model.PortForwardingMode {
+	if mode, ok := f.portForward[scope{serviceID, environmentID}]; ok {
+		return mode
+	}
+	return model.PortForwardingModeDisabled
+}
@@ -0,0 +1,165 @@
+package apitest
+
+import (
+	"context"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// templateSpec is the part of a template file the fake understands.
+type templateSpec struct {
+	Metadata struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Description string `yaml:"description"`
+		Services    []struct {
+			Name string `yaml:"name"`
+		} `yaml:"services"`
+	} `yaml:"spec"`
+}
+
+func parseTemplate(raw string) (*templateSpec, error) {
+	var spec templateSpec
+	if err := yaml.Unmarshal([]byte(raw), &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+func (f *Fake) ListTemplates(_ context.Context, skip, limit int) (*model.TemplateConnection, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListTemplates", skip, limit); err != nil {
+		return nil, err
+	}
+
+	conn := page(f.templates, skip, limit)
+	edges := make([]*model.TemplateEdge, 0, len(conn.Edges))
+	for _, e := range conn.Edges {
+		edges = append(edges, &model.TemplateEdge{Node: e.Node, Cursor: e.Cursor})
+	}
+	return &model.TemplateConnection{PageInfo: conn.PageInfo, Edges: edges}, nil
+}
+
+func (f *Fake) ListAllTemplates(_ context.Context) (model.Templates, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListAllTemplates"); err != nil {
+		return nil, err
+	}
+	return slices.Clone(f.templates), nil
+}
+
+func (f *Fake) GetTemplate(_ context.Context, code string) (*model.Template, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetTemplate", code); err != nil {
+		return nil, err
+	}
+
+	if t := f.findTemplate(code); t != nil {
+		return t, nil
+	}
+	return nil, notFound("template", code)
+}
+
+// DeployTemplate creates one service per entry in spec.services, in a new
+// project when projectID is empty.
+func (f *Fake) DeployTemplate(_ context.Context, rawSpecYaml string, variables model.Map, repoConfigs model.RepoConfigs, projectID string) (*model.Project, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("DeployTemplate", rawSpecYaml, variables, repoConfigs, projectID); err != nil {
+		return nil, err
+	}
+
+	spec, err := parseTemplate(rawSpecYaml)
+	if err != nil {
+		return nil, err
+	}
+
+	var p *model.Project
+	if projectID == "" {
+		p = f.createProject("", "", model.Region{})
+		f.createEnvironment(p.ID, "production")
+	} else {
+		found := f.findProject(projectID)
+		if found == nil {
+			return nil, notFound("project", projectID)
+		}
+		p = found.Project
+	}
+
+	for _, s := range spec.Spec.Services {
+		f.createService(p.ID, s.Name, "")
+	}
+	return p, nil
+}
+
+func (f *Fake) DeleteTemplate(_ context.Context, code string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("DeleteTemplate", code); err != nil {
+		return err
+	}
+
+	if f.findTemplate(code) == nil {
+		return notFound("template", code)
+	}
+	f.templates = slices.DeleteFunc(f.templates, func(t *model.Template) bool { return t.Code == code })
+	return nil
+}
+
+func (f *Fake) CreateTemplateFromFile(_ context.Context, rawSpecYaml string) (*model.Template, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CreateTemplateFromFile", rawSpecYaml); err != nil {
+		return nil, err
+	}
+
+	spec, err := parseTemplate(rawSpecYaml)
+	if err != nil {
+		return nil, err
+	}
+
+	id := f.newID()
+	t := &model.Template{
+		Code:        id[len(id)-6:],
+		Name:        spec.Metadata.Name,
+		Description: spec.Spec.Description,
+		CreatedAt:   f.tick(),
+	}
+	for _, s := range spec.Spec.Services {
+		t.Services = append(t.Services, model.TemplateServiceRef{Name: s.Name})
+	}
+	f.templates = append(f.templates, t)
+	return t, nil
+}
+
+func (f *Fake) UpdateTemplateFromFile(_ context.Context, code, rawSpecYaml string) (bool, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("UpdateTemplateFromFile", code, rawSpecYaml); err != nil {
+		return false, err
+	}
+
+	t := f.findTemplate(code)
+	if t == nil {
+		return false, notFound("template", code)
+	}
+	spec, err := parseTemplate(rawSpecYaml)
+	if err != nil {
+		return false, err
+	}
+
+	t.Name, t.Description, t.Services = spec.Metadata.Name, spec.Spec.Description, nil
+	for _, s := range spec.Spec.Services {
+		t.Services = append(t.Services, model.TemplateServiceRef{Name: s.Name})
+	}
+	return true, nil
+}
+
+func (f *Fake) findTemplate(code string) *model.Template {
+	for _, t := range f.templates {
+		if t.Code == code {
+			return t
+		}
+	}
+	return nil
+}
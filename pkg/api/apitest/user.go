@@ -0,0 +1,23 @@
+package apitest
+
+import (
+	"context"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+func (f *Fake) GetUserInfo(_ context.Context) (*model.User, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetUserInfo"); err != nil {
+		return nil, err
+	}
+	return f.User, nil
+}
+
+func (f *Fake) ListTeams(_ context.Context) ([]model.Team, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListTeams"); err != nil {
+		return nil, err
+	}
+	return f.Teams, nil
+}
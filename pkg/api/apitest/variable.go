@@ -0,0 +1,43 @@
+package apitest
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// ListVariables returns the service's own variables sorted by key, then the
+// variables exposed to it by other services.
+func (f *Fake) ListVariables(_ context.Context, serviceID string, environmentID string) (model.Variables, model.Variables, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListVariables", serviceID, environmentID); err != nil {
+		return nil, nil, err
+	}
+
+	if f.findService(serviceID) == nil {
+		return nil, nil, notFound("service", serviceID)
+	}
+
+	vars := f.variables[scope{serviceID, environmentID}]
+	own := make(model.Variables, 0, len(vars))
+	for _, k := range slices.Sorted(maps.Keys(vars)) {
+		own = append(own, &model.Variable{Key: k, Value: vars[k], ServiceID: serviceID})
+	}
+	return own, f.exposed[scope{serviceID, environmentID}], nil
+}
+
+// UpdateVariables replaces the whole variable set, like the backend does.
+func (f *Fake) UpdateVariables(_ context.Context, serviceID string, environmentID string, data map[string]string) (bool, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("UpdateVariables", serviceID, environmentID, maps.Clone(data)); err != nil {
+		return false, err
+	}
+
+	if f.findService(serviceID) == nil {
+		return false, notFound("service", serviceID)
+	}
+	f.variables[scope{serviceID, environmentID}] = maps.Clone(data)
+	return true, nil
+}
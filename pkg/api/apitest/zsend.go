@@ -0,0 +1,488 @@
+package apitest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// ErrInvalidAPIKey is returned by the Z-Send REST methods when the API key
+// does not belong to any key in ZSend.APIKeys.
+var ErrInvalidAPIKey = errors.New("invalid Z-Send API key")
+
+func (f *Fake) GetZSendOnboardingStatus(_ context.Context) (*model.ZSendOnboardingStatus, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetZSendOnboardingStatus"); err != nil {
+		return nil, err
+	}
+	status := f.ZSend.Onboarding
+	return &status, nil
+}
+
+func (f *Fake) GetZSendUserStatus(_ context.Context) (*model.ZSendUserStatus, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetZSendUserStatus"); err != nil {
+		return nil, err
+	}
+	return f.ZSend.User, nil
+}
+
+func (f *Fake) OnboardZSend(_ context.Context) (*model.ZSendOnboardingStatus, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("OnboardZSend"); err != nil {
+		return nil, err
+	}
+	f.ZSend.Onboarding = model.ZSendOnboardingStatus{IsNew: false, Submitted: true}
+	status := f.ZSend.Onboarding
+	return &status, nil
+}
+
+func (f *Fake) ListZSendDomains(_ context.Context, page, pageSize *int) (*model.ListZSendDomainsReply, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListZSendDomains", page, pageSize); err != nil {
+		return nil, err
+	}
+	return &model.ListZSendDomainsReply{Domains: paginate(f.ZSend.Domains, page, pageSize), TotalCount: len(f.ZSend.Domains)}, nil
+}
+
+func (f *Fake) GetZSendDomain(_ context.Context, id string) (*model.ZSendDomain, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetZSendDomain", id); err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(f.ZSend.Domains, func(d model.ZSendDomain) bool { return d.ID == id })
+	if i < 0 {
+		return nil, notFound("Z-Send domain", id)
+	}
+	d := f.ZSend.Domains[i]
+	return &d, nil
+}
+
+func (f *Fake) CreateZSendDomain(_ context.Context, domain, region string) (*model.ZSendDomain, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CreateZSendDomain", domain, region); err != nil {
+		return nil, err
+	}
+
+	now := f.tick()
+	d := model.ZSendDomain{
+		ID:        f.newID(),
+		UserID:    f.User.ID,
+		Type:      "DOMAIN",
+		Value:     domain,
+		Region:    region,
+		Status:    "PENDING",
+		Records:   []model.ZSendDNSRecord{{Category: "DKIM", Type: "TXT", Name: "zeabur._domainkey." + domain, Content: "v=DKIM1; p=fake", Status: "PENDING"}},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	f.ZSend.Domains = append(f.ZSend.Domains, d)
+	return &d, nil
+}
+
+// VerifyZSendDomain marks the domain and all its records as verified.
+func (f *Fake) VerifyZSendDomain(_ context.Context, id string) (*model.ZSendDomain, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("VerifyZSendDomain", id); err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(f.ZSend.Domains, func(d model.ZSendDomain) bool { return d.ID == id })
+	if i < 0 {
+		return nil, notFound("Z-Send domain", id)
+	}
+	d := &f.ZSend.Domains[i]
+	d.Status, d.UpdatedAt = "VERIFIED", f.tick()
+	for j := range d.Records {
+		d.Records[j].Status = "VERIFIED"
+	}
+	clone := *d
+	return &clone, nil
+}
+
+func (f *Fake) DeleteZSendDomain(_ context.Context, id string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("DeleteZSendDomain", id); err != nil {
+		return err
+	}
+	return deleteByID(&f.ZSend.Domains, "Z-Send domain", id, func(d model.ZSendDomain) string { return d.ID })
+}
+
+// ListZSendAPIKeys never returns tokens, like the real API.
+func (f *Fake) ListZSendAPIKeys(_ context.Context, page, pageSize *int) (*model.ListZSendAPIKeysReply, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListZSendAPIKeys", page, pageSize); err != nil {
+		return nil, err
+	}
+
+	keys := paginate(f.ZSend.APIKeys, page, pageSize)
+	for i := range keys {
+		keys[i].Token = nil
+	}
+	return &model.ListZSendAPIKeysReply{APIKeys: keys, TotalCount: len(f.ZSend.APIKeys)}, nil
+}
+
+func (f *Fake) GetZSendAPIKey(_ context.Context, id string) (*model.ZSendAPIKey, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetZSendAPIKey", id); err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(f.ZSend.APIKeys, func(k model.ZSendAPIKey) bool { return k.ID == id })
+	if i < 0 {
+		return nil, notFound("Z-Send API key", id)
+	}
+	k := f.ZSend.APIKeys[i]
+	k.Token = nil
+	return &k, nil
+}
+
+// CreateZSendAPIKey returns the token once; it is kept so the REST methods
+// can authenticate with it.
+func (f *Fake) CreateZSendAPIKey(_ context.Context, input model.CreateZSendAPIKeyInput) (*model.CreateZSendAPIKeyReply, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CreateZSendAPIKey", input); err != nil {
+		return nil, err
+	}
+
+	id := f.newID()
+	token := "zs_" + id
+	k := model.ZSendAPIKey{
+		ID:         id,
+		UserID:     f.User.ID,
+		Name:       input.Name,
+		Permission: input.Permission,
+		Domains:    input.Domains,
+		Token:      &token,
+		CreatedAt:  f.tick(),
+	}
+	f.ZSend.APIKeys = append(f.ZSend.APIKeys, k)
+	return &model.CreateZSendAPIKeyReply{APIKey: k}, nil
+}
+
+func (f *Fake) DeleteZSendAPIKey(_ context.Context, id string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("DeleteZSendAPIKey", id); err != nil {
+		return err
+	}
+	return deleteByID(&f.ZSend.APIKeys, "Z-Send API key", id, func(k model.ZSendAPIKey) string { return k.ID })
+}
+
+func (f *Fake) ListZSendWebhooks(_ context.Context, page, pageSize *int) (*model.ListZSendWebhooksReply, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListZSendWebhooks", page, pageSize); err != nil {
+		return nil, err
+	}
+	return &model.ListZSendWebhooksReply{Webhooks: paginate(f.ZSend.Webhooks, page, pageSize), TotalCount: len(f.ZSend.Webhooks)}, nil
+}
+
+func (f *Fake) GetZSendWebhook(_ context.Context, id string) (*model.ZSendWebhook, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetZSendWebhook", id); err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(f.ZSend.Webhooks, func(w model.ZSendWebhook) bool { return w.ID == id })
+	if i < 0 {
+		return nil, notFound("Z-Send webhook", id)
+	}
+	w := f.ZSend.Webhooks[i]
+	return &w, nil
+}
+
+func (f *Fake) CreateZSendWebhook(_ context.Context, input model.CreateZSendWebhookInput) (*model.CreateZSendWebhookReply, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("CreateZSendWebhook", input); err != nil {
+		return nil, err
+	}
+
+	now := f.tick()
+	w := model.ZSendWebhook{
+		ID:        f.newID(),
+		UserID:    f.User.ID,
+		Name:      input.Name,
+		Endpoint:  input.Endpoint,
+		Events:    input.Events,
+		Status:    "ACTIVE",
+		Enabled:   input.Enabled == nil || *input.Enabled,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	f.ZSend.Webhooks = append(f.ZSend.Webhooks, w)
+	return &model.CreateZSendWebhookReply{Webhook: w, Secret: "whsec_" + w.ID}, nil
+}
+
+func (f *Fake) DeleteZSendWebhook(_ context.Context, id string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("DeleteZSendWebhook", id); err != nil {
+		return err
+	}
+	return deleteByID(&f.ZSend.Webhooks, "Z-Send webhook", id, func(w model.ZSendWebhook) string { return w.ID })
+}
+
+func (f *Fake) VerifyZSendWebhook(_ context.Context, id string) (*model.VerifyZSendWebhookReply, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("VerifyZSendWebhook", id); err != nil {
+		return nil, err
+	}
+
+	if !slices.ContainsFunc(f.ZSend.Webhooks, func(w model.ZSendWebhook) bool { return w.ID == id }) {
+		return nil, notFound("Z-Send webhook", id)
+	}
+	return &model.VerifyZSendWebhookReply{Success: true, Message: "Webhook endpoint responded"}, nil
+}
+
+// ListZSendEmails filters by status, jobType and jobID when given.
+func (f *Fake) ListZSendEmails(_ context.Context, page, pageSize *int, status, jobType, jobID *string) (*model.ListZSendEmailsReply, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListZSendEmails", page, pageSize, status, jobType, jobID); err != nil {
+		return nil, err
+	}
+
+	var emails []model.ZSendEmail
+	for _, e := range f.ZSend.Emails {
+		if (status == nil || e.Status == *status) && (jobType == nil || e.JobType == *jobType) && (jobID == nil || e.JobID == *jobID) {
+			emails = append(emails, e)
+		}
+	}
+	return &model.ListZSendEmailsReply{Emails: paginate(emails, page, pageSize), TotalCount: len(emails)}, nil
+}
+
+func (f *Fake) GetZSendEmail(_ context.Context, id string) (*model.ZSendEmail, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetZSendEmail", id); err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(f.ZSend.Emails, func(e model.ZSendEmail) bool { return e.ID == id })
+	if i < 0 {
+		return nil, notFound("Z-Send email", id)
+	}
+	e := f.ZSend.Emails[i]
+	return &e, nil
+}
+
+// SendZSendEmail records the email as sent.
+func (f *Fake) SendZSendEmail(_ context.Context, apiKey string, req model.ZSendSendEmailRequest) (*model.ZSendSendEmailReply, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("SendZSendEmail", apiKey, req); err != nil {
+		return nil, err
+	}
+
+	keyID, err := f.zsendKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+	e := f.recordEmail(keyID, "SEND", "", req, "SENT")
+	return &model.ZSendSendEmailReply{ID: e.ID, MessageID: e.MessageID, Status: e.Status}, nil
+}
+
+func (f *Fake) ScheduleZSendEmail(_ context.Context, apiKey string, req model.ZSendScheduleEmailRequest) (*model.ZSendScheduleEmailReply, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ScheduleZSendEmail", apiKey, req); err != nil {
+		return nil, err
+	}
+
+	if _, err := f.zsendKey(apiKey); err != nil {
+		return nil, err
+	}
+	if _, err := time.Parse(time.RFC3339, req.ScheduledAt); err != nil {
+		return nil, fmt.Errorf("scheduled_at must be RFC3339: %w", err)
+	}
+
+	s := model.ZSendScheduledEmail{
+		ID:          f.newID(),
+		From:        req.From,
+		To:          req.To,
+		Subject:     req.Subject,
+		HTML:        req.HTML,
+		Text:        req.Text,
+		Status:      "SCHEDULED",
+		ScheduledAt: req.ScheduledAt,
+		CreatedAt:   f.tick().Format(time.RFC3339),
+		Headers:     req.Headers,
+		Tags:        req.Tags,
+	}
+	f.ZSend.ScheduledEmails = append(f.ZSend.ScheduledEmails, s)
+	return &model.ZSendScheduleEmailReply{ID: s.ID, Status: s.Status}, nil
+}
+
+// SendZSendBatchEmail sends every email right away and records a completed
+// batch job.
+func (f *Fake) SendZSendBatchEmail(_ context.Context, apiKey string, req model.ZSendBatchEmailRequest) (*model.ZSendBatchEmailReply, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("SendZSendBatchEmail", apiKey, req); err != nil {
+		return nil, err
+	}
+
+	keyID, err := f.zsendKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	now := f.tick().Format(time.RFC3339)
+	job := model.ZSendBatchJob{
+		JobID:       f.newID(),
+		TotalCount:  len(req.Emails),
+		SentCount:   len(req.Emails),
+		Status:      "COMPLETED",
+		CreatedAt:   now,
+		StartedAt:   now,
+		CompletedAt: now,
+	}
+	for _, e := range req.Emails {
+		f.recordEmail(keyID, "BATCH", job.JobID, e, "SENT")
+	}
+	f.ZSend.BatchJobs = append(f.ZSend.BatchJobs, job)
+	return &model.ZSendBatchEmailReply{JobID: job.JobID, Status: job.Status, TotalCount: job.TotalCount}, nil
+}
+
+func (f *Fake) ListZSendScheduledEmails(_ context.Context, apiKey string, page, pageSize *int, status *string) (*model.ZSendListScheduledEmailsReply, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListZSendScheduledEmails", apiKey, page, pageSize, status); err != nil {
+		return nil, err
+	}
+
+	if _, err := f.zsendKey(apiKey); err != nil {
+		return nil, err
+	}
+	var emails []model.ZSendScheduledEmail
+	for _, e := range f.ZSend.ScheduledEmails {
+		if status == nil || e.Status == *status {
+			emails = append(emails, e)
+		}
+	}
+	return &model.ZSendListScheduledEmailsReply{ScheduledEmails: paginate(emails, page, pageSize), TotalCount: len(emails)}, nil
+}
+
+func (f *Fake) GetZSendScheduledEmail(_ context.Context, apiKey string, id string) (*model.ZSendScheduledEmail, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetZSendScheduledEmail", apiKey, id); err != nil {
+		return nil, err
+	}
+
+	if _, err := f.zsendKey(apiKey); err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(f.ZSend.ScheduledEmails, func(e model.ZSendScheduledEmail) bool { return e.ID == id })
+	if i < 0 {
+		return nil, notFound("scheduled email", id)
+	}
+	e := f.ZSend.ScheduledEmails[i]
+	return &e, nil
+}
+
+func (f *Fake) CancelZSendScheduledEmail(_ context.Context, apiKey string, id string) error {
+	defer f.mu.Unlock()
+	if err := f.begin("CancelZSendScheduledEmail", apiKey, id); err != nil {
+		return err
+	}
+
+	if _, err := f.zsendKey(apiKey); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(f.ZSend.ScheduledEmails, func(e model.ZSendScheduledEmail) bool { return e.ID == id })
+	if i < 0 {
+		return notFound("scheduled email", id)
+	}
+	f.ZSend.ScheduledEmails[i].Status = "CANCELED"
+	return nil
+}
+
+func (f *Fake) ListZSendBatchEmailJobs(_ context.Context, apiKey string, page, pageSize *int, status *string) (*model.ZSendListBatchJobsReply, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("ListZSendBatchEmailJobs", apiKey, page, pageSize, status); err != nil {
+		return nil, err
+	}
+
+	if _, err := f.zsendKey(apiKey); err != nil {
+		return nil, err
+	}
+	var jobs []model.ZSendBatchJob
+	for _, j := range f.ZSend.BatchJobs {
+		if status == nil || j.Status == *status {
+			jobs = append(jobs, j)
+		}
+	}
+	return &model.ZSendListBatchJobsReply{Jobs: paginate(jobs, page, pageSize), TotalCount: len(jobs)}, nil
+}
+
+func (f *Fake) GetZSendBatchEmailJob(_ context.Context, apiKey string, id string) (*model.ZSendBatchJob, error) {
+	defer f.mu.Unlock()
+	if err := f.begin("GetZSendBatchEmailJob", apiKey, id); err != nil {
+		return nil, err
+	}
+
+	if _, err := f.zsendKey(apiKey); err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(f.ZSend.BatchJobs, func(j model.ZSendBatchJob) bool { return j.JobID == id })
+	if i < 0 {
+		return nil, notFound("batch job", id)
+	}
+	j := f.ZSend.BatchJobs[i]
+	return &j, nil
+}
+
+// zsendKey returns the ID of the API key whose token is apiKey.
+func (f *Fake) zsendKey(apiKey string) (string, error) {
+	for _, k := range f.ZSend.APIKeys {
+		if k.Token != nil && *k.Token == apiKey {
+			return k.ID, nil
+		}
+	}
+	return "", ErrInvalidAPIKey
+}
+
+func (f *Fake) recordEmail(keyID, jobType, jobID string, req model.ZSendSendEmailRequest, status string) model.ZSendEmail {
+	id := f.newID()
+	e := model.ZSendEmail{
+		ID:        id,
+		UserID:    f.User.ID,
+		APIKeyID:  keyID,
+		JobType:   jobType,
+		JobID:     jobID,
+		MessageID: "<" + id + "@zsend.zeabur.com>",
+		From:      req.From,
+		To:        req.To,
+		CC:        req.Cc,
+		BCC:       req.Bcc,
+		ReplyTo:   req.ReplyTo,
+		Subject:   req.Subject,
+		HTML:      req.HTML,
+		Text:      req.Text,
+		Status:    status,
+		CreatedAt: f.tick(),
+	}
+	f.ZSend.Emails = append(f.ZSend.Emails, e)
+	return e
+}
+
+// paginate applies the 1-based page / pageSize pair the Z-Send API uses. A
+// nil pageSize returns everything. The result is a copy.
+func paginate[T any](items []T, page, pageSize *int) []T {
+	if pageSize == nil || *pageSize <= 0 {
+		return slices.Clone(items)
+	}
+	p := 1
+	if page != nil && *page > 1 {
+		p = *page
+	}
+	start := min((p-1)**pageSize, len(items))
+	end := min(start+*pageSize, len(items))
+	return slices.Clone(items[start:end])
+}
+
+func deleteByID[T any](items *[]T, kind, id string, idOf func(T) string) error {
+	n := len(*items)
+	*items = slices.DeleteFunc(*items, func(item T) bool { return idOf(item) == id })
+	if len(*items) == n {
+		return notFound(kind, id)
+	}
+	return nil
+}
@@ -10,37 +10,69 @@ import (
 	"golang.org/x/oauth2"
 )
 
-// ZeaburGraphQLAPIEndpoint is the endpoint for the Zeabur GraphQL API.
-const (
-	ZeaburGraphQLAPIEndpoint    = constant.ZeaburServerURL + "/graphql"
-	WSSZeaburGraphQLAPIEndpoint = constant.WebsocketURL + "/graphql"
-)
+// GraphQLPath is the path of the GraphQL endpoint, relative to the API base URL.
+const GraphQLPath = "/graphql"
+
+// Endpoints holds the base URLs the client talks to. Overriding them lets
+// the CLI target a staging cluster or a local stand-in server.
+type Endpoints struct {
+	ServerURL    string // base URL of the HTTP API, e.g. https://api.zeabur.com
+	WebsocketURL string // base URL of the subscription endpoint, e.g. wss://api.zeabur.com
+}
+
+// DefaultEndpoints points at the production Zeabur platform.
+var DefaultEndpoints = Endpoints{
+	ServerURL:    constant.ZeaburServerURL,
+	WebsocketURL: constant.WebsocketURL,
+}
 
 type client struct {
 	*graphql.Client
-	sub *graphql.SubscriptionClient
+
+	// token authenticates the subscription clients, which are created per
+	// watch (see subscribeLogs)
+	token     string
+	endpoints Endpoints
 }
 
-// New returns a new Zeabur API client.
+// New returns a new Zeabur API client against the production endpoints.
 func New(token string) Client {
+	return NewWithEndpoints(token, DefaultEndpoints)
+}
+
+// NewWithEndpoints returns a new Zeabur API client against the given endpoints.
+func NewWithEndpoints(token string, endpoints Endpoints) Client {
 	return &client{
-		NewGraphQLClientWithToken(token),
-		NewSubscriptionClient(token),
+		Client:    NewGraphQLClientWithToken(endpoints.ServerURL, token),
+		token:     token,
+		endpoints: endpoints,
 	}
 }
 
 // NewGraphQLClientWithToken returns a new GraphQL client with the given token.
-func NewGraphQLClientWithToken(token string) *graphql.Client {
+func NewGraphQLClientWithToken(serverURL, token string) *graphql.Client {
 	src := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)
 	httpClient := oauth2.NewClient(context.Background(), src)
+	httpClient.Transport = NewRetryTransport(httpClient.Transport, DefaultRetryOptions)
+
+	return graphql.NewClient(serverURL+GraphQLPath, httpClient)
+}
+
+// Query runs a GraphQL query, wrapping failures with their typed error
+// (see ErrNotFound and friends).
+func (c *client) Query(ctx context.Context, q any, variables map[string]any, options ...graphql.Option) error {
+	return classifyError(c.Client.Query(ctx, q, variables, options...))
+}
 
-	return graphql.NewClient(ZeaburGraphQLAPIEndpoint, httpClient)
+// Mutate runs a GraphQL mutation, wrapping failures with their typed error.
+func (c *client) Mutate(ctx context.Context, m any, variables map[string]any, options ...graphql.Option) error {
+	return classifyError(c.Client.Mutate(ctx, m, variables, options...))
 }
 
-func NewSubscriptionClient(token string) *graphql.SubscriptionClient {
-	return graphql.NewSubscriptionClient(WSSZeaburGraphQLAPIEndpoint).
+func NewSubscriptionClient(websocketURL, token string) *graphql.SubscriptionClient {
+	return graphql.NewSubscriptionClient(websocketURL + GraphQLPath).
 		WithProtocol(graphql.GraphQLWS).
 		WithConnectionParams(map[string]any{
 			"authToken": token,
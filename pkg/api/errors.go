@@ -0,0 +1,117 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/hasura/go-graphql-client"
+)
+
+// Typed API errors. Errors returned by the client wrap one of these when the
+// failure can be classified, so commands can branch on them with errors.Is
+// while the original message (and the underlying graphql.Errors) is kept.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrValidation   = errors.New("validation failed")
+)
+
+// apiError attaches a typed error to the error the client returned.
+type apiError struct {
+	kind error
+	err  error
+}
+
+func (e *apiError) Error() string { return e.err.Error() }
+
+func (e *apiError) Unwrap() []error { return []error{e.kind, e.err} }
+
+// classifyError wraps err with the typed error it maps to, if any.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if kind := errorKind(err); kind != nil && !errors.Is(err, kind) {
+		return &apiError{kind: kind, err: err}
+	}
+	return err
+}
+
+// errorKind maps err to a typed error by, in order, the HTTP status of the
+// response, the GraphQL error code in extensions.code, and, for servers that
+// only send a message, a few well-known message fragments.
+func errorKind(err error) error {
+	var netErr graphql.NetworkError
+	if errors.As(err, &netErr) {
+		if kind := statusErrorKind(netErr.StatusCode()); kind != nil {
+			return kind
+		}
+	}
+
+	var gqlErrs graphql.Errors
+	if !errors.As(err, &gqlErrs) {
+		return nil
+	}
+	for _, e := range gqlErrs {
+		if code, ok := e.Extensions["code"].(string); ok {
+			if kind := codeErrorKind(code); kind != nil {
+				return kind
+			}
+		}
+		if kind := messageErrorKind(e.Message); kind != nil {
+			return kind
+		}
+	}
+	return nil
+}
+
+// statusErrorKind maps an HTTP status code to a typed error.
+func statusErrorKind(status int) error {
+	switch status {
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrValidation
+	}
+	return nil
+}
+
+func codeErrorKind(code string) error {
+	switch strings.ToUpper(code) {
+	case "UNAUTHENTICATED", "UNAUTHORIZED":
+		return ErrUnauthorized
+	case "FORBIDDEN", "PERMISSION_DENIED":
+		return ErrForbidden
+	case "NOT_FOUND":
+		return ErrNotFound
+	case "RATE_LIMITED", "TOO_MANY_REQUESTS":
+		return ErrRateLimited
+	case "BAD_USER_INPUT", "GRAPHQL_VALIDATION_FAILED", "GRAPHQL_PARSE_FAILED", "VALIDATION_ERROR":
+		return ErrValidation
+	}
+	return nil
+}
+
+func messageErrorKind(message string) error {
+	msg := strings.ToLower(message)
+	switch {
+	case strings.HasPrefix(msg, "401 unauthorized"), strings.Contains(msg, "unauthenticated"):
+		return ErrUnauthorized
+	case strings.Contains(msg, "permission denied"), strings.HasPrefix(msg, "forbidden"):
+		return ErrForbidden
+	case strings.HasSuffix(msg, "not found"):
+		return ErrNotFound
+	case strings.Contains(msg, "rate limit"), strings.Contains(msg, "too many requests"):
+		return ErrRateLimited
+	}
+	return nil
+}
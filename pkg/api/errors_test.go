@@ -0,0 +1,76 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hasura/go-graphql-client"
+
+	"github.com/zeabur/cli/pkg/api"
+)
+
+func TestClient_TypedErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"http 401", http.StatusUnauthorized, `unauthorized`, api.ErrUnauthorized},
+		{"http 403", http.StatusForbidden, `forbidden`, api.ErrForbidden},
+		{"code not found", http.StatusOK, `{"errors":[{"message":"project is gone","extensions":{"code":"NOT_FOUND"}}]}`, api.ErrNotFound},
+		{"code validation", http.StatusOK, `{"errors":[{"message":"bad","extensions":{"code":"BAD_USER_INPUT"}}]}`, api.ErrValidation},
+		{"code forbidden", http.StatusOK, `{"errors":[{"message":"nope","extensions":{"code":"FORBIDDEN"}}]}`, api.ErrForbidden},
+		{"message not found", http.StatusOK, `{"errors":[{"message":"service not found"}]}`, api.ErrNotFound},
+		{"message permission", http.StatusOK, `{"errors":[{"message":"Permission denied"}]}`, api.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			c := api.NewWithEndpoints("token", api.Endpoints{ServerURL: srv.URL})
+			_, err := c.GetUserInfo(context.Background())
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("want %v, got %v", tt.want, err)
+			}
+			// the GraphQL errors stay reachable for callers that need details
+			var gqlErrs graphql.Errors
+			if !errors.As(err, &gqlErrs) {
+				t.Fatalf("want graphql.Errors in the chain, got %T", err)
+			}
+		})
+	}
+}
+
+func TestClient_UnclassifiedError(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"errors":[{"message":"something broke"}]}`))
+	}))
+	defer srv.Close()
+
+	c := api.NewWithEndpoints("token", api.Endpoints{ServerURL: srv.URL})
+	_, err := c.GetUserInfo(context.Background())
+	if err == nil {
+		t.Fatal("want an error")
+	}
+	for _, typed := range []error{api.ErrNotFound, api.ErrUnauthorized, api.ErrForbidden, api.ErrRateLimited, api.ErrValidation} {
+		if errors.Is(err, typed) {
+			t.Fatalf("error %v should not be %v", err, typed)
+		}
+	}
+}
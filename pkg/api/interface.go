@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/zeabur/cli/pkg/model"
@@ -25,6 +26,7 @@ type Client interface {
 	ZSendAPI
 	RegisteredDomainAPI
 	FileAPI
+	RawAPI
 }
 
 type (
@@ -130,8 +132,11 @@ type (
 		GetRuntimeLogs(ctx context.Context, serviceID, environmentID, deploymentID string) (model.Logs, error)
 		GetBuildLogs(ctx context.Context, deploymentID string) (model.Logs, error)
 
-		WatchRuntimeLogs(ctx context.Context, projectID, serviceID, environmentID, deploymentID string) (<-chan model.Log, error)
-		WatchBuildLogs(ctx context.Context, projectID, deploymentID string) (<-chan model.Log, error)
+		// Watch* stream logs until ctx is done or the stream ends. Both
+		// channels are closed at the end; the error channel carries the
+		// error that ended the stream, if any.
+		WatchRuntimeLogs(ctx context.Context, projectID, serviceID, environmentID, deploymentID string) (<-chan model.Log, <-chan error)
+		WatchBuildLogs(ctx context.Context, projectID, deploymentID string) (<-chan model.Log, <-chan error)
 	}
 
 	GitAPI interface {
@@ -197,6 +202,14 @@ type (
 		PullUploadFiles(ctx context.Context, uploadID string, targetDir string) (int, int, error)
 	}
 
+	// RawAPI sends hand-written requests, for what the typed methods do not
+	// cover (see `zeabur api`).
+	RawAPI interface {
+		ExecRaw(ctx context.Context, query string, variables map[string]any) (json.RawMessage, error)
+		SubscribeRaw(ctx context.Context, query string, variables map[string]any, handler func(json.RawMessage) error) error
+		ZSendREST(ctx context.Context, apiKey, method, path string, body json.RawMessage) (json.RawMessage, error)
+	}
+
 	ZSendAPI interface {
 		GetZSendOnboardingStatus(ctx context.Context) (*model.ZSendOnboardingStatus, error)
 		GetZSendUserStatus(ctx context.Context) (*model.ZSendUserStatus, error)
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hasura/go-graphql-client"
 	"github.com/hasura/go-graphql-client/pkg/jsonutil"
+	"github.com/zeabur/cli/pkg/api/logstream"
 	"github.com/zeabur/cli/pkg/model"
 )
 
@@ -60,147 +62,110 @@ func (c *client) GetBuildLogs(ctx context.Context, deploymentID string) (model.L
 	return query.Logs, nil
 }
 
-func (c *client) WatchRuntimeLogs(ctx context.Context, projectID, serviceID, environmentID, deploymentID string) (<-chan model.Log, error) {
-	if deploymentID != "" {
-		return c.watchRuntimeLogsWithDeployment(projectID, serviceID, environmentID, deploymentID)
-	}
-	return c.watchRuntimeLogs(projectID, serviceID, environmentID)
-}
-
-func (c *client) watchRuntimeLogs(projectID, serviceID, environmentID string) (<-chan model.Log, error) {
-	logs := make(chan model.Log, 100)
-	subClient := c.sub
-
-	type subscription struct {
-		Log model.Log `graphql:"runtimeLogReceived(projectID: $projectID, serviceID: $serviceID, environmentID: $environmentID)"`
+// WatchRuntimeLogs streams runtime logs, reconnecting after websocket drops
+// and backfilling the entries missed meanwhile (see logstream.Run).
+func (c *client) WatchRuntimeLogs(ctx context.Context, projectID, serviceID, environmentID, deploymentID string) (<-chan model.Log, <-chan error) {
+	backfill := func(ctx context.Context) (model.Logs, error) {
+		return c.GetRuntimeLogs(ctx, serviceID, environmentID, deploymentID)
 	}
 
-	sub := subscription{}
-
-	_, err := subClient.Subscribe(&sub, V{
-		"projectID":     ObjectID(projectID),
-		"serviceID":     ObjectID(serviceID),
-		"environmentID": ObjectID(environmentID),
-	}, func(dataValue []byte, errValue error) error {
-		if errValue != nil {
-			fmt.Println(errValue)
-			return nil
-		}
-
-		if dataValue == nil {
-			return nil
-		}
-
-		data := subscription{}
-		err := jsonutil.UnmarshalGraphQL(dataValue, &data)
-		if err != nil {
-			fmt.Println(err)
-			return nil
+	if deploymentID != "" {
+		type subscription struct {
+			Log model.Log `graphql:"runtimeLogReceived(projectID: $projectID, serviceID: $serviceID, environmentID: $environmentID, deploymentID: $deploymentID)"`
 		}
 
-		logs <- data.Log
-		return nil
-	})
-	if err != nil {
-		return nil, err
+		return logstream.Run(ctx, logstream.Source{
+			Connect: c.subscribeLogs(&subscription{}, V{
+				"projectID":     ObjectID(projectID),
+				"serviceID":     ObjectID(serviceID),
+				"environmentID": ObjectID(environmentID),
+				"deploymentID":  ObjectID(deploymentID),
+			}, func(data []byte) (model.Log, error) {
+				var s subscription
+				err := jsonutil.UnmarshalGraphQL(data, &s)
+				return s.Log, err
+			}),
+			Backfill: backfill,
+		}, logstream.DefaultPolicy)
 	}
 
-	go func() {
-		defer close(logs)
-		_ = subClient.Run()
-	}()
-
-	return logs, nil
-}
-
-func (c *client) watchRuntimeLogsWithDeployment(projectID, serviceID, environmentID, deploymentID string) (<-chan model.Log, error) {
-	logs := make(chan model.Log, 100)
-	subClient := c.sub
-
 	type subscription struct {
-		Log model.Log `graphql:"runtimeLogReceived(projectID: $projectID, serviceID: $serviceID, environmentID: $environmentID, deploymentID: $deploymentID)"`
-	}
-
-	sub := subscription{}
-
-	_, err := subClient.Subscribe(&sub, V{
-		"projectID":     ObjectID(projectID),
-		"serviceID":     ObjectID(serviceID),
-		"environmentID": ObjectID(environmentID),
-		"deploymentID":  ObjectID(deploymentID),
-	}, func(dataValue []byte, errValue error) error {
-		if errValue != nil {
-			fmt.Println(errValue)
-			return nil
-		}
-
-		if dataValue == nil {
-			return nil
-		}
-
-		data := subscription{}
-		err := jsonutil.UnmarshalGraphQL(dataValue, &data)
-		if err != nil {
-			fmt.Println(err)
-			return nil
-		}
-
-		logs <- data.Log
-		return nil
-	})
-	if err != nil {
-		return nil, err
+		Log model.Log `graphql:"runtimeLogReceived(projectID: $projectID, serviceID: $serviceID, environmentID: $environmentID)"`
 	}
 
-	go func() {
-		defer close(logs)
-		_ = subClient.Run()
-	}()
-
-	return logs, nil
+	return logstream.Run(ctx, logstream.Source{
+		Connect: c.subscribeLogs(&subscription{}, V{
+			"projectID":     ObjectID(projectID),
+			"serviceID":     ObjectID(serviceID),
+			"environmentID": ObjectID(environmentID),
+		}, func(data []byte) (model.Log, error) {
+			var s subscription
+			err := jsonutil.UnmarshalGraphQL(data, &s)
+			return s.Log, err
+		}),
+		Backfill: backfill,
+	}, logstream.DefaultPolicy)
 }
 
-func (c *client) WatchBuildLogs(ctx context.Context, projectID, deploymentID string) (<-chan model.Log, error) {
-	logs := make(chan model.Log, 100)
-	subClient := c.sub
-
+// WatchBuildLogs streams build logs, reconnecting after websocket drops and
+// backfilling the entries missed meanwhile (see logstream.Run).
+func (c *client) WatchBuildLogs(ctx context.Context, projectID, deploymentID string) (<-chan model.Log, <-chan error) {
 	type subscription struct {
 		Log model.Log `graphql:"buildLogReceived(projectID: $projectID, deploymentID: $deploymentID)"`
 	}
 
-	sub := subscription{}
-
-	_, err := subClient.Subscribe(&sub, V{
-		"projectID":    ObjectID(projectID),
-		"deploymentID": ObjectID(deploymentID),
-	}, func(dataValue []byte, errValue error) error {
-		if errValue != nil {
-			fmt.Println(errValue)
-			return nil
-		}
+	return logstream.Run(ctx, logstream.Source{
+		Connect: c.subscribeLogs(&subscription{}, V{
+			"projectID":    ObjectID(projectID),
+			"deploymentID": ObjectID(deploymentID),
+		}, func(data []byte) (model.Log, error) {
+			var s subscription
+			err := jsonutil.UnmarshalGraphQL(data, &s)
+			return s.Log, err
+		}),
+		Backfill: func(ctx context.Context) (model.Logs, error) {
+			return c.GetBuildLogs(ctx, deploymentID)
+		},
+	}, logstream.DefaultPolicy)
+}
 
-		if dataValue == nil {
+// subscribeLogs returns a logstream connect function that runs query on a
+// websocket connection of its own, so concurrent watches do not tear each
+// other down when one of them reconnects.
+func (c *client) subscribeLogs(query any, variables V, decode func(data []byte) (model.Log, error)) func(context.Context, func(model.Log)) error {
+	return func(ctx context.Context, emit func(model.Log)) error {
+		sub := NewSubscriptionClient(c.endpoints.WebsocketURL, c.token).
+			// hand every connection error to logstream, which decides
+			// whether to reconnect, instead of retrying here
+			OnError(func(_ *graphql.SubscriptionClient, err error) error {
+				return err
+			})
+		defer func() { _ = sub.Close() }()
+
+		_, err := sub.Subscribe(query, variables, func(data []byte, errValue error) error {
+			if errValue != nil {
+				// a GraphQL error about the subscription itself
+				return logstream.Terminal(errValue)
+			}
+			if data == nil {
+				return nil
+			}
+
+			log, err := decode(data)
+			if err != nil {
+				return logstream.Terminal(fmt.Errorf("decode log: %w", err))
+			}
+			emit(log)
 			return nil
-		}
-
-		data := subscription{}
-		err := jsonutil.UnmarshalGraphQL(dataValue, &data)
+		})
 		if err != nil {
-			fmt.Println(err)
-			return nil
+			return err
 		}
 
-		logs <- data.Log
-		return nil
-	})
-	if err != nil {
-		return nil, err
+		err = sub.RunWithContext(ctx)
+		if err != nil && sub.IsUnauthorized(err) {
+			return logstream.Terminal(err)
+		}
+		return err
 	}
-
-	go func() {
-		defer close(logs)
-		_ = subClient.Run()
-	}()
-
-	return logs, nil
 }
@@ -0,0 +1,206 @@
+// Package logstream keeps a log subscription alive across websocket drops.
+//
+// A Source connects once and emits logs until the connection ends. Run
+// reconnects it with exponential backoff, backfills the entries missed
+// while disconnected, drops the ones already delivered, and reports the
+// error that finally ends the stream on a separate channel.
+package logstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// Source is one log subscription.
+type Source struct {
+	// Connect subscribes and calls emit for every log until the connection
+	// ends. It returns nil when the server completes the subscription (the
+	// stream is over), an error wrapped with Terminal when reconnecting
+	// cannot help, and any other error when the connection dropped.
+	Connect func(ctx context.Context, emit func(model.Log)) error
+
+	// Backfill, if set, fetches recent logs after a reconnect so entries
+	// emitted while disconnected are not lost.
+	Backfill func(ctx context.Context) (model.Logs, error)
+}
+
+// Policy controls reconnection.
+type Policy struct {
+	InitialBackoff time.Duration // delay before the first reconnect
+	MaxBackoff     time.Duration // cap of the doubling delay
+	MaxAttempts    int           // consecutive failed reconnects before giving up
+}
+
+// DefaultPolicy retries for roughly five minutes of consecutive failures.
+var DefaultPolicy = Policy{
+	InitialBackoff: time.Second,
+	MaxBackoff:     30 * time.Second,
+	MaxAttempts:    12,
+}
+
+// terminalError marks an error reconnecting cannot fix, such as an invalid
+// token or a GraphQL error about the subscription itself.
+type terminalError struct{ err error }
+
+func (e terminalError) Error() string { return e.err.Error() }
+func (e terminalError) Unwrap() error { return e.err }
+
+// Terminal wraps err so Run reports it instead of reconnecting.
+func Terminal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return terminalError{err: err}
+}
+
+// IsTerminal reports whether err was wrapped with Terminal.
+func IsTerminal(err error) bool {
+	var t terminalError
+	return errors.As(err, &t)
+}
+
+// Run streams logs from src until ctx is done, the server completes the
+// subscription, or a terminal error occurs. Both channels are closed when
+// the stream ends; the error channel receives at most one error, and none
+// when the stream ended because ctx was cancelled or the server completed
+// it.
+func Run(ctx context.Context, src Source, policy Policy) (<-chan model.Log, <-chan error) {
+	logs := make(chan model.Log, 100)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(logs)
+
+		var d dedup
+		emit := func(l model.Log) {
+			if d.seen(l) {
+				return
+			}
+			select {
+			case logs <- l:
+			case <-ctx.Done():
+			}
+		}
+
+		failures := 0
+		for {
+			delivered := d.count()
+			err := src.Connect(ctx, emit)
+			if ctx.Err() != nil {
+				return
+			}
+			if err == nil {
+				return
+			}
+			if IsTerminal(err) {
+				errs <- err
+				return
+			}
+
+			// a connection that delivered logs was healthy; start over
+			if d.count() > delivered {
+				failures = 0
+			}
+			failures++
+			if failures > policy.MaxAttempts {
+				errs <- fmt.Errorf("log stream lost after %d reconnect attempts: %w", policy.MaxAttempts, err)
+				return
+			}
+
+			select {
+			case <-time.After(policy.backoff(failures)):
+			case <-ctx.Done():
+				return
+			}
+
+			// from here on, the server may send entries we already have
+			d.resume()
+			if src.Backfill != nil {
+				backlog, err := src.Backfill(ctx)
+				if err != nil {
+					// the subscription itself may still work; try it
+					continue
+				}
+				sort.SliceStable(backlog, func(i, j int) bool {
+					return backlog[i].Timestamp.Before(backlog[j].Timestamp)
+				})
+				for _, l := range backlog {
+					emit(*l)
+				}
+			}
+		}
+	}()
+
+	return logs, errs
+}
+
+// backoff returns the delay before the attempt-th consecutive reconnect:
+// doubling from InitialBackoff up to MaxBackoff, with up to 20% jitter so
+// many CLIs dropped at once do not reconnect in lockstep.
+func (p Policy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, p.MaxBackoff)
+	if d <= 0 {
+		return 0
+	}
+	return d - time.Duration(rand.Int64N(int64(d)/5+1))
+}
+
+// dedup drops logs already delivered once the stream resumed after a
+// reconnect: anything older than the newest delivered entry, and repeats
+// of the entries sharing its timestamp. Before the first reconnect every
+// log passes, so entries arriving slightly out of order are kept.
+type dedup struct {
+	mu        sync.Mutex
+	resumed   bool
+	delivered int
+
+	latest   time.Time
+	atLatest map[string]struct{} // messages delivered with timestamp latest
+}
+
+func (d *dedup) seen(l model.Log) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch {
+	case d.resumed && l.Timestamp.Before(d.latest):
+		return true
+	case l.Timestamp.Equal(d.latest):
+		if _, ok := d.atLatest[l.Message]; ok && d.resumed {
+			return true
+		}
+		if d.atLatest == nil {
+			d.atLatest = map[string]struct{}{}
+		}
+		d.atLatest[l.Message] = struct{}{}
+	case l.Timestamp.After(d.latest):
+		d.latest = l.Timestamp
+		d.atLatest = map[string]struct{}{l.Message: {}}
+	}
+	d.delivered++
+	return false
+}
+
+func (d *dedup) resume() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.resumed = true
+}
+
+func (d *dedup) count() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.delivered
+}
@@ -0,0 +1,161 @@
+package logstream_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zeabur/cli/pkg/api/logstream"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+var fastPolicy = logstream.Policy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, MaxAttempts: 3}
+
+var t0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func at(sec int, msg string) model.Log {
+	return model.Log{Timestamp: t0.Add(time.Duration(sec) * time.Second), Message: msg}
+}
+
+// drain collects every log and the final error of a stream.
+func drain(t *testing.T, logs <-chan model.Log, errs <-chan error) ([]string, error) {
+	t.Helper()
+
+	var got []string
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case l, ok := <-logs:
+			if !ok {
+				return got, <-errs
+			}
+			got = append(got, l.Message)
+		case <-timeout:
+			t.Fatal("stream did not end")
+		}
+	}
+}
+
+// connections returns a Connect function whose n-th call emits the n-th
+// batch and then returns the n-th error.
+func connections(batches [][]model.Log, results []error) func(context.Context, func(model.Log)) error {
+	call := 0
+	return func(_ context.Context, emit func(model.Log)) error {
+		i := call
+		call++
+		if i < len(batches) {
+			for _, l := range batches[i] {
+				emit(l)
+			}
+		}
+		if i < len(results) {
+			return results[i]
+		}
+		return nil
+	}
+}
+
+func TestRun_ReconnectsAndDeduplicates(t *testing.T) {
+	dropped := errors.New("websocket closed")
+	src := logstream.Source{
+		Connect: connections([][]model.Log{
+			{at(1, "a"), at(2, "b")},
+			// the server resends what it had buffered around the drop
+			{at(2, "b"), at(3, "d"), at(4, "e")},
+		}, []error{dropped, nil}),
+		Backfill: func(context.Context) (model.Logs, error) {
+			c, b, a := at(3, "c"), at(2, "b"), at(1, "a")
+			return model.Logs{&c, &b, &a}, nil
+		},
+	}
+
+	logs, errs := logstream.Run(context.Background(), src, fastPolicy)
+	got, err := drain(t, logs, errs)
+	if err != nil {
+		t.Fatalf("stream error = %v, want nil after the server completed it", err)
+	}
+	want := []string{"a", "b", "c", "d", "e"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestRun_KeepsOutOfOrderLogsBeforeAReconnect(t *testing.T) {
+	src := logstream.Source{
+		Connect: connections([][]model.Log{{at(2, "b"), at(1, "a"), at(2, "b2")}}, nil),
+	}
+
+	logs, errs := logstream.Run(context.Background(), src, fastPolicy)
+	got, _ := drain(t, logs, errs)
+	if len(got) != 3 {
+		t.Fatalf("got %v, want all three entries", got)
+	}
+}
+
+func TestRun_TerminalError(t *testing.T) {
+	unauthorized := errors.New("unauthorized")
+	calls := 0
+	src := logstream.Source{
+		Connect: func(context.Context, func(model.Log)) error {
+			calls++
+			return logstream.Terminal(unauthorized)
+		},
+	}
+
+	logs, errs := logstream.Run(context.Background(), src, fastPolicy)
+	_, err := drain(t, logs, errs)
+	if !errors.Is(err, unauthorized) {
+		t.Fatalf("stream error = %v, want %v", err, unauthorized)
+	}
+	if calls != 1 {
+		t.Fatalf("connected %d times, want no reconnect after a terminal error", calls)
+	}
+}
+
+func TestRun_GivesUpAfterMaxAttempts(t *testing.T) {
+	dropped := errors.New("connection refused")
+	calls := 0
+	src := logstream.Source{
+		Connect: func(context.Context, func(model.Log)) error {
+			calls++
+			return dropped
+		},
+	}
+
+	logs, errs := logstream.Run(context.Background(), src, fastPolicy)
+	_, err := drain(t, logs, errs)
+	if !errors.Is(err, dropped) {
+		t.Fatalf("stream error = %v, want it to wrap %v", err, dropped)
+	}
+	if calls != fastPolicy.MaxAttempts+1 {
+		t.Fatalf("connected %d times, want %d", calls, fastPolicy.MaxAttempts+1)
+	}
+}
+
+func TestRun_ContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	src := logstream.Source{
+		Connect: func(ctx context.Context, emit func(model.Log)) error {
+			emit(at(1, "a"))
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	logs, errs := logstream.Run(ctx, src, fastPolicy)
+	if l := <-logs; l.Message != "a" {
+		t.Fatalf("first log = %q", l.Message)
+	}
+	cancel()
+
+	got, err := drain(t, logs, errs)
+	if len(got) != 0 || err != nil {
+		t.Fatalf("after cancel got %v, %v; want a clean end", got, err)
+	}
+}
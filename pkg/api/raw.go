@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hasura/go-graphql-client"
+)
+
+// ExecRaw runs a hand-written GraphQL query or mutation and returns the
+// "data" of the response. Variables are sent as is, so their types come
+// from the declarations in query.
+func (c *client) ExecRaw(ctx context.Context, query string, variables map[string]any) (json.RawMessage, error) {
+	data, err := c.Client.ExecRaw(ctx, query, variables)
+	return data, classifyError(err)
+}
+
+// SubscribeRaw runs a hand-written GraphQL subscription and hands the data of
+// every event to handler, until ctx is done, the server ends the
+// subscription or handler returns an error.
+func (c *client) SubscribeRaw(ctx context.Context, query string, variables map[string]any, handler func(json.RawMessage) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu         sync.Mutex
+		handlerErr error
+	)
+	stop := func(err error) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if handlerErr == nil {
+			handlerErr = err
+		}
+		cancel()
+		return graphql.ErrSubscriptionStopped
+	}
+
+	sub := NewSubscriptionClient(c.endpoints.WebsocketURL, c.token).
+		OnError(func(_ *graphql.SubscriptionClient, err error) error {
+			return err
+		}).
+		// the subscription is the only one on this client, so its
+		// completion is the end of the run
+		WithExitWhenNoSubscription(true)
+	defer func() { _ = sub.Close() }()
+
+	_, err := sub.Exec(query, variables, func(data []byte, errValue error) error {
+		if errValue != nil {
+			return stop(classifyError(errValue))
+		}
+		if data == nil {
+			return nil
+		}
+		if err := handler(data); err != nil {
+			return stop(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("subscribe: %w", err)
+	}
+
+	err = sub.RunWithContext(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if handlerErr != nil {
+		return handlerErr
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		if sub.IsUnauthorized(err) {
+			return &apiError{kind: ErrUnauthorized, err: err}
+		}
+		return err
+	}
+	return nil
+}
+
+// ZSendREST sends a request to the Z-Send REST API, authenticated with
+// apiKey, and returns the response body. path is relative to the REST API
+// root, e.g. "/emails".
+func (c *client) ZSendREST(ctx context.Context, apiKey, method, path string, body json.RawMessage) (json.RawMessage, error) {
+	var payload any
+	if len(body) > 0 {
+		payload = body
+	}
+	data, _, err := c.zsendDo(ctx, apiKey, method, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -12,8 +12,6 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/spf13/viper"
-	"github.com/zeabur/cli/pkg/constant"
 	"github.com/zeabur/cli/pkg/model"
 	"github.com/zeabur/cli/pkg/util"
 )
@@ -376,14 +374,13 @@ func (c *client) UploadZipToService(ctx context.Context, projectID string, servi
 		return nil, fmt.Errorf("failed to marshal create upload request: %w", err)
 	}
 
-	createUploadResp, err := http.NewRequestWithContext(ctx, "POST", constant.ZeaburServerURL+"/v2/upload", bytes.NewReader(createUploadBody))
+	createUploadResp, err := http.NewRequestWithContext(ctx, "POST", c.endpoints.ServerURL+"/v2/upload", bytes.NewReader(createUploadBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create upload request: %w", err)
 	}
 
-	token := viper.GetString("token")
 	createUploadResp.Header.Set("Content-Type", "application/json")
-	createUploadResp.Header.Set("Cookie", "token="+token)
+	createUploadResp.Header.Set("Cookie", "token="+c.token)
 
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(createUploadResp)
@@ -450,14 +447,14 @@ func (c *client) UploadZipToService(ctx context.Context, projectID string, servi
 	}
 
 	prepareResp, err := http.NewRequestWithContext(ctx, "POST",
-		fmt.Sprintf("%s/v2/upload/%s/prepare", constant.ZeaburServerURL, uploadSession.UploadID),
+		fmt.Sprintf("%s/v2/upload/%s/prepare", c.endpoints.ServerURL, uploadSession.UploadID),
 		bytes.NewReader(prepareBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create prepare request: %w", err)
 	}
 
 	prepareResp.Header.Set("Content-Type", "application/json")
-	prepareResp.Header.Set("Cookie", "token="+token)
+	prepareResp.Header.Set("Cookie", "token="+c.token)
 
 	resp, err = client.Do(prepareResp)
 	if err != nil {
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryOptions controls how the transport of NewRetryTransport retries failed requests.
+type RetryOptions struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry; it doubles with
+	// every further retry and is jittered.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff. A Retry-After longer than MaxDelay is not
+	// waited out: the response is returned as is.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryOptions are the retry options of the API client.
+var DefaultRetryOptions = RetryOptions{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+// retryableStatus are the responses worth another attempt: the server was
+// busy or a proxy in front of it failed.
+var retryableStatus = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+type retryTransport struct {
+	base http.RoundTripper
+	opts RetryOptions
+}
+
+// NewRetryTransport wraps base with retries of transient failures.
+//
+// Only idempotent requests - GraphQL queries, requests with an idempotent
+// HTTP method or an Idempotency-Key header - are retried after network
+// errors and 502/503/504 responses, since a mutation may already have been
+// applied. A 429 means the request was rejected before being processed, so
+// it is retried whatever the request.
+func NewRetryTransport(base http.RoundTripper, opts RetryOptions) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryTransport{base: base, opts: opts}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// buffer the body so that it can be replayed
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	idempotent := isIdempotent(req, body)
+
+	for attempt := 0; ; attempt++ {
+		r := req
+		if body != nil {
+			r = req.Clone(req.Context())
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(body)), nil
+			}
+		}
+
+		resp, err := t.base.RoundTrip(r)
+		if attempt >= t.opts.MaxRetries || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		var delay time.Duration
+		switch {
+		case err != nil:
+			if !idempotent {
+				return nil, err
+			}
+			delay = t.backoff(attempt)
+		case retryableStatus[resp.StatusCode]:
+			if !idempotent && resp.StatusCode != http.StatusTooManyRequests {
+				return resp, nil
+			}
+			delay = t.backoff(attempt)
+			if after, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+				if after > t.opts.MaxDelay {
+					return resp, nil
+				}
+				delay = after
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		default:
+			return resp, nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns a full-jitter exponential backoff for the given attempt.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	d := t.opts.BaseDelay << attempt
+	if d <= 0 || d > t.opts.MaxDelay {
+		d = t.opts.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryAfter parses a Retry-After header, in seconds or as an HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// isIdempotent reports whether req can safely be sent again.
+func isIdempotent(req *http.Request, body []byte) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	if req.Header.Get("Idempotency-Key") != "" {
+		return true
+	}
+	return isGraphQLQuery(body)
+}
+
+// isGraphQLQuery reports whether body is a GraphQL request whose operation
+// is a query. Anything that cannot be told apart is treated as a mutation.
+func isGraphQLQuery(body []byte) bool {
+	var payload struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Query == "" {
+		return false
+	}
+	op := strings.TrimSpace(payload.Query)
+	return strings.HasPrefix(op, "{") || strings.HasPrefix(op, "query")
+}
@@ -0,0 +1,101 @@
+package api_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zeabur/cli/pkg/api"
+)
+
+var fastRetry = api.RetryOptions{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 50 * time.Millisecond}
+
+// flakyServer fails the first `failures` requests with status, then succeeds.
+func flakyServer(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		// every attempt must carry the full body again
+		if body, _ := io.ReadAll(r.Body); len(body) == 0 {
+			t.Errorf("attempt %d: empty body", n)
+		}
+		if n <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func post(t *testing.T, url, body string) *http.Response {
+	t.Helper()
+
+	c := &http.Client{Transport: api.NewRetryTransport(nil, fastRetry)}
+	resp, err := c.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	_ = resp.Body.Close()
+	return resp
+}
+
+func TestRetryTransport_RetriesQueries(t *testing.T) {
+	t.Parallel()
+
+	srv, calls := flakyServer(t, 2, http.StatusServiceUnavailable, nil)
+	resp := post(t, srv.URL, `{"query":"query { me { _id } }"}`)
+	if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Fatalf("want 200 after 3 calls, got %d after %d", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestRetryTransport_DoesNotRetryMutations(t *testing.T) {
+	t.Parallel()
+
+	srv, calls := flakyServer(t, 1, http.StatusBadGateway, nil)
+	resp := post(t, srv.URL, `{"query":"mutation { deleteService(_id: \"x\") }"}`)
+	if resp.StatusCode != http.StatusBadGateway || calls.Load() != 1 {
+		t.Fatalf("want 502 after 1 call, got %d after %d", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestRetryTransport_RetriesRateLimitedMutations(t *testing.T) {
+	t.Parallel()
+
+	srv, calls := flakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"0"}})
+	resp := post(t, srv.URL, `{"query":"mutation { deleteService(_id: \"x\") }"}`)
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Fatalf("want 200 after 2 calls, got %d after %d", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestRetryTransport_GivesUpOnLongRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	srv, calls := flakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"3600"}})
+	resp := post(t, srv.URL, `{"query":"query { me { _id } }"}`)
+	if resp.StatusCode != http.StatusTooManyRequests || calls.Load() != 1 {
+		t.Fatalf("want 429 after 1 call, got %d after %d", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestRetryTransport_StopsAfterMaxRetries(t *testing.T) {
+	t.Parallel()
+
+	srv, calls := flakyServer(t, 100, http.StatusGatewayTimeout, nil)
+	resp := post(t, srv.URL, `{"query":"{ me { _id } }"}`)
+	if resp.StatusCode != http.StatusGatewayTimeout || calls.Load() != int32(fastRetry.MaxRetries+1) {
+		t.Fatalf("want 504 after %d calls, got %d after %d", fastRetry.MaxRetries+1, resp.StatusCode, calls.Load())
+	}
+}
@@ -11,13 +11,13 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/zeabur/cli/pkg/constant"
 	"github.com/zeabur/cli/pkg/model"
 )
 
-const zsendRESTBase = constant.ZeaburServerURL + "/api/v1/zsend"
+// zsendRESTPath is the Z-Send REST API path, relative to the API base URL.
+const zsendRESTPath = "/api/v1/zsend"
 
-func zsendDo(ctx context.Context, apiKey, method, path string, body any) ([]byte, int, error) {
+func (c *client) zsendDo(ctx context.Context, apiKey, method, path string, body any) ([]byte, int, error) {
 	var bodyReader io.Reader
 	if body != nil {
 		b, err := json.Marshal(body)
@@ -27,7 +27,7 @@ func zsendDo(ctx context.Context, apiKey, method, path string, body any) ([]byte
 		bodyReader = bytes.NewReader(b)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, zsendRESTBase+path, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoints.ServerURL+zsendRESTPath+path, bodyReader)
 	if err != nil {
 		return nil, 0, fmt.Errorf("create request: %w", err)
 	}
@@ -36,8 +36,8 @@ func zsendDo(ctx context.Context, apiKey, method, path string, body any) ([]byte
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	c := &http.Client{Timeout: 30 * time.Second}
-	resp, err := c.Do(req)
+	httpClient := &http.Client{Timeout: 30 * time.Second, Transport: NewRetryTransport(nil, DefaultRetryOptions)}
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, 0, fmt.Errorf("do request: %w", err)
 	}
@@ -52,17 +52,21 @@ func zsendDo(ctx context.Context, apiKey, method, path string, body any) ([]byte
 		var errResp struct {
 			Error string `json:"error"`
 		}
+		err := fmt.Errorf("API error (%d)", resp.StatusCode)
 		if jsonErr := json.Unmarshal(data, &errResp); jsonErr == nil && errResp.Error != "" {
-			return nil, resp.StatusCode, fmt.Errorf("API error (%d): %s", resp.StatusCode, errResp.Error)
+			err = fmt.Errorf("API error (%d): %s", resp.StatusCode, errResp.Error)
 		}
-		return nil, resp.StatusCode, fmt.Errorf("API error (%d)", resp.StatusCode)
+		if kind := statusErrorKind(resp.StatusCode); kind != nil {
+			err = &apiError{kind: kind, err: err}
+		}
+		return nil, resp.StatusCode, err
 	}
 
 	return data, resp.StatusCode, nil
 }
 
 func (c *client) SendZSendEmail(ctx context.Context, apiKey string, req model.ZSendSendEmailRequest) (*model.ZSendSendEmailReply, error) {
-	data, _, err := zsendDo(ctx, apiKey, http.MethodPost, "/emails", req)
+	data, _, err := c.zsendDo(ctx, apiKey, http.MethodPost, "/emails", req)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +78,7 @@ func (c *client) SendZSendEmail(ctx context.Context, apiKey string, req model.ZS
 }
 
 func (c *client) ScheduleZSendEmail(ctx context.Context, apiKey string, req model.ZSendScheduleEmailRequest) (*model.ZSendScheduleEmailReply, error) {
-	data, _, err := zsendDo(ctx, apiKey, http.MethodPost, "/emails/schedule", req)
+	data, _, err := c.zsendDo(ctx, apiKey, http.MethodPost, "/emails/schedule", req)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +90,7 @@ func (c *client) ScheduleZSendEmail(ctx context.Context, apiKey string, req mode
 }
 
 func (c *client) SendZSendBatchEmail(ctx context.Context, apiKey string, req model.ZSendBatchEmailRequest) (*model.ZSendBatchEmailReply, error) {
-	data, _, err := zsendDo(ctx, apiKey, http.MethodPost, "/emails/batch", req)
+	data, _, err := c.zsendDo(ctx, apiKey, http.MethodPost, "/emails/batch", req)
 	if err != nil {
 		return nil, err
 	}
@@ -113,7 +117,7 @@ func (c *client) ListZSendScheduledEmails(ctx context.Context, apiKey string, pa
 		path += "?" + q.Encode()
 	}
 
-	data, _, err := zsendDo(ctx, apiKey, http.MethodGet, path, nil)
+	data, _, err := c.zsendDo(ctx, apiKey, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -125,7 +129,7 @@ func (c *client) ListZSendScheduledEmails(ctx context.Context, apiKey string, pa
 }
 
 func (c *client) GetZSendScheduledEmail(ctx context.Context, apiKey string, id string) (*model.ZSendScheduledEmail, error) {
-	data, _, err := zsendDo(ctx, apiKey, http.MethodGet, "/emails/scheduled/"+url.PathEscape(id), nil)
+	data, _, err := c.zsendDo(ctx, apiKey, http.MethodGet, "/emails/scheduled/"+url.PathEscape(id), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -137,7 +141,7 @@ func (c *client) GetZSendScheduledEmail(ctx context.Context, apiKey string, id s
 }
 
 func (c *client) CancelZSendScheduledEmail(ctx context.Context, apiKey string, id string) error {
-	_, _, err := zsendDo(ctx, apiKey, http.MethodDelete, "/emails/scheduled/"+url.PathEscape(id), nil)
+	_, _, err := c.zsendDo(ctx, apiKey, http.MethodDelete, "/emails/scheduled/"+url.PathEscape(id), nil)
 	return err
 }
 
@@ -157,7 +161,7 @@ func (c *client) ListZSendBatchEmailJobs(ctx context.Context, apiKey string, pag
 		path += "?" + q.Encode()
 	}
 
-	data, _, err := zsendDo(ctx, apiKey, http.MethodGet, path, nil)
+	data, _, err := c.zsendDo(ctx, apiKey, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -169,7 +173,7 @@ func (c *client) ListZSendBatchEmailJobs(ctx context.Context, apiKey string, pag
 }
 
 func (c *client) GetZSendBatchEmailJob(ctx context.Context, apiKey string, id string) (*model.ZSendBatchJob, error) {
-	data, _, err := zsendDo(ctx, apiKey, http.MethodGet, "/emails/batch/"+url.PathEscape(id), nil)
+	data, _, err := c.zsendDo(ctx, apiKey, http.MethodGet, "/emails/batch/"+url.PathEscape(id), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -21,8 +21,10 @@ type ImplicitFlowClient struct {
 	callbackServer *CallbackServer
 }
 
-func NewImplicitFlowClient(callbackServer *CallbackServer) *ImplicitFlowClient {
-	endpointURL, err := url.Parse(ZeaburApiKeyConfirmEndpoint)
+// NewImplicitFlowClient returns a client that confirms the API key on the
+// dashboard at dashURL.
+func NewImplicitFlowClient(dashURL string, callbackServer *CallbackServer) *ImplicitFlowClient {
+	endpointURL, err := url.Parse(dashURL + ZeaburApiKeyConfirmPath)
 	if err != nil {
 		panic(fmt.Sprintf("failed to parse endpoint URL (internal error): %v", err))
 	}
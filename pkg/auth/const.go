@@ -1,8 +1,7 @@
 package auth
 
-import "github.com/zeabur/cli/pkg/constant"
-
 // Zeabur "API Key Confirmation" endpoint constants
 const (
-	ZeaburApiKeyConfirmEndpoint = constant.ZeaburDashURL + "/auth/api-key/confirm"
+	// ZeaburApiKeyConfirmPath is relative to the dashboard URL.
+	ZeaburApiKeyConfirmPath = "/auth/api-key/confirm"
 )
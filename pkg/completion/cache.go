@@ -0,0 +1,101 @@
+// Package completion caches the candidates of dynamic shell completion, so
+// that pressing tab does not hit the API every time.
+package completion
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultTTL is how long cached candidates are served. It is short on
+// purpose: a project created a minute ago should complete.
+const DefaultTTL = time.Minute
+
+// Cache stores candidate lists under Dir, one file per key.
+type Cache struct {
+	Dir string
+	TTL time.Duration
+}
+
+type entry struct {
+	Created time.Time `json:"created"`
+	Items   []string  `json:"items"`
+}
+
+// NewCache returns a cache in dir whose entries live for ttl.
+func NewCache(dir string, ttl time.Duration) *Cache {
+	return &Cache{Dir: dir, TTL: ttl}
+}
+
+// DefaultDir returns the cache directory, zeabur/completion under the
+// user's cache directory (e.g. ~/.cache on Linux).
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "zeabur", "completion"), nil
+}
+
+// Get returns the candidates cached under key, or calls fetch and caches
+// its result when there are none or they have expired. Fetch errors are not
+// cached, and a cache that cannot be read or written only costs speed.
+//
+// key should identify everything the candidates depend on, including the
+// credentials; it is hashed before it touches the disk.
+func (c *Cache) Get(key string, fetch func() ([]string, error)) ([]string, error) {
+	path := c.path(key)
+
+	if data, err := os.ReadFile(path); err == nil {
+		var e entry
+		if json.Unmarshal(data, &e) == nil {
+			if age := time.Now().Sub(e.Created); age >= 0 && age < c.TTL {
+				return e.Items, nil
+			}
+		}
+	}
+
+	items, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.put(path, items)
+	return items, nil
+}
+
+func (c *Cache) put(path string, items []string) {
+	data, err := json.Marshal(entry{Created: time.Now(), Items: items})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.Dir, 0o700); err != nil {
+		return
+	}
+
+	// write to a temporary file first so that a concurrent completion
+	// never reads half an entry
+	tmp, err := os.CreateTemp(c.Dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:16])+".json")
+}
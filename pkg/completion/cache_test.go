@@ -0,0 +1,67 @@
+package completion_test
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/zeabur/cli/pkg/completion"
+)
+
+func TestCache_ServesUntilExpired(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	calls := 0
+	fetch := func() ([]string, error) {
+		calls++
+		return []string{"a", "b"}, nil
+	}
+
+	c := completion.NewCache(dir, time.Hour)
+	for range 2 {
+		items, err := c.Get("key", fetch)
+		if err != nil || !slices.Equal(items, []string{"a", "b"}) {
+			t.Fatalf("Get = %v, %v", items, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("fetched %d times, want 1", calls)
+	}
+
+	// another key is another entry
+	if _, err := c.Get("other", fetch); err != nil || calls != 2 {
+		t.Fatalf("fetched %d times for a new key (%v), want 2", calls, err)
+	}
+
+	// a cache whose entries have expired fetches again
+	if _, err := completion.NewCache(dir, 0).Get("key", fetch); err != nil || calls != 3 {
+		t.Fatalf("fetched %d times after expiry (%v), want 3", calls, err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Name() == "key" || e.Name() == "other" {
+			t.Fatalf("cache file %s is named after the raw key", e.Name())
+		}
+	}
+}
+
+func TestCache_DoesNotCacheErrors(t *testing.T) {
+	t.Parallel()
+
+	c := completion.NewCache(t.TempDir(), time.Hour)
+	boom := errors.New("boom")
+	if _, err := c.Get("key", func() ([]string, error) { return nil, boom }); !errors.Is(err, boom) {
+		t.Fatalf("want the fetch error, got %v", err)
+	}
+	items, err := c.Get("key", func() ([]string, error) { return []string{"ok"}, nil })
+	if err != nil || !slices.Equal(items, []string{"ok"}) {
+		t.Fatalf("Get after an error = %v, %v", items, err)
+	}
+}
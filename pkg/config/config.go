@@ -2,8 +2,12 @@
 package config
 
 import (
+	"path/filepath"
+	"strings"
+
 	"github.com/spf13/viper"
 
+	"github.com/zeabur/cli/pkg/credential"
 	"github.com/zeabur/cli/pkg/zcontext"
 )
 
@@ -14,63 +18,145 @@ const (
 	KeyUsername    = "username"
 )
 
+// Keys about API endpoints, used to point the CLI at a staging cluster or a
+// local stand-in server. Each can also be set through the matching ZEABUR_*
+// env var, e.g. ZEABUR_API_URL.
+const (
+	KeyAPIURL       = "api_url"
+	KeyWebsocketURL = "websocket_url"
+	KeyDashURL      = "dash_url"
+)
+
 // Keys about CLI behavior
 const (
 	KeyInteractive     = "interactive"
 	KeyAutoCheckUpdate = "auto_check_update"
+
+	// KeyCredentialStore selects where tokens are kept, one of
+	// credential.Backends; also settable as ZEABUR_CREDENTIAL_STORE.
+	KeyCredentialStore = "credential_store"
+
+	// KeySecretPatterns lists globs of variable keys whose values are
+	// masked on top of the built-in ones; also settable as
+	// ZEABUR_SECRET_PATTERNS, comma-separated.
+	KeySecretPatterns = "secret_patterns"
 )
 
 type Config interface {
 	GetTokenString() string // token string is the single token string, it may be set by user or our login function
 	SetTokenString(token string)
 
+	// GetCredentialStore names the backend tokens are kept in and returns
+	// the last error it reported, if any.
+	GetCredentialStore() (string, error)
+
 	GetUser() string // nickname of user
 	SetUser(user string)
 	GetUsername() string // it is kind like id of user
 	SetUsername(username string)
 
+	GetAPIURL() string       // base URL of the API, empty when not overridden
+	GetWebsocketURL() string // base URL of the subscription endpoint, empty when not overridden
+	GetDashURL() string      // base URL of the dashboard, empty when not overridden
+
+	GetSecretPatterns() []string // extra globs of variable keys whose values are masked
+
 	GetContext() zcontext.Context
 
+	// Token, user, username, workspace and context belong to the active
+	// profile; endpoints and CLI behavior are shared by all profiles.
+	GetProfile() string                         // name of the active profile, DefaultProfile unless selected
+	SetProfile(name string, persist bool) error // activate a profile; persist makes it the default for later invocations
+	ListProfiles() []string                     // DefaultProfile first, then the named profiles sorted
+	RemoveProfile(name string) error            // delete a named profile and everything stored in it
+
 	Write() error
 }
 
 type config struct {
-	ctx  zcontext.Context
-	path string
+	v       *viper.Viper
+	ctx     zcontext.Context
+	profile string
+
+	// store keeps the tokens; nil keeps them in the config file
+	store    credential.Store
+	storeErr error
+	tokens   map[string]string // profile -> token, read or set in this process
+	dirty    map[string]bool   // profiles whose token Write has to save
 }
 
 func New(path string) Config {
 	// create the config file and init viper
 	initViper(path)
 
-	return &config{
-		path: path,
-		ctx:  zcontext.NewViperContext(viper.GetViper()),
+	store, err := credential.Open(viper.GetString(KeyCredentialStore), credential.Options{
+		Dir:        filepath.Dir(path),
+		Passphrase: credential.PromptPassphrase,
+	})
+	if err != nil {
+		// never fall back to plaintext silently: every token access
+		// reports the error instead
+		store = brokenStore{name: viper.GetString(KeyCredentialStore), err: err}
 	}
+
+	c := newConfig(viper.GetViper(), store)
+	c.migrateTokens()
+	return c
 }
 
-func (c *config) GetTokenString() string {
-	return viper.GetString(KeyTokenString)
+// FromViper returns a Config backed by v, which must already hold the
+// settings, keeping tokens in store, or in v itself when store is nil. New
+// uses the global viper read from the config file; tests pass an in-memory
+// one.
+func FromViper(v *viper.Viper, store credential.Store) Config {
+	return newConfig(v, store)
 }
 
-func (c *config) SetTokenString(token string) {
-	viper.Set(KeyTokenString, token)
+func newConfig(v *viper.Viper, store credential.Store) *config {
+	c := &config{
+		v:      v,
+		store:  store,
+		tokens: map[string]string{},
+		dirty:  map[string]bool{},
+	}
+	c.activate(v.GetString(KeyActiveProfile))
+	return c
 }
 
 func (c *config) GetUser() string {
-	return viper.GetString(KeyUser)
+	return c.v.GetString(c.key(KeyUser))
 }
 
 func (c *config) SetUser(user string) {
-	viper.Set(KeyUser, user)
+	c.v.Set(c.key(KeyUser), user)
 }
 
 func (c *config) GetUsername() string {
-	return viper.GetString(KeyUsername)
+	return c.v.GetString(c.key(KeyUsername))
 }
 
 func (c *config) SetUsername(username string) {
-	viper.Set(KeyUsername, username)
+	c.v.Set(c.key(KeyUsername), username)
+}
+
+func (c *config) GetAPIURL() string {
+	return c.v.GetString(KeyAPIURL)
+}
+
+func (c *config) GetWebsocketURL() string {
+	return c.v.GetString(KeyWebsocketURL)
+}
+
+func (c *config) GetDashURL() string {
+	return c.v.GetString(KeyDashURL)
+}
+
+func (c *config) GetSecretPatterns() []string {
+	var patterns []string
+	for _, p := range c.v.GetStringSlice(KeySecretPatterns) {
+		patterns = append(patterns, strings.Split(p, ",")...)
+	}
+	return patterns
 }
 
 func (c *config) GetContext() zcontext.Context {
@@ -78,7 +164,10 @@ func (c *config) GetContext() zcontext.Context {
 }
 
 func (c *config) Write() error {
-	return viper.WriteConfig()
+	if err := c.saveTokens(); err != nil {
+		return err
+	}
+	return c.v.WriteConfig()
 }
 
 var _ Config = &config{}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/zeabur/cli/pkg/zcontext"
+)
+
+// DefaultProfile is the profile stored at the top level of the config file,
+// where every setting lived before profiles existed.
+const DefaultProfile = "default"
+
+// Keys about profiles. Named profiles are stored under profiles.<name> with
+// the same token / user / username / workspace / context keys as the top
+// level.
+const (
+	KeyActiveProfile = "active_profile"
+	KeyProfiles      = "profiles"
+)
+
+// ProfileEnv selects a profile for one invocation, like the --profile flag.
+const ProfileEnv = "ZEABUR_PROFILE"
+
+// viper lowercases keys and splits them on dots, so names are restricted
+// to what survives both.
+var profileNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateProfileName reports whether name can be used as a profile name.
+func ValidateProfileName(name string) error {
+	if !profileNameRe.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+func (c *config) GetProfile() string {
+	return c.profile
+}
+
+func (c *config) SetProfile(name string, persist bool) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	c.activate(name)
+	if persist {
+		if name == DefaultProfile {
+			c.v.Set(KeyActiveProfile, "")
+		} else {
+			c.v.Set(KeyActiveProfile, name)
+		}
+	}
+	return nil
+}
+
+func (c *config) ListProfiles() []string {
+	names := []string{DefaultProfile}
+	named := make([]string, 0)
+	// AllSettings merges the file with values set in this process, which
+	// GetStringMap does not for nested maps
+	profiles, _ := c.v.AllSettings()[KeyProfiles].(map[string]any)
+	for name := range profiles {
+		if name != DefaultProfile {
+			named = append(named, name)
+		}
+	}
+	sort.Strings(named)
+	return append(names, named...)
+}
+
+func (c *config) RemoveProfile(name string) error {
+	if name == DefaultProfile {
+		return fmt.Errorf("the %s profile cannot be removed; log out of it instead", DefaultProfile)
+	}
+
+	settings := c.v.AllSettings()
+	profiles, _ := settings[KeyProfiles].(map[string]any)
+	if _, ok := profiles[name]; !ok {
+		return fmt.Errorf("profile %q not found", name)
+	}
+	delete(profiles, name)
+	active := c.v.GetString(KeyActiveProfile) == name
+	if active {
+		delete(settings, KeyActiveProfile)
+	}
+
+	// viper cannot unset a key: reload the file layer without the profile,
+	// and shadow values set earlier in this process with the trimmed map
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	c.v.SetConfigType("yaml")
+	if err := c.v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	c.v.Set(KeyProfiles, profiles)
+	if active {
+		c.v.Set(KeyActiveProfile, "")
+	}
+
+	c.forgetToken(name)
+	if c.profile == name {
+		c.activate(DefaultProfile)
+	}
+	return nil
+}
+
+// activate points the profile-scoped keys and the context at name; an empty
+// name is the default profile.
+func (c *config) activate(name string) {
+	if name == "" {
+		name = DefaultProfile
+	}
+	c.profile = name
+	if name == DefaultProfile {
+		c.ctx = zcontext.NewViperContext(c.v)
+	} else {
+		c.ctx = zcontext.NewPrefixedViperContext(c.v, profilePrefix(name))
+	}
+}
+
+// key returns where key is stored for the active profile.
+func (c *config) key(key string) string {
+	return profileKey(c.profile, key)
+}
+
+func profileKey(profile, key string) string {
+	if profile == DefaultProfile {
+		return key
+	}
+	return profilePrefix(profile) + "." + key
+}
+
+func profilePrefix(name string) string {
+	return KeyProfiles + "." + name
+}
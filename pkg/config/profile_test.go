@@ -0,0 +1,111 @@
+package config_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/zeabur/cli/pkg/config"
+	"github.com/zeabur/cli/pkg/zcontext"
+)
+
+// TestProfiles_Isolated: each profile has its own token, user and context,
+// and the default profile keeps reading the top-level keys older configs
+// were written with.
+func TestProfiles_Isolated(t *testing.T) {
+	v := viper.New()
+	v.Set(config.KeyTokenString, "personal-token")
+	v.Set(zcontext.KeyProjectID, "p-personal")
+	c := config.FromViper(v, nil)
+
+	if c.GetProfile() != config.DefaultProfile || c.GetTokenString() != "personal-token" {
+		t.Fatalf("default profile = %s / %s, want the top-level token", c.GetProfile(), c.GetTokenString())
+	}
+
+	if err := c.SetProfile("work", false); err != nil {
+		t.Fatal(err)
+	}
+	if c.GetTokenString() != "" || !c.GetContext().GetProject().Empty() {
+		t.Fatalf("new profile sees token %q, project %v", c.GetTokenString(), c.GetContext().GetProject())
+	}
+	c.SetTokenString("work-token")
+	c.SetUsername("acme-bot")
+	c.GetContext().SetProject(zcontext.NewBasicInfo("p-work", "api"))
+
+	if got := v.GetString("profiles.work.token"); got != "work-token" {
+		t.Errorf("profiles.work.token = %q", got)
+	}
+	if got := v.GetString("profiles.work.context.project.id"); got != "p-work" {
+		t.Errorf("profiles.work.context.project.id = %q", got)
+	}
+	if got := v.GetString(config.KeyTokenString); got != "personal-token" {
+		t.Errorf("top-level token = %q, want it untouched", got)
+	}
+	if got := v.GetString(config.KeyActiveProfile); got != "" {
+		t.Errorf("a one-shot switch persisted %s = %q", config.KeyActiveProfile, got)
+	}
+
+	if err := c.SetProfile(config.DefaultProfile, false); err != nil {
+		t.Fatal(err)
+	}
+	if c.GetContext().GetProject().GetID() != "p-personal" {
+		t.Errorf("default profile project = %v", c.GetContext().GetProject())
+	}
+}
+
+func TestProfiles_PersistAndRemove(t *testing.T) {
+	v := viper.New()
+	c := config.FromViper(v, nil)
+
+	for _, name := range []string{"work", "client-b"} {
+		if err := c.SetProfile(name, false); err != nil {
+			t.Fatal(err)
+		}
+		c.SetTokenString(name + "-token")
+	}
+	if err := c.SetProfile("work", true); err != nil {
+		t.Fatal(err)
+	}
+
+	// a new process starts in the persisted profile
+	if got := config.FromViper(v, nil).GetProfile(); got != "work" {
+		t.Fatalf("restarted in profile %q, want work", got)
+	}
+	if got, want := c.ListProfiles(), []string{config.DefaultProfile, "client-b", "work"}; !slices.Equal(got, want) {
+		t.Fatalf("ListProfiles() = %v, want %v", got, want)
+	}
+
+	if err := c.RemoveProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	if c.GetProfile() != config.DefaultProfile {
+		t.Errorf("after removing the active profile, active = %q", c.GetProfile())
+	}
+	if got := config.FromViper(v, nil).GetProfile(); got != config.DefaultProfile {
+		t.Errorf("restarted in profile %q after removing it", got)
+	}
+	if got, want := c.ListProfiles(), []string{config.DefaultProfile, "client-b"}; !slices.Equal(got, want) {
+		t.Errorf("ListProfiles() = %v, want %v", got, want)
+	}
+
+	if err := c.RemoveProfile(config.DefaultProfile); err == nil {
+		t.Error("removed the default profile")
+	}
+	if err := c.RemoveProfile("missing"); err == nil {
+		t.Error("removed a profile that does not exist")
+	}
+}
+
+func TestValidateProfileName(t *testing.T) {
+	for _, name := range []string{"work", "client-b", "a_1"} {
+		if err := config.ValidateProfileName(name); err != nil {
+			t.Errorf("%q: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "Work", "a.b", "-x", "a b"} {
+		if err := config.ValidateProfileName(name); err == nil {
+			t.Errorf("%q: accepted", name)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/zeabur/cli/pkg/credential"
+)
+
+const tokenEnv = "ZEABUR_TOKEN"
+
+func (c *config) GetTokenString() string {
+	// AutomaticEnv maps ZEABUR_TOKEN to the top-level key only; let it win
+	// over every profile and credential store
+	if token := os.Getenv(tokenEnv); token != "" {
+		return token
+	}
+	if c.store == nil {
+		return c.v.GetString(c.key(KeyTokenString))
+	}
+
+	if token, ok := c.tokens[c.profile]; ok {
+		return token
+	}
+	token, err := c.store.Get(c.profile)
+	if err != nil && !errors.Is(err, credential.ErrNotFound) {
+		c.storeErr = err
+		// a token not migrated yet, because the store failed, still logs
+		// the user in
+		if plain := c.v.GetString(c.key(KeyTokenString)); plain != "" {
+			fmt.Fprintf(os.Stderr, "Warning: the %s credential store failed (%v); using the token still in the config file\n", c.store.Name(), err)
+			token = plain
+		}
+	}
+	c.tokens[c.profile] = token
+	return token
+}
+
+// SetTokenString keeps the token in memory; Write saves it to the store.
+func (c *config) SetTokenString(token string) {
+	if c.store == nil {
+		c.v.Set(c.key(KeyTokenString), token)
+		return
+	}
+	c.tokens[c.profile] = token
+	c.dirty[c.profile] = true
+}
+
+func (c *config) GetCredentialStore() (string, error) {
+	if c.store == nil {
+		return credential.BackendPlaintext, nil
+	}
+	return c.store.Name(), c.storeErr
+}
+
+// forgetToken drops the stored token of a removed profile on the next Write.
+func (c *config) forgetToken(profile string) {
+	if c.store == nil {
+		return
+	}
+	c.tokens[profile] = ""
+	c.dirty[profile] = true
+}
+
+func (c *config) saveTokens() error {
+	profiles := make([]string, 0, len(c.dirty))
+	for profile := range c.dirty {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+
+	for _, profile := range profiles {
+		var err error
+		if token := c.tokens[profile]; token == "" {
+			err = c.store.Delete(profile)
+		} else {
+			err = c.store.Set(profile, token)
+		}
+		if err != nil {
+			c.storeErr = err
+			return fmt.Errorf("save token of profile %s to the %s store: %w", profile, c.store.Name(), err)
+		}
+		delete(c.dirty, profile)
+	}
+	return nil
+}
+
+// migrateTokens moves tokens an older version, or the plaintext backend,
+// left in the config file into the store, then rewrites the file without
+// them. A failure leaves the file alone; the next run tries again.
+func (c *config) migrateTokens() {
+	if c.store == nil {
+		return
+	}
+
+	moved := false
+	for _, profile := range c.ListProfiles() {
+		key := profileKey(profile, KeyTokenString)
+		if !c.v.InConfig(key) {
+			continue
+		}
+		token := c.v.GetString(key)
+		if token == "" || token == os.Getenv(tokenEnv) {
+			continue
+		}
+		if err := c.store.Set(profile, token); err != nil {
+			c.storeErr = fmt.Errorf("move token of profile %s to the %s store: %w", profile, c.store.Name(), err)
+			return
+		}
+		c.v.Set(key, "")
+		moved = true
+	}
+	if moved {
+		if err := c.v.WriteConfig(); err != nil {
+			c.storeErr = fmt.Errorf("remove migrated tokens from the config file: %w", err)
+		}
+	}
+}
+
+// brokenStore stands in for a backend that could not be opened, so the
+// error surfaces on use instead of tokens landing in plaintext.
+type brokenStore struct {
+	name string
+	err  error
+}
+
+func (s brokenStore) Name() string               { return s.name }
+func (s brokenStore) Get(string) (string, error) { return "", s.err }
+func (s brokenStore) Set(string, string) error   { return s.err }
+func (s brokenStore) Delete(string) error        { return s.err }
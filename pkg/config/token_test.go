@@ -0,0 +1,137 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/zeabur/cli/pkg/config"
+	"github.com/zeabur/cli/pkg/credential"
+)
+
+// memStore is an in-memory credential.Store.
+type memStore map[string]string
+
+func (m memStore) Name() string { return "memory" }
+
+func (m memStore) Get(profile string) (string, error) {
+	if s, ok := m[profile]; ok {
+		return s, nil
+	}
+	return "", credential.ErrNotFound
+}
+
+func (m memStore) Set(profile, secret string) error { m[profile] = secret; return nil }
+func (m memStore) Delete(profile string) error      { delete(m, profile); return nil }
+
+func TestToken_SavedToStoreOnWrite(t *testing.T) {
+	t.Setenv("ZEABUR_TOKEN", "")
+
+	path := filepath.Join(t.TempDir(), "cli.yaml")
+	v := viper.New()
+	v.SetConfigFile(path)
+	store := memStore{}
+	c := config.FromViper(v, store)
+
+	c.SetTokenString("tok-personal")
+	if c.GetTokenString() != "tok-personal" {
+		t.Fatalf("GetTokenString() = %q before Write", c.GetTokenString())
+	}
+	if len(store) != 0 {
+		t.Fatalf("store written before Write: %v", store)
+	}
+	if err := c.Write(); err != nil {
+		t.Fatal(err)
+	}
+	if store[config.DefaultProfile] != "tok-personal" {
+		t.Fatalf("store = %v", store)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "tok-personal") {
+		t.Fatalf("token written to the config file:\n%s", data)
+	}
+	if name, err := c.GetCredentialStore(); name != "memory" || err != nil {
+		t.Errorf("GetCredentialStore() = %s, %v", name, err)
+	}
+
+	// logging out deletes it
+	c.SetTokenString("")
+	if err := c.Write(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store[config.DefaultProfile]; ok {
+		t.Errorf("token kept after logout: %v", store)
+	}
+}
+
+// TestNew_MigratesPlaintextTokens: the first run with a credential store
+// moves every profile's token out of cli.yaml.
+func TestNew_MigratesPlaintextTokens(t *testing.T) {
+	t.Setenv("ZEABUR_TOKEN", "")
+	t.Setenv(credential.PassphraseEnv, "hunter2")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cli.yaml")
+	yaml := `credential_store: file
+token: tok-personal
+username: me
+profiles:
+  work:
+    token: tok-work
+`
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := config.New(path)
+	if name, err := c.GetCredentialStore(); name != credential.BackendFile || err != nil {
+		t.Fatalf("GetCredentialStore() = %s, %v", name, err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "tok-") {
+		t.Fatalf("tokens left in the config file:\n%s", data)
+	}
+	if !strings.Contains(string(data), "username: me") {
+		t.Errorf("migration lost other settings:\n%s", data)
+	}
+
+	store := credential.NewFile(dir, credential.PromptPassphrase)
+	for profile, want := range map[string]string{config.DefaultProfile: "tok-personal", "work": "tok-work"} {
+		if got, err := store.Get(profile); got != want || err != nil {
+			t.Errorf("stored token of %s = %q, %v; want %q", profile, got, err, want)
+		}
+	}
+	if c.GetTokenString() != "tok-personal" {
+		t.Errorf("GetTokenString() = %q after migration", c.GetTokenString())
+	}
+}
+
+// brokenStore fails every access, like a keyring whose daemon is gone.
+type brokenStore struct{}
+
+func (brokenStore) Name() string               { return "broken" }
+func (brokenStore) Get(string) (string, error) { return "", errors.New("daemon gone") }
+func (brokenStore) Set(string, string) error   { return errors.New("daemon gone") }
+func (brokenStore) Delete(string) error        { return errors.New("daemon gone") }
+
+// TestToken_FallsBackToConfigFile keeps a user whose token could not be
+// migrated logged in when the store fails.
+func TestToken_FallsBackToConfigFile(t *testing.T) {
+	t.Setenv("ZEABUR_TOKEN", "")
+
+	v := viper.New()
+	v.Set(config.KeyTokenString, "tok-personal")
+	c := config.FromViper(v, brokenStore{})
+
+	if got := c.GetTokenString(); got != "tok-personal" {
+		t.Errorf("GetTokenString() = %q, want the token of the config file", got)
+	}
+	if _, err := c.GetCredentialStore(); err == nil {
+		t.Error("GetCredentialStore() hides the failure of the store")
+	}
+}
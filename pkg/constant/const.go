@@ -1,7 +1,13 @@
 package constant
 
+// Endpoints of the production Zeabur platform. They are the defaults; see
+// cmdutil.Factory.EffectiveAPIURL for how they are overridden.
 const (
 	ZeaburDashURL   = "https://zeabur.com"
 	ZeaburServerURL = "https://api.zeabur.com"
 	WebsocketURL    = "wss://api.zeabur.com"
+
+	// ZeaburLegacyDashURL is the older dashboard host, which still serves
+	// the AI Hub checkout and the project pages linked after a clone.
+	ZeaburLegacyDashURL = "https://dash.zeabur.com"
 )
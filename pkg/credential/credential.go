@@ -0,0 +1,67 @@
+// Package credential stores API tokens outside the config file.
+//
+// A Store keeps one secret per auth profile. Backends are the Secret Service
+// keyring (through libsecret's secret-tool, which talks to it over D-Bus) and
+// a passphrase-encrypted file; the plaintext config file remains the
+// fallback and is implemented by the config package itself.
+package credential
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Backend names, as accepted by Open and the credential_store config key.
+const (
+	BackendAuto      = "auto"      // keyring when available, plaintext otherwise
+	BackendKeyring   = "keyring"   // Secret Service over D-Bus
+	BackendFile      = "file"      // passphrase-encrypted file next to the config
+	BackendPlaintext = "plaintext" // the token key of the config file
+)
+
+// Backends lists the names Open accepts, for help texts.
+var Backends = []string{BackendAuto, BackendKeyring, BackendFile, BackendPlaintext}
+
+// ErrNotFound is returned by Get when the store holds no secret for the
+// profile.
+var ErrNotFound = errors.New("credential not found")
+
+// Store keeps one secret per profile.
+type Store interface {
+	Name() string
+	Get(profile string) (string, error)
+	Set(profile, secret string) error
+	Delete(profile string) error
+}
+
+// Options configure the backends Open may return.
+type Options struct {
+	// Dir is where the encrypted file lives, usually the config directory.
+	Dir string
+	// Passphrase returns the passphrase of the encrypted file. It is called
+	// at most once, on first access.
+	Passphrase func() (string, error)
+}
+
+// Open returns the store for backend. It returns nil, nil for the plaintext
+// backend, and for auto when no keyring is reachable, leaving tokens in the
+// config file.
+func Open(backend string, opts Options) (Store, error) {
+	switch backend {
+	case "", BackendAuto:
+		if KeyringAvailable() {
+			return NewKeyring(), nil
+		}
+		return nil, nil
+	case BackendKeyring:
+		if err := probeKeyring(); err != nil {
+			return nil, fmt.Errorf("keyring: no Secret Service answers (%v); install libsecret's secret-tool and run and unlock a keyring daemon, or use another credential store", err)
+		}
+		return NewKeyring(), nil
+	case BackendFile:
+		return NewFile(opts.Dir, opts.Passphrase), nil
+	case BackendPlaintext:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown credential store %q, want one of %v", backend, Backends)
+}
@@ -0,0 +1,145 @@
+package credential_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zeabur/cli/pkg/credential"
+)
+
+func passphrase(p string) func() (string, error) {
+	return func() (string, error) { return p, nil }
+}
+
+func TestFile_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	s := credential.NewFile(dir, passphrase("hunter2"))
+	if _, err := s.Get("default"); !errors.Is(err, credential.ErrNotFound) {
+		t.Fatalf("Get on a new file = %v, want ErrNotFound", err)
+	}
+	if err := s.Set("default", "tok-personal"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("work", "tok-work"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, credential.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("tok-personal")) {
+		t.Fatal("token stored in clear text")
+	}
+	if fi, _ := os.Stat(filepath.Join(dir, credential.FileName)); fi.Mode().Perm() != 0o600 {
+		t.Errorf("file mode = %v, want 0600", fi.Mode().Perm())
+	}
+
+	// a later invocation reads it back with the same passphrase
+	s = credential.NewFile(dir, passphrase("hunter2"))
+	if got, err := s.Get("work"); err != nil || got != "tok-work" {
+		t.Fatalf("Get(work) = %q, %v", got, err)
+	}
+	if err := s.Delete("work"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := credential.NewFile(dir, passphrase("hunter2")).Get("work"); !errors.Is(err, credential.ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+
+	if _, err := credential.NewFile(dir, passphrase("wrong")).Get("default"); err == nil {
+		t.Error("decrypted with the wrong passphrase")
+	}
+}
+
+// TestKeyring drives the keyring store against a stand-in secret-tool that
+// keeps secrets in files, checking the arguments and that secrets travel
+// over stdin.
+func TestKeyring(t *testing.T) {
+	bin := t.TempDir()
+	store := t.TempDir()
+	script := `#!/bin/sh
+# secret-tool <store|lookup|clear> [--label L] service zeabur-cli profile NAME
+op=$1; shift
+[ "$1" = --label ] && shift 2
+[ "$1 $2 $3" = "service zeabur-cli profile" ] || { echo "bad attributes: $*" >&2; exit 2; }
+f="` + store + `/$4"
+case $op in
+store) cat > "$f" ;;
+lookup) [ -f "$f" ] || exit 1; cat "$f" ;;
+clear) rm -f "$f" ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(bin, "secret-tool"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "unix:path=/dev/null")
+
+	s, err := credential.Open(credential.BackendAuto, credential.Options{})
+	if err != nil || s == nil || s.Name() != credential.BackendKeyring {
+		t.Fatalf("Open(auto) = %v, %v; want the keyring", s, err)
+	}
+
+	if _, err := s.Get("work"); !errors.Is(err, credential.ErrNotFound) {
+		t.Fatalf("Get before Set = %v, want ErrNotFound", err)
+	}
+	if err := s.Set("work", "tok-work"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Get("work"); err != nil || got != "tok-work" {
+		t.Fatalf("Get = %q, %v", got, err)
+	}
+	if err := s.Delete("work"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get("work"); !errors.Is(err, credential.ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+}
+
+// TestKeyring_Unreachable falls back when secret-tool is installed but no
+// keyring daemon answers, or it hangs waiting for an unlock.
+func TestKeyring_Unreachable(t *testing.T) {
+	for name, script := range map[string]string{
+		"no daemon": "#!/bin/sh\necho 'Cannot autolaunch D-Bus without X11 $DISPLAY' >&2\nexit 1\n",
+		"locked":    "#!/bin/sh\nexec sleep 10\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			bin := t.TempDir()
+			if err := os.WriteFile(filepath.Join(bin, "secret-tool"), []byte(script), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+			t.Setenv("DBUS_SESSION_BUS_ADDRESS", "unix:path=/dev/null")
+			defer func(d time.Duration) { credential.ProbeTimeout = d }(credential.ProbeTimeout)
+			credential.ProbeTimeout = 200 * time.Millisecond
+
+			if s, err := credential.Open(credential.BackendAuto, credential.Options{}); s != nil || err != nil {
+				t.Errorf("Open(auto) = %v, %v; want the plaintext fallback", s, err)
+			}
+			if _, err := credential.Open(credential.BackendKeyring, credential.Options{}); err == nil {
+				t.Error("Open(keyring) succeeded without a keyring answering")
+			}
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "")
+
+	if s, err := credential.Open(credential.BackendAuto, credential.Options{}); s != nil || err != nil {
+		t.Errorf("Open(auto) without a keyring = %v, %v; want the plaintext fallback", s, err)
+	}
+	if _, err := credential.Open(credential.BackendKeyring, credential.Options{}); err == nil {
+		t.Error("Open(keyring) succeeded without a keyring")
+	}
+	if _, err := credential.Open("vault", credential.Options{}); err == nil {
+		t.Error("Open accepted an unknown backend")
+	}
+}
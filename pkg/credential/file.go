@@ -0,0 +1,197 @@
+package credential
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+// FileName is the encrypted credentials file inside Options.Dir.
+const FileName = "credentials.enc"
+
+// The file is magic || log2(N) || salt || nonce || XChaCha20-Poly1305(JSON
+// of profile -> token), keyed with scrypt(passphrase, salt, N). The key is
+// derived once per invocation, so N trades brute-force cost against the
+// latency every command pays; it is stored so it can be raised later.
+var fileMagic = []byte("zeabur-credentials-v1\n")
+
+const (
+	saltSize = 16
+	scryptR  = 8
+	scryptP  = 1
+)
+
+// scryptLogN is log2 of the scrypt cost for new files: about 100ms and
+// 64 MiB per invocation.
+const scryptLogN = 16
+
+type file struct {
+	path       string
+	passphrase func() (string, error)
+
+	mu      sync.Mutex
+	loaded  bool
+	key     []byte
+	logN    byte
+	salt    []byte
+	secrets map[string]string
+}
+
+// NewFile returns a Store in an encrypted file in dir. passphrase is asked
+// for once, when the file is first read or written.
+func NewFile(dir string, passphrase func() (string, error)) Store {
+	return &file{path: filepath.Join(dir, FileName), passphrase: passphrase}
+}
+
+func (f *file) Name() string { return BackendFile }
+
+func (f *file) Get(profile string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.load(); err != nil {
+		return "", err
+	}
+	secret, ok := f.secrets[profile]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (f *file) Set(profile, secret string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.load(); err != nil {
+		return err
+	}
+	f.secrets[profile] = secret
+	return f.save()
+}
+
+func (f *file) Delete(profile string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.load(); err != nil {
+		return err
+	}
+	if _, ok := f.secrets[profile]; !ok {
+		return nil
+	}
+	delete(f.secrets, profile)
+	return f.save()
+}
+
+func (f *file) load() error {
+	if f.loaded {
+		return nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		f.logN = scryptLogN
+		f.salt = make([]byte, saltSize)
+		if _, err := rand.Read(f.salt); err != nil {
+			return err
+		}
+		if err := f.deriveKey(); err != nil {
+			return err
+		}
+		f.secrets = map[string]string{}
+		f.loaded = true
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !bytes.HasPrefix(data, fileMagic) || len(data) < len(fileMagic)+1+saltSize+chacha20poly1305.NonceSizeX {
+		return fmt.Errorf("%s is not a credentials file", f.path)
+	}
+	data = data[len(fileMagic):]
+	f.logN, data = data[0], data[1:]
+	if f.logN < 10 || f.logN > 22 {
+		return fmt.Errorf("%s: unsupported scrypt cost 2^%d", f.path, f.logN)
+	}
+	f.salt, data = data[:saltSize], data[saltSize:]
+	nonce, ciphertext := data[:chacha20poly1305.NonceSizeX], data[chacha20poly1305.NonceSizeX:]
+
+	if err := f.deriveKey(); err != nil {
+		return err
+	}
+	aead, err := chacha20poly1305.NewX(f.key)
+	if err != nil {
+		return err
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, fileMagic)
+	if err != nil {
+		return fmt.Errorf("decrypt %s: wrong passphrase or corrupted file", f.path)
+	}
+	if err := json.Unmarshal(plaintext, &f.secrets); err != nil {
+		return fmt.Errorf("decrypt %s: %w", f.path, err)
+	}
+	if f.secrets == nil {
+		f.secrets = map[string]string{}
+	}
+	f.loaded = true
+	return nil
+}
+
+func (f *file) deriveKey() error {
+	if f.passphrase == nil {
+		return errors.New("a passphrase is required for the encrypted credentials file")
+	}
+	passphrase, err := f.passphrase()
+	if err != nil {
+		return err
+	}
+	if passphrase == "" {
+		return errors.New("the passphrase of the encrypted credentials file cannot be empty")
+	}
+	f.key, err = scrypt.Key([]byte(passphrase), f.salt, 1<<f.logN, scryptR, scryptP, chacha20poly1305.KeySize)
+	return err
+}
+
+// save rewrites the whole file with a fresh nonce, through a temporary file
+// so a crash never leaves it half written.
+func (f *file) save() error {
+	plaintext, err := json.Marshal(f.secrets)
+	if err != nil {
+		return err
+	}
+	aead, err := chacha20poly1305.NewX(f.key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(fileMagic)
+	buf.WriteByte(f.logN)
+	buf.Write(f.salt)
+	buf.Write(nonce)
+	buf.Write(aead.Seal(nil, nonce, plaintext, fileMagic))
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
@@ -0,0 +1,110 @@
+package credential
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// keyringService is the attribute every secret stored by the CLI carries,
+// next to the profile attribute.
+const keyringService = "zeabur-cli"
+
+// secretTool is the libsecret CLI, looked up in PATH.
+const secretTool = "secret-tool"
+
+// probeProfile is looked up to check that the keyring answers; nothing is
+// ever stored under it.
+const probeProfile = "zeabur-cli-probe"
+
+// ProbeTimeout bounds the lookup KeyringAvailable makes. A daemon that is
+// not running, or a locked keyring waiting for an unlock prompt, does not
+// answer in time.
+var ProbeTimeout = 3 * time.Second
+
+type keyring struct{}
+
+// NewKeyring returns a Store in the Secret Service keyring (GNOME Keyring,
+// KWallet, KeePassXC, ...).
+func NewKeyring() Store {
+	return keyring{}
+}
+
+// KeyringAvailable reports whether secret-tool is installed, a D-Bus
+// session bus is around for it to reach the keyring daemon through, and the
+// daemon answers a lookup, unlocked, within ProbeTimeout.
+func KeyringAvailable() bool {
+	return probeKeyring() == nil
+}
+
+func probeKeyring() error {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return errors.New("no D-Bus session bus")
+	}
+	if _, err := exec.LookPath(secretTool); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ProbeTimeout)
+	defer cancel()
+	if _, err := (keyring{}).get(ctx, probeProfile); err != nil && !errors.Is(err, ErrNotFound) {
+		if ctx.Err() != nil {
+			return fmt.Errorf("the Secret Service did not answer within %s", ProbeTimeout)
+		}
+		return err
+	}
+	return nil
+}
+
+func (keyring) Name() string { return BackendKeyring }
+
+func (k keyring) Get(profile string) (string, error) {
+	return k.get(context.Background(), profile)
+}
+
+func (k keyring) get(ctx context.Context, profile string) (string, error) {
+	out, stderr, err := k.run(ctx, "", "lookup", "service", keyringService, "profile", profile)
+	if err != nil {
+		// lookup exits 1 without any output when nothing matches; it
+		// complains on stderr when the daemon cannot be reached
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && len(out) == 0 && stderr == "" {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func (k keyring) Set(profile, secret string) error {
+	label := fmt.Sprintf("Zeabur CLI token (%s)", profile)
+	_, _, err := k.run(context.Background(), secret, "store", "--label", label, "service", keyringService, "profile", profile)
+	return err
+}
+
+func (k keyring) Delete(profile string) error {
+	_, _, err := k.run(context.Background(), "", "clear", "service", keyringService, "profile", profile)
+	return err
+}
+
+// run passes secrets on stdin so they never show up in the process list.
+// It returns stdout and stderr.
+func (keyring) run(ctx context.Context, stdin string, args ...string) ([]byte, string, error) {
+	cmd := exec.CommandContext(ctx, secretTool, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	msg := strings.TrimSpace(stderr.String())
+	if err != nil && msg != "" {
+		return out, msg, fmt.Errorf("keyring: %s: %w", msg, err)
+	}
+	if err != nil {
+		return out, msg, fmt.Errorf("keyring: %w", err)
+	}
+	return out, msg, nil
+}
@@ -0,0 +1,31 @@
+package credential
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// PassphraseEnv holds the passphrase of the encrypted credentials file for
+// non-interactive use.
+const PassphraseEnv = "ZEABUR_CREDENTIALS_PASSPHRASE"
+
+// PromptPassphrase reads the passphrase from PassphraseEnv, or asks for it
+// on the terminal without echo.
+func PromptPassphrase() (string, error) {
+	if p := os.Getenv(PassphraseEnv); p != "" {
+		return p, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", errors.New("the encrypted credentials file needs a passphrase: set " + PassphraseEnv)
+	}
+	fmt.Fprint(os.Stderr, "Passphrase for the Zeabur credentials file: ")
+	p, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("read passphrase: %w", err)
+	}
+	return string(p), nil
+}
@@ -0,0 +1,42 @@
+package envcrypt
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// Recipient and Identity are age (https://age-encryption.org) recipients
+// and identities: keys as made by age-keygen, or a Passphrase.
+type (
+	Recipient = age.Recipient
+	Identity  = age.Identity
+)
+
+// ParseRecipient parses an age public key, "age1..." or "age1pq1...".
+func ParseRecipient(s string) (Recipient, error) {
+	if strings.ContainsAny(s, "\r\n") {
+		return nil, fmt.Errorf("invalid age recipient %q", s)
+	}
+	rs, err := age.ParseRecipients(strings.NewReader(s))
+	if err != nil {
+		return nil, fmt.Errorf("invalid age recipient %q: %w", s, err)
+	}
+	return rs[0], nil
+}
+
+// ParseIdentities reads the identities of an age key file: one per line,
+// skipping blank lines and # comments.
+func ParseIdentities(r io.Reader) ([]Identity, error) {
+	return age.ParseIdentities(r)
+}
+
+// describe returns how Recipients shows an age key.
+func describe(r Recipient) string {
+	if s, ok := r.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", r)
+}
@@ -0,0 +1,292 @@
+// Package envcrypt encrypts the values of variable files so they can be
+// kept in git, leaving the keys readable to review changes, like sops.
+//
+// Every value becomes ENC[base64(nonce || XChaCha20-Poly1305(value))],
+// bound to its key, under a random file key. The MetadataKey variable
+// holds the file key as age files for the recipients, age keys in one and
+// a passphrase in another since age keeps a passphrase apart, and a MAC
+// over all the encrypted values so none can be dropped, added or swapped
+// unnoticed. Each key decodes from raw base64 into a file that age -d
+// decrypts.
+package envcrypt
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"filippo.io/age"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// MetadataKey is the variable of an encrypted file holding its file key,
+// recipients and MAC. It is never sent as a variable.
+const MetadataKey = "ZEABUR_ENCRYPTION"
+
+const (
+	version    = "v2"
+	toPrefix   = "to:"
+	keyPrefix  = "key:"
+	macPrefix  = "mac:"
+	passphrase = "passphrase"
+	encPrefix  = "ENC["
+	encSuffix  = "]"
+	valuesInfo = "zeabur-variables/values"
+	macInfo    = "zeabur-variables/mac"
+)
+
+// IsEncrypted reports whether vars were read from an encrypted file.
+func IsEncrypted(vars map[string]string) bool {
+	_, ok := vars[MetadataKey]
+	return ok
+}
+
+// File is a decrypted file, which can be sealed again with the same file
+// key and recipients.
+type File struct {
+	// Vars are the decrypted variables, without MetadataKey.
+	Vars map[string]string
+
+	key []byte
+	// to describes the recipients and keys holds the file key encrypted
+	// to them, both as stored in MetadataKey.
+	to   []string
+	keys []string
+	// plain and sealed are the values as decrypted and as read, so that
+	// unchanged values keep their ciphertext and diffs stay small.
+	plain, sealed map[string]string
+}
+
+// Encrypt encrypts vars for recipients.
+func Encrypt(vars map[string]string, recipients ...Recipient) (map[string]string, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("at least one recipient is required")
+	}
+	if IsEncrypted(vars) {
+		return nil, errors.New("the variables are encrypted already")
+	}
+	f := &File{key: make([]byte, chacha20poly1305.KeySize)}
+	if _, err := rand.Read(f.key); err != nil {
+		return nil, err
+	}
+	// the age keys go first, so Decrypt tries them before prompting for
+	// a passphrase
+	var keys, passphrases []Recipient
+	for _, r := range recipients {
+		if _, ok := r.(*Passphrase); ok {
+			f.to = append(f.to, passphrase)
+			passphrases = append(passphrases, r)
+		} else {
+			f.to = append(f.to, describe(r))
+			keys = append(keys, r)
+		}
+	}
+	if len(keys) > 0 {
+		if err := f.addKey(keys...); err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range passphrases {
+		if err := f.addKey(r); err != nil {
+			return nil, err
+		}
+	}
+	return f.Seal(vars)
+}
+
+// addKey encrypts the file key to recipients as an age file.
+func (f *File) addKey(recipients ...Recipient) error {
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, recipients...)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(f.key); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	f.keys = append(f.keys, b64(buf.Bytes()))
+	return nil
+}
+
+// Decrypt decrypts vars with the first of identities that can unwrap
+// their file key.
+func Decrypt(vars map[string]string, identities ...Identity) (*File, error) {
+	f, mac, err := parseMetadata(vars[MetadataKey])
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range f.keys {
+		data, err := unb64(key)
+		if err != nil {
+			return nil, err
+		}
+		r, err := age.Decrypt(bytes.NewReader(data), identities...)
+		var noMatch *age.NoIdentityMatchError
+		if errors.As(err, &noMatch) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if f.key, err = io.ReadAll(r); err != nil {
+			return nil, err
+		}
+		if len(f.key) != chacha20poly1305.KeySize {
+			return nil, fmt.Errorf("malformed %s", MetadataKey)
+		}
+		break
+	}
+	if f.key == nil {
+		return nil, fmt.Errorf("no key can decrypt the variables, which are encrypted for %s", strings.Join(f.Recipients(), ", "))
+	}
+
+	encrypted := maps.Clone(vars)
+	delete(encrypted, MetadataKey)
+	want, err := f.mac(encrypted)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(mac, want) {
+		return nil, errors.New("the variables were modified since they were encrypted: MAC mismatch")
+	}
+
+	aead, err := f.aead()
+	if err != nil {
+		return nil, err
+	}
+	f.Vars = make(map[string]string, len(encrypted))
+	f.sealed = encrypted
+	for k, v := range encrypted {
+		if !strings.HasPrefix(v, encPrefix) || !strings.HasSuffix(v, encSuffix) {
+			return nil, fmt.Errorf("%s is not encrypted", k)
+		}
+		data, err := unb64(v[len(encPrefix) : len(v)-len(encSuffix)])
+		if err != nil {
+			return nil, err
+		}
+		if len(data) < aead.NonceSize() {
+			return nil, fmt.Errorf("decrypt %s: value too short", k)
+		}
+		plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(k))
+		if err != nil {
+			return nil, fmt.Errorf("decrypt %s: %w", k, err)
+		}
+		f.Vars[k] = string(plaintext)
+	}
+	f.plain = maps.Clone(f.Vars)
+	return f, nil
+}
+
+// Seal encrypts vars with the file key of f, for its recipients. Values
+// unchanged since Decrypt keep their ciphertext.
+func (f *File) Seal(vars map[string]string) (map[string]string, error) {
+	aead, err := f.aead()
+	if err != nil {
+		return nil, err
+	}
+	encrypted := make(map[string]string, len(vars)+1)
+	for k, v := range vars {
+		if k == MetadataKey {
+			return nil, fmt.Errorf("%s is reserved", MetadataKey)
+		}
+		if old, ok := f.plain[k]; ok && old == v {
+			encrypted[k] = f.sealed[k]
+			continue
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+		encrypted[k] = encPrefix + b64(aead.Seal(nonce, nonce, []byte(v), []byte(k))) + encSuffix
+	}
+	mac, err := f.mac(encrypted)
+	if err != nil {
+		return nil, err
+	}
+	fields := []string{version, toPrefix + strings.Join(f.to, ",")}
+	for _, key := range f.keys {
+		fields = append(fields, keyPrefix+key)
+	}
+	encrypted[MetadataKey] = strings.Join(append(fields, macPrefix+b64(mac)), " ")
+	return encrypted, nil
+}
+
+// parseMetadata reads the recipients, keys and MAC of MetadataKey.
+func parseMetadata(metadata string) (*File, []byte, error) {
+	fields := strings.Fields(metadata)
+	if len(fields) < 4 || fields[0] != version || !strings.HasPrefix(fields[1], toPrefix) || !strings.HasPrefix(fields[len(fields)-1], macPrefix) {
+		return nil, nil, fmt.Errorf("malformed %s", MetadataKey)
+	}
+	f := &File{to: strings.Split(strings.TrimPrefix(fields[1], toPrefix), ",")}
+	for _, field := range fields[2 : len(fields)-1] {
+		key, ok := strings.CutPrefix(field, keyPrefix)
+		if !ok {
+			return nil, nil, fmt.Errorf("malformed %s", MetadataKey)
+		}
+		f.keys = append(f.keys, key)
+	}
+	mac, err := unb64(strings.TrimPrefix(fields[len(fields)-1], macPrefix))
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, mac, nil
+}
+
+// Recipients describes who can decrypt f: the age keys, and "a passphrase"
+// when one was used.
+func (f *File) Recipients() []string {
+	rs := slices.Clone(f.to)
+	for i, r := range rs {
+		if r == passphrase {
+			rs[i] = "a passphrase"
+		}
+	}
+	return rs
+}
+
+func (f *File) aead() (cipher.AEAD, error) {
+	key, err := hkdf.Key(sha256.New, f.key, nil, valuesInfo, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	return chacha20poly1305.NewX(key)
+}
+
+// mac authenticates the encrypted values and their keys.
+func (f *File) mac(encrypted map[string]string) ([]byte, error) {
+	key, err := hkdf.Key(sha256.New, f.key, nil, macInfo, sha256.Size)
+	if err != nil {
+		return nil, err
+	}
+	h := hmac.New(sha256.New, key)
+	for _, k := range slices.Sorted(maps.Keys(encrypted)) {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(encrypted[k]))
+		h.Write([]byte{0})
+	}
+	return h.Sum(nil), nil
+}
+
+func b64(b []byte) string { return base64.RawStdEncoding.EncodeToString(b) }
+
+func unb64(s string) ([]byte, error) {
+	b, err := base64.RawStdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("malformed %s: %w", MetadataKey, err)
+	}
+	return b, nil
+}
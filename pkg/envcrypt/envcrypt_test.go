@@ -0,0 +1,155 @@
+package envcrypt_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/zeabur/cli/pkg/envcrypt"
+)
+
+func init() {
+	// keep the tests fast; the cost is stored in each file
+	envcrypt.ScryptLogN = 10
+}
+
+func passphrase(p string) *envcrypt.Passphrase {
+	return envcrypt.NewPassphrase(func() (string, error) { return p, nil })
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	alice, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := map[string]string{"PORT": "8080", "DB_PASSWORD": "hunter2", "EMPTY": ""}
+
+	encrypted, err := envcrypt.Encrypt(vars, alice.Recipient(), passphrase("correct horse"))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if !envcrypt.IsEncrypted(encrypted) || !strings.HasPrefix(encrypted["DB_PASSWORD"], "ENC[") || strings.Contains(encrypted["DB_PASSWORD"], "hunter2") {
+		t.Fatalf("encrypted = %v", encrypted)
+	}
+
+	for name, id := range map[string]envcrypt.Identity{"age": alice, "passphrase": passphrase("correct horse")} {
+		f, err := envcrypt.Decrypt(encrypted, id)
+		if err != nil {
+			t.Fatalf("decrypt with %s: %v", name, err)
+		}
+		if !maps.Equal(f.Vars, vars) {
+			t.Errorf("decrypted with %s = %v, want %v", name, f.Vars, vars)
+		}
+	}
+
+	_, err = envcrypt.Decrypt(encrypted, bob)
+	if err == nil || !strings.Contains(err.Error(), alice.Recipient().String()) || !strings.Contains(err.Error(), "a passphrase") {
+		t.Errorf("decrypt with another key: %v", err)
+	}
+	if _, err := envcrypt.Decrypt(encrypted, passphrase("wrong")); err == nil {
+		t.Error("decrypted with a wrong passphrase")
+	}
+
+	// reseal keeps the recipients
+	f, _ := envcrypt.Decrypt(encrypted, alice)
+	resealed, err := f.Seal(map[string]string{"PORT": "9090"})
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if f, err := envcrypt.Decrypt(resealed, passphrase("correct horse")); err != nil || !maps.Equal(f.Vars, map[string]string{"PORT": "9090"}) {
+		t.Errorf("decrypt resealed = %v, %v", f, err)
+	}
+
+	// the file key is an age file
+	key, _, _ := strings.Cut(strings.SplitN(encrypted[envcrypt.MetadataKey], " key:", 2)[1], " ")
+	data, err := base64.RawStdEncoding.DecodeString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := age.Decrypt(bytes.NewReader(data), alice); err != nil {
+		t.Errorf("age decrypt the file key: %v", err)
+	}
+
+	// unchanged values keep their ciphertext
+	resealed, err = f.Seal(map[string]string{"PORT": "8080", "DB_PASSWORD": "hunter3", "EMPTY": ""})
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if resealed["PORT"] != encrypted["PORT"] || resealed["EMPTY"] != encrypted["EMPTY"] || resealed["DB_PASSWORD"] == encrypted["DB_PASSWORD"] {
+		t.Errorf("resealed = %v, encrypted = %v", resealed, encrypted)
+	}
+}
+
+func TestDecrypt_Tampered(t *testing.T) {
+	t.Parallel()
+
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := envcrypt.Encrypt(map[string]string{"A": "1", "B": "2"}, id.Recipient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tamper := range map[string]func(map[string]string){
+		"swapped":   func(m map[string]string) { m["A"], m["B"] = m["B"], m["A"] },
+		"removed":   func(m map[string]string) { delete(m, "B") },
+		"added":     func(m map[string]string) { m["C"] = m["A"] },
+		"plaintext": func(m map[string]string) { m["A"] = "1" },
+	} {
+		m := maps.Clone(encrypted)
+		tamper(m)
+		if _, err := envcrypt.Decrypt(m, id); err == nil {
+			t.Errorf("decrypted variables with a value %s", name)
+		}
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	t.Parallel()
+
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(id.String(), "AGE-SECRET-KEY-1") || !strings.HasPrefix(id.Recipient().String(), "age1") {
+		t.Fatalf("keys = %s, %s", id, id.Recipient())
+	}
+
+	ids, err := envcrypt.ParseIdentities(strings.NewReader("# created: today\n# public key: " + id.Recipient().String() + "\n" + id.String() + "\n"))
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("parse identities = %v, %v", ids, err)
+	}
+	if parsed, ok := ids[0].(*age.X25519Identity); !ok || parsed.String() != id.String() {
+		t.Errorf("parsed %v, want %s", ids[0], id)
+	}
+
+	r, err := envcrypt.ParseRecipient(id.Recipient().String())
+	if err != nil || fmt.Sprint(r) != id.Recipient().String() {
+		t.Errorf("parse recipient = %v, %v", r, err)
+	}
+	// a typo breaks the checksum
+	bad := strings.Replace(id.Recipient().String(), "age1q", "age1p", 1)
+	if bad == id.Recipient().String() {
+		bad = strings.Replace(bad, "age1", "age1q", 1)
+	}
+	for _, s := range []string{"age1", "bogus", id.String(), bad} {
+		if _, err := envcrypt.ParseRecipient(s); err == nil {
+			t.Errorf("parsed recipient %q", s)
+		}
+	}
+	if _, err := envcrypt.ParseIdentities(strings.NewReader("# nothing\n")); err == nil {
+		t.Error("parsed an empty key file")
+	}
+}
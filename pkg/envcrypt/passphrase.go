@@ -0,0 +1,87 @@
+package envcrypt
+
+import (
+	"errors"
+	"slices"
+
+	"filippo.io/age"
+)
+
+// ScryptLogN is log2 of the scrypt cost for new files: about 100ms and
+// 64 MiB per encryption or decryption.
+var ScryptLogN = 16
+
+// Passphrase is an age scrypt Recipient and Identity whose passphrase is
+// asked for the first time it is needed, so a file that an age key
+// decrypts never prompts.
+type Passphrase struct {
+	get        func() (string, error)
+	passphrase string
+}
+
+// NewPassphrase returns a Passphrase whose passphrase get returns.
+func NewPassphrase(get func() (string, error)) *Passphrase {
+	return &Passphrase{get: get}
+}
+
+func (p *Passphrase) load() (string, error) {
+	if p.passphrase == "" {
+		passphrase, err := p.get()
+		if err != nil {
+			return "", err
+		}
+		if passphrase == "" {
+			return "", errors.New("the passphrase cannot be empty")
+		}
+		p.passphrase = passphrase
+	}
+	return p.passphrase, nil
+}
+
+func (p *Passphrase) recipient() (*age.ScryptRecipient, error) {
+	passphrase, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	r, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	r.SetWorkFactor(ScryptLogN)
+	return r, nil
+}
+
+// Wrap implements age.Recipient.
+func (p *Passphrase) Wrap(fileKey []byte) ([]*age.Stanza, error) {
+	r, err := p.recipient()
+	if err != nil {
+		return nil, err
+	}
+	return r.Wrap(fileKey)
+}
+
+// WrapWithLabels implements age.RecipientWithLabels, which keeps age from
+// mixing a passphrase with other recipients.
+func (p *Passphrase) WrapWithLabels(fileKey []byte) ([]*age.Stanza, []string, error) {
+	r, err := p.recipient()
+	if err != nil {
+		return nil, nil, err
+	}
+	return r.WrapWithLabels(fileKey)
+}
+
+// Unwrap implements age.Identity.
+func (p *Passphrase) Unwrap(stanzas []*age.Stanza) ([]byte, error) {
+	if !slices.ContainsFunc(stanzas, func(s *age.Stanza) bool { return s.Type == "scrypt" }) {
+		return nil, age.ErrIncorrectIdentity
+	}
+	passphrase, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	id, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return id.Unwrap(stanzas)
+}
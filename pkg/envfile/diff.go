@@ -0,0 +1,92 @@
+package envfile
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+
+	"github.com/fatih/color"
+)
+
+// ChangeKind is what a change does to a variable.
+type ChangeKind string
+
+const (
+	ChangeAdd    ChangeKind = "add"
+	ChangeUpdate ChangeKind = "update"
+	ChangeRemove ChangeKind = "remove"
+)
+
+// Change is a difference between two sets of variables.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	Key  string     `json:"key"`
+	// Old is the current value; empty when adding.
+	Old string `json:"old,omitempty"`
+	// New is the wanted value; empty when removing.
+	New string `json:"new,omitempty"`
+}
+
+// Diff returns the changes turning current into want, sorted by key.
+// Variables missing from want are removed only when prune is set.
+func Diff(current, want map[string]string, prune bool) []Change {
+	var changes []Change
+	for _, k := range slices.Sorted(maps.Keys(want)) {
+		old, ok := current[k]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Kind: ChangeAdd, Key: k, New: want[k]})
+		case old != want[k]:
+			changes = append(changes, Change{Kind: ChangeUpdate, Key: k, Old: old, New: want[k]})
+		}
+	}
+	if prune {
+		for _, k := range slices.Sorted(maps.Keys(current)) {
+			if _, ok := want[k]; !ok {
+				changes = append(changes, Change{Kind: ChangeRemove, Key: k, Old: current[k]})
+			}
+		}
+	}
+	slices.SortStableFunc(changes, func(a, b Change) int { return cmp.Compare(a.Key, b.Key) })
+	return changes
+}
+
+// Apply returns a copy of vars with changes made.
+func Apply(vars map[string]string, changes []Change) map[string]string {
+	out := maps.Clone(vars)
+	if out == nil {
+		out = map[string]string{}
+	}
+	for _, c := range changes {
+		if c.Kind == ChangeRemove {
+			delete(out, c.Key)
+		} else {
+			out[c.Key] = c.New
+		}
+	}
+	return out
+}
+
+// Masked returns c with its values passed through mask.
+func (c Change) Masked(mask func(string) string) Change {
+	if c.Kind != ChangeAdd {
+		c.Old = mask(c.Old)
+	}
+	if c.Kind != ChangeRemove {
+		c.New = mask(c.New)
+	}
+	return c
+}
+
+// String renders the change as a colored +/~/- line, e.g.
+// "~ DEBUG: 0 → 1".
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdd:
+		return color.GreenString("+") + " " + c.Key + ": " + c.New
+	case ChangeUpdate:
+		return color.YellowString("~") + " " + c.Key + ": " + c.Old + " → " + c.New
+	default:
+		return color.RedString("-") + " " + c.Key
+	}
+}
@@ -0,0 +1,184 @@
+// Package envfile reads and writes variables as .env, JSON or YAML files,
+// and compares two sets of variables.
+package envfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-envparse"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a file format for variables.
+type Format string
+
+const (
+	FormatDotenv Format = "env"
+	FormatJSON   Format = "json"
+	FormatYAML   Format = "yaml"
+)
+
+// Formats are the supported formats, for help texts and completion.
+var Formats = []string{string(FormatDotenv), string(FormatJSON), string(FormatYAML)}
+
+// ParseFormat parses a format name; "dotenv" and "yml" are accepted too.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "env", "dotenv", ".env":
+		return FormatDotenv, nil
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("unknown format %q, must be one of %s", name, strings.Join(Formats, ", "))
+}
+
+// FormatOf returns the format of a file by its extension: JSON for .json,
+// YAML for .yaml and .yml, and .env otherwise.
+func FormatOf(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return FormatDotenv
+}
+
+// Read reads the variables in the file at path, in the format of its
+// extension.
+func Read(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("file not found: %s", path)
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	vars, err := Parse(file, FormatOf(path))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return vars, nil
+}
+
+// Parse reads variables in format from r. JSON and YAML files hold a single
+// object whose values are strings, numbers or booleans.
+func Parse(r io.Reader, format Format) (map[string]string, error) {
+	switch format {
+	case FormatJSON, FormatYAML:
+		var raw map[string]any
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		if format == FormatJSON {
+			// numbers keep their text, so 10485760 does not become 1.048576e+07
+			dec := json.NewDecoder(bytes.NewReader(data))
+			dec.UseNumber()
+			err = dec.Decode(&raw)
+		} else {
+			err = yaml.Unmarshal(data, &raw)
+		}
+		if err != nil {
+			return nil, err
+		}
+		vars := make(map[string]string, len(raw))
+		for k, v := range raw {
+			switch v := v.(type) {
+			case string:
+				vars[k] = v
+			case nil:
+				vars[k] = ""
+			case json.Number:
+				vars[k] = v.String()
+			case float64:
+				vars[k] = strconv.FormatFloat(v, 'f', -1, 64)
+			case bool, int, int64, uint64:
+				vars[k] = fmt.Sprint(v)
+			default:
+				return nil, fmt.Errorf("the value of %s must be a string, a number or a boolean", k)
+			}
+		}
+		return vars, nil
+	default:
+		return envparse.Parse(r)
+	}
+}
+
+// Write writes vars in format to w, sorted by key.
+func Write(w io.Writer, vars map[string]string, format Format) error {
+	keys := slices.Sorted(maps.Keys(vars))
+
+	var buf bytes.Buffer
+	switch format {
+	case FormatJSON:
+		// json.Marshal sorts the keys; an encoder keeps & < > readable
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if vars == nil {
+			vars = map[string]string{}
+		}
+		if err := enc.Encode(vars); err != nil {
+			return err
+		}
+	case FormatYAML:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for _, k := range keys {
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: k},
+				// a string tag keeps values such as "true" or "8080" strings
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: vars[k]})
+		}
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(node); err != nil {
+			return err
+		}
+		if err := enc.Close(); err != nil {
+			return err
+		}
+	default:
+		for _, k := range keys {
+			fmt.Fprintf(&buf, "%s=%s\n", k, quote(vars[k]))
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// quote quotes a .env value unless it is made of characters that need no
+// quoting. Quoted values use the JSON escapes envparse understands.
+func quote(v string) string {
+	plain := v != ""
+	for _, r := range v {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:@,+=%~", r)) {
+			plain = false
+			break
+		}
+	}
+	if plain {
+		return v
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return strconv.Quote(v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
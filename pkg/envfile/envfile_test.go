@@ -0,0 +1,128 @@
+package envfile_test
+
+import (
+	"bytes"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zeabur/cli/pkg/envfile"
+)
+
+var vars = map[string]string{
+	"PORT":         "8080",
+	"DEBUG":        "true",
+	"DATABASE_URL": "postgres://app:p@ss@db:5432/app?sslmode=disable",
+	"GREETING":     `say "hi" & <wave>`,
+	"MULTILINE":    "line 1\nline 2\ttabbed",
+	"EMPTY":        "",
+	"UNICODE":      "café ☕",
+}
+
+func TestWriteParse_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []envfile.Format{envfile.FormatDotenv, envfile.FormatJSON, envfile.FormatYAML} {
+		var buf bytes.Buffer
+		if err := envfile.Write(&buf, vars, format); err != nil {
+			t.Fatalf("%s: write: %v", format, err)
+		}
+		got, err := envfile.Parse(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: parse: %v\n%s", format, err, buf.String())
+		}
+		if !maps.Equal(got, vars) {
+			t.Errorf("%s: round trip = %v, want %v", format, got, vars)
+		}
+	}
+}
+
+// TestParse_Numbers keeps numbers as written, never in exponent form.
+func TestParse_Numbers(t *testing.T) {
+	t.Parallel()
+
+	want := map[string]string{"MAX_BODY": "10485760", "BIG": "12345678901234567890", "RATIO": "0.25", "NEG": "-3"}
+	for format, data := range map[envfile.Format]string{
+		envfile.FormatJSON: `{"MAX_BODY": 10485760, "BIG": 12345678901234567890, "RATIO": 0.25, "NEG": -3}`,
+		envfile.FormatYAML: "MAX_BODY: 10485760\nBIG: 12345678901234567890\nRATIO: 0.25\nNEG: -3\n",
+	} {
+		got, err := envfile.Parse(strings.NewReader(data), format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if !maps.Equal(got, want) {
+			t.Errorf("%s: got %v, want %v", format, got, want)
+		}
+	}
+}
+
+func TestWrite_Dotenv(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := envfile.Write(&buf, map[string]string{"B": "two words", "A": "plain-value_1.0", "C": ""}, envfile.FormatDotenv); err != nil {
+		t.Fatal(err)
+	}
+	want := "A=plain-value_1.0\nB=\"two words\"\nC=\"\"\n"
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		".env":        "# comment\nexport PORT=8080\nNAME='web app'\n",
+		"vars.json":   `{"PORT": 8080, "NAME": "web app"}`,
+		"vars.yml":    "PORT: 8080\nNAME: web app\n",
+		"nested.yaml": "PORT:\n  value: 8080\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := map[string]string{"PORT": "8080", "NAME": "web app"}
+	for _, name := range []string{".env", "vars.json", "vars.yml"} {
+		got, err := envfile.Read(filepath.Join(dir, name))
+		if err != nil || !maps.Equal(got, want) {
+			t.Errorf("Read(%s) = %v, %v", name, got, err)
+		}
+	}
+	if _, err := envfile.Read(filepath.Join(dir, "nested.yaml")); err == nil || !strings.Contains(err.Error(), "PORT must be a string") {
+		t.Errorf("nested value: error = %v", err)
+	}
+	if _, err := envfile.Read(filepath.Join(dir, "missing.env")); err == nil || !strings.Contains(err.Error(), "file not found") {
+		t.Errorf("missing file: error = %v", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	current := map[string]string{"PORT": "8080", "DEBUG": "0", "OLD": "x"}
+	want := map[string]string{"PORT": "8080", "DEBUG": "1", "NEW": "y"}
+
+	changes := envfile.Diff(current, want, true)
+	expected := []envfile.Change{
+		{Kind: envfile.ChangeUpdate, Key: "DEBUG", Old: "0", New: "1"},
+		{Kind: envfile.ChangeAdd, Key: "NEW", New: "y"},
+		{Kind: envfile.ChangeRemove, Key: "OLD", Old: "x"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Diff = %+v, want %+v", changes, expected)
+	}
+	if got := envfile.Apply(current, changes); !maps.Equal(got, want) {
+		t.Errorf("Apply = %v, want %v", got, want)
+	}
+
+	if changes := envfile.Diff(current, want, false); len(changes) != 2 {
+		t.Errorf("Diff without pruning = %+v, want no removal", changes)
+	}
+}
@@ -0,0 +1,92 @@
+// Package logfile writes log lines to files as text or NDJSON, optionally
+// gzip-compressed.
+package logfile
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// Format is how lines are written.
+type Format string
+
+const (
+	FormatText   Format = "text"   // "timestamp message" lines
+	FormatNDJSON Format = "ndjson" // one JSON object per line
+)
+
+// Writer writes log lines to a file.
+type Writer struct {
+	Format Format
+
+	file *os.File
+	gz   *gzip.Writer
+	buf  *bufio.Writer
+}
+
+// Create creates or truncates the file at path. The format follows the
+// extension: a .gz suffix compresses the file, and a .ndjson or .jsonl
+// extension before it selects NDJSON; anything else is text.
+func Create(path string) (*Writer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Writer{Format: FormatText, file: file}
+	name := strings.ToLower(filepath.Base(path))
+	var out io.Writer = file
+	if base, ok := strings.CutSuffix(name, ".gz"); ok {
+		name = base
+		w.gz = gzip.NewWriter(file)
+		out = w.gz
+	}
+	if ext := filepath.Ext(name); ext == ".ndjson" || ext == ".jsonl" {
+		w.Format = FormatNDJSON
+	}
+	w.buf = bufio.NewWriter(out)
+	return w, nil
+}
+
+// Write writes l as one line. Uncompressed files are flushed after every
+// line, so they can be followed with tail -f.
+func (w *Writer) Write(l *model.Log) error {
+	switch w.Format {
+	case FormatNDJSON:
+		data, err := json.Marshal(l)
+		if err != nil {
+			return err
+		}
+		_, _ = w.buf.Write(data)
+		_ = w.buf.WriteByte('\n')
+	default:
+		_, _ = fmt.Fprintf(w.buf, "%s %s\n", l.Timestamp.Format(time.RFC3339), l.Message)
+	}
+	if w.gz != nil {
+		return nil
+	}
+	return w.buf.Flush()
+}
+
+// Close flushes the remaining lines and closes the file.
+func (w *Writer) Close() error {
+	err := w.buf.Flush()
+	if w.gz != nil {
+		if gzErr := w.gz.Close(); err == nil {
+			err = gzErr
+		}
+	}
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,112 @@
+package logfile_test
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zeabur/cli/pkg/logfile"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	logs := model.Logs{
+		{Timestamp: ts, Message: "listening on :8080"},
+		{Timestamp: ts.Add(time.Second), Message: `GET / "200"`},
+	}
+	text := "2024-01-01T00:00:00Z listening on :8080\n2024-01-01T00:00:01Z GET / \"200\"\n"
+	ndjson := `{"timestamp":"2024-01-01T00:00:00Z","message":"listening on :8080"}
+{"timestamp":"2024-01-01T00:00:01Z","message":"GET / \"200\""}
+`
+
+	for _, tc := range []struct {
+		name string
+		want string
+	}{
+		{"web.log", text},
+		{"web.log.gz", text},
+		{"web.ndjson", ndjson},
+		{"web.JSONL.gz", ndjson},
+	} {
+		path := filepath.Join(t.TempDir(), tc.name)
+		w, err := logfile.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, l := range logs {
+			if err := w.Write(l); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := readFile(t, path); got != tc.want {
+			t.Errorf("%s:\n%s\nwant\n%s", tc.name, got, tc.want)
+		}
+	}
+}
+
+// readFile reads path, decompressing it when it is gzipped.
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var r io.Reader = file
+	if filepath.Ext(path) == ".gz" {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatalf("%s is not gzipped: %v", path, err)
+		}
+		r = gz
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRotating(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "logs", "web.log")
+	r, err := logfile.OpenRotating(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"one\n", "two\n", "three\n", "four\n", "five\n", "six\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a line that would take a file past 10 bytes starts a new one; the
+	// file with one and two went beyond the two backups kept
+	for name, want := range map[string]string{
+		path:        "six\n",
+		path + ".1": "four\nfive\n",
+		path + ".2": "three\n",
+	} {
+		if got := readFile(t, name); got != want {
+			t.Errorf("%s = %q, want %q", filepath.Base(name), got, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("kept more than two backups: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package logfile
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Rotating is a file that is rotated when it would grow past a size: path
+// is renamed to path.1, path.1 to path.2 and so on, keeping at most
+// MaxBackups old files, and a new path is started.
+type Rotating struct {
+	Path       string
+	MaxSize    int64
+	MaxBackups int
+
+	file *os.File
+	size int64
+}
+
+// OpenRotating opens path for appending, creating it and its directory if
+// needed.
+func OpenRotating(path string, maxSize int64, maxBackups int) (*Rotating, error) {
+	if maxSize <= 0 {
+		return nil, errors.New("the maximum size of a rotated file must be positive")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	r := &Rotating{Path: path, MaxSize: maxSize, MaxBackups: max(maxBackups, 0)}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Rotating) open() error {
+	file, err := os.OpenFile(r.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	r.file, r.size = file, info.Size()
+	return nil
+}
+
+// Write appends p, rotating the file first if p would make it larger than
+// MaxSize. p is never split across files, so a larger p gets a file of its
+// own.
+func (r *Rotating) Write(p []byte) (int, error) {
+	if r.size > 0 && r.size+int64(len(p)) > r.MaxSize {
+		if err := r.rotate(); err != nil {
+			return 0, fmt.Errorf("rotate %s: %w", r.Path, err)
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Sync commits the written data to disk.
+func (r *Rotating) Sync() error {
+	return r.file.Sync()
+}
+
+// Close closes the current file.
+func (r *Rotating) Close() error {
+	return r.file.Close()
+}
+
+func (r *Rotating) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	if r.MaxBackups == 0 {
+		if err := os.Remove(r.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return r.open()
+	}
+
+	backup := func(i int) string { return fmt.Sprintf("%s.%d", r.Path, i) }
+	if err := os.Remove(backup(r.MaxBackups)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	for i := r.MaxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(backup(i), backup(i+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	if err := os.Rename(r.Path, backup(1)); err != nil {
+		return err
+	}
+	return r.open()
+}
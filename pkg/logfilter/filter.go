@@ -0,0 +1,60 @@
+// Package logfilter selects log lines by time, content and level, for the
+// commands that print or ship service logs.
+package logfilter
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// Filter selects log lines. The zero Filter matches every line.
+type Filter struct {
+	// Since and Until bound the timestamps of the lines, inclusively.
+	// Zero values leave that end open.
+	Since, Until time.Time
+	// Pattern, when set, is a regular expression the message must match,
+	// or must not match if Invert is set.
+	Pattern *regexp.Regexp
+	Invert  bool
+	// Level, when set, is the least severe level to keep; lines whose
+	// level cannot be detected are dropped.
+	Level Level
+}
+
+// Match reports whether l passes the filter.
+func (f *Filter) Match(l *model.Log) bool {
+	if !f.Since.IsZero() && l.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && l.Timestamp.After(f.Until) {
+		return false
+	}
+	if f.Pattern != nil && f.Pattern.MatchString(l.Message) == f.Invert {
+		return false
+	}
+	if f.Level != LevelUnknown && DetectLevel(l.Message) < f.Level {
+		return false
+	}
+	return true
+}
+
+// Apply returns the lines of logs that pass the filter.
+func (f *Filter) Apply(logs model.Logs) model.Logs {
+	matched := model.Logs{}
+	for _, l := range logs {
+		if f.Match(l) {
+			matched = append(matched, l)
+		}
+	}
+	return matched
+}
+
+// Tail returns the last n lines of logs, or all of them when n is 0.
+func Tail(logs model.Logs, n int) model.Logs {
+	if n > 0 && len(logs) > n {
+		return logs[len(logs)-n:]
+	}
+	return logs
+}
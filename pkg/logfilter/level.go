@@ -0,0 +1,132 @@
+package logfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Level is the severity of a log line.
+type Level int
+
+// Levels from the least to the most severe. LevelUnknown is for lines
+// whose level cannot be told.
+const (
+	LevelUnknown Level = iota
+	LevelTrace
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+var levelNames = map[Level]string{
+	LevelTrace: "trace",
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+	LevelFatal: "fatal",
+}
+
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseLevel parses a level name as loggers write it, in any case:
+// trace, debug, info, warn or warning, error or err, and fatal, panic,
+// critical and their kin.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "trace":
+		return LevelTrace, nil
+	case "debug", "dbug":
+		return LevelDebug, nil
+	case "info", "information", "informational", "notice":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error", "err", "eror":
+		return LevelError, nil
+	case "fatal", "panic", "critical", "crit", "alert", "emerg", "emergency":
+		return LevelFatal, nil
+	}
+	return LevelUnknown, fmt.Errorf("unknown log level %q, must be one of trace, debug, info, warn, error, fatal", s)
+}
+
+// jsonLevelKeys are the fields structured loggers put the level in.
+var jsonLevelKeys = []string{"level", "lvl", "severity", "log.level", "levelname", "loglevel", "severity_text"}
+
+var logfmtLevel = regexp.MustCompile(`(?:^|\s)(?:level|lvl|severity)="?([A-Za-z]+)"?(?:\s|$)`)
+
+// DetectLevel tells the level of a log line from the level field of a JSON
+// object (including the numeric levels of pino and bunyan), from a logfmt
+// level=... pair, or from a level word such as ERROR or [warn] among the
+// first words of the line. It returns LevelUnknown when there is none.
+func DetectLevel(message string) Level {
+	message = strings.TrimSpace(message)
+
+	if strings.HasPrefix(message, "{") {
+		var fields map[string]any
+		if json.Unmarshal([]byte(message), &fields) == nil {
+			for _, key := range jsonLevelKeys {
+				switch v := fields[key].(type) {
+				case string:
+					if level, err := ParseLevel(v); err == nil {
+						return level
+					}
+				case float64:
+					return numericLevel(v)
+				}
+			}
+			return LevelUnknown
+		}
+	}
+
+	if m := logfmtLevel.FindStringSubmatch(message); m != nil {
+		if level, err := ParseLevel(m[1]); err == nil {
+			return level
+		}
+	}
+
+	for i, word := range strings.Fields(message) {
+		if i == 4 {
+			break
+		}
+		bracketed := strings.HasPrefix(word, "[") || strings.HasPrefix(word, "<")
+		word = strings.Trim(word, "[]<>:|")
+		// bare words only count in capitals, so that "info" in a sentence
+		// is not taken for a level
+		if !bracketed && word != strings.ToUpper(word) {
+			continue
+		}
+		if level, err := ParseLevel(word); err == nil {
+			return level
+		}
+	}
+	return LevelUnknown
+}
+
+// numericLevel maps the numeric levels of pino and bunyan.
+func numericLevel(v float64) Level {
+	switch {
+	case v >= 60:
+		return LevelFatal
+	case v >= 50:
+		return LevelError
+	case v >= 40:
+		return LevelWarn
+	case v >= 30:
+		return LevelInfo
+	case v >= 20:
+		return LevelDebug
+	case v >= 10:
+		return LevelTrace
+	}
+	return LevelUnknown
+}
@@ -0,0 +1,91 @@
+package logfilter_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/zeabur/cli/pkg/logfilter"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+func TestDetectLevel(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		message string
+		want    logfilter.Level
+	}{
+		{`{"level":"warn","msg":"slow query"}`, logfilter.LevelWarn},
+		{`{"severity":"ERROR","message":"boom"}`, logfilter.LevelError},
+		{`{"level":50,"msg":"pino error"}`, logfilter.LevelError},
+		{`{"log.level":"debug"}`, logfilter.LevelDebug},
+		{`{"msg":"no level"}`, logfilter.LevelUnknown},
+		{`time=2024-01-01T00:00:00Z level=info msg="started"`, logfilter.LevelInfo},
+		{`lvl="error" msg=failed`, logfilter.LevelError},
+		{`2024-01-01 12:00:00 WARN disk almost full`, logfilter.LevelWarn},
+		{`[error] connection refused`, logfilter.LevelError},
+		{`FATAL: out of memory`, logfilter.LevelFatal},
+		{`Server info: listening on :8080`, logfilter.LevelUnknown},
+		{`GET / 200`, logfilter.LevelUnknown},
+	} {
+		if got := logfilter.DetectLevel(tc.message); got != tc.want {
+			t.Errorf("DetectLevel(%q) = %s, want %s", tc.message, got, tc.want)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	logs := model.Logs{
+		{Timestamp: ts, Message: "level=info msg=starting"},
+		{Timestamp: ts.Add(time.Minute), Message: "level=error msg=boom"},
+		{Timestamp: ts.Add(2 * time.Minute), Message: "GET /healthz 200"},
+		{Timestamp: ts.Add(3 * time.Minute), Message: "level=warn msg=slow"},
+	}
+
+	messages := func(logs model.Logs) []string {
+		out := make([]string, 0, len(logs))
+		for _, l := range logs {
+			out = append(out, l.Message)
+		}
+		return out
+	}
+
+	for _, tc := range []struct {
+		name   string
+		filter logfilter.Filter
+		want   int
+	}{
+		{"zero", logfilter.Filter{}, 4},
+		{"since", logfilter.Filter{Since: ts.Add(time.Minute)}, 3},
+		{"until", logfilter.Filter{Until: ts.Add(time.Minute)}, 2},
+		{"grep", logfilter.Filter{Pattern: regexp.MustCompile(`msg=s`)}, 2},
+		{"invert", logfilter.Filter{Pattern: regexp.MustCompile(`healthz`), Invert: true}, 3},
+		{"level", logfilter.Filter{Level: logfilter.LevelWarn}, 2},
+	} {
+		if got := tc.filter.Apply(logs); len(got) != tc.want {
+			t.Errorf("%s: matched %q, want %d lines", tc.name, messages(got), tc.want)
+		}
+	}
+
+	if got := logfilter.Tail(logs, 2); len(got) != 2 || got[1] != logs[3] {
+		t.Errorf("Tail(2) = %q", messages(got))
+	}
+	if got := logfilter.Tail(logs, 0); len(got) != 4 {
+		t.Errorf("Tail(0) = %q, want every line", messages(got))
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+
+	if l, err := logfilter.ParseLevel("WARNING"); err != nil || l != logfilter.LevelWarn {
+		t.Errorf("ParseLevel(WARNING) = %s, %v", l, err)
+	}
+	if _, err := logfilter.ParseLevel("loud"); err == nil {
+		t.Error("ParseLevel(loud) succeeded")
+	}
+}
@@ -0,0 +1,44 @@
+package logship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/zeabur/cli/pkg/logfile"
+)
+
+// File appends entries to a local file as NDJSON, rotating it by size.
+type File struct {
+	file *logfile.Rotating
+}
+
+// NewFile opens path for appending; it is rotated when it would grow past
+// maxSize, keeping maxFiles old files.
+func NewFile(path string, maxSize int64, maxFiles int) (*File, error) {
+	file, err := logfile.OpenRotating(path, maxSize, maxFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &File{file: file}, nil
+}
+
+// Send implements Sink.
+func (f *File) Send(_ context.Context, batch []Entry) error {
+	for _, e := range batch {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return Permanent(err)
+		}
+		line = append(bytes.TrimSpace(line), '\n')
+		if _, err := f.file.Write(line); err != nil {
+			return err
+		}
+	}
+	return f.file.Sync()
+}
+
+// Close implements Sink.
+func (f *File) Close() error {
+	return f.file.Close()
+}
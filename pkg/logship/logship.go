@@ -0,0 +1,78 @@
+// Package logship forwards log entries to external sinks: the Loki push API,
+// OTLP/HTTP, RFC 5424 syslog and size-rotated files. Each sink is fed by a
+// Shipper, which buffers entries on disk and sends them in batches, so that
+// an outage of the sink delays lines rather than dropping them.
+package logship
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zeabur/cli/pkg/logfilter"
+)
+
+// Entry is a log line with where it comes from.
+type Entry struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Message       string    `json:"message"`
+	Project       string    `json:"project"`
+	ProjectID     string    `json:"projectID"`
+	Environment   string    `json:"environment"`
+	EnvironmentID string    `json:"environmentID"`
+	Service       string    `json:"service"`
+	ServiceID     string    `json:"serviceID"`
+	// Type is "runtime" or "build".
+	Type         string `json:"type"`
+	DeploymentID string `json:"deploymentID,omitempty"`
+}
+
+// Level is the level detected in the message.
+func (e *Entry) Level() logfilter.Level {
+	return logfilter.DetectLevel(e.Message)
+}
+
+// Sink delivers entries somewhere.
+type Sink interface {
+	// Send delivers a batch. On error the whole batch is sent again later,
+	// so a sink may receive some entries twice; errors wrapped with
+	// Permanent drop the batch instead.
+	Send(ctx context.Context, batch []Entry) error
+	Close() error
+}
+
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as one retrying cannot fix, such as a receiver
+// rejecting the batch as malformed.
+func Permanent(err error) error {
+	return &permanentError{err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// statusError turns an unsuccessful HTTP status into an error, permanent
+// unless the receiver may accept the batch later.
+func statusError(sink string, status int, body []byte) error {
+	err := fmt.Errorf("%s responded %d: %s", sink, status, truncate(string(body), 200))
+	switch {
+	case status == 408 || status == 429 || status >= 500:
+		return err
+	}
+	return Permanent(err)
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n] + "…"
+	}
+	return s
+}
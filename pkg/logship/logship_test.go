@@ -0,0 +1,388 @@
+package logship_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zeabur/cli/pkg/logship"
+)
+
+var ts = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+func entries() []logship.Entry {
+	base := logship.Entry{
+		Project: "api", ProjectID: "p1",
+		Environment: "production", EnvironmentID: "e1",
+		Service: "web", ServiceID: "s1",
+		Type: "runtime",
+	}
+	first, second, third := base, base, base
+	first.Timestamp, first.Message = ts.Add(time.Second), "ERROR connection refused"
+	second.Timestamp, second.Message = ts, "listening on :8080"
+	third.Timestamp, third.Message = ts, "building"
+	third.Service, third.ServiceID, third.Type, third.DeploymentID = "worker", "s2", "build", "d1"
+	return []logship.Entry{first, second, third}
+}
+
+func TestParseSink(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		spec string
+		want string
+		err  string
+	}{
+		{spec: "loki+http://localhost:3100?tenant=team", want: "Loki"},
+		{spec: "otlp+https://collector:4318", want: "OTLP"},
+		{spec: "syslog+udp://localhost:514", want: "Syslog"},
+		{spec: "file://" + filepath.Join(dir, "app.log") + "?max-size=1MB&max-files=2", want: "File"},
+		{spec: "loki://localhost:3100", err: "use loki+http://"},
+		{spec: "syslog+tcp://localhost", err: "missing port"},
+		{spec: "file://app.log", err: "absolute path"},
+		{spec: "file:///tmp/app.log?max-size=lots", err: "max-size"},
+		{spec: "kafka://localhost:9092", err: "unknown sink"},
+		{spec: "localhost:3100", err: "unknown sink"},
+	} {
+		sink, err := logship.ParseSink(tc.spec)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("ParseSink(%q) error = %v, want %q", tc.spec, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSink(%q): %v", tc.spec, err)
+			continue
+		}
+		if got := typeName(sink); got != tc.want {
+			t.Errorf("ParseSink(%q) = %s, want %s", tc.spec, got, tc.want)
+		}
+		_ = sink.Close()
+	}
+}
+
+func typeName(sink logship.Sink) string {
+	switch sink.(type) {
+	case *logship.Loki:
+		return "Loki"
+	case *logship.OTLP:
+		return "OTLP"
+	case *logship.Syslog:
+		return "Syslog"
+	case *logship.File:
+		return "File"
+	}
+	return "unknown"
+}
+
+func TestParseSize(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]int64{"512": 512, "64KB": 64 << 10, "100mb": 100 << 20, "1 GiB": 1 << 30} {
+		if got, err := logship.ParseSize(in); err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v, want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "0", "-1MB", "MB", "1TB"} {
+		if _, err := logship.ParseSize(in); err == nil {
+			t.Errorf("ParseSize(%q) succeeded", in)
+		}
+	}
+}
+
+// receiver records the requests of an HTTP sink and answers with status.
+func receiver(t *testing.T, status int) (*httptest.Server, <-chan *http.Request, <-chan []byte) {
+	t.Helper()
+	requests, bodies := make(chan *http.Request, 10), make(chan []byte, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- r
+		bodies <- body
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, requests, bodies
+}
+
+func TestLoki(t *testing.T) {
+	t.Parallel()
+
+	srv, requests, bodies := receiver(t, http.StatusNoContent)
+	sink, err := logship.ParseSink("loki+" + strings.Replace(srv.URL, "://", "://bot:secret@", 1) + "?tenant=team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	if err := sink.Send(context.Background(), entries()); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	r := <-requests
+	if r.URL.Path != "/loki/api/v1/push" || r.Header.Get("X-Scope-OrgID") != "team" {
+		t.Errorf("request = %s %s, tenant %q", r.Method, r.URL.Path, r.Header.Get("X-Scope-OrgID"))
+	}
+	if user, pass, _ := r.BasicAuth(); user != "bot" || pass != "secret" {
+		t.Errorf("basic auth = %q:%q", user, pass)
+	}
+
+	var push struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(<-bodies, &push); err != nil {
+		t.Fatal(err)
+	}
+	if len(push.Streams) != 2 {
+		t.Fatalf("streams = %+v, want web and worker", push.Streams)
+	}
+	web := push.Streams[0]
+	if web.Stream["service"] != "web" || web.Stream["type"] != "runtime" || web.Stream["project"] != "api" {
+		t.Errorf("labels = %v", web.Stream)
+	}
+	want := [][2]string{
+		{strconv.FormatInt(ts.UnixNano(), 10), "listening on :8080"},
+		{strconv.FormatInt(ts.Add(time.Second).UnixNano(), 10), "ERROR connection refused"},
+	}
+	if len(web.Values) != 2 || web.Values[0] != want[0] || web.Values[1] != want[1] {
+		t.Errorf("values = %v, want %v in time order", web.Values, want)
+	}
+}
+
+func TestLoki_Status(t *testing.T) {
+	t.Parallel()
+
+	for status, permanent := range map[int]bool{400: true, 401: true, 429: false, 503: false} {
+		srv, _, _ := receiver(t, status)
+		err := logship.NewLoki(srv.URL, "").Send(context.Background(), entries())
+		if err == nil || logship.IsPermanent(err) != permanent {
+			t.Errorf("status %d: error = %v, permanent = %v, want %v", status, err, logship.IsPermanent(err), permanent)
+		}
+	}
+}
+
+func TestOTLP(t *testing.T) {
+	t.Parallel()
+
+	srv, requests, bodies := receiver(t, http.StatusOK)
+	sink, err := logship.ParseSink("otlp+" + srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	if err := sink.Send(context.Background(), entries()); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if r := <-requests; r.URL.Path != "/v1/logs" || r.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("request = %s %s (%s)", r.Method, r.URL.Path, r.Header.Get("Content-Type"))
+	}
+
+	var export struct {
+		ResourceLogs []struct {
+			Resource struct {
+				Attributes []struct {
+					Key   string `json:"key"`
+					Value struct {
+						StringValue string `json:"stringValue"`
+					} `json:"value"`
+				} `json:"attributes"`
+			} `json:"resource"`
+			ScopeLogs []struct {
+				LogRecords []struct {
+					TimeUnixNano   string `json:"timeUnixNano"`
+					SeverityNumber int    `json:"severityNumber"`
+					SeverityText   string `json:"severityText"`
+					Body           struct {
+						StringValue string `json:"stringValue"`
+					} `json:"body"`
+				} `json:"logRecords"`
+			} `json:"scopeLogs"`
+		} `json:"resourceLogs"`
+	}
+	if err := json.Unmarshal(<-bodies, &export); err != nil {
+		t.Fatal(err)
+	}
+	if len(export.ResourceLogs) != 2 {
+		t.Fatalf("%d resources, want web and worker", len(export.ResourceLogs))
+	}
+	web := export.ResourceLogs[0]
+	if attr := web.Resource.Attributes[0]; attr.Key != "service.name" || attr.Value.StringValue != "web" {
+		t.Errorf("first resource attribute = %+v", attr)
+	}
+	records := web.ScopeLogs[0].LogRecords
+	if len(records) != 2 {
+		t.Fatalf("%d records, want 2", len(records))
+	}
+	if r := records[0]; r.Body.StringValue != "ERROR connection refused" || r.SeverityNumber != 17 || r.SeverityText != "ERROR" ||
+		r.TimeUnixNano != strconv.FormatInt(ts.Add(time.Second).UnixNano(), 10) {
+		t.Errorf("record = %+v", r)
+	}
+	if r := records[1]; r.SeverityNumber != 0 {
+		t.Errorf("a line without a level got severity %d", r.SeverityNumber)
+	}
+}
+
+func TestSyslog_TCP(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	frames := make(chan string, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			// octet counting: the length, a space, then the message
+			length, err := r.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(length))
+			msg := make([]byte, n)
+			if _, err := io.ReadFull(r, msg); err != nil {
+				return
+			}
+			frames <- string(msg)
+		}
+	}()
+
+	sink, err := logship.ParseSink("syslog+tcp://" + ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	if err := sink.Send(context.Background(), entries()); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	want := `<11>1 2024-05-01T10:00:01.000000Z api web - runtime [zeabur@32473 project="api" projectID="p1" environment="production" environmentID="e1" service="web" serviceID="s1"] ERROR connection refused`
+	if got := <-frames; got != want {
+		t.Errorf("frame =\n%s\nwant\n%s", got, want)
+	}
+	if got := <-frames; !strings.HasPrefix(got, "<14>1 ") || !strings.HasSuffix(got, "] listening on :8080") {
+		t.Errorf("frame = %s", got)
+	}
+	if got := <-frames; !strings.Contains(got, ` worker - build [`) || !strings.Contains(got, `deploymentID="d1"`) {
+		t.Errorf("frame = %s", got)
+	}
+}
+
+func TestSyslog_UDP(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	sink := logship.NewSyslog("udp", conn.LocalAddr().String())
+	defer sink.Close()
+	if err := sink.Send(context.Background(), entries()[:2]); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 2048)
+	for _, want := range []string{"] ERROR connection refused", "] listening on :8080"} {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(buf[:n]); !strings.HasPrefix(got, "<") || !strings.HasSuffix(got, want) {
+			t.Errorf("datagram = %q, want one message ending in %q", got, want)
+		}
+	}
+}
+
+func TestSyslog_Redials(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	sink := logship.NewSyslog("tcp", addr)
+	defer sink.Close()
+
+	_ = ln.Close()
+	if err := sink.Send(context.Background(), entries()); err == nil || logship.IsPermanent(err) {
+		t.Fatalf("send to a closed port: error = %v, want a temporary one", err)
+	}
+
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("cannot listen on %s again: %v", addr, err)
+	}
+	defer ln.Close()
+	go func() {
+		if conn, err := ln.Accept(); err == nil {
+			_, _ = io.Copy(io.Discard, conn)
+		}
+	}()
+	if err := sink.Send(context.Background(), entries()); err != nil {
+		t.Errorf("send after the receiver came back: %v", err)
+	}
+}
+
+func TestFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	// each entry takes about 250 bytes: two per file
+	sink, err := logship.ParseSink("file://" + path + "?max-size=600B&max-files=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch := entries()
+	if err := sink.Send(context.Background(), batch); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	readLines := func(path string) []logship.Entry {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []logship.Entry
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var e logship.Entry
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				t.Fatalf("line %q: %v", line, err)
+			}
+			got = append(got, e)
+		}
+		return got
+	}
+	if got := readLines(path + ".1"); len(got) != 2 || got[0].Message != batch[0].Message {
+		t.Errorf("rotated file = %+v", got)
+	}
+	if got := readLines(path); len(got) != 1 || got[0] != batch[2] {
+		t.Errorf("current file = %+v, want %+v", got, batch[2])
+	}
+}
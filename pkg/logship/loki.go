@@ -0,0 +1,118 @@
+package logship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Loki pushes entries to the Loki push API, one stream per service and
+// log type.
+type Loki struct {
+	endpoint string
+	tenant   string
+	username string
+	password string
+	client   *http.Client
+}
+
+// NewLoki returns a sink pushing to the Loki at base, e.g.
+// http://localhost:3100. User info in base is sent as basic auth, and a
+// non-empty tenant as the X-Scope-OrgID header.
+func NewLoki(base, tenant string) *Loki {
+	l := &Loki{tenant: tenant, client: &http.Client{Timeout: 30 * time.Second}}
+	if u, err := url.Parse(base); err == nil {
+		if u.User != nil {
+			l.username = u.User.Username()
+			l.password, _ = u.User.Password()
+			u.User = nil
+		}
+		if u.Path == "" || u.Path == "/" {
+			u.Path = "/loki/api/v1/push"
+		}
+		base = u.String()
+	}
+	l.endpoint = base
+	return l
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// Send implements Sink.
+func (l *Loki) Send(ctx context.Context, batch []Entry) error {
+	streams := map[string]*lokiStream{}
+	var keys []string
+	for _, e := range batch {
+		labels := map[string]string{
+			"project":     e.Project,
+			"environment": e.Environment,
+			"service":     e.Service,
+			"type":        e.Type,
+		}
+		key := strings.Join([]string{e.Project, e.Environment, e.Service, e.Type}, "\x00")
+		s, ok := streams[key]
+		if !ok {
+			s = &lokiStream{Stream: labels}
+			streams[key] = s
+			keys = append(keys, key)
+		}
+		s.Values = append(s.Values, [2]string{strconv.FormatInt(e.Timestamp.UnixNano(), 10), e.Message})
+	}
+
+	body := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{Streams: make([]*lokiStream, 0, len(keys))}
+	for _, key := range keys {
+		s := streams[key]
+		// older Loki versions reject out-of-order entries within a stream
+		slices.SortStableFunc(s.Values, func(a, b [2]string) int {
+			x, _ := strconv.ParseInt(a[0], 10, 64)
+			y, _ := strconv.ParseInt(b[0], 10, 64)
+			return int(min(max(x-y, -1), 1))
+		})
+		body.Streams = append(body.Streams, s)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if l.tenant != "" {
+		req.Header.Set("X-Scope-OrgID", l.tenant)
+	}
+	if l.username != "" {
+		req.SetBasicAuth(l.username, l.password)
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("push to loki: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return statusError("loki", resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+// Close implements Sink.
+func (l *Loki) Close() error {
+	l.client.CloseIdleConnections()
+	return nil
+}
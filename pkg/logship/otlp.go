@@ -0,0 +1,157 @@
+package logship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zeabur/cli/pkg/logfilter"
+)
+
+// OTLP sends entries as OTLP/HTTP logs, JSON encoded, with a resource per
+// service.
+type OTLP struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewOTLP returns a sink posting to the OTLP/HTTP receiver at base, e.g.
+// http://localhost:4318; without a path, /v1/logs is used.
+func NewOTLP(base string) *OTLP {
+	if u, err := url.Parse(base); err == nil && (u.Path == "" || u.Path == "/") {
+		u.Path = "/v1/logs"
+		base = u.String()
+	}
+	return &OTLP{endpoint: base, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpRecord struct {
+	TimeUnixNano         string          `json:"timeUnixNano"`
+	ObservedTimeUnixNano string          `json:"observedTimeUnixNano"`
+	SeverityNumber       int             `json:"severityNumber,omitempty"`
+	SeverityText         string          `json:"severityText,omitempty"`
+	Body                 otlpValue       `json:"body"`
+	Attributes           []otlpAttribute `json:"attributes"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope    `json:"scope"`
+	LogRecords []otlpRecord `json:"logRecords"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+// severities maps detected levels to the first OTLP severity number of
+// their range.
+var severities = map[logfilter.Level]int{
+	logfilter.LevelTrace: 1,
+	logfilter.LevelDebug: 5,
+	logfilter.LevelInfo:  9,
+	logfilter.LevelWarn:  13,
+	logfilter.LevelError: 17,
+	logfilter.LevelFatal: 21,
+}
+
+func attr(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: value}}
+}
+
+// Send implements Sink.
+func (o *OTLP) Send(ctx context.Context, batch []Entry) error {
+	observed := strconv.FormatInt(time.Now().UnixNano(), 10)
+	resources := map[string]*otlpResourceLogs{}
+	var order []string
+	for _, e := range batch {
+		rl, ok := resources[e.ServiceID]
+		if !ok {
+			rl = &otlpResourceLogs{
+				Resource: otlpResource{Attributes: []otlpAttribute{
+					attr("service.name", e.Service),
+					attr("service.instance.id", e.ServiceID),
+					attr("zeabur.project", e.Project),
+					attr("zeabur.project.id", e.ProjectID),
+					attr("zeabur.environment", e.Environment),
+					attr("zeabur.environment.id", e.EnvironmentID),
+				}},
+				ScopeLogs: []otlpScopeLogs{{Scope: otlpScope{Name: "zeabur"}}},
+			}
+			resources[e.ServiceID] = rl
+			order = append(order, e.ServiceID)
+		}
+
+		record := otlpRecord{
+			TimeUnixNano:         strconv.FormatInt(e.Timestamp.UnixNano(), 10),
+			ObservedTimeUnixNano: observed,
+			Body:                 otlpValue{StringValue: e.Message},
+			Attributes:           []otlpAttribute{attr("zeabur.log.type", e.Type)},
+		}
+		if e.DeploymentID != "" {
+			record.Attributes = append(record.Attributes, attr("zeabur.deployment.id", e.DeploymentID))
+		}
+		if level := e.Level(); level != logfilter.LevelUnknown {
+			record.SeverityNumber = severities[level]
+			record.SeverityText = strings.ToUpper(level.String())
+		}
+		rl.ScopeLogs[0].LogRecords = append(rl.ScopeLogs[0].LogRecords, record)
+	}
+
+	body := struct {
+		ResourceLogs []*otlpResourceLogs `json:"resourceLogs"`
+	}{ResourceLogs: make([]*otlpResourceLogs, 0, len(order))}
+	for _, id := range order {
+		body.ResourceLogs = append(body.ResourceLogs, resources[id])
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("export to otlp: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return statusError("otlp", resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+// Close implements Sink.
+func (o *OTLP) Close() error {
+	o.client.CloseIdleConnections()
+	return nil
+}
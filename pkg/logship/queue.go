@@ -0,0 +1,416 @@
+package logship
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+const (
+	// segmentSize is the size past which the queue starts a new segment
+	// file, so that shipped entries can be deleted a file at a time.
+	segmentSize = 8 << 20
+	cursorFile  = "cursor"
+	lockFile    = "lock"
+)
+
+// ErrQueueLocked is returned by OpenQueue when another process has the
+// queue open.
+var ErrQueueLocked = errors.New("the queue is in use by another process")
+
+// Queue is a first-in, first-out queue of entries kept on disk, so that
+// entries not yet shipped survive both a sink outage and a restart.
+//
+// Entries are appended as NDJSON to numbered segment files; the position of
+// the oldest unshipped entry is kept in a cursor file. Push blocks while
+// the unshipped entries take more than the maximum size, which slows the
+// producer down rather than dropping lines. There must be a single reader;
+// a lock file keeps other processes from opening the same queue.
+type Queue struct {
+	dir     string
+	maxSize int64
+	lock    *flock.Flock
+
+	mu      sync.Mutex
+	changed chan struct{} // closed and replaced whenever the queue changes
+	closed  bool
+
+	segments []int // numbers of the segment files, oldest first
+	writer   *os.File
+	written  int64 // size of the last segment
+
+	read    position // oldest entry not yet committed
+	pending position // after the entries returned by the last Read
+	// pendingSize is the bytes between read and pending
+	pendingSize int64
+	size        int64 // bytes not yet committed
+	count       int   // entries not yet committed
+	unread      int   // entries after pending
+}
+
+// position is an offset in a segment.
+type position struct {
+	segment int
+	offset  int64
+}
+
+// OpenQueue opens the queue in dir, creating the directory if needed.
+// Push blocks while more than maxSize bytes are queued. It returns
+// ErrQueueLocked when another process has the queue open.
+func OpenQueue(dir string, maxSize int64) (_ *Queue, err error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	lock := flock.New(filepath.Join(dir, lockFile), flock.SetPermissions(0o600))
+	locked, err := lock.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("lock the queue: %w", err)
+	}
+	if !locked {
+		return nil, ErrQueueLocked
+	}
+	defer func() {
+		if err != nil {
+			_ = lock.Unlock()
+		}
+	}()
+	q := &Queue{dir: dir, maxSize: maxSize, lock: lock, changed: make(chan struct{})}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".ndjson")
+		if n, err := strconv.Atoi(name); ok && err == nil {
+			q.segments = append(q.segments, n)
+		}
+	}
+	slices.Sort(q.segments)
+	if len(q.segments) == 0 {
+		q.segments = []int{1}
+	}
+
+	q.read = position{segment: q.segments[0]}
+	if data, err := os.ReadFile(filepath.Join(dir, cursorFile)); err == nil {
+		var cursor position
+		if _, err := fmt.Sscanf(string(data), "%d %d", &cursor.segment, &cursor.offset); err == nil && slices.Contains(q.segments, cursor.segment) {
+			q.read = cursor
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	// segments before the cursor were shipped but not deleted yet
+	for len(q.segments) > 1 && q.segments[0] < q.read.segment {
+		_ = os.Remove(q.segmentPath(q.segments[0]))
+		q.segments = q.segments[1:]
+	}
+	q.pending = q.read
+
+	last := q.segments[len(q.segments)-1]
+	q.writer, err = os.OpenFile(q.segmentPath(last), os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	// a crash in the middle of a write leaves a partial line behind
+	if q.written, err = truncatePartialLine(q.writer); err != nil {
+		_ = q.writer.Close()
+		return nil, err
+	}
+	if _, err := q.writer.Seek(0, io.SeekEnd); err != nil {
+		_ = q.writer.Close()
+		return nil, err
+	}
+
+	for _, segment := range q.segments {
+		from := int64(0)
+		if segment == q.read.segment {
+			from = q.read.offset
+		}
+		size, count, err := q.measure(segment, from)
+		if err != nil {
+			_ = q.writer.Close()
+			return nil, err
+		}
+		q.size += size
+		q.count += count
+	}
+	q.unread = q.count
+	return q, nil
+}
+
+func (q *Queue) segmentPath(n int) string {
+	return filepath.Join(q.dir, fmt.Sprintf("%08d.ndjson", n))
+}
+
+// truncatePartialLine cuts file after its last newline and returns the
+// resulting size.
+func truncatePartialLine(file *os.File) (int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+	if size == 0 {
+		return 0, nil
+	}
+	tail := make([]byte, min(size, 64<<10))
+	for end := size; end > 0; {
+		start := max(end-int64(len(tail)), 0)
+		chunk := tail[:end-start]
+		if _, err := file.ReadAt(chunk, start); err != nil {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			size = start + int64(i) + 1
+			break
+		}
+		end, size = start, 0
+	}
+	if size != info.Size() {
+		if err := file.Truncate(size); err != nil {
+			return 0, err
+		}
+	}
+	return size, nil
+}
+
+// measure returns the bytes and lines of segment after offset.
+func (q *Queue) measure(segment int, offset int64) (int64, int, error) {
+	file, err := os.Open(q.segmentPath(segment))
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, 0, nil
+	} else if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return 0, 0, err
+	}
+
+	var size int64
+	count := 0
+	buf := make([]byte, 64<<10)
+	for {
+		n, err := file.Read(buf)
+		size += int64(n)
+		count += bytes.Count(buf[:n], []byte{'\n'})
+		if err == io.EOF {
+			return size, count, nil
+		} else if err != nil {
+			return 0, 0, err
+		}
+	}
+}
+
+// Len returns the number of entries not yet committed.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.count
+}
+
+// Size returns the bytes taken by the entries not yet committed.
+func (q *Queue) Size() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size
+}
+
+// notify wakes up everyone waiting for a change. q.mu must be held.
+func (q *Queue) notify() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// Push appends entries, waiting while the queue is full until there is room
+// or ctx is done.
+func (q *Queue) Push(ctx context.Context, entries ...Entry) error {
+	var data []byte
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	q.mu.Lock()
+	for q.maxSize > 0 && q.size > 0 && q.size+int64(len(data)) > q.maxSize && !q.closed {
+		changed := q.changed
+		q.mu.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		q.mu.Lock()
+	}
+	defer q.mu.Unlock()
+	if q.closed {
+		return errors.New("the queue is closed")
+	}
+
+	if q.written > 0 && q.written+int64(len(data)) > segmentSize {
+		if err := q.roll(); err != nil {
+			return err
+		}
+	}
+	n, err := q.writer.Write(data)
+	q.written += int64(n)
+	if err != nil {
+		// leave no partial line for the reader to trip over
+		_ = q.writer.Truncate(q.written - int64(n))
+		q.written -= int64(n)
+		return fmt.Errorf("write the log buffer: %w", err)
+	}
+	q.size += int64(len(data))
+	q.count += len(entries)
+	q.unread += len(entries)
+	q.notify()
+	return nil
+}
+
+// roll starts a new segment. q.mu must be held.
+func (q *Queue) roll() error {
+	if err := q.writer.Close(); err != nil {
+		return err
+	}
+	next := q.segments[len(q.segments)-1] + 1
+	writer, err := os.OpenFile(q.segmentPath(next), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	q.writer, q.written = writer, 0
+	q.segments = append(q.segments, next)
+	return nil
+}
+
+// Read returns up to n entries after those returned by the previous Read,
+// without removing them from the queue; Commit does. It waits until there
+// is at least one entry, then up to wait for the batch to fill. It returns
+// ctx.Err() when ctx is done first.
+func (q *Queue) Read(ctx context.Context, n int, wait time.Duration) ([]Entry, error) {
+	var timeout <-chan time.Time
+	for {
+		q.mu.Lock()
+		if q.unread >= n || (q.unread > 0 && wait <= 0) {
+			defer q.mu.Unlock()
+			return q.readLocked(n)
+		}
+		if q.unread > 0 && timeout == nil {
+			timer := time.NewTimer(wait)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+		changed := q.changed
+		q.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-timeout:
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			return q.readLocked(n)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// readLocked reads up to n entries at q.pending. q.mu must be held.
+func (q *Queue) readLocked(n int) ([]Entry, error) {
+	var entries []Entry
+	for len(entries) < n && q.unread > 0 {
+		file, err := os.Open(q.segmentPath(q.pending.segment))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := file.Seek(q.pending.offset, io.SeekStart); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		r := bufio.NewReader(file)
+		for len(entries) < n && q.unread > 0 {
+			line, err := r.ReadBytes('\n')
+			if err != nil {
+				// the end of a segment that is no longer written to
+				break
+			}
+			q.pending.offset += int64(len(line))
+			q.pendingSize += int64(len(line))
+			q.unread--
+			var e Entry
+			if err := json.Unmarshal(line, &e); err != nil {
+				// a corrupt line cannot be shipped; skip it
+				continue
+			}
+			entries = append(entries, e)
+		}
+		_ = file.Close()
+
+		if len(entries) < n && q.unread > 0 {
+			i := slices.Index(q.segments, q.pending.segment)
+			if i < 0 || i == len(q.segments)-1 {
+				return nil, fmt.Errorf("the log buffer in %s is inconsistent", q.dir)
+			}
+			q.pending = position{segment: q.segments[i+1]}
+		}
+	}
+	return entries, nil
+}
+
+// Commit removes the entries returned by the previous Reads from the queue.
+func (q *Queue) Commit() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.pending == q.read {
+		return nil
+	}
+
+	cursor := filepath.Join(q.dir, cursorFile)
+	data := fmt.Sprintf("%d %d\n", q.pending.segment, q.pending.offset)
+	if err := os.WriteFile(cursor+".tmp", []byte(data), 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(cursor+".tmp", cursor); err != nil {
+		return err
+	}
+
+	for len(q.segments) > 1 && q.segments[0] < q.pending.segment {
+		_ = os.Remove(q.segmentPath(q.segments[0]))
+		q.segments = q.segments[1:]
+	}
+	q.count = q.unread
+	q.size -= q.pendingSize
+	q.read, q.pendingSize = q.pending, 0
+	q.notify()
+	return nil
+}
+
+// Close closes the queue; the entries not yet committed are read again
+// when it is next opened. Push calls waiting for room return an error.
+func (q *Queue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return nil
+	}
+	q.closed = true
+	q.notify()
+	return errors.Join(q.writer.Close(), q.lock.Unlock())
+}
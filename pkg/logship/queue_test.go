@@ -0,0 +1,240 @@
+package logship_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zeabur/cli/pkg/logship"
+)
+
+func TestQueue_Persists(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	q, err := logship.OpenQueue(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Push(ctx, entries()...); err != nil {
+		t.Fatal(err)
+	}
+	batch, err := q.Read(ctx, 2, 0)
+	if err != nil || len(batch) != 2 {
+		t.Fatalf("read = %d entries, %v", len(batch), err)
+	}
+	if err := q.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	// read but not committed: shipped again after a restart
+	if batch, _ := q.Read(ctx, 2, 0); len(batch) != 1 {
+		t.Fatalf("second read = %d entries, want 1", len(batch))
+	}
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a crash in the middle of a write
+	segment := filepath.Join(dir, "00000001.ndjson")
+	file, err := os.OpenFile(segment, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = file.WriteString(`{"message":"cut sh`)
+	_ = file.Close()
+
+	q, err = logship.OpenQueue(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+	if q.Len() != 1 {
+		t.Errorf("reopened queue holds %d entries, want 1", q.Len())
+	}
+	if err := q.Push(ctx, entries()[0]); err != nil {
+		t.Fatal(err)
+	}
+	batch, err = q.Read(ctx, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch) != 2 || batch[0] != entries()[2] || batch[1] != entries()[0] {
+		t.Errorf("batch after reopening = %+v", batch)
+	}
+}
+
+func TestQueue_Locked(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	q, err := logship.OpenQueue(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := logship.OpenQueue(dir, 0); !errors.Is(err, logship.ErrQueueLocked) {
+		t.Fatalf("second open = %v, want ErrQueueLocked", err)
+	}
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err = logship.OpenQueue(dir, 0)
+	if err != nil {
+		t.Fatalf("open after close: %v", err)
+	}
+	_ = q.Close()
+}
+
+func TestQueue_Backpressure(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	// room for about one entry
+	q, err := logship.OpenQueue(t.TempDir(), 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	if err := q.Push(ctx, entries()[0]); err != nil {
+		t.Fatal(err)
+	}
+	timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := q.Push(timeout, entries()[1]); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("push to a full queue = %v, want it to block", err)
+	}
+
+	pushed := make(chan error, 1)
+	go func() { pushed <- q.Push(ctx, entries()[1]) }()
+	if _, err := q.Read(ctx, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-pushed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("push still blocked after the queue was drained")
+	}
+	if q.Len() != 1 {
+		t.Errorf("queue holds %d entries, want 1", q.Len())
+	}
+}
+
+func TestQueue_ReadWaitsForBatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	q, err := logship.OpenQueue(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	go func() {
+		_ = q.Push(ctx, entries()[0])
+		time.Sleep(20 * time.Millisecond)
+		_ = q.Push(ctx, entries()[1])
+	}()
+	batch, err := q.Read(ctx, 10, time.Second)
+	if err != nil || len(batch) != 2 {
+		t.Errorf("read = %d entries, %v; want both within the batch wait", len(batch), err)
+	}
+}
+
+// flakySink fails the first sends, then records the batches.
+type flakySink struct {
+	mu       sync.Mutex
+	failures int
+	err      error
+	batches  [][]logship.Entry
+}
+
+func (s *flakySink) Send(_ context.Context, batch []logship.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return s.err
+	}
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func (s *flakySink) Close() error { return nil }
+
+func TestShipper_RetriesUntilDelivered(t *testing.T) {
+	t.Parallel()
+
+	q, err := logship.OpenQueue(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+	sink := &flakySink{failures: 1, err: errors.New("connection refused")}
+	var retries []time.Duration
+	s := &logship.Shipper{
+		Sink: sink, Queue: q, BatchSize: 2,
+		OnError: func(_ error, retryIn time.Duration) { retries = append(retries, retryIn) },
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+	if err := s.Enqueue(ctx, entries()...); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for q.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if len(retries) != 1 || retries[0] != time.Second {
+		t.Errorf("retries = %v, want one after 1s", retries)
+	}
+	if len(sink.batches) != 2 || len(sink.batches[0]) != 2 || len(sink.batches[1]) != 1 {
+		t.Errorf("batches = %v, want 2 entries then 1", sink.batches)
+	}
+}
+
+func TestShipper_DropsRejectedBatches(t *testing.T) {
+	t.Parallel()
+
+	q, err := logship.OpenQueue(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+	sink := &flakySink{failures: 1, err: logship.Permanent(errors.New("400 bad request"))}
+	dropped := 0
+	s := &logship.Shipper{Sink: sink, Queue: q, BatchSize: 10, OnDrop: func(_ error, n int) { dropped += n }}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+	_ = s.Enqueue(ctx, entries()...)
+	deadline := time.Now().Add(5 * time.Second)
+	for q.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if dropped != 3 || len(sink.batches) != 0 {
+		t.Errorf("dropped %d, delivered %v; want the batch dropped", dropped, sink.batches)
+	}
+}
@@ -0,0 +1,92 @@
+package logship
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	retryDelay    = time.Second
+	maxRetryDelay = time.Minute
+)
+
+// Shipper sends the entries of a queue to a sink in batches. A batch the
+// sink fails to take stays in the queue and is sent again after a growing
+// delay, so an outage holds lines back instead of losing them.
+type Shipper struct {
+	Sink  Sink
+	Queue *Queue
+	// BatchSize is the most entries sent at once.
+	BatchSize int
+	// BatchWait is how long to wait for a batch to fill before sending
+	// what there is.
+	BatchWait time.Duration
+	// OnError, if not nil, is told about each failed send and when it is
+	// retried.
+	OnError func(err error, retryIn time.Duration)
+	// OnDrop, if not nil, is told about batches dropped because the sink
+	// rejected them for good.
+	OnDrop func(err error, dropped int)
+}
+
+// Enqueue queues entries for shipping, waiting while the queue is full.
+func (s *Shipper) Enqueue(ctx context.Context, entries ...Entry) error {
+	return s.Queue.Push(ctx, entries...)
+}
+
+// Run ships queued entries until ctx is done, when it returns nil; the
+// entries not shipped yet stay in the queue.
+func (s *Shipper) Run(ctx context.Context) error {
+	for {
+		batch, err := s.Queue.Read(ctx, max(s.BatchSize, 1), s.BatchWait)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			// only corrupt lines were read
+			if err := s.Queue.Commit(); err != nil {
+				return err
+			}
+			continue
+		}
+		if !s.send(ctx, batch) {
+			return nil
+		}
+		if err := s.Queue.Commit(); err != nil {
+			return err
+		}
+	}
+}
+
+// send sends batch until the sink takes or rejects it, and returns false
+// if ctx is done first.
+func (s *Shipper) send(ctx context.Context, batch []Entry) bool {
+	delay := retryDelay
+	for {
+		err := s.Sink.Send(ctx, batch)
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		if IsPermanent(err) {
+			if s.OnDrop != nil {
+				s.OnDrop(err, len(batch))
+			}
+			return true
+		}
+		if s.OnError != nil {
+			s.OnError(err, delay)
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
@@ -0,0 +1,103 @@
+package logship
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SinkSpecs describes the accepted --sink values, for help texts.
+const SinkSpecs = `loki+http://host:3100[?tenant=ID]      Loki push API (or loki+https)
+otlp+http://host:4318                  OTLP/HTTP logs, JSON encoded (or otlp+https)
+syslog+tcp://host:514                  RFC 5424 syslog over TCP (or syslog+udp)
+file:///path/to/app.log[?max-size=100MB&max-files=5]
+                                       NDJSON file rotated by size`
+
+// ParseSink builds a sink from a URL such as loki+http://localhost:3100;
+// see SinkSpecs.
+func ParseSink(spec string) (Sink, error) {
+	u, err := url.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sink %q: %w", spec, err)
+	}
+	kind, transport, _ := strings.Cut(u.Scheme, "+")
+	query := u.Query()
+
+	switch kind {
+	case "loki", "otlp":
+		if transport != "http" && transport != "https" {
+			return nil, fmt.Errorf("invalid sink %q: use %s+http:// or %s+https://", spec, kind, kind)
+		}
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid sink %q: missing host", spec)
+		}
+		endpoint := *u
+		endpoint.Scheme = transport
+		endpoint.RawQuery = ""
+		if kind == "loki" {
+			return NewLoki(endpoint.String(), query.Get("tenant")), nil
+		}
+		return NewOTLP(endpoint.String()), nil
+	case "syslog":
+		if transport != "tcp" && transport != "udp" {
+			return nil, fmt.Errorf("invalid sink %q: use syslog+tcp:// or syslog+udp://", spec)
+		}
+		if u.Port() == "" {
+			return nil, fmt.Errorf("invalid sink %q: missing port", spec)
+		}
+		return NewSyslog(transport, u.Host), nil
+	case "file":
+		path := u.Path
+		if u.Host != "" || u.Opaque != "" {
+			// file://relative/path is not a valid file URL, but a likely typo
+			return nil, fmt.Errorf("invalid sink %q: use an absolute path, e.g. file:///var/log/app.log", spec)
+		}
+		if path == "" {
+			return nil, fmt.Errorf("invalid sink %q: missing path", spec)
+		}
+		maxSize := int64(100 << 20)
+		if s := query.Get("max-size"); s != "" {
+			if maxSize, err = ParseSize(s); err != nil {
+				return nil, fmt.Errorf("invalid sink %q: max-size: %w", spec, err)
+			}
+		}
+		maxFiles := 5
+		if s := query.Get("max-files"); s != "" {
+			if maxFiles, err = strconv.Atoi(s); err != nil || maxFiles < 0 {
+				return nil, fmt.Errorf("invalid sink %q: max-files must be a number of files", spec)
+			}
+		}
+		return NewFile(path, maxSize, maxFiles)
+	case "":
+		return nil, fmt.Errorf("invalid sink %q: missing scheme such as loki+http://", spec)
+	}
+	return nil, fmt.Errorf("unknown sink %q: must be loki, otlp, syslog or file", u.Scheme)
+}
+
+// ParseSize parses a size such as 512, 64KB, 100MB or 1GiB. Units are
+// powers of 1024 either way.
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	upper := strings.ToUpper(s)
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		value  int64
+	}{
+		{"KIB", 1 << 10}, {"MIB", 1 << 20}, {"GIB", 1 << 30},
+		{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30},
+		{"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30}, {"B", 1},
+	} {
+		if strings.HasSuffix(upper, unit.suffix) {
+			upper, multiplier = strings.TrimSpace(strings.TrimSuffix(upper, unit.suffix)), unit.value
+			break
+		}
+	}
+	n, err := strconv.ParseInt(upper, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, errors.New("invalid size " + strconv.Quote(s) + ", e.g. 100MB")
+	}
+	return n * multiplier, nil
+}
@@ -0,0 +1,165 @@
+package logship
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeabur/cli/pkg/logfilter"
+)
+
+const (
+	// syslogFacility is the facility of the messages: user-level.
+	syslogFacility = 1
+	// syslogSDID identifies the structured data carrying the Zeabur fields.
+	syslogSDID = "zeabur@32473"
+	// maxDatagram caps a message sent over UDP, which cannot be split.
+	maxDatagram = 60 * 1024
+)
+
+// syslogSeverities maps detected levels to syslog severities; lines
+// without a level are informational.
+var syslogSeverities = map[logfilter.Level]int{
+	logfilter.LevelTrace: 7,
+	logfilter.LevelDebug: 7,
+	logfilter.LevelInfo:  6,
+	logfilter.LevelWarn:  4,
+	logfilter.LevelError: 3,
+	logfilter.LevelFatal: 2,
+}
+
+// Syslog sends entries as RFC 5424 messages, over TCP with octet-counting
+// framing (RFC 6587) or over UDP with a message per datagram (RFC 5426).
+type Syslog struct {
+	network string
+	address string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslog returns a sink sending to address over network, "tcp" or
+// "udp". It connects on the first Send and again after a failed one.
+func NewSyslog(network, address string) *Syslog {
+	return &Syslog{network: network, address: address}
+}
+
+// Send implements Sink.
+func (s *Syslog) Send(ctx context.Context, batch []Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, s.network, s.address)
+		if err != nil {
+			return fmt.Errorf("connect to syslog: %w", err)
+		}
+		s.conn = conn
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(30 * time.Second)
+	}
+	_ = s.conn.SetWriteDeadline(deadline)
+
+	var buf bytes.Buffer
+	for _, e := range batch {
+		msg := formatSyslog(&e)
+		if s.network == "udp" {
+			if len(msg) > maxDatagram {
+				msg = msg[:maxDatagram]
+			}
+			if _, err := s.conn.Write(msg); err != nil {
+				return s.fail(err)
+			}
+			continue
+		}
+		buf.WriteString(strconv.Itoa(len(msg)))
+		buf.WriteByte(' ')
+		buf.Write(msg)
+	}
+	if buf.Len() > 0 {
+		if _, err := s.conn.Write(buf.Bytes()); err != nil {
+			return s.fail(err)
+		}
+	}
+	return nil
+}
+
+// fail drops the connection so that the next Send dials again.
+func (s *Syslog) fail(err error) error {
+	_ = s.conn.Close()
+	s.conn = nil
+	return fmt.Errorf("send to syslog: %w", err)
+}
+
+// Close implements Sink.
+func (s *Syslog) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// formatSyslog formats e as an RFC 5424 message: the project is the host
+// name, the service the app name and the log type the message ID.
+func formatSyslog(e *Entry) []byte {
+	severity, ok := syslogSeverities[e.Level()]
+	if !ok {
+		severity = 6
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<%d>1 %s %s %s - %s [%s",
+		syslogFacility*8+severity,
+		e.Timestamp.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		headerField(e.Project, 255),
+		headerField(e.Service, 48),
+		headerField(e.Type, 32),
+		syslogSDID)
+	for _, param := range [][2]string{
+		{"project", e.Project},
+		{"projectID", e.ProjectID},
+		{"environment", e.Environment},
+		{"environmentID", e.EnvironmentID},
+		{"service", e.Service},
+		{"serviceID", e.ServiceID},
+		{"deploymentID", e.DeploymentID},
+	} {
+		if param[1] != "" {
+			fmt.Fprintf(&b, ` %s="%s"`, param[0], sdEscaper.Replace(param[1]))
+		}
+	}
+	b.WriteString("] ")
+	b.WriteString(e.Message)
+	return b.Bytes()
+}
+
+var sdEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// headerField makes s a valid header field: printable ASCII without spaces,
+// at most n characters, or "-" when empty.
+func headerField(s string, n int) string {
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '_'
+		}
+		return r
+	}, s)
+	if len(s) > n {
+		s = s[:n]
+	}
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,106 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// Apply executes plan through the mutation APIs. It takes the manifest for
+// the values the plan does not carry (variable values, service sources) and
+// the state the plan was computed from for the IDs of existing services.
+//
+// Actions run in plan order and Apply stops at the first failure; onAction,
+// when non-nil, is called after each action succeeds so callers can report
+// progress.
+func Apply(ctx context.Context, client api.Client, m *Manifest, state *State, plan *Plan, onAction func(Action)) error {
+	desired := make(map[string]Service, len(m.Services))
+	for _, s := range m.Services {
+		desired[s.Name] = s
+	}
+	ids := make(map[string]string, len(state.Services))
+	for name, s := range state.Services {
+		ids[name] = s.ID
+	}
+	envID := plan.EnvironmentID
+
+	// UpdateVariables replaces the whole set, so it runs once per service
+	// no matter how many variable actions the plan holds.
+	variablesSynced := make(map[string]bool)
+
+	for _, a := range plan.Actions {
+		id := ids[a.Service]
+		var err error
+
+		switch {
+		case a.Resource == ResourceService && a.Kind == ActionCreate:
+			var service *model.Service
+			service, err = createService(ctx, client, plan.ProjectID, desired[a.Service])
+			if err == nil {
+				ids[a.Service] = service.ID
+			}
+		case a.Resource == ResourceService && a.Kind == ActionDelete:
+			err = client.DeleteService(ctx, id)
+		case a.Resource == ResourceVariable:
+			if !variablesSynced[a.Service] {
+				err = updateVariables(ctx, client, id, envID, desired[a.Service].Variables)
+				variablesSynced[a.Service] = err == nil
+			}
+		case a.Resource == ResourcePortForwarding:
+			err = client.UpdatePortForwardingMode(ctx, id, envID, model.PortForwardingMode(a.To))
+		case a.Resource == ResourceImageTag:
+			err = client.UpdateImageTag(ctx, id, envID, a.To)
+		case a.Resource == ResourceDomain && a.Kind == ActionCreate:
+			isGenerated, domain := splitGeneratedDomain(a.Key)
+			_, err = client.AddDomain(ctx, id, envID, isGenerated, domain)
+		case a.Resource == ResourceDomain && a.Kind == ActionDelete:
+			_, err = client.RemoveDomain(ctx, a.Key)
+		default:
+			err = fmt.Errorf("unsupported action")
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", a.Kind, a.Describe(), err)
+		}
+		if onAction != nil {
+			onAction(a)
+		}
+	}
+
+	return nil
+}
+
+func createService(ctx context.Context, client api.Client, projectID string, s Service) (*model.Service, error) {
+	switch {
+	case s.Source != nil && s.Source.Prebuilt != "":
+		return client.CreatePrebuiltService(ctx, projectID, s.Source.Prebuilt)
+	case s.Source != nil && s.Source.Git != nil:
+		return client.CreateService(ctx, projectID, s.Name, s.Source.Git.RepoID, s.Source.Git.Branch)
+	default:
+		return client.CreateEmptyService(ctx, projectID, s.Name)
+	}
+}
+
+func updateVariables(ctx context.Context, client api.Client, serviceID, environmentID string, vars map[string]string) error {
+	ok, err := client.UpdateVariables(ctx, serviceID, environmentID, vars)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("variables were not updated")
+	}
+	return nil
+}
+
+// splitGeneratedDomain turns "my-api.zeabur.app" into the subdomain part
+// that addDomain expects for generated domains; custom domains pass through.
+func splitGeneratedDomain(domain string) (bool, string) {
+	lower := strings.ToLower(domain)
+	if strings.HasSuffix(lower, GeneratedDomainSuffix) {
+		return true, domain[:len(domain)-len(GeneratedDomainSuffix)]
+	}
+	return false, domain
+}
@@ -0,0 +1,143 @@
+// Package manifest describes a project declaratively and converges the live
+// state towards it, the engine behind `zeabur plan` and `zeabur apply`.
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// DefaultFile is the manifest file looked up in the working directory.
+const DefaultFile = "zeabur.yaml"
+
+// GeneratedDomainSuffix marks domains that Zeabur generates for a service
+// (as opposed to custom domains the user owns).
+const GeneratedDomainSuffix = ".zeabur.app"
+
+// Manifest is the declarative description of a project, meant to be kept in
+// git next to the code.
+//
+//	project: my-project
+//	services:
+//	  - name: api
+//	    port_forwarding: disabled
+//	    variables:
+//	      LOG_LEVEL: info
+//	    domains:
+//	      - api.example.com
+//	      - my-api.zeabur.app
+//	  - name: cache
+//	    source:
+//	      prebuilt: redis
+//	    image_tag: "7.2"
+//
+// Fields left out are unmanaged: omitting `variables` leaves the live
+// variables alone, while `variables: {}` removes all of them.
+type Manifest struct {
+	// Project is the name or ID of the project the manifest applies to.
+	Project string `yaml:"project" json:"project"`
+	// Environment is the environment ID. Optional; defaults to the
+	// project's environment.
+	Environment string    `yaml:"environment,omitempty" json:"environment,omitempty"`
+	Services    []Service `yaml:"services" json:"services"`
+}
+
+// Service is the desired state of one service, matched to the live
+// service by name.
+type Service struct {
+	Name string `yaml:"name" json:"name"`
+	// Source is only used when the service has to be created.
+	Source *Source `yaml:"source,omitempty" json:"source,omitempty"`
+	// ImageTag is the image tag of a prebuilt service.
+	ImageTag string `yaml:"image_tag,omitempty" json:"imageTag,omitempty"`
+	// PortForwarding is "enabled" or "disabled".
+	PortForwarding string            `yaml:"port_forwarding,omitempty" json:"portForwarding,omitempty"`
+	Variables      map[string]string `yaml:"variables,omitempty" json:"variables,omitempty"`
+	Domains        []string          `yaml:"domains,omitempty" json:"domains,omitempty"`
+}
+
+// Source describes how a missing service is created. Leaving it empty
+// creates an empty service that code can be deployed to with `zeabur deploy`.
+type Source struct {
+	// Prebuilt is a marketplace code, e.g. "redis" or "postgresql". The
+	// created service is named by the marketplace item, so the manifest
+	// entry has to use that name to be matched on the next run.
+	Prebuilt string     `yaml:"prebuilt,omitempty" json:"prebuilt,omitempty"`
+	Git      *GitSource `yaml:"git,omitempty" json:"git,omitempty"`
+}
+
+// GitSource is a git repository a service is built from.
+type GitSource struct {
+	RepoID int    `yaml:"repo_id" json:"repoID"`
+	Branch string `yaml:"branch" json:"branch"`
+}
+
+// Load reads and validates the manifest at path.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes and validates a manifest.
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Validate reports the first structural problem of the manifest.
+func (m *Manifest) Validate() error {
+	if strings.TrimSpace(m.Project) == "" {
+		return errors.New("manifest: project is required")
+	}
+
+	seen := make(map[string]bool, len(m.Services))
+	for i, s := range m.Services {
+		if s.Name == "" {
+			return fmt.Errorf("manifest: services[%d]: name is required", i)
+		}
+		if seen[s.Name] {
+			return fmt.Errorf("manifest: service %q is declared twice", s.Name)
+		}
+		seen[s.Name] = true
+
+		if s.PortForwarding != "" {
+			if _, err := s.PortForwardingMode(); err != nil {
+				return fmt.Errorf("manifest: service %q: %w", s.Name, err)
+			}
+		}
+		if s.Source != nil && s.Source.Prebuilt != "" && s.Source.Git != nil {
+			return fmt.Errorf("manifest: service %q: source must be either prebuilt or git, not both", s.Name)
+		}
+		if s.Source != nil && s.Source.Git != nil && (s.Source.Git.RepoID == 0 || s.Source.Git.Branch == "") {
+			return fmt.Errorf("manifest: service %q: git source needs repo_id and branch", s.Name)
+		}
+	}
+	return nil
+}
+
+// PortForwardingMode maps the manifest value onto the API enum.
+func (s *Service) PortForwardingMode() (model.PortForwardingMode, error) {
+	switch strings.ToLower(s.PortForwarding) {
+	case "enabled":
+		return model.PortForwardingModeEnabled, nil
+	case "disabled":
+		return model.PortForwardingModeDisabled, nil
+	default:
+		return "", fmt.Errorf("port_forwarding must be enabled or disabled, got %q", s.PortForwarding)
+	}
+}
@@ -0,0 +1,70 @@
+package manifest_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zeabur/cli/pkg/manifest"
+)
+
+func TestParse_Valid(t *testing.T) {
+	m, err := manifest.Parse([]byte(`
+project: my-project
+services:
+  - name: api
+    port_forwarding: Enabled
+    variables:
+      LOG_LEVEL: info
+    domains: [api.example.com]
+  - name: redis
+    source:
+      prebuilt: redis
+    image_tag: "7.2"
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Project != "my-project" || len(m.Services) != 2 {
+		t.Fatalf("got %+v", m)
+	}
+	if m.Services[0].Variables["LOG_LEVEL"] != "info" {
+		t.Errorf("variables not decoded: %+v", m.Services[0].Variables)
+	}
+	if m.Services[1].Source == nil || m.Services[1].Source.Prebuilt != "redis" {
+		t.Errorf("source not decoded: %+v", m.Services[1].Source)
+	}
+}
+
+// TestParse_EmptyVariablesIsManaged: `variables: {}` means "no variables",
+// which is different from leaving the key out (unmanaged).
+func TestParse_EmptyVariablesIsManaged(t *testing.T) {
+	m, err := manifest.Parse([]byte("project: p\nservices:\n  - name: a\n    variables: {}\n  - name: b\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Services[0].Variables == nil {
+		t.Error("variables: {} must decode to a non-nil (managed) map")
+	}
+	if m.Services[1].Variables != nil {
+		t.Error("missing variables must stay nil (unmanaged)")
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name, yaml, want string
+	}{
+		{"no project", "services: []", "project is required"},
+		{"no service name", "project: p\nservices:\n  - image_tag: x\n", "name is required"},
+		{"duplicate service", "project: p\nservices:\n  - name: a\n  - name: a\n", "declared twice"},
+		{"bad port forwarding", "project: p\nservices:\n  - name: a\n    port_forwarding: maybe\n", "enabled or disabled"},
+		{"two sources", "project: p\nservices:\n  - name: a\n    source: {prebuilt: redis, git: {repo_id: 1, branch: main}}\n", "not both"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := manifest.Parse([]byte(tc.yaml))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("error = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// ActionKind is what an action does to its resource.
+type ActionKind string
+
+// valid action kinds
+const (
+	ActionCreate ActionKind = "create"
+	ActionUpdate ActionKind = "update"
+	ActionDelete ActionKind = "delete"
+)
+
+// ResourceKind is the kind of resource an action touches.
+type ResourceKind string
+
+// valid resource kinds
+const (
+	ResourceService        ResourceKind = "service"
+	ResourceVariable       ResourceKind = "variable"
+	ResourceDomain         ResourceKind = "domain"
+	ResourcePortForwarding ResourceKind = "port_forwarding"
+	ResourceImageTag       ResourceKind = "image_tag"
+)
+
+// Action is a single change of the plan. Variable values are deliberately
+// not recorded so that a plan can be printed in CI logs.
+type Action struct {
+	Kind     ActionKind   `json:"action"`
+	Resource ResourceKind `json:"resource"`
+	Service  string       `json:"service"`
+	// Key is the variable key or the domain, empty for service-level resources.
+	Key  string `json:"key,omitempty"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// Plan is the ordered list of actions that converges the live state to the
+// manifest: service creations first, then per-service changes, then
+// deletions.
+type Plan struct {
+	ProjectID     string   `json:"projectID"`
+	EnvironmentID string   `json:"environmentID"`
+	Actions       []Action `json:"actions"`
+}
+
+// DiffOptions tunes which changes Diff plans.
+type DiffOptions struct {
+	// Prune deletes the services that exist live but not in the manifest.
+	Prune bool
+	// ForceImageTags re-applies the image tags of existing services. The API
+	// does not expose the current tag, so they cannot be diffed and are left
+	// out of the plan otherwise.
+	ForceImageTags bool
+}
+
+// Diff computes the plan that converges state to m. Services that exist
+// live but not in the manifest are only deleted with opts.Prune.
+func Diff(m *Manifest, state *State, opts DiffOptions) *Plan {
+	plan := &Plan{ProjectID: state.ProjectID, EnvironmentID: state.EnvironmentID}
+
+	var creates, changes []Action
+	for _, desired := range m.Services {
+		live, exists := state.Services[desired.Name]
+		if !exists {
+			creates = append(creates, Action{
+				Kind: ActionCreate, Resource: ResourceService, Service: desired.Name, To: desired.Source.describe(),
+			})
+			live = &ServiceState{}
+		}
+		changes = append(changes, diffService(desired, live, exists, opts)...)
+	}
+
+	var deletes []Action
+	if opts.Prune {
+		declared := make(map[string]bool, len(m.Services))
+		for _, s := range m.Services {
+			declared[s.Name] = true
+		}
+		for _, name := range sortedKeys(state.Services) {
+			if !declared[name] {
+				deletes = append(deletes, Action{Kind: ActionDelete, Resource: ResourceService, Service: name})
+			}
+		}
+	}
+
+	plan.Actions = append(append(creates, changes...), deletes...)
+	return plan
+}
+
+func diffService(desired Service, live *ServiceState, exists bool, opts DiffOptions) []Action {
+	var actions []Action
+
+	if desired.Variables != nil {
+		for _, key := range sortedKeys(desired.Variables) {
+			current, ok := live.Variables[key]
+			switch {
+			case !ok:
+				actions = append(actions, Action{Kind: ActionCreate, Resource: ResourceVariable, Service: desired.Name, Key: key})
+			case current != desired.Variables[key]:
+				actions = append(actions, Action{Kind: ActionUpdate, Resource: ResourceVariable, Service: desired.Name, Key: key})
+			}
+		}
+		for _, key := range sortedKeys(live.Variables) {
+			if _, ok := desired.Variables[key]; !ok {
+				actions = append(actions, Action{Kind: ActionDelete, Resource: ResourceVariable, Service: desired.Name, Key: key})
+			}
+		}
+	}
+
+	if desired.PortForwarding != "" {
+		// Validate already rejected unknown modes.
+		mode, _ := desired.PortForwardingMode()
+		if live.PortForwarding != mode {
+			from := string(live.PortForwarding)
+			if !exists {
+				from = ""
+			}
+			actions = append(actions, Action{
+				Kind: ActionUpdate, Resource: ResourcePortForwarding, Service: desired.Name, From: from, To: string(mode),
+			})
+		}
+	}
+
+	if desired.ImageTag != "" && (!exists || opts.ForceImageTags) {
+		actions = append(actions, Action{
+			Kind: ActionUpdate, Resource: ResourceImageTag, Service: desired.Name, To: desired.ImageTag,
+		})
+	}
+
+	if desired.Domains != nil {
+		liveDomains := make(map[string]bool, len(live.Domains))
+		for _, d := range live.Domains {
+			liveDomains[strings.ToLower(d)] = true
+		}
+		desiredDomains := make(map[string]bool, len(desired.Domains))
+		for _, d := range desired.Domains {
+			desiredDomains[strings.ToLower(d)] = true
+			if !liveDomains[strings.ToLower(d)] {
+				actions = append(actions, Action{Kind: ActionCreate, Resource: ResourceDomain, Service: desired.Name, Key: d})
+			}
+		}
+		for _, d := range live.Domains {
+			if !desiredDomains[strings.ToLower(d)] {
+				actions = append(actions, Action{Kind: ActionDelete, Resource: ResourceDomain, Service: desired.Name, Key: d})
+			}
+		}
+	}
+
+	return actions
+}
+
+// Empty reports whether the live state already matches the manifest.
+func (p *Plan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+// Count returns how many actions of the given kind the plan holds.
+func (p *Plan) Count(kind ActionKind) int {
+	n := 0
+	for _, a := range p.Actions {
+		if a.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary is the one-line "Plan: x to create, ..." footer.
+func (p *Plan) Summary() string {
+	return fmt.Sprintf("Plan: %d to create, %d to update, %d to delete.",
+		p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionDelete))
+}
+
+// Render writes the human-readable plan, one action per line, marked with
+// "+" for creations, "~" for updates and "-" for deletions, followed by
+// the Summary line.
+func (p *Plan) Render(w io.Writer) {
+	if p.Empty() {
+		_, _ = fmt.Fprintln(w, "No changes. The live state matches the manifest.")
+		return
+	}
+	for _, a := range p.Actions {
+		_, _ = fmt.Fprintln(w, a.String())
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, p.Summary())
+}
+
+// String renders the action as a colored +/~/- line.
+func (a Action) String() string {
+	var symbol string
+	switch a.Kind {
+	case ActionCreate:
+		symbol = color.GreenString("+")
+	case ActionUpdate:
+		symbol = color.YellowString("~")
+	case ActionDelete:
+		symbol = color.RedString("-")
+	}
+	return "  " + symbol + " " + a.Describe()
+}
+
+// Describe renders the action without the +/~/- marker, e.g.
+// "port forwarding api: DISABLED → ENABLED".
+func (a Action) Describe() string {
+	var target string
+	switch a.Resource {
+	case ResourceService:
+		target = "service " + a.Service
+	case ResourceVariable:
+		target = "variable " + a.Service + "." + a.Key
+	case ResourceDomain:
+		target = "domain " + a.Service + ": " + a.Key
+	case ResourcePortForwarding:
+		target = "port forwarding " + a.Service
+	case ResourceImageTag:
+		target = "image tag " + a.Service
+	}
+
+	switch {
+	case a.From != "" && a.To != "":
+		return fmt.Sprintf("%s: %s → %s", target, a.From, a.To)
+	case a.To != "" && a.Resource == ResourceService:
+		return fmt.Sprintf("%s (%s)", target, a.To)
+	case a.To != "":
+		return fmt.Sprintf("%s: %s", target, a.To)
+	default:
+		return target
+	}
+}
+
+func (s *Source) describe() string {
+	switch {
+	case s == nil:
+		return "empty"
+	case s.Prebuilt != "":
+		return "prebuilt: " + s.Prebuilt
+	case s.Git != nil:
+		return fmt.Sprintf("git: repo %d@%s", s.Git.RepoID, s.Git.Branch)
+	default:
+		return "empty"
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,164 @@
+package manifest_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/manifest"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+func liveState() *manifest.State {
+	return &manifest.State{
+		ProjectID:     "project",
+		EnvironmentID: "env",
+		Services: map[string]*manifest.ServiceState{
+			"api": {
+				ID:             "api-id",
+				Variables:      map[string]string{"KEEP": "1", "CHANGE": "old", "DROP": "x"},
+				Domains:        []string{"api.example.com", "old.example.com"},
+				PortForwarding: model.PortForwardingModeDisabled,
+			},
+			"legacy": {ID: "legacy-id"},
+		},
+	}
+}
+
+func desiredManifest() *manifest.Manifest {
+	return &manifest.Manifest{
+		Project: "p",
+		Services: []manifest.Service{
+			{
+				Name:           "api",
+				PortForwarding: "enabled",
+				Variables:      map[string]string{"KEEP": "1", "CHANGE": "new", "ADD": "y"},
+				Domains:        []string{"API.example.com", "my-api.zeabur.app"},
+			},
+			{Name: "cache", Source: &manifest.Source{Prebuilt: "redis"}, ImageTag: "7.2"},
+		},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	plan := manifest.Diff(desiredManifest(), liveState(), manifest.DiffOptions{})
+
+	want := []manifest.Action{
+		{Kind: manifest.ActionCreate, Resource: manifest.ResourceService, Service: "cache", To: "prebuilt: redis"},
+		{Kind: manifest.ActionCreate, Resource: manifest.ResourceVariable, Service: "api", Key: "ADD"},
+		{Kind: manifest.ActionUpdate, Resource: manifest.ResourceVariable, Service: "api", Key: "CHANGE"},
+		{Kind: manifest.ActionDelete, Resource: manifest.ResourceVariable, Service: "api", Key: "DROP"},
+		{Kind: manifest.ActionUpdate, Resource: manifest.ResourcePortForwarding, Service: "api", From: "DISABLED", To: "ENABLED"},
+		{Kind: manifest.ActionCreate, Resource: manifest.ResourceDomain, Service: "api", Key: "my-api.zeabur.app"},
+		{Kind: manifest.ActionDelete, Resource: manifest.ResourceDomain, Service: "api", Key: "old.example.com"},
+		{Kind: manifest.ActionUpdate, Resource: manifest.ResourceImageTag, Service: "cache", To: "7.2"},
+	}
+	if !reflect.DeepEqual(plan.Actions, want) {
+		t.Fatalf("actions:\n got %+v\nwant %+v", plan.Actions, want)
+	}
+	if plan.Summary() != "Plan: 3 to create, 3 to update, 2 to delete." {
+		t.Errorf("summary = %q", plan.Summary())
+	}
+}
+
+// TestDiff_PruneOnly: undeclared live services are only deleted on --prune,
+// so a partial manifest never wipes out a project by accident.
+func TestDiff_PruneOnly(t *testing.T) {
+	m := &manifest.Manifest{Project: "p", Services: []manifest.Service{{Name: "api"}}}
+
+	if plan := manifest.Diff(m, liveState(), manifest.DiffOptions{}); !plan.Empty() {
+		t.Fatalf("without prune want empty plan, got %+v", plan.Actions)
+	}
+
+	plan := manifest.Diff(m, liveState(), manifest.DiffOptions{Prune: true})
+	want := []manifest.Action{{Kind: manifest.ActionDelete, Resource: manifest.ResourceService, Service: "legacy"}}
+	if !reflect.DeepEqual(plan.Actions, want) {
+		t.Fatalf("got %+v, want %+v", plan.Actions, want)
+	}
+}
+
+// TestDiff_ImageTag: the live tag cannot be read, so the tag of an existing
+// service is only re-applied when forced and never shows up as drift.
+func TestDiff_ImageTag(t *testing.T) {
+	m := &manifest.Manifest{Project: "p", Services: []manifest.Service{{Name: "legacy", ImageTag: "7.2"}}}
+
+	if plan := manifest.Diff(m, liveState(), manifest.DiffOptions{}); !plan.Empty() {
+		t.Fatalf("without force want empty plan, got %+v", plan.Actions)
+	}
+
+	plan := manifest.Diff(m, liveState(), manifest.DiffOptions{ForceImageTags: true})
+	want := []manifest.Action{{Kind: manifest.ActionUpdate, Resource: manifest.ResourceImageTag, Service: "legacy", To: "7.2"}}
+	if !reflect.DeepEqual(plan.Actions, want) {
+		t.Fatalf("got %+v, want %+v", plan.Actions, want)
+	}
+}
+
+// recordingClient records the mutations Apply performs.
+type recordingClient struct {
+	api.Client
+	calls []string
+}
+
+func (c *recordingClient) CreatePrebuiltService(_ context.Context, projectID, code string) (*model.Service, error) {
+	c.calls = append(c.calls, "createPrebuilt "+projectID+" "+code)
+	return &model.Service{ID: "cache-id", Name: "cache"}, nil
+}
+
+func (c *recordingClient) UpdateVariables(_ context.Context, serviceID, _ string, data map[string]string) (bool, error) {
+	c.calls = append(c.calls, "updateVariables "+serviceID+" "+data["CHANGE"])
+	return true, nil
+}
+
+func (c *recordingClient) UpdatePortForwardingMode(_ context.Context, serviceID, _ string, mode model.PortForwardingMode) error {
+	c.calls = append(c.calls, "portForwarding "+serviceID+" "+string(mode))
+	return nil
+}
+
+func (c *recordingClient) UpdateImageTag(_ context.Context, serviceID, _, tag string) error {
+	c.calls = append(c.calls, "imageTag "+serviceID+" "+tag)
+	return nil
+}
+
+func (c *recordingClient) AddDomain(_ context.Context, serviceID, _ string, isGenerated bool, domain string, _ ...string) (*string, error) {
+	kind := "custom"
+	if isGenerated {
+		kind = "generated"
+	}
+	c.calls = append(c.calls, "addDomain "+serviceID+" "+kind+" "+domain)
+	return &domain, nil
+}
+
+func (c *recordingClient) RemoveDomain(_ context.Context, domain string) (bool, error) {
+	c.calls = append(c.calls, "removeDomain "+domain)
+	return true, nil
+}
+
+// TestApply: variables are pushed once per service as the full desired set,
+// new services get their ID threaded into later actions, and generated
+// domains are sent as their subdomain.
+func TestApply(t *testing.T) {
+	m, state := desiredManifest(), liveState()
+	plan := manifest.Diff(m, state, manifest.DiffOptions{})
+	c := &recordingClient{}
+
+	var applied int
+	if err := manifest.Apply(context.Background(), c, m, state, plan, func(manifest.Action) { applied++ }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"createPrebuilt project redis",
+		"updateVariables api-id new",
+		"portForwarding api-id ENABLED",
+		"addDomain api-id generated my-api",
+		"removeDomain old.example.com",
+		"imageTag cache-id 7.2",
+	}
+	if !reflect.DeepEqual(c.calls, want) {
+		t.Fatalf("calls:\n got %q\nwant %q", c.calls, want)
+	}
+	if applied != len(plan.Actions) {
+		t.Errorf("onAction called %d times, want %d", applied, len(plan.Actions))
+	}
+}
@@ -0,0 +1,80 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zeabur/cli/pkg/api"
+	"github.com/zeabur/cli/pkg/model"
+)
+
+// State is the live state of the fields a manifest manages.
+type State struct {
+	ProjectID     string
+	EnvironmentID string
+	// Services maps service name to its live state.
+	Services map[string]*ServiceState
+}
+
+// ServiceState is the live state of one service. Fields the manifest does
+// not manage for that service are left empty and never fetched.
+type ServiceState struct {
+	ID             string
+	Variables      map[string]string
+	Domains        []string
+	PortForwarding model.PortForwardingMode
+}
+
+// FetchState reads the live state of the project, fetching variables,
+// domains and port-forwarding modes only for the services where m manages
+// them.
+func FetchState(ctx context.Context, client api.Client, projectID, environmentID string, m *Manifest) (*State, error) {
+	services, err := client.ListAllServicesDetailByEnvironment(ctx, projectID, environmentID)
+	if err != nil {
+		return nil, fmt.Errorf("list services: %w", err)
+	}
+
+	state := &State{
+		ProjectID:     projectID,
+		EnvironmentID: environmentID,
+		Services:      make(map[string]*ServiceState, len(services)),
+	}
+	for _, s := range services {
+		state.Services[s.Name] = &ServiceState{ID: s.ID}
+	}
+
+	for _, desired := range m.Services {
+		live, ok := state.Services[desired.Name]
+		if !ok {
+			continue
+		}
+
+		if desired.Variables != nil {
+			vars, _, err := client.ListVariables(ctx, live.ID, environmentID)
+			if err != nil {
+				return nil, fmt.Errorf("list variables of service %q: %w", desired.Name, err)
+			}
+			live.Variables = vars.ToMap()
+		}
+
+		if desired.Domains != nil {
+			domains, err := client.ListDomains(ctx, live.ID, environmentID)
+			if err != nil {
+				return nil, fmt.Errorf("list domains of service %q: %w", desired.Name, err)
+			}
+			for _, d := range domains {
+				live.Domains = append(live.Domains, d.Domain)
+			}
+		}
+
+		if desired.PortForwarding != "" {
+			mode, err := client.GetPortForwardingMode(ctx, live.ID, environmentID)
+			if err != nil {
+				return nil, fmt.Errorf("get port forwarding mode of service %q: %w", desired.Name, err)
+			}
+			live.PortForwarding = mode
+		}
+	}
+
+	return state, nil
+}
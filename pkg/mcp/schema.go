@@ -0,0 +1,42 @@
+package mcp
+
+// Schema is a JSON Schema describing tool arguments.
+type Schema map[string]any
+
+// Object returns the schema of an object with the given properties, of
+// which required must be present.
+func Object(properties map[string]Schema, required ...string) Schema {
+	if properties == nil {
+		properties = map[string]Schema{}
+	}
+	s := Schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// String returns the schema of a string.
+func String(description string) Schema {
+	return Schema{"type": "string", "description": description}
+}
+
+// Enum returns the schema of a string that is one of values.
+func Enum(description string, values ...string) Schema {
+	return Schema{"type": "string", "description": description, "enum": values}
+}
+
+// Integer returns the schema of an integer.
+func Integer(description string) Schema {
+	return Schema{"type": "integer", "description": description}
+}
+
+// StringArray returns the schema of an array of strings.
+func StringArray(description string) Schema {
+	return Schema{"type": "array", "description": description, "items": Schema{"type": "string"}}
+}
+
+// StringMap returns the schema of an object with string values.
+func StringMap(description string) Schema {
+	return Schema{"type": "object", "description": description, "additionalProperties": Schema{"type": "string"}}
+}
@@ -0,0 +1,380 @@
+// Package mcp implements the tools part of a Model Context Protocol server
+// over stdio: newline-delimited JSON-RPC 2.0 messages on a reader and a
+// writer. It covers what `zeabur mcp serve` needs, namely initialize, ping,
+// tools/list, tools/call, progress and cancellation.
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+)
+
+// ProtocolVersion is the latest protocol revision the server speaks.
+const ProtocolVersion = "2025-06-18"
+
+// supportedVersions are the revisions a client may ask for in initialize;
+// the tools part is the same in all of them.
+var supportedVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Tool is an operation the client can call.
+type Tool struct {
+	Name        string
+	Description string
+	InputSchema Schema
+	// ReadOnly tools do not modify anything. The others are hidden and
+	// refused when the server is read-only.
+	ReadOnly bool
+	// Destructive tools may delete data or interrupt a running service.
+	Destructive bool
+	Handler     func(ctx context.Context, call *Call) (any, error)
+}
+
+// Call is a single tools/call request.
+type Call struct {
+	// Arguments are the tool arguments as sent by the client.
+	Arguments json.RawMessage
+
+	progress func(message string)
+}
+
+// Bind decodes the arguments into v.
+func (c *Call) Bind(v any) error {
+	if len(c.Arguments) == 0 || string(c.Arguments) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(c.Arguments, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// Progress reports a step of a long-running call to the client, if it
+// asked for progress notifications; otherwise it does nothing.
+func (c *Call) Progress(message string) {
+	if c.progress != nil {
+		c.progress(message)
+	}
+}
+
+// Server serves tools to one client.
+type Server struct {
+	Name    string
+	Version string
+	// Instructions tell the model how to use the tools.
+	Instructions string
+	// ReadOnly hides and refuses tools that are not ReadOnly.
+	ReadOnly bool
+
+	tools []Tool
+
+	writeMu sync.Mutex
+	w       io.Writer
+
+	callsMu sync.Mutex
+	calls   map[string]context.CancelFunc
+}
+
+// NewServer returns a server that reports itself as name and version.
+func NewServer(name, version string) *Server {
+	return &Server{Name: name, Version: version}
+}
+
+// AddTool registers t. Tools are listed in the order they were added.
+func (s *Server) AddTool(t Tool) {
+	s.tools = append(s.tools, t)
+}
+
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve reads requests from r and writes responses to w until r ends or
+// ctx is done. Tool calls run concurrently, so that a slow one can be
+// cancelled; Serve waits for them before it returns.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.w = w
+	s.calls = map[string]context.CancelFunc{}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		br := bufio.NewReader(r)
+		for {
+			line, err := br.ReadBytes('\n')
+			if len(line) > 0 {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					readErr <- err
+				}
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				select {
+				case err := <-readErr:
+					return err
+				default:
+					return nil
+				}
+			}
+			s.handle(ctx, &wg, line)
+		}
+	}
+}
+
+func (s *Server) handle(ctx context.Context, wg *sync.WaitGroup, line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+
+	if line[0] == '[' {
+		s.reply(nil, nil, &rpcError{codeInvalidRequest, "batches are not supported"})
+		return
+	}
+	var msg message
+	if err := json.Unmarshal(line, &msg); err != nil {
+		s.reply(nil, nil, &rpcError{codeParseError, "parse error: " + err.Error()})
+		return
+	}
+	if msg.Method == "" {
+		// a response to a request of ours; the server sends none
+		return
+	}
+	if msg.ID == nil {
+		s.notification(msg)
+		return
+	}
+
+	switch msg.Method {
+	case "initialize":
+		s.reply(msg.ID, s.initialize(msg.Params), nil)
+	case "ping":
+		s.reply(msg.ID, struct{}{}, nil)
+	case "tools/list":
+		s.reply(msg.ID, map[string]any{"tools": s.listTools()}, nil)
+	case "tools/call":
+		callCtx, cancel := context.WithCancel(ctx)
+		key := string(msg.ID)
+		s.callsMu.Lock()
+		s.calls[key] = cancel
+		s.callsMu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				s.callsMu.Lock()
+				delete(s.calls, key)
+				s.callsMu.Unlock()
+				cancel()
+			}()
+			result, rpcErr := s.callTool(callCtx, msg.Params)
+			s.reply(msg.ID, result, rpcErr)
+		}()
+	default:
+		s.reply(msg.ID, nil, &rpcError{codeMethodNotFound, "method not found: " + msg.Method})
+	}
+}
+
+func (s *Server) notification(msg message) {
+	if msg.Method != "notifications/cancelled" {
+		// notifications/initialized and the like need no action
+		return
+	}
+	var params struct {
+		RequestID json.RawMessage `json:"requestId"`
+	}
+	if json.Unmarshal(msg.Params, &params) != nil {
+		return
+	}
+	s.callsMu.Lock()
+	cancel := s.calls[string(params.RequestID)]
+	s.callsMu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+func (s *Server) initialize(params json.RawMessage) any {
+	var req struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	_ = json.Unmarshal(params, &req)
+
+	version := ProtocolVersion
+	if slices.Contains(supportedVersions, req.ProtocolVersion) {
+		version = req.ProtocolVersion
+	}
+
+	result := map[string]any{
+		"protocolVersion": version,
+		"capabilities":    map[string]any{"tools": map[string]any{"listChanged": false}},
+		"serverInfo":      map[string]any{"name": s.Name, "version": s.Version},
+	}
+	if s.Instructions != "" {
+		result["instructions"] = s.Instructions
+	}
+	return result
+}
+
+func (s *Server) listTools() []map[string]any {
+	tools := make([]map[string]any, 0, len(s.tools))
+	for _, t := range s.tools {
+		if s.ReadOnly && !t.ReadOnly {
+			continue
+		}
+		schema := t.InputSchema
+		if schema == nil {
+			schema = Object(nil)
+		}
+		tools = append(tools, map[string]any{
+			"name":        t.Name,
+			"description": t.Description,
+			"inputSchema": schema,
+			"annotations": map[string]any{
+				"readOnlyHint":    t.ReadOnly,
+				"destructiveHint": t.Destructive,
+			},
+		})
+	}
+	return tools
+}
+
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (any, *rpcError) {
+	var req struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+		Meta      struct {
+			ProgressToken json.RawMessage `json:"progressToken"`
+		} `json:"_meta"`
+	}
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &rpcError{codeInvalidParams, "invalid params: " + err.Error()}
+	}
+
+	i := slices.IndexFunc(s.tools, func(t Tool) bool { return t.Name == req.Name })
+	if i < 0 {
+		return nil, &rpcError{codeInvalidParams, "unknown tool: " + req.Name}
+	}
+	tool := s.tools[i]
+	if s.ReadOnly && !tool.ReadOnly {
+		return toolError(fmt.Errorf("%s modifies resources and the server is read-only", tool.Name)), nil
+	}
+
+	call := &Call{Arguments: req.Arguments}
+	if token := req.Meta.ProgressToken; token != nil {
+		var n int
+		call.progress = func(message string) {
+			n++
+			s.notify("notifications/progress", map[string]any{
+				"progressToken": token,
+				"progress":      n,
+				"message":       message,
+			})
+		}
+	}
+
+	out, err := tool.Handler(ctx, call)
+	if err != nil {
+		return toolError(err), nil
+	}
+	return toolResult(out)
+}
+
+// toolResult returns out as JSON text, and as structured content when it is
+// an object.
+func toolResult(out any) (any, *rpcError) {
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return toolError(fmt.Errorf("encode result: %w", err)), nil
+	}
+	result := map[string]any{
+		"content": []map[string]any{{"type": "text", "text": string(data)}},
+	}
+	if len(data) > 0 && data[0] == '{' {
+		result["structuredContent"] = json.RawMessage(data)
+	}
+	return result, nil
+}
+
+// toolError reports a failed call as a result, so that the model sees it.
+func toolError(err error) any {
+	return map[string]any{
+		"content": []map[string]any{{"type": "text", "text": err.Error()}},
+		"isError": true,
+	}
+}
+
+func (s *Server) reply(id json.RawMessage, result any, rpcErr *rpcError) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	if result == nil && rpcErr == nil {
+		result = struct{}{}
+	}
+	s.write(response{JSONRPC: "2.0", ID: id, Result: result, Error: rpcErr})
+}
+
+func (s *Server) notify(method string, params any) {
+	s.write(map[string]any{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (s *Server) write(v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_, _ = s.w.Write(append(data, '\n'))
+}
@@ -0,0 +1,174 @@
+package mcp_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/zeabur/cli/pkg/mcp"
+)
+
+// session runs a server on a pipe, like a client on the other end of stdio.
+type session struct {
+	t   *testing.T
+	in  *io.PipeWriter
+	out *bufio.Scanner
+	err chan error
+}
+
+func start(t *testing.T, s *mcp.Server) *session {
+	t.Helper()
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Serve(context.Background(), inR, outW)
+		outW.Close()
+	}()
+	t.Cleanup(func() { inW.Close() })
+	return &session{t: t, in: inW, out: bufio.NewScanner(outR), err: done}
+}
+
+func (s *session) send(msg string) {
+	s.t.Helper()
+	if _, err := io.WriteString(s.in, msg+"\n"); err != nil {
+		s.t.Fatal(err)
+	}
+}
+
+func (s *session) receive() map[string]any {
+	s.t.Helper()
+	if !s.out.Scan() {
+		s.t.Fatalf("no message from the server: %v", s.out.Err())
+	}
+	var msg map[string]any
+	if err := json.Unmarshal(s.out.Bytes(), &msg); err != nil {
+		s.t.Fatalf("invalid message %s: %v", s.out.Text(), err)
+	}
+	return msg
+}
+
+func newServer() *mcp.Server {
+	s := mcp.NewServer("zeabur", "1.2.3")
+	s.AddTool(mcp.Tool{
+		Name:     "echo",
+		ReadOnly: true,
+		InputSchema: mcp.Object(map[string]mcp.Schema{
+			"text": mcp.String("Text to echo"),
+		}, "text"),
+		Handler: func(_ context.Context, call *mcp.Call) (any, error) {
+			var args struct{ Text string }
+			if err := call.Bind(&args); err != nil {
+				return nil, err
+			}
+			call.Progress("echoing")
+			if args.Text == "" {
+				return nil, errors.New("text is required")
+			}
+			return map[string]string{"text": args.Text}, nil
+		},
+	})
+	s.AddTool(mcp.Tool{
+		Name: "wait",
+		Handler: func(ctx context.Context, _ *mcp.Call) (any, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+	return s
+}
+
+func TestServer_InitializeAndList(t *testing.T) {
+	s := start(t, newServer())
+
+	s.send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{}}}`)
+	result := s.receive()["result"].(map[string]any)
+	if result["protocolVersion"] != "2025-03-26" {
+		t.Errorf("protocolVersion = %v, want the client's", result["protocolVersion"])
+	}
+	if info := result["serverInfo"].(map[string]any); info["version"] != "1.2.3" {
+		t.Errorf("serverInfo = %v", info)
+	}
+
+	s.send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	s.send(`{"jsonrpc":"2.0","id":"list","method":"tools/list"}`)
+	msg := s.receive()
+	if msg["id"] != "list" {
+		t.Fatalf("response to %v, want list; notifications get no response", msg["id"])
+	}
+	tools := msg["result"].(map[string]any)["tools"].([]any)
+	if len(tools) != 2 || tools[0].(map[string]any)["name"] != "echo" {
+		t.Errorf("tools = %v", tools)
+	}
+
+	s.send(`{"jsonrpc":"2.0","id":2,"method":"resources/list"}`)
+	if e := s.receive()["error"].(map[string]any); e["code"].(float64) != -32601 {
+		t.Errorf("error = %v, want method not found", e)
+	}
+}
+
+func TestServer_CallTool(t *testing.T) {
+	s := start(t, newServer())
+
+	s.send(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"},"_meta":{"progressToken":"p"}}}`)
+	progress := s.receive()
+	if progress["method"] != "notifications/progress" || progress["params"].(map[string]any)["progressToken"] != "p" {
+		t.Errorf("first message = %v, want a progress notification", progress)
+	}
+	result := s.receive()["result"].(map[string]any)
+	if result["structuredContent"].(map[string]any)["text"] != "hi" || result["isError"] != nil {
+		t.Errorf("result = %v", result)
+	}
+
+	// a failing tool is a result the model can read, not a protocol error
+	s.send(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{}}}`)
+	result = s.receive()["result"].(map[string]any)
+	if result["isError"] != true || !strings.Contains(result["content"].([]any)[0].(map[string]any)["text"].(string), "text is required") {
+		t.Errorf("result = %v, want the tool error", result)
+	}
+
+	s.send(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"missing"}}`)
+	if e := s.receive()["error"]; e == nil {
+		t.Error("called a tool that does not exist")
+	}
+}
+
+func TestServer_ReadOnly(t *testing.T) {
+	server := newServer()
+	server.ReadOnly = true
+	s := start(t, server)
+
+	s.send(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	tools := s.receive()["result"].(map[string]any)["tools"].([]any)
+	if len(tools) != 1 {
+		t.Errorf("tools = %v, want only the read-only echo", tools)
+	}
+
+	s.send(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"wait"}}`)
+	result := s.receive()["result"].(map[string]any)
+	if result["isError"] != true {
+		t.Errorf("result = %v, want the mutating tool refused", result)
+	}
+}
+
+func TestServer_Cancel(t *testing.T) {
+	s := start(t, newServer())
+
+	s.send(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"wait"}}`)
+	s.send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7}}`)
+
+	// hangs until the test times out if the call is not cancelled
+	if msg := s.receive(); msg["id"].(float64) != 7 || msg["result"].(map[string]any)["isError"] != true {
+		t.Errorf("response = %v, want the cancelled call to end", msg)
+	}
+
+	s.in.Close()
+	if err := <-s.err; err != nil {
+		t.Errorf("Serve = %v after stdin closed", err)
+	}
+}
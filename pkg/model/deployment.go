@@ -87,3 +87,35 @@ var (
 	_ Tabler = (Deployments)(nil)
 	_ Tabler = (*Deployment)(nil)
 )
+
+// Deployment statuses reported by the API.
+const (
+	DeploymentStatusPending   = "PENDING"
+	DeploymentStatusBuilding  = "BUILDING"
+	DeploymentStatusDeploying = "DEPLOYING"
+	DeploymentStatusRunning   = "RUNNING"
+	DeploymentStatusFailed    = "FAILED"
+	DeploymentStatusCrashed   = "CRASHED"
+	DeploymentStatusCanceled  = "CANCELED"
+	DeploymentStatusRemoved   = "REMOVED"
+)
+
+// IsBuilding reports whether the deployment has not finished building yet.
+func (d *Deployment) IsBuilding() bool {
+	return d.Status == "" || d.Status == DeploymentStatusPending || d.Status == DeploymentStatusBuilding
+}
+
+// IsTerminal reports whether the deployment has settled: it is either
+// running or will never run.
+func (d *Deployment) IsTerminal() bool {
+	return d.Status == DeploymentStatusRunning || d.IsFailed()
+}
+
+// IsFailed reports whether the deployment ended without reaching RUNNING.
+func (d *Deployment) IsFailed() bool {
+	switch d.Status {
+	case DeploymentStatusFailed, DeploymentStatusCrashed, DeploymentStatusCanceled, DeploymentStatusRemoved:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+// Package plugin finds, installs and removes zeabur-<name> executables that
+// extend the CLI with a `zeabur <name>` command.
+package plugin
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/zeabur/cli/pkg/config"
+)
+
+// Prefix is the file name prefix of plugin executables.
+const Prefix = "zeabur-"
+
+// DirEnv overrides the plugins directory.
+const DirEnv = "ZEABUR_PLUGIN_DIR"
+
+// Plugin is an executable providing `zeabur <Name>`.
+type Plugin struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Installed is true for plugins in the plugins directory, which
+	// `zeabur plugin remove` manages, and false for those found on PATH.
+	Installed bool `json:"installed"`
+}
+
+// DefaultDir returns the plugins directory: $ZEABUR_PLUGIN_DIR, or plugins
+// next to the config file.
+func DefaultDir() (string, error) {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return dir, nil
+	}
+	configPath, err := config.DefaultConfigFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "plugins"), nil
+}
+
+// Discover returns the plugins in dir and on PATH, sorted by name. When
+// two executables provide the same name, the one in dir wins, then the
+// first one on PATH, the way the shell would pick it.
+func Discover(dir string) []Plugin {
+	seen := map[string]bool{}
+	var plugins []Plugin
+
+	add := func(d string, installed bool) {
+		entries, err := os.ReadDir(d)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			name, ok := nameOf(e.Name())
+			if !ok || seen[name] {
+				continue
+			}
+			path := filepath.Join(d, e.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path, Installed: installed})
+		}
+	}
+
+	if dir != "" {
+		add(dir, true)
+	}
+	for _, d := range filepath.SplitList(os.Getenv("PATH")) {
+		if d != "" && d != dir {
+			add(d, false)
+		}
+	}
+
+	slices.SortFunc(plugins, func(a, b Plugin) int { return strings.Compare(a.Name, b.Name) })
+	return plugins
+}
+
+// Install copies the plugin at src into dir and returns what it installed.
+// src is either an executable named zeabur-<name>, or a .tar, .tar.gz or
+// .tgz archive whose zeabur-* files are all installed. name renames a
+// single executable; existing plugins are only replaced with force.
+func Install(dir, src, name string, force bool) ([]Plugin, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create plugins directory: %w", err)
+	}
+
+	if isArchive(src) {
+		if name != "" {
+			return nil, errors.New("a name can only be given for a single executable, not an archive")
+		}
+		return installArchive(dir, src, force)
+	}
+
+	if name == "" {
+		n, ok := nameOf(filepath.Base(src))
+		if !ok {
+			return nil, fmt.Errorf("%s is not named %s<name>; give the plugin a name", filepath.Base(src), Prefix)
+		}
+		name = n
+	}
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err != nil {
+		return nil, err
+	} else if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a file", src)
+	}
+
+	p, err := write(dir, name, f, force)
+	if err != nil {
+		return nil, err
+	}
+	return []Plugin{p}, nil
+}
+
+func installArchive(dir, src string, force bool) ([]Plugin, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if !strings.HasSuffix(src, ".tar") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", src, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var installed []Plugin
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return installed, fmt.Errorf("read %s: %w", src, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		// only the base name is used, so entries cannot escape dir
+		name, ok := nameOf(filepath.Base(hdr.Name))
+		if !ok || ValidateName(name) != nil {
+			continue
+		}
+		p, err := write(dir, name, tr, force)
+		if err != nil {
+			return installed, err
+		}
+		installed = append(installed, p)
+	}
+	if len(installed) == 0 {
+		return nil, fmt.Errorf("no %s* executable found in %s", Prefix, src)
+	}
+	return installed, nil
+}
+
+// write stores the executable read from r as the plugin name in dir.
+func write(dir, name string, r io.Reader, force bool) (Plugin, error) {
+	path := filepath.Join(dir, fileName(name))
+	if _, err := os.Stat(path); err == nil && !force {
+		return Plugin{}, fmt.Errorf("plugin %q is already installed; use --force to replace it", name)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".install-*")
+	if err != nil {
+		return Plugin{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Plugin{}, fmt.Errorf("install plugin %q: %w", name, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return Plugin{}, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return Plugin{}, fmt.Errorf("install plugin %q: %w", name, err)
+	}
+	return Plugin{Name: name, Path: path, Installed: true}, nil
+}
+
+// Remove deletes the plugin name from dir. Plugins found on PATH are left
+// alone: they belong to whatever put them there.
+func Remove(dir, name string) error {
+	for _, p := range Discover(dir) {
+		if p.Name != name {
+			continue
+		}
+		if !p.Installed {
+			return fmt.Errorf("plugin %q was not installed with `zeabur plugin install`; remove %s yourself", name, p.Path)
+		}
+		return os.Remove(p.Path)
+	}
+	return fmt.Errorf("plugin %q not found", name)
+}
+
+// ValidateName checks that name can be a command name.
+func ValidateName(name string) error {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, `/\ `) {
+		return fmt.Errorf("invalid plugin name %q", name)
+	}
+	return nil
+}
+
+// nameOf returns the plugin name of an executable's file name.
+func nameOf(file string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(file))
+		if ext != ".exe" && ext != ".cmd" && ext != ".bat" {
+			return "", false
+		}
+		file = strings.TrimSuffix(file, filepath.Ext(file))
+	}
+	name, ok := strings.CutPrefix(file, Prefix)
+	return name, ok && name != ""
+}
+
+func fileName(name string) string {
+	if runtime.GOOS == "windows" {
+		return Prefix + name + ".exe"
+	}
+	return Prefix + name
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode()&0o111 != 0
+}
+
+func isArchive(path string) bool {
+	for _, ext := range []string{".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package plugin_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/zeabur/cli/pkg/plugin"
+)
+
+func writeExecutable(t *testing.T, path string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscover_PluginsDirWinsOverPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins need an extension on Windows")
+	}
+	dir, bin := t.TempDir(), t.TempDir()
+	t.Setenv("PATH", bin)
+
+	writeExecutable(t, filepath.Join(dir, "zeabur-backup"))
+	writeExecutable(t, filepath.Join(bin, "zeabur-backup"))
+	writeExecutable(t, filepath.Join(bin, "zeabur-audit"))
+	// neither a plugin name nor executable
+	writeExecutable(t, filepath.Join(bin, "zeabur"))
+	if err := os.WriteFile(filepath.Join(bin, "zeabur-notes"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := plugin.Discover(dir)
+	if len(got) != 2 {
+		t.Fatalf("Discover = %+v, want audit and backup", got)
+	}
+	if got[0].Name != "audit" || got[0].Installed {
+		t.Errorf("first plugin = %+v, want audit from PATH", got[0])
+	}
+	if got[1].Name != "backup" || !got[1].Installed || filepath.Dir(got[1].Path) != dir {
+		t.Errorf("second plugin = %+v, want backup from the plugins directory", got[1])
+	}
+}
+
+func TestInstall_Executable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins need an extension on Windows")
+	}
+	dir, src := t.TempDir(), t.TempDir()
+	t.Setenv("PATH", "")
+
+	script := filepath.Join(src, "backup.sh")
+	writeExecutable(t, script)
+
+	if _, err := plugin.Install(dir, script, "", false); err == nil {
+		t.Error("installed an executable without a zeabur- name")
+	}
+	got, err := plugin.Install(dir, script, "backup", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Path != filepath.Join(dir, "zeabur-backup") {
+		t.Fatalf("Install = %+v", got)
+	}
+	if _, err := plugin.Install(dir, script, "backup", false); err == nil {
+		t.Error("replaced an installed plugin without force")
+	}
+	if _, err := plugin.Install(dir, script, "backup", true); err != nil {
+		t.Errorf("Install with force: %v", err)
+	}
+
+	if err := plugin.Remove(dir, "backup"); err != nil {
+		t.Fatal(err)
+	}
+	if len(plugin.Discover(dir)) != 0 {
+		t.Error("plugin still discovered after Remove")
+	}
+	if err := plugin.Remove(dir, "backup"); err == nil {
+		t.Error("removed a plugin that does not exist")
+	}
+}
+
+func TestInstall_Tarball(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins need an extension on Windows")
+	}
+	dir := t.TempDir()
+	t.Setenv("PATH", "")
+
+	archive := filepath.Join(t.TempDir(), "backup.tar.gz")
+	file, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	for _, name := range []string{"README.md", "bin/zeabur-backup", "../zeabur-restore"} {
+		body := []byte("#!/bin/sh\n")
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, c := range []interface{ Close() error }{tw, gz, file} {
+		if err := c.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := plugin.Install(dir, archive, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Name != "backup" || got[1].Name != "restore" {
+		t.Fatalf("Install = %+v, want backup and restore", got)
+	}
+	// entries are flattened into dir and made executable
+	if found := plugin.Discover(dir); len(found) != 2 {
+		t.Errorf("Discover = %+v, want both installed plugins", found)
+	}
+}
+
+func TestRemove_LeavesPathPluginsAlone(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins need an extension on Windows")
+	}
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+	writeExecutable(t, filepath.Join(bin, "zeabur-audit"))
+
+	if err := plugin.Remove(t.TempDir(), "audit"); err == nil {
+		t.Fatal("removed a plugin found on PATH")
+	}
+	if _, err := os.Stat(filepath.Join(bin, "zeabur-audit")); err != nil {
+		t.Errorf("plugin on PATH deleted: %v", err)
+	}
+}
@@ -1,6 +1,8 @@
 package printer
 
 type Printer interface {
-	Table(header []string, rows [][]string) // Print a table with the given header and rows
-	JSON(v any) error                       // Print any value as indented JSON
+	SetOutput(o Output)                     // Select the output format, table by default
+	Table(header []string, rows [][]string) // Print rows as a table, csv or tsv; structured formats get one object per row
+	Data(v any) error                       // Print a value as json, yaml, or through a template or JSONPath expression; a Tabler is a table in the other formats
+	Event(v any, row []string) error        // Print one event of a stream on its own line and flush it
 }
@@ -0,0 +1,83 @@
+package printer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Format is an output format selectable with --output/-o.
+type Format string
+
+const (
+	FormatTable    Format = "table"    // aligned, colored columns (default)
+	FormatWide     Format = "wide"     // the same as table, accepted as in kubectl
+	FormatCSV      Format = "csv"      // comma-separated header and rows
+	FormatTSV      Format = "tsv"      // tab-separated header and rows
+	FormatPlain    Format = "plain"    // rows as space-separated fields, without header or colors
+	FormatJSON     Format = "json"     // the underlying value as indented JSON
+	FormatNDJSON   Format = "ndjson"   // one compact JSON value per line; lists are split into their elements
+	FormatYAML     Format = "yaml"     // the underlying value as YAML
+	FormatTemplate Format = "template" // the underlying value rendered by a Go template
+	FormatJSONPath Format = "jsonpath" // the underlying value queried with a kubectl JSONPath template
+)
+
+// Formats lists every accepted --output value, for help texts and completion.
+var Formats = []string{"table", "wide", "plain", "json", "ndjson", "yaml", "csv", "tsv", "template=...", "jsonpath=..."}
+
+// Output is a parsed --output value.
+type Output struct {
+	Format Format
+	// Expr is the template or JSONPath expression for FormatTemplate and
+	// FormatJSONPath; empty otherwise.
+	Expr string
+}
+
+// ParseOutput parses an --output value such as "yaml" or
+// "jsonpath={.items[*].name}". The empty string selects the table format.
+func ParseOutput(s string) (Output, error) {
+	name, expr, hasExpr := strings.Cut(s, "=")
+	format := Format(strings.ToLower(strings.TrimSpace(name)))
+
+	switch format {
+	case "":
+		if hasExpr {
+			return Output{}, fmt.Errorf("invalid output format %q", s)
+		}
+		return Output{Format: FormatTable}, nil
+	case FormatTable, FormatWide, FormatCSV, FormatTSV, FormatPlain, FormatJSON, FormatNDJSON, FormatYAML:
+		if hasExpr {
+			return Output{}, fmt.Errorf("output format %q does not take an expression", format)
+		}
+		return Output{Format: format}, nil
+	case FormatTemplate, FormatJSONPath:
+		if expr == "" {
+			return Output{}, fmt.Errorf("output format %q requires an expression, e.g. %s=...", format, format)
+		}
+		return Output{Format: format, Expr: expr}, nil
+	default:
+		return Output{}, fmt.Errorf("unknown output format %q (valid: %s)", name, strings.Join(Formats, ", "))
+	}
+}
+
+// Structured reports whether the format renders the underlying value
+// (json, ndjson, yaml, template, jsonpath) rather than the rows of a table.
+func (o Output) Structured() bool {
+	switch o.Format {
+	case FormatJSON, FormatNDJSON, FormatYAML, FormatTemplate, FormatJSONPath:
+		return true
+	}
+	return false
+}
+
+// MachineReadable reports whether the output is meant for other programs
+// rather than for a human at a terminal: every format except table and wide.
+func (o Output) MachineReadable() bool {
+	return o.Format != "" && o.Format != FormatTable && o.Format != FormatWide
+}
+
+func (o Output) String() string {
+	if o.Expr != "" {
+		return string(o.Format) + "=" + o.Expr
+	}
+	return string(o.Format)
+}
@@ -1,20 +1,62 @@
 package printer
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"text/template"
 
 	"github.com/olekukonko/tablewriter"
+	"k8s.io/client-go/util/jsonpath"
 )
 
-type defaultPrinter struct{}
+type defaultPrinter struct {
+	out    io.Writer
+	output Output
+}
 
+// New returns a printer writing to stdout in the table format.
 func New() Printer {
-	return &defaultPrinter{}
+	return NewWriter(os.Stdout)
+}
+
+// NewWriter returns a printer writing to w in the table format.
+func NewWriter(w io.Writer) Printer {
+	return &defaultPrinter{out: w, output: Output{Format: FormatTable}}
+}
+
+func (p *defaultPrinter) SetOutput(o Output) {
+	p.output = o
 }
 
 func (p *defaultPrinter) Table(header []string, rows [][]string) {
+	var err error
+	switch {
+	case p.output.Structured():
+		err = p.Data(tableObjects(header, rows))
+	case p.output.Format == FormatCSV:
+		err = p.delimited(',', header, rows)
+	case p.output.Format == FormatTSV:
+		err = p.delimited('\t', header, rows)
+	case p.output.Format == FormatPlain:
+		for _, row := range rows {
+			if err = p.writeLine([]byte(strings.Join(row, " "))); err != nil {
+				break
+			}
+		}
+	default:
+		p.table(header, rows)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to print %s output: %v\n", p.output.Format, err)
+	}
+}
+
+func (p *defaultPrinter) table(header []string, rows [][]string) {
 	columnsCount := len(header)
 
 	colors := []tablewriter.Colors{
@@ -26,7 +68,7 @@ func (p *defaultPrinter) Table(header []string, rows [][]string) {
 	}
 	headerColor := tablewriter.Colors{tablewriter.Bold, tablewriter.FgBlackColor}
 
-	table := tablewriter.NewWriter(os.Stdout)
+	table := tablewriter.NewWriter(p.out)
 	table.SetHeader(header)
 	table.SetBorder(false)
 	table.SetColumnSeparator("")
@@ -52,13 +94,274 @@ func (p *defaultPrinter) Table(header []string, rows [][]string) {
 	table.Render()
 }
 
-func (p *defaultPrinter) JSON(v any) error {
-	data, err := json.MarshalIndent(v, "", "  ")
+func (p *defaultPrinter) delimited(comma rune, header []string, rows [][]string) error {
+	w := csv.NewWriter(p.out)
+	w.Comma = comma
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+func (p *defaultPrinter) Data(v any) error {
+	v = emptyIfNil(v)
+	if !p.output.Structured() {
+		switch v := v.(type) {
+		case result:
+			return nil
+		case Tabler:
+			// an empty list is left to the message of the command, except
+			// in csv and tsv, whose header still names the columns
+			if rows := v.Rows(); len(rows) > 0 || p.output.Format == FormatCSV || p.output.Format == FormatTSV {
+				p.Table(v.Header(), rows)
+			}
+			return nil
+		}
+	}
+
+	switch p.output.Format {
+	case FormatNDJSON:
+		return p.ndjson(v)
+	case FormatYAML, FormatTemplate, FormatJSONPath:
+		data, err := p.render(v)
+		if err != nil {
+			return err
+		}
+		return p.writeLine(data)
+	default:
+		// json, and the fallback for commands that only have a structured
+		// representation
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		return p.writeLine(data)
+	}
+}
+
+// Event prints one event of a long-running stream, such as a log line, in
+// a single write so it can be piped into jq or grep as it arrives: compact
+// JSON for json and ndjson, a YAML document for yaml, a csv or tsv record
+// for csv and tsv, and the row's fields separated by spaces for table,
+// wide and plain.
+func (p *defaultPrinter) Event(v any, row []string) error {
+	var (
+		data []byte
+		err  error
+	)
+	switch p.output.Format {
+	case FormatJSON, FormatNDJSON:
+		data, err = json.Marshal(v)
+	case FormatYAML:
+		data, err = p.render(v)
+		data = append([]byte("---\n"), data...)
+	case FormatTemplate, FormatJSONPath:
+		data, err = p.render(v)
+	case FormatCSV, FormatTSV:
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if p.output.Format == FormatTSV {
+			w.Comma = '\t'
+		}
+		if err = w.Write(row); err == nil {
+			w.Flush()
+			err = w.Error()
+		}
+		data = buf.Bytes()
+	default:
+		data = []byte(strings.Join(row, " "))
+	}
 	if err != nil {
 		return err
 	}
-	fmt.Println(string(data))
+
+	if err := p.writeLine(data); err != nil {
+		return err
+	}
+	if f, ok := p.out.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
 	return nil
 }
 
+// ndjson prints v as compact JSON on one line, or every element on its own
+// line when v is a list.
+func (p *defaultPrinter) ndjson(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var items []json.RawMessage
+	if len(data) == 0 || data[0] != '[' || json.Unmarshal(data, &items) != nil {
+		return p.writeLine(data)
+	}
+	for _, item := range items {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, item); err != nil {
+			return err
+		}
+		if err := p.writeLine(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// render renders v in the yaml, template or jsonpath format.
+func (p *defaultPrinter) render(v any) ([]byte, error) {
+	switch p.output.Format {
+	case FormatYAML:
+		node, err := yamlNode(v)
+		if err != nil {
+			return nil, err
+		}
+		return marshalYAML(node)
+	case FormatTemplate:
+		return renderTemplate(p.output.Expr, v)
+	case FormatJSONPath:
+		return renderJSONPath(p.output.Expr, v)
+	}
+	return nil, fmt.Errorf("output format %q cannot render values", p.output.Format)
+}
+
+func renderTemplate(expr string, v any) ([]byte, error) {
+	tmpl, err := template.New("output").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+
+	data, err := genericValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("execute template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderJSONPath renders v through a kubectl JSONPath template such as
+// {.items[*].name}. A template without braces is one expression, and
+// missing keys print nothing rather than failing.
+func renderJSONPath(expr string, v any) ([]byte, error) {
+	if !strings.Contains(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+	jp := jsonpath.New("output").AllowMissingKeys(true)
+	if err := jp.Parse(expr); err != nil {
+		return nil, fmt.Errorf("parse jsonpath: %w", err)
+	}
+
+	data, err := genericValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := jp.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("execute jsonpath: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeLine writes data, terminating it with a newline unless it is empty
+// or already ends with one, so the shell prompt does not run into it.
+func (p *defaultPrinter) writeLine(data []byte) error {
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	_, err := p.out.Write(data)
+	return err
+}
+
+// genericValue converts v into the shape encoding/json decodes into (maps,
+// slices, strings, float64/int64, bools and nil), so templates and JSONPath
+// expressions address fields by the same names the json format prints.
+func genericValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return normalizeNumbers(out), nil
+}
+
+func normalizeNumbers(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = normalizeNumbers(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = normalizeNumbers(e)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
+
+// tableObjects turns table rows into one object per row keyed by the
+// header, preserving the column order, for commands that only have a
+// tabular representation.
+func tableObjects(header []string, rows [][]string) []orderedObject {
+	objects := make([]orderedObject, 0, len(rows))
+	for _, row := range rows {
+		obj := orderedObject{keys: header, values: make([]string, len(header))}
+		copy(obj.values, row)
+		objects = append(objects, obj)
+	}
+	return objects
+}
+
+type orderedObject struct {
+	keys   []string
+	values []string
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
 var _ Printer = &defaultPrinter{}
@@ -0,0 +1,315 @@
+package printer_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zeabur/cli/pkg/printer"
+)
+
+type service struct {
+	ID    string   `json:"id"`
+	Name  string   `json:"name"`
+	Port  int      `json:"port"`
+	Ready bool     `json:"ready"`
+	Tags  []string `json:"tags"`
+}
+
+var services = []service{
+	{ID: "s1", Name: "web", Port: 8080, Ready: true, Tags: []string{"frontend"}},
+	{ID: "s2", Name: "db", Port: 5432, Ready: false, Tags: []string{"storage", "postgres"}},
+}
+
+func newPrinter(t *testing.T, output string) (printer.Printer, *bytes.Buffer) {
+	t.Helper()
+
+	o, err := printer.ParseOutput(output)
+	if err != nil {
+		t.Fatalf("ParseOutput(%q): %v", output, err)
+	}
+	var buf bytes.Buffer
+	p := printer.NewWriter(&buf)
+	p.SetOutput(o)
+	return p, &buf
+}
+
+func TestParseOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in     string
+		format printer.Format
+		expr   string
+	}{
+		{"", printer.FormatTable, ""},
+		{"table", printer.FormatTable, ""},
+		{"JSON", printer.FormatJSON, ""},
+		{"wide", printer.FormatWide, ""},
+		{"template={{.name}}", printer.FormatTemplate, "{{.name}}"},
+		{"jsonpath={.items[?(@.a==\"=\")]}", printer.FormatJSONPath, "{.items[?(@.a==\"=\")]}"},
+	}
+	for _, tt := range tests {
+		o, err := printer.ParseOutput(tt.in)
+		if err != nil {
+			t.Errorf("ParseOutput(%q): %v", tt.in, err)
+			continue
+		}
+		if o.Format != tt.format || o.Expr != tt.expr {
+			t.Errorf("ParseOutput(%q) = %+v, want %s %q", tt.in, o, tt.format, tt.expr)
+		}
+	}
+
+	for _, bad := range []string{"xml", "template", "jsonpath=", "yaml=x"} {
+		if _, err := printer.ParseOutput(bad); err == nil {
+			t.Errorf("ParseOutput(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestOutputClassification(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		in                        string
+		structured, machineOutput bool
+	}{
+		{"table", false, false},
+		{"wide", false, false},
+		{"csv", false, true},
+		{"tsv", false, true},
+		{"json", true, true},
+		{"yaml", true, true},
+		{"template=x", true, true},
+		{"jsonpath=x", true, true},
+	} {
+		o, _ := printer.ParseOutput(tt.in)
+		if o.Structured() != tt.structured || o.MachineReadable() != tt.machineOutput {
+			t.Errorf("%s: Structured() = %v, MachineReadable() = %v", tt.in, o.Structured(), o.MachineReadable())
+		}
+	}
+}
+
+func TestTableDelimited(t *testing.T) {
+	t.Parallel()
+
+	header := []string{"ID", "Value"}
+	rows := [][]string{{"a", "x,y"}, {"b", "tab\there"}}
+
+	p, buf := newPrinter(t, "csv")
+	p.Table(header, rows)
+	if want := "ID,Value\na,\"x,y\"\nb,tab\there\n"; buf.String() != want {
+		t.Errorf("csv = %q, want %q", buf.String(), want)
+	}
+
+	p, buf = newPrinter(t, "tsv")
+	p.Table(header, [][]string{{"a", "x,y"}})
+	if want := "ID\tValue\na\tx,y\n"; buf.String() != want {
+		t.Errorf("tsv = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestTableStructured(t *testing.T) {
+	t.Parallel()
+
+	p, buf := newPrinter(t, "json")
+	p.Table([]string{"Name", "ID"}, [][]string{{"web", "s1"}})
+	want := "[\n  {\n    \"Name\": \"web\",\n    \"ID\": \"s1\"\n  }\n]\n"
+	if buf.String() != want {
+		t.Errorf("json = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestTableNotTruncated(t *testing.T) {
+	t.Parallel()
+
+	header := []string{"ID", "Message"}
+	rows := [][]string{{"s1", strings.Repeat("long commit message ", 10)}}
+
+	for _, output := range []string{"table", "wide"} {
+		p, buf := newPrinter(t, output)
+		p.Table(header, rows)
+		if !strings.Contains(buf.String(), rows[0][1]) {
+			t.Errorf("%s output truncated the message:\n%s", output, buf.String())
+		}
+	}
+}
+
+type serviceList []service
+
+func (l serviceList) Header() []string { return []string{"ID", "Name"} }
+
+func (l serviceList) Rows() [][]string {
+	rows := make([][]string, len(l))
+	for i, s := range l {
+		rows[i] = []string{s.ID, s.Name}
+	}
+	return rows
+}
+
+func TestDataTabler(t *testing.T) {
+	t.Parallel()
+
+	p, buf := newPrinter(t, "csv")
+	if err := p.Data(serviceList(services)); err != nil {
+		t.Fatal(err)
+	}
+	if want := "ID,Name\ns1,web\ns2,db\n"; buf.String() != want {
+		t.Errorf("csv = %q, want %q", buf.String(), want)
+	}
+
+	p, buf = newPrinter(t, "json")
+	if err := p.Data(printer.WithTable(serviceList(nil), []string{"ID"}, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if want := "[]\n"; buf.String() != want {
+		t.Errorf("json of an empty list = %q, want %q", buf.String(), want)
+	}
+
+	// an empty table and an action result leave the table format to the
+	// messages of the command
+	p, buf = newPrinter(t, "table")
+	if err := p.Data(serviceList(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Data(printer.Result(map[string]string{"status": "deleted"})); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("table = %q, want nothing", buf.String())
+	}
+
+	p, buf = newPrinter(t, "yaml")
+	if err := p.Data(printer.Result(map[string]string{"status": "deleted"})); err != nil {
+		t.Fatal(err)
+	}
+	if want := "status: deleted\n"; buf.String() != want {
+		t.Errorf("yaml = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDataYAML(t *testing.T) {
+	t.Parallel()
+
+	p, buf := newPrinter(t, "yaml")
+	if err := p.Data(map[string]any{"version": "1.0", "count": 3, "items": services[:1]}); err != nil {
+		t.Fatal(err)
+	}
+	want := `count: 3
+items:
+  - id: s1
+    name: web
+    port: 8080
+    ready: true
+    tags:
+      - frontend
+version: "1.0"
+`
+	if buf.String() != want {
+		t.Errorf("yaml =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestDataTemplate(t *testing.T) {
+	t.Parallel()
+
+	p, buf := newPrinter(t, `template={{range .}}{{.name}}:{{.port}} {{end}}`)
+	if err := p.Data(services); err != nil {
+		t.Fatal(err)
+	}
+	if want := "web:8080 db:5432 \n"; buf.String() != want {
+		t.Errorf("template = %q, want %q", buf.String(), want)
+	}
+
+	p, _ = newPrinter(t, `template={{.missing.field}}`)
+	if err := p.Data(services); err == nil {
+		t.Error("expected an error executing the template against a list")
+	}
+}
+
+func TestDataJSONPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`{[*].name}`, "web db\n"},
+		{`{[0].id}`, "s1\n"},
+		{`[-1].name`, "db\n"},
+		{`{$[*].port}`, "8080 5432\n"},
+		{`{[?(@.ready==true)].name}`, "web\n"},
+		{`{[?(@.port>6000)].id}`, "s1\n"},
+		{`{[?(@.name!="web")].tags[1]}`, "postgres\n"},
+		{`{..tags[0]}`, "frontend storage\n"},
+		{`{[0:1].name}`, "web\n"},
+		{`{[0]['id','name']}`, "s1 web\n"},
+		{`{[0].tags}`, "[\"frontend\"]\n"},
+		{`{range [*]}{.id}={.name}{"\n"}{end}`, "s1=web\ns2=db\n"},
+		{`{range $[*]}{.name},{end}`, "web,db,\n"},
+		{`{[0].nope}`, ""},
+	}
+	for _, tt := range tests {
+		p, buf := newPrinter(t, "jsonpath="+tt.expr)
+		if err := p.Data(services); err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s = %q, want %q", tt.expr, buf.String(), tt.want)
+		}
+	}
+
+	for _, bad := range []string{`{end}`, `{[abc]}`, `{.a`} {
+		p, _ := newPrinter(t, "jsonpath="+bad)
+		if err := p.Data(services); err == nil {
+			t.Errorf("%s: expected an error", bad)
+		}
+	}
+}
+
+func TestNDJSONAndPlain(t *testing.T) {
+	t.Parallel()
+
+	p, buf := newPrinter(t, "ndjson")
+	if err := p.Data(services); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":"s1","name":"web","port":8080,"ready":true,"tags":["frontend"]}
+{"id":"s2","name":"db","port":5432,"ready":false,"tags":["storage","postgres"]}
+`
+	if buf.String() != want {
+		t.Errorf("ndjson =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	p, buf = newPrinter(t, "plain")
+	p.Table([]string{"ID", "Name"}, [][]string{{"s1", "web"}, {"s2", "db"}})
+	if want := "s1 web\ns2 db\n"; buf.String() != want {
+		t.Errorf("plain = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEvent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"table", "s1 web\n"},
+		{"json", `{"id":"s1","name":"web","port":8080,"ready":true,"tags":["frontend"]}` + "\n"},
+		{"yaml", "---\nid: s1\nname: web\nport: 8080\nready: true\ntags:\n  - frontend\n"},
+		{"csv", "s1,web\n"},
+		{"template={{.name}}:{{.port}}", "web:8080\n"},
+	}
+	for _, tt := range tests {
+		p, buf := newPrinter(t, tt.output)
+		if err := p.Event(services[0], []string{"s1", "web"}); err != nil {
+			t.Errorf("%s: %v", tt.output, err)
+			continue
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s = %q, want %q", tt.output, buf.String(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package printer
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Tabler is a value with a table form, such as the types in pkg/model.
+// Data prints it as that table in the table, wide, csv, tsv and plain
+// formats, and as the value itself in the structured formats.
+type Tabler interface {
+	Header() []string
+	Rows() [][]string
+}
+
+// WithTable returns v with the table the non-structured formats print for
+// it, for commands that build their table rather than getting it from a
+// model.
+func WithTable(v any, header []string, rows [][]string) Tabler {
+	return tabled{value: emptyIfNil(v), header: header, rows: rows}
+}
+
+type tabled struct {
+	value  any
+	header []string
+	rows   [][]string
+}
+
+func (t tabled) Header() []string             { return t.header }
+func (t tabled) Rows() [][]string             { return t.rows }
+func (t tabled) MarshalJSON() ([]byte, error) { return json.Marshal(t.value) }
+
+// Result marks v as the outcome of an action, such as a created key, that
+// only the structured formats print: the other formats leave the output to
+// the messages the command logs.
+func Result(v any) any {
+	return result{value: emptyIfNil(v)}
+}
+
+type result struct {
+	value any
+}
+
+func (r result) MarshalJSON() ([]byte, error) { return json.Marshal(r.value) }
+
+// emptyIfNil turns a nil slice into an empty one, so an empty list prints
+// as [] rather than null.
+func emptyIfNil(v any) any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		return reflect.MakeSlice(rv.Type(), 0, 0).Interface()
+	}
+	return v
+}
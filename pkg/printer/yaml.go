@@ -0,0 +1,103 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlNode converts v into a YAML node tree through its JSON encoding, so
+// the yaml format uses the same keys as the json format and keeps struct
+// fields in declaration order instead of sorting them like a map would.
+func yamlNode(v any) (*yaml.Node, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeYAMLNode(dec)
+}
+
+func decodeYAMLNode(dec *json.Decoder) (*yaml.Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok := tok.(type) {
+	case json.Delim:
+		switch tok {
+		case '{':
+			node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, ok := keyTok.(string)
+				if !ok {
+					return nil, fmt.Errorf("unexpected object key %v", keyTok)
+				}
+				value, err := decodeYAMLNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, scalar("!!str", key), value)
+			}
+			if _, err := dec.Token(); err != nil { // closing }
+				return nil, err
+			}
+			return node, nil
+		case '[':
+			node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for dec.More() {
+				value, err := decodeYAMLNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, value)
+			}
+			if _, err := dec.Token(); err != nil { // closing ]
+				return nil, err
+			}
+			return node, nil
+		}
+		return nil, fmt.Errorf("unexpected delimiter %v", tok)
+	case string:
+		return scalar("!!str", tok), nil
+	case json.Number:
+		if _, err := tok.Int64(); err == nil {
+			return scalar("!!int", tok.String()), nil
+		}
+		return scalar("!!float", tok.String()), nil
+	case bool:
+		if tok {
+			return scalar("!!bool", "true"), nil
+		}
+		return scalar("!!bool", "false"), nil
+	case nil:
+		return scalar("!!null", "null"), nil
+	}
+	return nil, fmt.Errorf("unexpected token %v", tok)
+}
+
+func scalar(tag, value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+}
+
+func marshalYAML(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
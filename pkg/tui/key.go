@@ -0,0 +1,89 @@
+package tui
+
+import "unicode/utf8"
+
+// Key is a key press: a printable character such as "a", or the name of
+// a special key such as "up", "enter" or "ctrl+c".
+type Key string
+
+// Special keys.
+const (
+	KeyUp        Key = "up"
+	KeyDown      Key = "down"
+	KeyLeft      Key = "left"
+	KeyRight     Key = "right"
+	KeyEnter     Key = "enter"
+	KeyBackspace Key = "backspace"
+	KeyEscape    Key = "esc"
+	KeyTab       Key = "tab"
+	KeyCtrlC     Key = "ctrl+c"
+	KeyPageUp    Key = "pgup"
+	KeyPageDown  Key = "pgdown"
+)
+
+var sequences = map[string]Key{
+	"\x1b[A": KeyUp, "\x1bOA": KeyUp,
+	"\x1b[B": KeyDown, "\x1bOB": KeyDown,
+	"\x1b[C": KeyRight, "\x1bOC": KeyRight,
+	"\x1b[D": KeyLeft, "\x1bOD": KeyLeft,
+	"\x1b[5~": KeyPageUp,
+	"\x1b[6~": KeyPageDown,
+}
+
+// ParseKeys decodes the bytes a terminal in raw mode sends for key
+// presses. Unknown escape sequences are dropped.
+func ParseKeys(b []byte) []Key {
+	var keys []Key
+	for len(b) > 0 {
+		switch c := b[0]; {
+		case c == 0x1b:
+			if len(b) == 1 {
+				return append(keys, KeyEscape)
+			}
+			n := escapeLength(b)
+			if k, ok := sequences[string(b[:n])]; ok {
+				keys = append(keys, k)
+			} else if n == 1 {
+				keys = append(keys, KeyEscape)
+			}
+			b = b[n:]
+		case c == '\r' || c == '\n':
+			keys, b = append(keys, KeyEnter), b[1:]
+		case c == 0x7f || c == 0x08:
+			keys, b = append(keys, KeyBackspace), b[1:]
+		case c == '\t':
+			keys, b = append(keys, KeyTab), b[1:]
+		case c == 0x03:
+			keys, b = append(keys, KeyCtrlC), b[1:]
+		case c < 0x20:
+			b = b[1:]
+		default:
+			r, n := utf8.DecodeRune(b)
+			keys, b = append(keys, Key(string(r))), b[n:]
+		}
+	}
+	return keys
+}
+
+// escapeLength returns the length of the escape sequence at the start of
+// b: ESC [ params final, ESC O x, or a lone ESC.
+func escapeLength(b []byte) int {
+	switch b[1] {
+	case 'O':
+		return min(3, len(b))
+	case '[':
+		for i := 2; i < len(b); i++ {
+			if b[i] >= 0x40 && b[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(b)
+	}
+	return 1
+}
+
+// Printable reports whether k is a character rather than a special key.
+func (k Key) Printable() bool {
+	r, n := utf8.DecodeRuneInString(string(k))
+	return n == len(k) && r >= 0x20 && r != utf8.RuneError
+}
@@ -0,0 +1,141 @@
+// Package tui draws full-screen terminal interfaces with plain ANSI escape
+// sequences: a raw-mode terminal on the alternate screen, key decoding and
+// helpers to lay out text and sparklines.
+package tui
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrNotTerminal is returned by Open when stdin or stdout is not a terminal.
+var ErrNotTerminal = errors.New("not a terminal")
+
+// Terminal is a terminal in raw mode showing the alternate screen.
+type Terminal struct {
+	in    *os.File
+	out   *bufio.Writer
+	outFd int
+	state *term.State
+}
+
+// Open switches the terminal to raw mode and the alternate screen. Close
+// restores it.
+func Open(in, out *os.File) (*Terminal, error) {
+	if !term.IsTerminal(int(in.Fd())) || !term.IsTerminal(int(out.Fd())) {
+		return nil, ErrNotTerminal
+	}
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return nil, fmt.Errorf("raw mode: %w", err)
+	}
+
+	t := &Terminal{in: in, out: bufio.NewWriter(out), outFd: int(out.Fd()), state: state}
+	// alternate screen, hidden cursor
+	t.out.WriteString("\x1b[?1049h\x1b[?25l")
+	return t, t.out.Flush()
+}
+
+// Close leaves the alternate screen and restores the terminal mode.
+func (t *Terminal) Close() error {
+	t.out.WriteString("\x1b[0m\x1b[?25h\x1b[?1049l")
+	flushErr := t.out.Flush()
+	if err := term.Restore(int(t.in.Fd()), t.state); err != nil {
+		return err
+	}
+	return flushErr
+}
+
+// Size returns the width and height of the terminal.
+func (t *Terminal) Size() (width, height int) {
+	width, height, err := term.GetSize(t.outFd)
+	if err != nil {
+		return 80, 24
+	}
+	return width, height
+}
+
+// Draw replaces the screen with lines. Lines must fit the width, see Fit.
+func (t *Terminal) Draw(lines []string) error {
+	t.out.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i > 0 {
+			t.out.WriteString("\r\n")
+		}
+		t.out.WriteString(line)
+		t.out.WriteString("\x1b[0m\x1b[K")
+	}
+	// clear whatever is left below
+	t.out.WriteString("\x1b[J")
+	return t.out.Flush()
+}
+
+// Keys returns the keys pressed until ctx is done or the input ends.
+func (t *Terminal) Keys(ctx context.Context) <-chan Key {
+	return ReadKeys(ctx, t.in)
+}
+
+// ReadKeys decodes the keys read from r. The channel is closed when r
+// ends; a read blocked at that point is abandoned, not interrupted.
+func ReadKeys(ctx context.Context, r io.Reader) <-chan Key {
+	keys := make(chan Key)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 256)
+		for {
+			n, err := r.Read(buf)
+			for _, k := range ParseKeys(buf[:n]) {
+				select {
+				case keys <- k:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return keys
+}
+
+// Reverse returns s in reverse video, e.g. for a selected row.
+func Reverse(s string) string {
+	return "\x1b[7m" + s + "\x1b[27m"
+}
+
+// Bold returns s in bold.
+func Bold(s string) string {
+	return "\x1b[1m" + s + "\x1b[22m"
+}
+
+// Faint returns s dimmed.
+func Faint(s string) string {
+	return "\x1b[2m" + s + "\x1b[22m"
+}
+
+// stripANSI removes escape sequences, for measuring the width of s.
+func stripANSI(s string) string {
+	if !strings.Contains(s, "\x1b[") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0x1b && i+1 < len(s) && s[i+1] == '[' {
+			i += 2
+			for i < len(s) && (s[i] < 0x40 || s[i] > 0x7e) {
+				i++
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
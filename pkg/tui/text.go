@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Width returns the number of columns s takes, ignoring escape sequences.
+func Width(s string) int {
+	return runewidth.StringWidth(stripANSI(s))
+}
+
+// Fit truncates s to width columns, ending it with "…" when cut, and pads
+// it with spaces to exactly width. s must not contain escape sequences;
+// style the result instead.
+func Fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	s = strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return ' '
+		}
+		return r
+	}, s)
+	if runewidth.StringWidth(s) > width {
+		s = runewidth.Truncate(s, width, "…")
+	}
+	return runewidth.FillRight(s, width)
+}
+
+// Sanitize removes the escape sequences and control characters from s,
+// text from a remote source such as a log line, so that it cannot move the
+// cursor or restyle the screen. Tabs become spaces.
+func Sanitize(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == 0x1b:
+			i += escapeSequenceLength(s[i:])
+			continue
+		case r == '\t':
+			b.WriteByte(' ')
+		case unicode.IsControl(r):
+		case r == utf8.RuneError && size == 1:
+			b.WriteRune(utf8.RuneError)
+		default:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// escapeSequenceLength returns the length of the escape sequence at the
+// start of s: a CSI sequence (ESC [ params final), a string such as an OSC
+// title (ESC ] ... BEL or ESC \), or ESC and the character after it.
+func escapeSequenceLength(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']', 'P', 'X', '^', '_':
+		for i := 2; i < len(s); i++ {
+			if s[i] == 0x07 {
+				return i + 1
+			}
+			if s[i] == 0x1b && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	return 2
+}
+
+// Pad pads s, which may be styled, with spaces to width columns.
+func Pad(s string, width int) string {
+	if w := Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws the last width values as a line of bar characters,
+// scaled between their minimum and maximum.
+func Sparkline(values []float64, width int) string {
+	if width <= 0 || len(values) == 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if hi > lo {
+			i = int((v - lo) / (hi - lo) * float64(len(sparks)-1))
+		}
+		b.WriteRune(sparks[i])
+	}
+	return b.String()
+}
+
+// Chart draws values as a bar chart height lines tall and at most width
+// columns wide, scaled from zero to their maximum. When there are more
+// values than columns, each column shows the largest value of its share,
+// so that short spikes stay visible. Lines are padded to width.
+func Chart(values []float64, width, height int) []string {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	columns := values
+	if len(values) > width {
+		columns = make([]float64, width)
+		for i := range columns {
+			bucket := values[i*len(values)/width : (i+1)*len(values)/width]
+			columns[i] = bucket[0]
+			for _, v := range bucket {
+				columns[i] = math.Max(columns[i], v)
+			}
+		}
+	}
+
+	hi := 0.0
+	for _, v := range columns {
+		hi = math.Max(hi, v)
+	}
+
+	// the height of each column in eighths of a line
+	eighths := make([]int, len(columns))
+	for i, v := range columns {
+		if hi > 0 && v > 0 {
+			eighths[i] = max(1, int(math.Round(v/hi*float64(height*8))))
+		}
+	}
+
+	lines := make([]string, height)
+	for row := range lines {
+		base := (height - 1 - row) * 8
+		var b strings.Builder
+		for _, e := range eighths {
+			switch fill := e - base; {
+			case fill <= 0:
+				b.WriteByte(' ')
+			case fill >= 8:
+				b.WriteRune(sparks[7])
+			default:
+				b.WriteRune(sparks[fill-1])
+			}
+		}
+		lines[row] = runewidth.FillRight(b.String(), width)
+	}
+	return lines
+}
@@ -0,0 +1,87 @@
+package tui_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/zeabur/cli/pkg/tui"
+)
+
+func TestParseKeys(t *testing.T) {
+	got := tui.ParseKeys([]byte("j\x1b[A\x1b[B\x1bOC\r\x7f\x03é\x1b[1;5Z\x1b"))
+	want := []tui.Key{"j", tui.KeyUp, tui.KeyDown, tui.KeyRight, tui.KeyEnter, tui.KeyBackspace, tui.KeyCtrlC, "é", tui.KeyEscape}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseKeys = %q, want %q", got, want)
+	}
+	if !tui.Key("é").Printable() || tui.KeyUp.Printable() {
+		t.Error("Printable confuses characters and special keys")
+	}
+}
+
+func TestFit(t *testing.T) {
+	for _, tc := range []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"web", 5, "web  "},
+		{"api-gateway", 6, "api-g…"},
+		{"服務名稱", 5, "服務…"},
+		{"a\tb", 3, "a b"},
+	} {
+		if got := tui.Fit(tc.in, tc.width); got != tc.want {
+			t.Errorf("Fit(%q, %d) = %q, want %q", tc.in, tc.width, got, tc.want)
+		}
+	}
+	if w := tui.Width(tui.Bold("web")); w != 3 {
+		t.Errorf("Width of a styled string = %d, want 3", w)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+	}{
+		{"listening on :8080", "listening on :8080"},
+		{"\x1b[31merror\x1b[0m: failed", "error: failed"},
+		{"\x1b]0;owned\x07title", "title"},
+		{"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"\x1b[2J\x1b[Hclear", "clear"},
+		{"progress\r50%\b\x00", "progress50%"},
+		{"a\tb", "a b"},
+		{"服務\u009b31m", "服務31m"},
+		{"cut\x1b[", "cut"},
+	} {
+		if got := tui.Sanitize(tc.in); got != tc.want {
+			t.Errorf("Sanitize(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := tui.Sparkline([]float64{0, 1, 2, 3, 4, 5, 6, 7}, 8); got != "▁▂▃▄▅▆▇█" {
+		t.Errorf("Sparkline = %q", got)
+	}
+	// only the latest values that fit are drawn
+	if got := tui.Sparkline([]float64{9, 0, 7}, 2); got != "▁█" {
+		t.Errorf("Sparkline = %q", got)
+	}
+	if got := tui.Sparkline([]float64{3, 3}, 4); got != "▁▁" {
+		t.Errorf("Sparkline of a flat series = %q", got)
+	}
+}
+
+func TestChart(t *testing.T) {
+	got := tui.Chart([]float64{0, 2, 4, 8}, 5, 2)
+	want := []string{"   █ ", " ▄██ "}
+	if !slices.Equal(got, want) {
+		t.Errorf("Chart = %q, want %q", got, want)
+	}
+	// columns keep the peak of the values they stand for
+	if got := tui.Chart([]float64{1, 0, 0, 4}, 2, 1); !slices.Equal(got, []string{"▂█"}) {
+		t.Errorf("Chart of a resampled series = %q", got)
+	}
+	if got := tui.Chart([]float64{0, 0}, 2, 1); !slices.Equal(got, []string{"  "}) {
+		t.Errorf("Chart of an idle series = %q", got)
+	}
+}
@@ -28,7 +28,8 @@ const (
 )
 
 type viperContext struct {
-	viper *viper.Viper
+	viper  *viper.Viper
+	prefix string
 }
 
 // NewViperContext creates a new Context based on viper
@@ -36,11 +37,24 @@ func NewViperContext(viper *viper.Viper) Context {
 	return &viperContext{viper: viper}
 }
 
+// NewPrefixedViperContext creates a Context based on viper whose keys live
+// under prefix, e.g. "profiles.work" for a named auth profile.
+func NewPrefixedViperContext(viper *viper.Viper, prefix string) Context {
+	return &viperContext{viper: viper, prefix: prefix}
+}
+
+func (c *viperContext) key(k string) string {
+	if c.prefix == "" {
+		return k
+	}
+	return c.prefix + "." + k
+}
+
 func (c *viperContext) GetWorkspace() *Workspace {
 	return &Workspace{
-		ID:   c.viper.GetString(KeyWorkspaceID),
-		Name: c.viper.GetString(KeyWorkspaceName),
-		Kind: c.viper.GetString(KeyWorkspaceKind),
+		ID:   c.viper.GetString(c.key(KeyWorkspaceID)),
+		Name: c.viper.GetString(c.key(KeyWorkspaceName)),
+		Kind: c.viper.GetString(c.key(KeyWorkspaceKind)),
 	}
 }
 
@@ -49,66 +63,66 @@ func (c *viperContext) SetWorkspace(w *Workspace) {
 		c.ClearWorkspace()
 		return
 	}
-	c.viper.Set(KeyWorkspaceID, w.ID)
-	c.viper.Set(KeyWorkspaceName, w.Name)
-	c.viper.Set(KeyWorkspaceKind, w.Kind)
+	c.viper.Set(c.key(KeyWorkspaceID), w.ID)
+	c.viper.Set(c.key(KeyWorkspaceName), w.Name)
+	c.viper.Set(c.key(KeyWorkspaceKind), w.Kind)
 }
 
 func (c *viperContext) ClearWorkspace() {
-	c.viper.Set(KeyWorkspaceID, "")
-	c.viper.Set(KeyWorkspaceName, "")
-	c.viper.Set(KeyWorkspaceKind, "")
+	c.viper.Set(c.key(KeyWorkspaceID), "")
+	c.viper.Set(c.key(KeyWorkspaceName), "")
+	c.viper.Set(c.key(KeyWorkspaceKind), "")
 }
 
 func (c *viperContext) GetProject() BasicInfo {
 	return &basicInfo{
-		id:   c.viper.GetString(KeyProjectID),
-		name: c.viper.GetString(KeyProjectName),
+		id:   c.viper.GetString(c.key(KeyProjectID)),
+		name: c.viper.GetString(c.key(KeyProjectName)),
 	}
 }
 
 func (c *viperContext) SetProject(project BasicInfo) {
-	c.viper.Set(KeyProjectID, project.GetID())
-	c.viper.Set(KeyProjectName, project.GetName())
+	c.viper.Set(c.key(KeyProjectID), project.GetID())
+	c.viper.Set(c.key(KeyProjectName), project.GetName())
 }
 
 func (c *viperContext) ClearProject() {
-	c.viper.Set(KeyProjectID, "")
-	c.viper.Set(KeyProjectName, "")
+	c.viper.Set(c.key(KeyProjectID), "")
+	c.viper.Set(c.key(KeyProjectName), "")
 }
 
 func (c *viperContext) GetEnvironment() BasicInfo {
 	return &basicInfo{
-		id:   c.viper.GetString(KeyEnvironmentID),
-		name: c.viper.GetString(KeyEnvironmentName),
+		id:   c.viper.GetString(c.key(KeyEnvironmentID)),
+		name: c.viper.GetString(c.key(KeyEnvironmentName)),
 	}
 }
 
 func (c *viperContext) SetEnvironment(environment BasicInfo) {
-	c.viper.Set(KeyEnvironmentID, environment.GetID())
-	c.viper.Set(KeyEnvironmentName, environment.GetName())
+	c.viper.Set(c.key(KeyEnvironmentID), environment.GetID())
+	c.viper.Set(c.key(KeyEnvironmentName), environment.GetName())
 }
 
 func (c *viperContext) ClearEnvironment() {
-	c.viper.Set(KeyEnvironmentID, "")
-	c.viper.Set(KeyEnvironmentName, "")
+	c.viper.Set(c.key(KeyEnvironmentID), "")
+	c.viper.Set(c.key(KeyEnvironmentName), "")
 }
 
 func (c *viperContext) GetService() BasicInfo {
 	return &basicInfo{
-		id:   c.viper.GetString(KeyServiceID),
-		name: c.viper.GetString(KeyServiceName),
+		id:   c.viper.GetString(c.key(KeyServiceID)),
+		name: c.viper.GetString(c.key(KeyServiceName)),
 	}
 }
 
 func (c *viperContext) SetService(service BasicInfo) {
-	c.viper.Set(KeyServiceID, service.GetID())
-	c.viper.Set(KeyServiceName, service.GetName())
+	c.viper.Set(c.key(KeyServiceID), service.GetID())
+	c.viper.Set(c.key(KeyServiceName), service.GetName())
 }
 
 func (c *viperContext) ClearService() {
-	c.viper.Set(KeyServiceID, "")
-	c.viper.Set(KeyServiceName, "")
+	c.viper.Set(c.key(KeyServiceID), "")
+	c.viper.Set(c.key(KeyServiceName), "")
 }
 
 func (c *viperContext) ClearAll() {
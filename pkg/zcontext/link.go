@@ -0,0 +1,142 @@
+package zcontext
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LinkDir and LinkFile locate the per-directory link written by `zeabur
+// link`: <dir>/.zeabur/link.yaml binds a checkout to a project, environment
+// and, optionally, a service.
+const (
+	LinkDir  = ".zeabur"
+	LinkFile = "link.yaml"
+)
+
+// Link is the content of a link file.
+type Link struct {
+	Project     LinkTarget `yaml:"project"`
+	Environment LinkTarget `yaml:"environment,omitempty"`
+	Service     LinkTarget `yaml:"service,omitempty"`
+
+	// path is the file the link was read from or written to.
+	path string
+}
+
+// LinkTarget is one linked resource. The name is informational; commands
+// resolve resources by ID.
+type LinkTarget struct {
+	ID   string `yaml:"id,omitempty"`
+	Name string `yaml:"name,omitempty"`
+}
+
+// Path returns the file the link was read from or written to.
+func (l *Link) Path() string {
+	return l.path
+}
+
+// Dir returns the linked directory, the parent of LinkDir.
+func (l *Link) Dir() string {
+	return filepath.Dir(filepath.Dir(l.path))
+}
+
+// LinkPath returns the link file path for dir.
+func LinkPath(dir string) string {
+	return filepath.Join(dir, LinkDir, LinkFile)
+}
+
+// FindLink looks for a link file in dir and its parents, nearest first.
+// It returns nil without an error when none of them is linked.
+func FindLink(dir string) (*Link, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		link, err := ReadLink(LinkPath(dir))
+		if err == nil {
+			return link, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// ReadLink reads the link file at path.
+func ReadLink(path string) (*Link, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var link Link
+	if err := yaml.Unmarshal(data, &link); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if link.Project.ID == "" {
+		return nil, fmt.Errorf("parse %s: project.id is required", path)
+	}
+	link.path = path
+	return &link, nil
+}
+
+// WriteLink writes link to dir's link file, creating LinkDir if needed.
+func WriteLink(dir string, link *Link) error {
+	path := LinkPath(dir)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(link)
+	if err != nil {
+		return err
+	}
+	header := []byte("# Written by `zeabur link`; remove with `zeabur unlink`.\n")
+	if err := os.WriteFile(path, append(header, data...), 0o644); err != nil {
+		return err
+	}
+	link.path = path
+	return nil
+}
+
+// RemoveLink deletes the link file of link, and LinkDir too when nothing
+// else is left in it.
+func RemoveLink(link *Link) error {
+	if err := os.Remove(link.path); err != nil {
+		return err
+	}
+	// best effort: keep the directory if the user stored other files there
+	_ = os.Remove(filepath.Dir(link.path))
+	return nil
+}
+
+// NewLinkContext returns an in-memory Context whose project, environment
+// and service start from link and whose workspace is the given one. Writes
+// stay in memory like those of NewEphemeralContext: the link file only
+// changes through `zeabur link`.
+func NewLinkContext(link *Link, workspace *Workspace) Context {
+	ctx := NewEphemeralContext(workspace)
+	if link.Project.ID != "" {
+		ctx.SetProject(NewBasicInfo(link.Project.ID, link.Project.Name))
+	}
+	if link.Environment.ID != "" {
+		ctx.SetEnvironment(NewBasicInfo(link.Environment.ID, link.Environment.Name))
+	}
+	if link.Service.ID != "" {
+		ctx.SetService(NewBasicInfo(link.Service.ID, link.Service.Name))
+	}
+	return ctx
+}
@@ -0,0 +1,124 @@
+package zcontext_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zeabur/cli/pkg/zcontext"
+)
+
+// TestFindLink_WalksUp: commands run from a subdirectory of a linked
+// checkout must find the link at its root.
+func TestFindLink_WalksUp(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "cmd", "server")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	link := &zcontext.Link{
+		Project:     zcontext.LinkTarget{ID: "p1", Name: "api"},
+		Environment: zcontext.LinkTarget{ID: "e1", Name: "production"},
+		Service:     zcontext.LinkTarget{ID: "s1", Name: "web"},
+	}
+	if err := zcontext.WriteLink(root, link); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := zcontext.FindLink(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Project != link.Project || got.Environment != link.Environment || got.Service != link.Service {
+		t.Fatalf("FindLink = %+v, want %+v", got, link)
+	}
+	if got.Path() != zcontext.LinkPath(root) {
+		t.Errorf("Path() = %s, want %s", got.Path(), zcontext.LinkPath(root))
+	}
+	if got.Dir() != root {
+		t.Errorf("Dir() = %s, want %s", got.Dir(), root)
+	}
+}
+
+func TestFindLink_NoLink(t *testing.T) {
+	got, err := zcontext.FindLink(t.TempDir())
+	if got != nil || err != nil {
+		t.Fatalf("FindLink = %+v, %v; want nil, nil", got, err)
+	}
+}
+
+// TestFindLink_Invalid reports a link without a project rather than
+// silently falling back to a parent's link or the global context.
+func TestFindLink_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, zcontext.LinkDir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(zcontext.LinkPath(dir), []byte("service:\n  id: s1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := zcontext.FindLink(dir); err == nil {
+		t.Fatal("FindLink succeeded on a link without project.id")
+	}
+}
+
+// TestRemoveLink_KeepsOtherFiles removes .zeabur only when it is empty.
+func TestRemoveLink_KeepsOtherFiles(t *testing.T) {
+	for _, extra := range []bool{false, true} {
+		dir := t.TempDir()
+		link := &zcontext.Link{Project: zcontext.LinkTarget{ID: "p1"}}
+		if err := zcontext.WriteLink(dir, link); err != nil {
+			t.Fatal(err)
+		}
+		other := filepath.Join(dir, zcontext.LinkDir, "notes")
+		if extra {
+			if err := os.WriteFile(other, nil, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := zcontext.RemoveLink(link); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(link.Path()); !os.IsNotExist(err) {
+			t.Errorf("link file still exists: %v", err)
+		}
+		_, err := os.Stat(filepath.Join(dir, zcontext.LinkDir))
+		if extra && err != nil {
+			t.Errorf("%s was removed along with the link", zcontext.LinkDir)
+		}
+		if !extra && !os.IsNotExist(err) {
+			t.Errorf("empty %s was kept", zcontext.LinkDir)
+		}
+	}
+}
+
+// TestLinkContext: the link seeds the context, and writes stay in memory.
+func TestLinkContext(t *testing.T) {
+	ws := &zcontext.Workspace{ID: "65aa1234567890abcdef1234", Name: "acme", Kind: zcontext.WorkspaceKindTeam}
+	link := &zcontext.Link{
+		Project:     zcontext.LinkTarget{ID: "p1", Name: "api"},
+		Environment: zcontext.LinkTarget{ID: "e1", Name: "production"},
+	}
+
+	ctx := zcontext.NewLinkContext(link, ws)
+	if ctx.GetProject().GetID() != "p1" || ctx.GetEnvironment().GetID() != "e1" {
+		t.Fatalf("context = %v / %v, want the linked project and environment", ctx.GetProject(), ctx.GetEnvironment())
+	}
+	if !ctx.GetService().Empty() {
+		t.Errorf("service = %v, want unset", ctx.GetService())
+	}
+	if ctx.GetWorkspace().ID != ws.ID {
+		t.Errorf("workspace = %+v, want %+v", ctx.GetWorkspace(), ws)
+	}
+
+	ctx.SetService(zcontext.NewBasicInfo("s1", "web"))
+	if ctx.GetService().GetID() != "s1" {
+		t.Errorf("SetService did not stick")
+	}
+	if link.Service.ID != "" {
+		t.Errorf("SetService wrote through to the link")
+	}
+}